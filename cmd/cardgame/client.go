@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// client talks to the card game REST API on behalf of one player.
+// It remembers the session token the server issues so later commands act as that player.
+type client struct {
+	baseURL string
+	token   string
+	apiKey  string
+	org     string // Organization the client acts in; empty for the default organization
+	http    *http.Client
+}
+
+// apiPrefix is the path prefix of the API version the client speaks.
+const apiPrefix = "/api/v1"
+
+// newClient creates a client for the server at baseURL, optionally starting with an existing session token or API key.
+func newClient(baseURL, token, apiKey string) *client {
+	return &client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		apiKey:  apiKey,
+		http:    &http.Client{},
+	}
+}
+
+// do sends a request with an optional JSON body and decodes the JSON response into out, if it is not nil.
+func (c *client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	resp, err := c.send(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// send sends a request and returns the response once it is known to have succeeded.
+// The caller must close the response body.
+func (c *client) send(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	// Encode the request body, if there is one
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+apiPrefix+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	// Identify the caller by their session, or by an API key for admin commands
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	if c.org != "" {
+		req.Header.Set("X-Org-ID", c.org)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep any session the server issued, such as when joining a game
+	if token := resp.Header.Get("X-Session-Token"); token != "" {
+		c.token = token
+	}
+
+	// Turn error statuses into errors carrying the server's message
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	return resp, nil
+}
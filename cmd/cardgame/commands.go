@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"my-card-game/internal/api/models"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+)
+
+// command is one of the CLI's subcommands.
+type command struct {
+	usage string
+	help  string
+	run   func(ctx context.Context, c *client, args []string) error
+}
+
+// commands lists the subcommands by name.
+var commands = map[string]command{
+	"register": {"register [-password password] <player>", "claim a player name with a password and start a session as it", runRegister},
+	"login":    {"login [-password password] <player>", "start a session as the player", runLogin},
+	"create":   {"create [-mode mode] [-private] [-password password] <name>", "create a game owned by the current player", runCreate},
+	"join":     {"join [-password password] <game-id> [player]", "join a game, as the current player by default", runJoin},
+	"shuffle":  {"shuffle <game-id>", "shuffle the game's deck", runShuffle},
+	"deal":     {"deal <game-id> <player>", "deal one card to a player", runDeal},
+	"hand":     {"hand <game-id> [player]", "show a player's hand, the current player's by default", runHand},
+	"values":   {"values <game-id>", "list the players with the value of their hands", runValues},
+	"watch":    {"watch <game-id>", "print the game's changes as they happen, until interrupted", runWatch},
+}
+
+// runRegister claims a player name with a password and starts a session so later commands act as the player.
+func runRegister(ctx context.Context, c *client, args []string) error {
+	return startSession(ctx, c, "register", "/players", args)
+}
+
+// runLogin signs in with the player's password and starts a session so later commands act as the player.
+func runLogin(ctx context.Context, c *client, args []string) error {
+	return startSession(ctx, c, "login", "/sessions", args)
+}
+
+// startSession posts the player's name and password to path, which issues a session the client keeps. The
+// password comes from the -password flag, or from the CARDGAME_PASSWORD environment variable.
+func startSession(ctx context.Context, c *client, name, path string, args []string) error {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	password := flags.String("password", os.Getenv("CARDGAME_PASSWORD"), "the player's password")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 || *password == "" {
+		return errUsage
+	}
+
+	var session struct {
+		Token     string `json:"token"`
+		ExpiresAt string `json:"expires_at"`
+	}
+	body := map[string]string{"player_name": flags.Arg(0), "password": *password}
+	if err := c.do(ctx, "POST", path, body, &session); err != nil {
+		return err
+	}
+
+	fmt.Printf("Logged in as %s until %s\n", flags.Arg(0), session.ExpiresAt)
+	fmt.Printf("Session token: %s\n", session.Token)
+	return nil
+}
+
+// runCreate creates a new game.
+func runCreate(ctx context.Context, c *client, args []string) error {
+	flags := flag.NewFlagSet("create", flag.ContinueOnError)
+	mode := flags.String("mode", "", "game mode, such as standard, war, or crazy_eights")
+	private := flags.Bool("private", false, "hide the game from public listings")
+	password := flags.String("password", "", "password players must supply to join")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errUsage
+	}
+
+	var game models.Game
+	body := map[string]interface{}{"name": flags.Arg(0), "mode": *mode, "private": *private, "password": *password}
+	if err := c.do(ctx, "POST", "/games", body, &game); err != nil {
+		return err
+	}
+
+	printGame(&game)
+	return nil
+}
+
+// runJoin seats a player in a game, as the current player by default. Without a session, joining with a player name
+// nobody has claimed claims it, and the server issues a session for it, which the client keeps.
+func runJoin(ctx context.Context, c *client, args []string) error {
+	flags := flag.NewFlagSet("join", flag.ContinueOnError)
+	password := flags.String("password", "", "password of a password-protected game")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() < 1 || flags.NArg() > 2 {
+		return errUsage
+	}
+	player := ""
+	if flags.NArg() == 2 {
+		player = flags.Arg(1)
+	}
+
+	var game models.Game
+	body := map[string]string{"player_name": player, "password": *password}
+	if err := c.do(ctx, "POST", gamePath(flags.Arg(0), "add-player"), body, &game); err != nil {
+		return err
+	}
+
+	printGame(&game)
+	return nil
+}
+
+// runShuffle shuffles a game's deck.
+func runShuffle(ctx context.Context, c *client, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	if err := c.do(ctx, "POST", gamePath(args[0], "shuffle"), nil, nil); err != nil {
+		return err
+	}
+
+	fmt.Println("Deck shuffled")
+	return nil
+}
+
+// runDeal deals a card to a player.
+func runDeal(ctx context.Context, c *client, args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+
+	var card models.Card
+	if err := c.do(ctx, "POST", gamePath(args[0], "deal-card"), map[string]string{"player_name": args[1]}, &card); err != nil {
+		return err
+	}
+
+	fmt.Printf("Dealt %s to %s\n", formatCard(card), args[1])
+	return nil
+}
+
+// runHand shows the cards in a player's hand.
+func runHand(ctx context.Context, c *client, args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return errUsage
+	}
+	path := gamePath(args[0], "player-hand")
+	if len(args) == 2 {
+		path += "?player_name=" + url.QueryEscape(args[1])
+	}
+
+	var hand []models.Card
+	if err := c.do(ctx, "GET", path, nil, &hand); err != nil {
+		return err
+	}
+
+	if len(hand) == 0 {
+		fmt.Println("(no cards)")
+		return nil
+	}
+	for _, card := range hand {
+		fmt.Println(formatCard(card))
+	}
+	return nil
+}
+
+// runValues lists the players in a game with the value of their hands.
+func runValues(ctx context.Context, c *client, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	var values []map[string]interface{}
+	if err := c.do(ctx, "GET", gamePath(args[0], "player-hand-values"), nil, &values); err != nil {
+		return err
+	}
+
+	for _, value := range values {
+		fmt.Printf("%v\t%v\n", value["player_name"], value["hand_value"])
+	}
+	return nil
+}
+
+// runWatch follows the game's server-sent event stream, printing each change until the stream ends or the user interrupts it.
+func runWatch(ctx context.Context, c *client, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	// Stop watching on Ctrl-C, which in the interactive shell returns to the prompt
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	resp, err := c.send(ctx, "GET", gamePath(args[0], "stream"), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	fmt.Printf("Watching game %s, press Ctrl-C to stop\n", args[0])
+
+	// Each event's data arrives on a single "data:" line
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+
+		var update struct {
+			Operation string       `json:"operation"`
+			Game      *models.Game `json:"game"`
+		}
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &update); err != nil {
+			continue
+		}
+
+		fmt.Printf("-- %s\n", update.Operation)
+		if update.Game != nil {
+			printGame(update.Game)
+		}
+	}
+
+	// An interrupt or a closed stream ends the watch normally
+	if err := scanner.Err(); err != nil && ctx.Err() == nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// printGame prints a summary of a game: its identity, players, and the hands the caller can see.
+func printGame(game *models.Game) {
+	fmt.Printf("Game %s %q (%s, %s)\n", game.ID.Hex(), game.Name, game.Mode, game.Status)
+	fmt.Printf("  Owner:   %s\n", game.Owner)
+	fmt.Printf("  Players: %s\n", strings.Join(game.Players, ", "))
+	fmt.Printf("  Deck:    %d cards\n", game.CardsLeft())
+
+	// Print the hands in a stable order
+	players := make([]string, 0, len(game.PlayerHands))
+	for player := range game.PlayerHands {
+		players = append(players, player)
+	}
+	sort.Strings(players)
+	for _, player := range players {
+		cards := make([]string, 0, len(game.PlayerHands[player]))
+		for _, card := range game.PlayerHands[player] {
+			cards = append(cards, formatCard(card))
+		}
+		fmt.Printf("  %s: %s\n", player, strings.Join(cards, " "))
+	}
+}
+
+// formatCard renders a card as, for example, "Queen of Hearts".
+func formatCard(card models.Card) string {
+	if card.Suit == models.SuitJoker {
+		return "Joker"
+	}
+	return card.Value.String() + " of " + card.Suit.String()
+}
+
+// gamePath builds the path of one of a game's endpoints.
+func gamePath(gameID, endpoint string) string {
+	return "/games/" + url.PathEscape(gameID) + "/" + endpoint
+}
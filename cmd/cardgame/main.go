@@ -0,0 +1,118 @@
+// Command cardgame is a terminal client for the card game server.
+//
+// Run a single command, for example:
+//
+//	cardgame -server http://localhost:8080 login -password s3cret-pass alice
+//	cardgame -token <token> hand <game-id>
+//
+// or run it without a command to get an interactive shell in which the session
+// from login or join carries over between commands.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// errUsage is returned by a command whose arguments are wrong, so its usage line is shown.
+var errUsage = errors.New("wrong arguments")
+
+func main() {
+	// Read the connection settings from flags, falling back to the environment
+	server := flag.String("server", envOr("CARDGAME_SERVER", "http://localhost:8080"), "base URL of the card game server")
+	token := flag.String("token", os.Getenv("CARDGAME_TOKEN"), "session token to act as an existing player")
+	apiKey := flag.String("api-key", os.Getenv("CARDGAME_API_KEY"), "API key for admin requests")
+	org := flag.String("org", os.Getenv("CARDGAME_ORG"), "organization to act in, if not the default one")
+	flag.Usage = usage
+	flag.Parse()
+
+	c := newClient(*server, *token, *apiKey)
+	c.org = *org
+
+	// Run a single command when one is given, otherwise start the interactive shell
+	if flag.NArg() > 0 {
+		if err := execute(c, flag.Args()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	shell(c)
+}
+
+// shell reads commands from standard input, one per line, until end of input or "quit".
+func shell(c *client) {
+	fmt.Println(`Card game shell. Type "help" for the list of commands.`)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print("> ")
+		if !scanner.Scan() {
+			fmt.Println()
+			return
+		}
+
+		args := strings.Fields(scanner.Text())
+		if len(args) == 0 {
+			continue
+		}
+		if args[0] == "quit" || args[0] == "exit" {
+			return
+		}
+
+		if err := execute(c, args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+}
+
+// execute runs one command with its arguments.
+func execute(c *client, args []string) error {
+	if args[0] == "help" {
+		usage()
+		return nil
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	err := cmd.run(context.Background(), c, args[1:])
+	if errors.Is(err, errUsage) {
+		return fmt.Errorf("usage: %s", cmd.usage)
+	}
+	return err
+}
+
+// usage prints the flags and the list of commands.
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: cardgame [flags] [command [args]]")
+	fmt.Fprintln(os.Stderr, "\nflags:")
+	flag.PrintDefaults()
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-40s %s\n", commands[name].usage, commands[name].help)
+	}
+}
+
+// envOr returns the environment variable's value, or def if it is not set.
+func envOr(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
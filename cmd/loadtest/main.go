@@ -0,0 +1,274 @@
+// Command loadtest drives the card game HTTP API with concurrent simulated tables and reports
+// throughput and latency percentiles for each kind of request.
+//
+// Each worker repeatedly sets up a table: it logs in an owner, creates a game, adds decks, seats players,
+// shuffles, deals every player a few cards, and reads the hand values. For example:
+//
+//	loadtest -server http://localhost:8080 -workers 20 -duration 1m -decks 6
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"time"
+)
+
+// options holds the load test's settings.
+type options struct {
+	server   string
+	workers  int
+	players  int
+	decks    int
+	cards    int
+	duration time.Duration
+}
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.server, "server", "http://localhost:8080", "base URL of the card game server")
+	flag.IntVar(&opts.workers, "workers", 10, "number of tables played concurrently")
+	flag.IntVar(&opts.players, "players", 4, "players seated at each table")
+	flag.IntVar(&opts.decks, "decks", 1, "decks added to each game")
+	flag.IntVar(&opts.cards, "cards", 5, "cards dealt to each player per table")
+	flag.DurationVar(&opts.duration, "duration", 30*time.Second, "how long to generate load")
+	flag.Parse()
+
+	if opts.workers < 1 || opts.players < 1 || opts.decks < 1 || opts.cards < 0 {
+		log.Fatal("workers, players, and decks must be at least 1 and cards cannot be negative")
+	}
+
+	// Stop at the end of the run, or early on Ctrl-C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, opts.duration)
+	defer cancel()
+
+	stats := newRecorder()
+	client := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{MaxIdleConnsPerHost: opts.workers},
+	}
+
+	log.Printf("Running %d workers against %s for %s", opts.workers, opts.server, opts.duration)
+	started := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < opts.workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for table := 0; ctx.Err() == nil; table++ {
+				t := &tableRun{opts: opts, client: client, stats: stats, name: fmt.Sprintf("load-%d-%d-%d", started.Unix(), worker, table)}
+				if err := t.play(ctx); err != nil && ctx.Err() == nil {
+					// Back off briefly so a failing server is not hammered in a tight loop
+					log.Printf("worker %d: %v", worker, err)
+					time.Sleep(100 * time.Millisecond)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	stats.report(os.Stdout, time.Since(started))
+}
+
+// tableRun plays one simulated table from start to finish.
+type tableRun struct {
+	opts   options
+	client *http.Client
+	stats  *recorder
+	name   string
+	token  string
+}
+
+// play runs the table's requests in order, stopping at the first failure.
+func (t *tableRun) play(ctx context.Context) error {
+	// Register the table's owner, whose name is new to every run
+	var session struct {
+		Token string `json:"token"`
+	}
+	owner := map[string]string{"player_name": t.name + "-owner", "password": t.name + "-password"}
+	if err := t.call(ctx, "register", "POST", "/players", owner, &session); err != nil {
+		return err
+	}
+	t.token = session.Token
+
+	// Create the game and fill its shoe
+	var game struct {
+		ID string `json:"id"`
+	}
+	if err := t.call(ctx, "create_game", "POST", "/games", map[string]string{"name": t.name}, &game); err != nil {
+		return err
+	}
+	for i := 0; i < t.opts.decks; i++ {
+		if err := t.call(ctx, "add_deck", "POST", "/games/"+game.ID+"/add-deck", nil, nil); err != nil {
+			return err
+		}
+	}
+
+	// Seat the players, each joining anonymously to claim their name, and keep the owner's session rather than the
+	// ones issued to the players
+	players := make([]string, t.opts.players)
+	ownerToken := t.token
+	t.token = ""
+	for i := range players {
+		players[i] = fmt.Sprintf("%s-p%d", t.name, i)
+		if err := t.call(ctx, "add_player", "POST", "/games/"+game.ID+"/add-player", map[string]string{"player_name": players[i]}, nil); err != nil {
+			t.token = ownerToken
+			return err
+		}
+	}
+	t.token = ownerToken
+
+	if err := t.call(ctx, "shuffle", "POST", "/games/"+game.ID+"/shuffle", nil, nil); err != nil {
+		return err
+	}
+
+	// Deal round by round, as a dealer would
+	for round := 0; round < t.opts.cards; round++ {
+		for _, player := range players {
+			if err := t.call(ctx, "deal", "POST", "/games/"+game.ID+"/deal-card", map[string]string{"player_name": player}, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Read the table's state back
+	if err := t.call(ctx, "hand_values", "GET", "/games/"+game.ID+"/player-hand-values", nil, nil); err != nil {
+		return err
+	}
+	return t.call(ctx, "remaining_cards", "GET", "/games/"+game.ID+"/remaining-cards-sorted", nil, nil)
+}
+
+// call sends one request, records its latency and outcome under the operation name, and decodes the response into out if it is not nil.
+func (t *tableRun) call(ctx context.Context, op, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, t.opts.server+"/api/v1"+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if t.token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.token)
+	}
+
+	start := time.Now()
+	resp, err := t.client.Do(req)
+	if err != nil {
+		// Requests cut off by the end of the run are not failures
+		if ctx.Err() == nil {
+			t.stats.record(op, time.Since(start), false)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	// Read the whole body so the latency covers the full response and the connection can be reused
+	data, err := io.ReadAll(resp.Body)
+	elapsed := time.Since(start)
+	if err == nil && resp.StatusCode >= http.StatusBadRequest {
+		err = fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(data))
+	}
+	t.stats.record(op, elapsed, err == nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	return nil
+}
+
+// recorder collects the latency of every request, grouped by operation.
+type recorder struct {
+	mu        sync.Mutex
+	latencies map[string][]time.Duration
+	failures  map[string]int
+}
+
+// newRecorder creates an empty recorder.
+func newRecorder() *recorder {
+	return &recorder{latencies: map[string][]time.Duration{}, failures: map[string]int{}}
+}
+
+// record adds one request's outcome. Only successful requests count towards the latency percentiles.
+func (r *recorder) record(op string, latency time.Duration, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ok {
+		r.latencies[op] = append(r.latencies[op], latency)
+	} else {
+		r.failures[op]++
+	}
+}
+
+// report writes the throughput and latency percentiles of each operation, and of all requests together.
+func (r *recorder) report(w io.Writer, elapsed time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ops := []string{}
+	for op := range r.latencies {
+		ops = append(ops, op)
+	}
+	for op := range r.failures {
+		if _, ok := r.latencies[op]; !ok {
+			ops = append(ops, op)
+		}
+	}
+	sort.Strings(ops)
+
+	fmt.Fprintf(w, "%-16s %8s %7s %9s %9s %9s %9s %9s\n", "operation", "ok", "failed", "req/s", "p50", "p90", "p99", "max")
+	all := []time.Duration{}
+	failed := 0
+	for _, op := range ops {
+		writeRow(w, op, r.latencies[op], r.failures[op], elapsed)
+		all = append(all, r.latencies[op]...)
+		failed += r.failures[op]
+	}
+	writeRow(w, "total", all, failed, elapsed)
+}
+
+// writeRow writes one line of the report.
+func writeRow(w io.Writer, op string, latencies []time.Duration, failed int, elapsed time.Duration) {
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	rate := float64(len(latencies)+failed) / elapsed.Seconds()
+	fmt.Fprintf(w, "%-16s %8d %7d %9.1f %9s %9s %9s %9s\n", op, len(latencies), failed, rate,
+		percentile(latencies, 50), percentile(latencies, 90), percentile(latencies, 99), percentile(latencies, 100))
+}
+
+// percentile returns the latency below which p percent of the sorted latencies fall, rounded for display.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := (len(sorted)*p + 99) / 100
+	if i < 1 {
+		i = 1
+	}
+	if i > len(sorted) {
+		i = len(sorted)
+	}
+	return sorted[i-1].Round(10 * time.Microsecond)
+}
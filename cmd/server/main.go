@@ -1,32 +1,125 @@
 package main
 
 import (
+	"errors"
 	"log"
+	"my-card-game/internal/accesslog"
 	"my-card-game/internal/api"
 	"my-card-game/internal/config"
 	"my-card-game/internal/db"
+	"my-card-game/internal/grpcapi"
+	"net"
 	"net/http"
+	"os"
 
 	"github.com/gorilla/mux"
+	"golang.org/x/crypto/acme/autocert"
 )
 
 func main() {
-	// Load configuration
-	cfg := config.LoadConfig()
+	// Load configuration from the defaults, the config file, and the environment
+	cfg, err := config.Load(configPath())
+	if err != nil {
+		log.Fatalf("could not load configuration: %v", err)
+	}
 
 	// Connect to MongoDB
 	db.ConnectDB(cfg) // Ensure this is called first
 	//defer db.DisconnectDB()
 
+	// Create the indexes the application relies on
+	if err := db.EnsureIndexes(); err != nil {
+		log.Fatalf("could not create database indexes: %v", err)
+	}
+
 	//Initialize the router
 	r := mux.NewRouter()
 
+	// Initialize the service layer shared by the REST and gRPC APIs
+	svc := api.NewServices(cfg)
+
 	// Register routes
-	api.RegisterRoutes(r)
+	api.RegisterRoutes(r, cfg, svc)
+
+	// Serve the gRPC API next to the HTTP one if it is enabled
+	if cfg.GRPCAddr != "" {
+		go serveGRPC(cfg.GRPCAddr, svc)
+	}
 
-	// Start the server
-	log.Println("Starting server on :8080")
-	if err := http.ListenAndServe(":8080", r); err != nil {
+	// Log every request the server handles
+	handler := accesslog.Middleware(accesslog.Options{Level: cfg.AccessLogLevel, SampleRate: cfg.AccessLogSampleRate})(r)
+
+	// Configure the server with timeouts so slow or idle clients cannot hold connections open forever
+	server := &http.Server{
+		Addr:              cfg.ServerAddr,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+
+	// Start the server, over HTTPS when TLS is configured
+	if err := serve(server, cfg); err != nil {
 		log.Fatalf("could not start server: %v", err)
 	}
 }
+
+// serve starts the server over plain HTTP, over HTTPS with the configured certificate files,
+// or over HTTPS with certificates obtained automatically from Let's Encrypt.
+func serve(server *http.Server, cfg *config.Config) error {
+	switch {
+	case len(cfg.AutocertDomains) > 0:
+		// Obtain and renew certificates for the configured domains automatically
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.AutocertDomains...),
+			Cache:      autocert.DirCache(cfg.AutocertCacheDir),
+		}
+		server.TLSConfig = manager.TLSConfig()
+
+		// Answer the ACME HTTP-01 challenges on port 80, redirecting other plain HTTP requests to HTTPS
+		go func() {
+			if err := http.ListenAndServe(":80", manager.HTTPHandler(nil)); err != nil {
+				log.Printf("ACME challenge listener stopped: %v", err)
+			}
+		}()
+
+		log.Printf("Starting HTTPS server on %s for %v", cfg.ServerAddr, cfg.AutocertDomains)
+		return server.ListenAndServeTLS("", "")
+	case cfg.TLSCertFile != "" || cfg.TLSKeyFile != "":
+		if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+			return errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+		}
+		log.Printf("Starting HTTPS server on %s", cfg.ServerAddr)
+		return server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+	default:
+		log.Printf("Starting server on %s", cfg.ServerAddr)
+		return server.ListenAndServe()
+	}
+}
+
+// configPath returns the config file to load: the CONFIG_FILE environment variable if it is set,
+// otherwise config.yaml in the working directory if it exists, otherwise no file at all.
+func configPath() string {
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		return path
+	}
+	if _, err := os.Stat("config.yaml"); err == nil {
+		return "config.yaml"
+	}
+	return ""
+}
+
+// serveGRPC runs the gRPC API on the given address.
+func serveGRPC(addr string, svc *api.Services) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("could not listen for gRPC on %s: %v", addr, err)
+	}
+
+	log.Printf("Starting gRPC server on %s", addr)
+	if err := grpcapi.NewServer(svc.Game, svc.Sessions, svc.APIKeys).Serve(listener); err != nil {
+		log.Fatalf("gRPC server stopped: %v", err)
+	}
+}
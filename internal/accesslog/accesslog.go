@@ -0,0 +1,134 @@
+// Package accesslog logs one line for every HTTP request the server handles.
+package accesslog
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"my-card-game/internal/auth"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Access log levels.
+const (
+	LevelOff    = "off"    // Log nothing
+	LevelErrors = "errors" // Log only requests answered with a 4xx or 5xx status
+	LevelAll    = "all"    // Log every request, subject to sampling
+)
+
+// Options controls which requests are logged.
+type Options struct {
+	Level      string  // One of LevelOff, LevelErrors, or LevelAll
+	SampleRate float64 // Fraction of successful requests logged at LevelAll; error responses are always logged
+}
+
+// entry collects the details of a request as it passes through the router.
+type entry struct {
+	route  string
+	caller string
+}
+
+type contextKey struct{}
+
+// Middleware wraps the whole server so that every request is logged, including those that match no route.
+// It records the method, path, route pattern, status, response size, duration, and caller of each request.
+func Middleware(opts Options) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if opts.Level == LevelOff {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			e := &entry{}
+			rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), contextKey{}, e)))
+
+			if !shouldLog(opts, rec.status) {
+				return
+			}
+			route := e.route
+			if route == "" {
+				route = "-"
+			}
+			caller := e.caller
+			if caller == "" {
+				caller = "-"
+			}
+			log.Printf("access method=%s path=%s route=%s status=%d bytes=%d duration=%s caller=%s",
+				r.Method, r.URL.Path, route, rec.status, rec.bytes, time.Since(start), caller)
+		})
+	}
+}
+
+// Annotate is router middleware that records the matched route pattern and the caller's identity for the access log.
+// It must run after the auth middleware so the caller has been resolved.
+func Annotate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if e, ok := r.Context().Value(contextKey{}).(*entry); ok {
+			if route := mux.CurrentRoute(r); route != nil {
+				e.route, _ = route.GetPathTemplate()
+			}
+			identity := auth.FromRequest(r)
+			switch {
+			case identity.PlayerName != "":
+				e.caller = identity.PlayerName
+			case identity.Admin:
+				e.caller = "admin"
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// shouldLog decides whether a finished request is logged under the options.
+func shouldLog(opts Options, status int) bool {
+	if status >= 400 {
+		return true
+	}
+	if opts.Level != LevelAll {
+		return false
+	}
+	return opts.SampleRate >= 1 || rand.Float64() < opts.SampleRate
+}
+
+// recorder captures the status code and size of a response.
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+// WriteHeader records the status code before passing it on.
+func (rec *recorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status = status
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+// Write counts the bytes written to the response.
+func (rec *recorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+	return n, err
+}
+
+// Unwrap returns the underlying response writer so http.ResponseController can reach it.
+func (rec *recorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// Flush passes flushes through so streaming responses keep working.
+func (rec *recorder) Flush() {
+	if flusher, ok := rec.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
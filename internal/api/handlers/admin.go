@@ -0,0 +1,433 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/render"
+	"my-card-game/internal/validate"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// CreateAPIKeyHandler handles the HTTP request to create a new administrative API key.
+// It decodes the key's name from the request payload and returns the key as a JSON response.
+// The raw key is only ever shown in this response.
+func CreateAPIKeyHandler(keyService *services.APIKeyService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			Name string `json:"name" validate:"required,name,max=100"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Create the key using the API key service
+		key, apiKey, err := keyService.CreateAPIKey(req.Name, viewerFromRequest(r))
+		if err != nil {
+			// Return a 500 Internal Server Error status if creating the key fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		// Encode the new key as JSON and write it to the response
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"key":     key,
+			"api_key": apiKey,
+		})
+	}
+}
+
+// ListAPIKeysHandler handles the HTTP request to list the administrative API keys.
+// Only key metadata is returned as a JSON response, never the keys themselves.
+func ListAPIKeysHandler(keyService *services.APIKeyService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Retrieve the keys using the API key service
+		keys, err := keyService.ListAPIKeys()
+		if err != nil {
+			// Return a 500 Internal Server Error status if listing the keys fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the keys as JSON and write them to the response
+		json.NewEncoder(w).Encode(keys)
+	}
+}
+
+// DeleteAPIKeyHandler handles the HTTP request to revoke an administrative API key.
+// It returns a 204 No Content status once the key has been revoked.
+func DeleteAPIKeyHandler(keyService *services.APIKeyService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the key ID from the URL path variables
+		vars := mux.Vars(r)
+		keyID := vars["key_id"]
+
+		// Revoke the key using the API key service
+		if err := keyService.DeleteAPIKey(keyID, viewerFromRequest(r)); err != nil {
+			// Return a 404 Not Found status if the key does not exist
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Return a 204 No Content status to indicate successful revocation
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// AdminListGamesHandler handles the HTTP request to list every game, including private games and games the caller does not own.
+// The status, mode, and owner query parameters filter the list, limit and offset page through it, and fields limits
+// each game to the fields named.
+// The games and the total number of matches are returned as a JSON response.
+func AdminListGamesHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Build the filter from the query parameters
+		query := r.URL.Query()
+		filter := services.AdminGameFilter{
+			Status: query.Get("status"),
+			Mode:   query.Get("mode"),
+			Owner:  query.Get("owner"),
+		}
+		for name, target := range map[string]*int64{"limit": &filter.Limit, "offset": &filter.Offset} {
+			if value := query.Get(name); value != "" {
+				n, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					// Return a 400 Bad Request status if the paging parameters are not numbers
+					http.Error(w, name+" must be a number", http.StatusBadRequest)
+					return
+				}
+				*target = n
+			}
+		}
+
+		// Load only the fields the client selected, if it selected any
+		fields := render.Fields(r)
+		projection, err := models.GameProjection(fields)
+		if err != nil {
+			// Return a 400 Bad Request status if a selected field does not exist
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		filter.Fields = projection
+
+		// Retrieve the games using the game service
+		games, total, err := gameService.ListAllGames(filter)
+		if err != nil {
+			// Return a 500 Internal Server Error status if listing the games fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the games as JSON and write them to the response
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"games": render.Select(games, fields),
+			"total": total,
+		})
+	}
+}
+
+// AuditLogHandler handles the HTTP request to list the audit log of administrative and destructive actions, newest first.
+// The log can be filtered by action, actor, game_id, and a since/until time range given in RFC 3339 format,
+// and paged with limit and offset.
+func AuditLogHandler(auditService *services.AuditService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Build the filter from the query parameters
+		query := r.URL.Query()
+		filter := services.AuditFilter{
+			Action: query.Get("action"),
+			Actor:  query.Get("actor"),
+			GameID: query.Get("game_id"),
+		}
+		for name, target := range map[string]*int64{"limit": &filter.Limit, "offset": &filter.Offset} {
+			if value := query.Get(name); value != "" {
+				n, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					// Return a 400 Bad Request status if the paging parameters are not numbers
+					http.Error(w, name+" must be a number", http.StatusBadRequest)
+					return
+				}
+				*target = n
+			}
+		}
+		for name, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+			if value := query.Get(name); value != "" {
+				t, err := time.Parse(time.RFC3339, value)
+				if err != nil {
+					// Return a 400 Bad Request status if the time range is not in RFC 3339 format
+					http.Error(w, name+" must be an RFC 3339 time", http.StatusBadRequest)
+					return
+				}
+				*target = t
+			}
+		}
+
+		// Retrieve the audit entries using the audit service
+		entries, total, err := auditService.ListAuditLog(filter)
+		if err != nil {
+			// Return a 400 Bad Request status if the game ID filter is malformed
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the audit entries as JSON and write them to the response
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"entries": entries,
+			"total":   total,
+		})
+	}
+}
+
+// AdminForceEndGameHandler handles the HTTP request to end a game immediately and archive it.
+// An optional reason is read from the request payload and recorded with the game's events.
+func AdminForceEndGameHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the optional request payload
+		var req struct {
+			Reason string `json:"reason" validate:"max=500"`
+		}
+
+		// Decode the JSON request body into the req struct, allowing it to be empty
+		if err := decodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// End the game using the game service
+		game, err := gameService.ForceEndGame(gameID, req.Reason, viewerFromRequest(r))
+		if err != nil {
+			// Return a 400 Bad Request status if the game cannot be ended
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the ended game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
+
+// AdminDeleteGameHandler handles the HTTP request to permanently delete a game along with its events,
+// snapshots, and archived copy. It returns a 204 No Content status once everything has been removed.
+func AdminDeleteGameHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Delete the game using the game service
+		if err := gameService.ForceDeleteGame(gameID, viewerFromRequest(r)); err != nil {
+			// Return a 404 Not Found status if the game does not exist
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Return a 204 No Content status to indicate successful deletion
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// AdminRawGameHandler handles the HTTP request to inspect a game's stored document.
+// The document is returned unredacted as relaxed MongoDB Extended JSON so field types are preserved.
+func AdminRawGameHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Load the raw document using the game service
+		raw, err := gameService.GetRawGame(gameID)
+		if err != nil {
+			// Return a 404 Not Found status if the game does not exist
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Convert the document to Extended JSON
+		data, err := bson.MarshalExtJSON(raw, false, false)
+		if err != nil {
+			// Return a 500 Internal Server Error status if the document cannot be converted
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Write the document to the response
+		w.Write(data)
+	}
+}
+
+// AdminStatsHandler handles the HTTP request to view aggregate counts across the deployment,
+// such as the number of games in each status and mode. The counts are returned as JSON, CSV, or MessagePack
+// depending on the Accept header.
+func AdminStatsHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Gather the counts using the game service
+		stats, err := gameService.GetGameStats()
+		if err != nil {
+			// Return a 500 Internal Server Error status if counting fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Encode the counts in the format the client accepts
+		render.Write(w, r, stats)
+	}
+}
+
+// AdminShuffleLogHandler handles the HTTP request to view a game's shuffle log: the entropy source each shuffle
+// drew from, and why the configured source was passed over when it failed. The log is returned as a JSON response.
+func AdminShuffleLogHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Retrieve the shuffle log using the game service
+		records, err := gameService.ListShuffles(gameID)
+		if err != nil {
+			// Return a 500 Internal Server Error status if retrieving the log fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the shuffle log as JSON and write it to the response
+		json.NewEncoder(w).Encode(records)
+	}
+}
+
+// AdminVerifyReplayHandler handles the HTTP request to verify a game by replaying it: the game is re-simulated
+// server-side from its recorded shuffle seeds and event log, and the report says whether the replay reached the
+// recorded state, and where it parted from it if not. The report is returned as a JSON response.
+func AdminVerifyReplayHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Replay the game using the game service
+		report, err := gameService.VerifyReplay(gameID)
+		if err != nil {
+			// Return a 404 Not Found status if the game does not exist
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the replay report as JSON and write it to the response
+		json.NewEncoder(w).Encode(report)
+	}
+}
+
+// CreateOrgHandler handles the HTTP request to create an organization, a community or product hosted on the
+// deployment whose data is kept apart from every other's. It decodes the organization's ID and name from the
+// request payload and returns the organization as a JSON response.
+func CreateOrgHandler(orgService *services.OrgService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			ID   string `json:"id" validate:"required,max=32"`
+			Name string `json:"name" validate:"required,name,max=100"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Create the organization using the organization service
+		org, err := orgService.CreateOrg(req.ID, req.Name, viewerFromRequest(r))
+		if err != nil {
+			// Return a 400 Bad Request status if the organization cannot be created
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		// Encode the new organization as JSON and write it to the response
+		json.NewEncoder(w).Encode(org)
+	}
+}
+
+// ListOrgsHandler handles the HTTP request to list the organizations hosted on the deployment.
+// The organizations are returned as a JSON response.
+func ListOrgsHandler(orgService *services.OrgService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Retrieve the organizations using the organization service
+		orgs, err := orgService.ListOrgs()
+		if err != nil {
+			// Return a 500 Internal Server Error status if listing the organizations fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the organizations as JSON and write them to the response
+		json.NewEncoder(w).Encode(orgs)
+	}
+}
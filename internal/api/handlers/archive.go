@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"encoding/json"
+	"my-card-game/internal/api/services"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// GetArchivedGameHandler handles the HTTP request to retrieve a finished game from the archive.
+// The archived game and its full event history are returned as a JSON response.
+func GetArchivedGameHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Retrieve the archived game using the game service
+		archived, err := gameService.GetArchivedGame(gameID)
+		if err != nil {
+			// Return a 404 Not Found status if the game is not in the archive
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		if archived.Game != nil {
+			archived.Game.RedactFor(viewerFromRequest(r))
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the archived game as JSON and write it to the response
+		json.NewEncoder(w).Encode(archived)
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/validate"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// OpenAuctionHandler handles the HTTP request for the game's owner to open the bidding for the current hand.
+// The new auction is returned as a JSON response.
+func OpenAuctionHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Open the auction using the game service
+		auction, err := gameService.OpenAuction(gameID, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller does not own the game
+			http.Error(w, "only the game owner can open an auction", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			// Return a 409 Conflict status if the game is not ready for bidding
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		// Encode the auction as JSON and write it to the response
+		json.NewEncoder(w).Encode(auction)
+	}
+}
+
+// GetAuctionHandler handles the HTTP request to see the state of a game's auction: the calls made so far,
+// whose call it is, and the contract once the auction has closed. The auction is returned as a JSON response.
+func GetAuctionHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Retrieve the auction using the game service
+		auction, err := gameService.GetAuction(gameID)
+		if err != nil {
+			// Return a 404 Not Found status if the game does not exist or has no auction
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the auction as JSON and write it to the response
+		json.NewEncoder(w).Encode(auction)
+	}
+}
+
+// PlaceBidHandler handles the HTTP request for a player to make their call in the game's auction,
+// either passing or bidding a level and strain. The updated auction is returned as a JSON response.
+func PlaceBidHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName string `json:"player_name" validate:"player,max=32"`
+			Pass       bool   `json:"pass"`
+			Level      int    `json:"level"`
+			Strain     string `json:"strain" validate:"max=16"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Take the action as the caller's player
+		player, err := actingPlayer(r, req.PlayerName)
+		if err != nil {
+			writeActingPlayerError(w, err)
+			return
+		}
+
+		// Place the call using the game service
+		bid := models.Bid{Pass: req.Pass, Level: req.Level, Strain: req.Strain}
+		auction, err := gameService.PlaceBid(gameID, player, bid)
+		if err != nil {
+			// Return a 422 Unprocessable Entity status if the call breaks the rules, or a 409 Conflict status otherwise
+			writeActionError(w, err, http.StatusConflict)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the updated auction as JSON and write it to the response
+		json.NewEncoder(w).Encode(auction)
+	}
+}
@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"encoding/json"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/validate"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// SetPlayerChipsHandler handles the HTTP request to set a player's chip stack. Stacks can be cashed out to the
+// player's wallet, so only admins may set them; players bring chips to the table by buying in.
+// It decodes the player's name and chip amount from the request payload and returns the updated game as a JSON response.
+func SetPlayerChipsHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName string `json:"player_name" validate:"required,player,max=32"`
+			Amount     int    `json:"amount"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Set the player's chip stack using the game service
+		game, err := gameService.SetPlayerChips(gameID, req.PlayerName, req.Amount)
+		if err != nil {
+			// Return a 500 Internal Server Error status if setting the chips fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the updated game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
+
+// PlaceBetHandler handles the HTTP request for a player to bet chips in the current hand.
+// Bets larger than the player's stack put the player all-in. The updated game is returned as a JSON response.
+func PlaceBetHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName string `json:"player_name" validate:"player,max=32"`
+			Amount     int    `json:"amount"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Take the action as the caller's player
+		player, err := actingPlayer(r, req.PlayerName)
+		if err != nil {
+			writeActingPlayerError(w, err)
+			return
+		}
+
+		// Place the bet using the game service
+		game, err := gameService.PlaceBet(gameID, player, req.Amount)
+		if err != nil {
+			// Return a 422 Unprocessable Entity status if the bet breaks the rules, or a 500 Internal Server Error status if placing it fails
+			writeActionError(w, err, http.StatusInternalServerError)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the updated game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
+
+// FoldHandler handles the HTTP request for a player to fold the current hand.
+// The updated game is returned as a JSON response.
+func FoldHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName string `json:"player_name" validate:"player,max=32"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Take the action as the caller's player
+		player, err := actingPlayer(r, req.PlayerName)
+		if err != nil {
+			writeActingPlayerError(w, err)
+			return
+		}
+
+		// Fold the player's hand using the game service
+		game, err := gameService.Fold(gameID, player)
+		if err != nil {
+			// Return a 422 Unprocessable Entity status if the player cannot fold, or a 500 Internal Server Error status if folding fails
+			writeActionError(w, err, http.StatusInternalServerError)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the updated game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
+
+// GetPotsHandler handles the HTTP request to get the main pot and side pots of the current hand.
+// The pots are returned as a JSON response, main pot first.
+func GetPotsHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Calculate the pots using the game service
+		pots, err := gameService.GetPots(gameID)
+		if err != nil {
+			// Return a 500 Internal Server Error status if calculating the pots fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the pots as JSON and write it to the response
+		json.NewEncoder(w).Encode(pots)
+	}
+}
+
+// ShowdownHandler handles the HTTP request to resolve the current hand at showdown.
+// Each pot is awarded to its eligible winners and the per-pot results are returned as a JSON response.
+func ShowdownHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Resolve the showdown using the game service
+		results, err := gameService.Showdown(gameID)
+		if err != nil {
+			// Return a 500 Internal Server Error status if the showdown fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the pot results as JSON and write it to the response
+		json.NewEncoder(w).Encode(results)
+	}
+}
+
+// ConfigureBlindsHandler handles the HTTP request to configure the blind amounts of a game.
+// It decodes the base small and big blinds plus an optional escalation schedule from the request payload
+// and returns the updated game as a JSON response.
+func ConfigureBlindsHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			SmallBlind int                 `json:"small_blind"`
+			BigBlind   int                 `json:"big_blind"`
+			Schedule   []models.BlindLevel `json:"schedule"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Configure the blinds using the game service
+		game, err := gameService.ConfigureBlinds(gameID, req.SmallBlind, req.BigBlind, req.Schedule)
+		if err != nil {
+			// Return a 500 Internal Server Error status if configuring the blinds fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the updated game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
+
+// StartHandHandler handles the HTTP request to start a new betting hand.
+// The dealer button is rotated and the blinds are posted automatically. The updated game is returned as a JSON response.
+func StartHandHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Start the hand using the game service
+		game, err := gameService.StartHand(gameID)
+		if err != nil {
+			// Return a 500 Internal Server Error status if starting the hand fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the updated game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
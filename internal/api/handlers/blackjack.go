@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/validate"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// HitHandler handles the HTTP request for a blackjack player to take another card on their current hand.
+func HitHandler(gameService *services.GameService) http.HandlerFunc {
+	return blackjackMoveHandler(gameService.Hit)
+}
+
+// StandHandler handles the HTTP request for a blackjack player to stand on their current hand.
+func StandHandler(gameService *services.GameService) http.HandlerFunc {
+	return blackjackMoveHandler(gameService.Stand)
+}
+
+// DoubleDownHandler handles the HTTP request for a blackjack player to double their bet for one more card.
+func DoubleDownHandler(gameService *services.GameService) http.HandlerFunc {
+	return blackjackMoveHandler(gameService.DoubleDown)
+}
+
+// SplitHandler handles the HTTP request for a blackjack player to split a pair into two hands.
+func SplitHandler(gameService *services.GameService) http.HandlerFunc {
+	return blackjackMoveHandler(gameService.Split)
+}
+
+// SurrenderHandler handles the HTTP request for a blackjack player to give up their hand for half their bet.
+func SurrenderHandler(gameService *services.GameService) http.HandlerFunc {
+	return blackjackMoveHandler(gameService.Surrender)
+}
+
+// InsuranceHandler handles the HTTP request for a blackjack player to take insurance while the house shows an ace.
+// It decodes the player's name and the amount to stake from the request payload, and returns the updated game as
+// a JSON response.
+func InsuranceHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName string `json:"player_name" validate:"player,max=32"`
+			Amount     int    `json:"amount"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Take the action as the caller's player
+		player, err := actingPlayer(r, req.PlayerName)
+		if err != nil {
+			writeActingPlayerError(w, err)
+			return
+		}
+
+		// Stake the insurance using the game service
+		game, err := gameService.TakeInsurance(gameID, player, req.Amount)
+		if err != nil {
+			// Return a 422 Unprocessable Entity status listing the broken rules if the insurance is not allowed, or 400 for other errors
+			writeActionError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the updated game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
+
+// blackjackMoveHandler builds the handler for one blackjack move. It decodes the player's name from the request
+// payload, makes the move using the given service method, and returns the updated game as a JSON response.
+func blackjackMoveHandler(move func(gameID, playerName string) (*models.Game, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName string `json:"player_name" validate:"player,max=32"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Take the action as the caller's player
+		player, err := actingPlayer(r, req.PlayerName)
+		if err != nil {
+			writeActingPlayerError(w, err)
+			return
+		}
+
+		// Make the move using the game service
+		game, err := move(gameID, player)
+		if err != nil {
+			// Return a 422 Unprocessable Entity status listing the broken rules if the move is not allowed, or 400 for other errors
+			writeActionError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the updated game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
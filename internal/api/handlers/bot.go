@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/validate"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// AddBotHandler handles the HTTP request to seat a bot in a game.
+// The payload names the bot and may set its difficulty, aggression, and risk; anything left out plays as
+// models.DefaultBotConfig. Only the game's owner or an admin can add bots, and a bot cannot take a name a player
+// has claimed. The updated game is returned as a JSON response.
+func AddBotHandler(gameService *services.GameService, sessionService *services.SessionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			Name string `json:"name" validate:"required,player,max=32"`
+			models.BotPatch
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// A bot cannot take a name a player has claimed, so it is never mistaken for them
+		claimed, err := sessionService.IsClaimed(req.Name)
+		if err != nil {
+			// Return a 500 Internal Server Error status if the accounts cannot be checked
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if claimed {
+			// Return a 409 Conflict status if the name belongs to a player's account
+			http.Error(w, "the name belongs to a player's account", http.StatusConflict)
+			return
+		}
+
+		// Seat the bot, playing as the defaults unless the payload says otherwise
+		cfg := models.DefaultBotConfig
+		req.BotPatch.Apply(&cfg)
+		game, err := gameService.AddBot(gameID, req.Name, cfg, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller does not own the game
+			http.Error(w, "only the game owner can add bots", http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, services.ErrGameFull) || errors.Is(err, services.ErrAlreadySeated) {
+			// Return a 409 Conflict status if the game has no free seats or the name is taken
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			// Return a 400 Bad Request or 409 Conflict status if the bot cannot be seated
+			http.Error(w, err.Error(), seatErrorStatus(err, http.StatusBadRequest))
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the updated game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
+
+// UpdateBotHandler handles the HTTP request to change how a seated bot plays, at any point in the game.
+// Only the fields present in the payload are changed. Only the game's owner or an admin can change bots. The
+// bot's new configuration is returned as a JSON response.
+func UpdateBotHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID and bot name from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+		botName := vars["name"]
+
+		// Decode the JSON request body into the bot patch
+		var patch models.BotPatch
+		if err := decodeJSON(r, &patch); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Update the bot using the game service
+		cfg, err := gameService.UpdateBot(gameID, botName, patch, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller does not own the game
+			http.Error(w, "only the game owner can change bots", http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, services.ErrNotBot) {
+			// Return a 404 Not Found status if the game has no bot by that name
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			// Return a 400 Bad Request status if the update is not valid
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the bot's configuration as JSON and write it to the response
+		json.NewEncoder(w).Encode(cfg)
+	}
+}
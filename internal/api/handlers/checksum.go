@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"encoding/json"
+	"my-card-game/internal/api/services"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// GetChecksumHandler handles the HTTP request for a game's state checksum.
+// The response holds the checksum stored after the game's last change, the checksum of its state as it is now, and
+// whether they match, so a client can tell whether its copy has drifted and an audit can spot tampering.
+func GetChecksumHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Compare the stored checksum against the game's current state using the game service
+		report, err := gameService.GetChecksum(gameID)
+		if err != nil {
+			// Return a 404 Not Found status if the game does not exist
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the checksum report as JSON and write it to the response
+		json.NewEncoder(w).Encode(report)
+	}
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/validate"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// PlayCardHandler handles the HTTP request for a player to play a card onto the discard pile.
+// It decodes the player's name, the card, and the declared suit (for eights) from the request payload,
+// validates the play using the GameService, and returns the updated game as a JSON response.
+func PlayCardHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName   string      `json:"player_name" validate:"player,max=32"`
+			Card         models.Card `json:"card"`
+			DeclaredSuit string      `json:"declared_suit"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Take the action as the caller's player
+		player, err := actingPlayer(r, req.PlayerName)
+		if err != nil {
+			writeActingPlayerError(w, err)
+			return
+		}
+
+		// Play the card using the game service
+		game, err := gameService.PlayCard(gameID, player, req.Card, req.DeclaredSuit)
+		if err != nil {
+			// Return a 422 Unprocessable Entity status listing the broken rules if the play is not allowed, or 400 for other errors
+			writeActionError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the updated game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
+
+// DrawCardHandler handles the HTTP request for a player who cannot play to draw a card from the deck.
+// The drawn card is returned as a JSON response.
+func DrawCardHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName string `json:"player_name" validate:"player,max=32"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Take the action as the caller's player
+		player, err := actingPlayer(r, req.PlayerName)
+		if err != nil {
+			writeActingPlayerError(w, err)
+			return
+		}
+
+		// Draw the card using the game service
+		card, err := gameService.DrawCard(gameID, player)
+		if err != nil {
+			// Return a 422 Unprocessable Entity status listing the broken rules if the player is not allowed to draw, or 400 for other errors
+			writeActionError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the drawn card as JSON and write it to the response
+		json.NewEncoder(w).Encode(card)
+	}
+}
@@ -2,8 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"my-card-game/internal/api/services"
 	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
 )
 
 // CreateDeckHandler handles the HTTP request to create a new deck of cards.
@@ -20,3 +24,45 @@ func CreateDeckHandler(deckService *services.DeckService) http.HandlerFunc {
 		json.NewEncoder(w).Encode(deck)
 	}
 }
+
+// PeekDeckHandler handles the HTTP request for the game's dealer or an admin to look at the next cards of the deck
+// without drawing them. The count query parameter chooses how many cards to reveal and defaults to one.
+// The cards are returned in draw order as a JSON response.
+func PeekDeckHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Read how many cards to reveal
+		count := 1
+		if value := r.URL.Query().Get("count"); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				// Return a 400 Bad Request status if the count is not a number
+				http.Error(w, "count must be a number", http.StatusBadRequest)
+				return
+			}
+			count = n
+		}
+
+		// Peek at the deck using the game service
+		cards, err := gameService.PeekDeck(gameID, count, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller is neither the dealer nor an admin
+			http.Error(w, "only the dealer can peek at the deck", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			// Return a 400 Bad Request status if the count is out of range or the game cannot be found
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the cards as JSON and write them to the response
+		json.NewEncoder(w).Encode(cards)
+	}
+}
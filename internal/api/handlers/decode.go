@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// decodeJSON decodes the request body into v strictly: fields v does not have and anything after the
+// JSON value are rejected, so a typo'd field name is reported instead of being silently ignored.
+// An empty body is reported as io.EOF.
+func decodeJSON(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+
+	// The body must hold exactly one JSON value
+	if err := decoder.Decode(&json.RawMessage{}); !errors.Is(err, io.EOF) {
+		return errors.New("unexpected data after the JSON body")
+	}
+	return nil
+}
+
+// writeDecodeError writes the response for a body decodeJSON rejected:
+// 413 Request Entity Too Large when it exceeded the size limit, and 400 Bad Request otherwise.
+func writeDecodeError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+}
+
+// LimitRequestBody is middleware that caps the size of request bodies at limit bytes.
+// Routes can be given their own limit by their path template, such as "/games/import".
+// Reading past the limit fails, which the handlers report as 413 Request Entity Too Large.
+func LimitRequestBody(limit int64, routeLimits map[string]int64) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Use the route's own limit if it has one
+			maxBytes := limit
+			if route := mux.CurrentRoute(r); route != nil {
+				if template, err := route.GetPathTemplate(); err == nil {
+					if routeLimit, ok := routeLimits[template]; ok {
+						maxBytes = routeLimit
+					}
+				}
+			}
+
+			// Reject bodies that declare themselves too large straight away
+			if r.ContentLength > maxBytes {
+				http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
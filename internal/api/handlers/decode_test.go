@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/validate"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// fuzzPayload mixes the kinds of fields and validation rules the handlers' request payloads use.
+type fuzzPayload struct {
+	PlayerName string        `json:"player_name" validate:"player,max=32"`
+	Password   string        `json:"password" validate:"max=72"`
+	Name       *string       `json:"name" validate:"required,name,max=100"`
+	GameID     string        `json:"game_id" validate:"objectid"`
+	Amount     int           `json:"amount"`
+	Cards      []models.Card `json:"cards"`
+	Settings   struct {
+		Reason string `json:"reason" validate:"max=200"`
+	} `json:"settings"`
+}
+
+func FuzzDecodeJSON(f *testing.F) {
+	f.Add([]byte(`{"player_name":"alice","name":"Friday game","amount":20}`))
+	f.Add([]byte(`{"name":"x","game_id":"64b7f0c2a1b2c3d4e5f60718","cards":["AS",{"suit":"Hearts","value":"10"},{"code":"JK"}]}`))
+	f.Add([]byte(`{"name":"x","player_name":"bad.name","settings":{"reason":"because"}}`))
+	f.Add([]byte(`{"name":"x"} {"name":"y"}`))
+	f.Add([]byte(`{"nmae":"typo"}`))
+	f.Add([]byte(`{"name":null,"cards":[{"suit":"Joker","value":"2"}]}`))
+	f.Add([]byte(``))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		var req fuzzPayload
+		if err := decodeJSON(httptest.NewRequest("POST", "/", bytes.NewReader(body)), &req); err != nil {
+			return
+		}
+
+		// Validation either passes or reports the fields that failed
+		if err := validate.Struct(req); err != nil {
+			var fieldErrs validate.Errors
+			if !errors.As(err, &fieldErrs) || len(fieldErrs) == 0 {
+				t.Fatalf("validate.Struct returned %v, want field errors", err)
+			}
+		}
+
+		// A decoded payload encodes back to a body that decodes to the same payload
+		encoded, err := json.Marshal(req)
+		if err != nil {
+			t.Fatalf("encoding %+v: %v", req, err)
+		}
+		var again fuzzPayload
+		if err := decodeJSON(httptest.NewRequest("POST", "/", bytes.NewReader(encoded)), &again); err != nil {
+			t.Fatalf("decoding %s: %v", encoded, err)
+		}
+		if !reflect.DeepEqual(again, req) {
+			t.Fatalf("decoded %+v from %s, want %+v", again, encoded, req)
+		}
+	})
+}
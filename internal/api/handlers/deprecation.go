@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+)
+
+// LegacyPaths serves requests for the unversioned paths used before the API was versioned by rewriting them
+// under prefix and passing them to next, which is normally the router itself. Responses announce the deprecation
+// with a Deprecation header, a Warning naming the versioned path, a Link to it, and, when sunset is set
+// (as a YYYY-MM-DD date), a Sunset header giving the date the legacy paths will stop working.
+func LegacyPaths(prefix, sunset string, next http.Handler) http.Handler {
+	// The date is checked when the configuration is loaded, so a parse failure just leaves the header out
+	sunsetHeader := ""
+	if date, err := time.Parse("2006-01-02", sunset); err == nil {
+		sunsetHeader = date.UTC().Format(http.TimeFormat)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := prefix + r.URL.Path
+
+		// Announce the deprecation and where the resource lives now
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Warning", `299 - "Deprecated API path; use `+path+`"`)
+		w.Header().Add("Link", "<"+path+`>; rel="successor-version"`)
+		if sunsetHeader != "" {
+			w.Header().Set("Sunset", sunsetHeader)
+		}
+
+		// Serve the request from the versioned path
+		versioned := r.Clone(r.Context())
+		versioned.URL.Path = path
+		if r.URL.RawPath != "" {
+			versioned.URL.RawPath = prefix + r.URL.RawPath
+		}
+		next.ServeHTTP(w, versioned)
+	})
+}
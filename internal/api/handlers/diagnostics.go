@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"my-card-game/internal/scheduler"
+	"my-card-game/internal/version"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// DiagnosticsHandler handles the HTTP request for runtime diagnostics of the server process.
+// It reports the goroutine count, memory and garbage collector statistics, and the build version as a JSON response.
+func DiagnosticsHandler(started time.Time) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Read the memory statistics, which also describe the garbage collector
+		var mem runtime.MemStats
+		runtime.ReadMemStats(&mem)
+
+		var lastGC string
+		if mem.LastGC > 0 {
+			lastGC = time.Unix(0, int64(mem.LastGC)).UTC().Format(time.RFC3339)
+		}
+
+		diagnostics := map[string]interface{}{
+			"build":      version.Get(),
+			"uptime":     time.Since(started).Round(time.Second).String(),
+			"goroutines": runtime.NumGoroutine(),
+			"cpus":       runtime.NumCPU(),
+			"memory": map[string]uint64{
+				"heap_alloc_bytes":  mem.HeapAlloc,
+				"heap_sys_bytes":    mem.HeapSys,
+				"heap_objects":      mem.HeapObjects,
+				"total_alloc_bytes": mem.TotalAlloc,
+				"sys_bytes":         mem.Sys,
+			},
+			"gc": map[string]interface{}{
+				"count":          mem.NumGC,
+				"pause_total_ns": mem.PauseTotalNs,
+				"last_gc":        lastGC,
+				"cpu_fraction":   mem.GCCPUFraction,
+			},
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the diagnostics as JSON and write it to the response
+		json.NewEncoder(w).Encode(diagnostics)
+	}
+}
+
+// JobsHandler handles the HTTP request to view the background jobs and how their runs have gone on this replica,
+// including failures and runs skipped because another replica held the job's lock. The metrics are returned as a JSON response.
+func JobsHandler(jobs *scheduler.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the job metrics as JSON and write them to the response
+		json.NewEncoder(w).Encode(jobs.Stats())
+	}
+}
+
+// PprofProfileHandler serves a named runtime profile, such as heap, goroutine, or allocs, from net/http/pprof.
+func PprofProfileHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the profile name from the URL path variables
+		vars := mux.Vars(r)
+		pprof.Handler(vars["profile"]).ServeHTTP(w, r)
+	}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"encoding/json"
+	"my-card-game/internal/api/services"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// GetEventsHandler handles the HTTP request to get the event log of a game.
+// The events are returned as a JSON response, oldest first.
+func GetEventsHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Retrieve the events using the game service
+		events, err := gameService.GetEvents(gameID)
+		if err != nil {
+			// Return a 500 Internal Server Error status if retrieving the events fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the events as JSON and write it to the response
+		json.NewEncoder(w).Encode(events)
+	}
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// ExportGameHandler handles the HTTP request to export the full state of a game.
+// The export includes every hand, the deck, and the event history, and is returned as a JSON response.
+func ExportGameHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Export the game using the game service
+		export, err := gameService.ExportGame(gameID)
+		if err != nil {
+			// Return a 404 Not Found status if the game does not exist
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the export document as JSON and write it to the response
+		json.NewEncoder(w).Encode(export)
+	}
+}
+
+// ImportGameHandler handles the HTTP request to recreate a game from an export document.
+// The recreated game is returned as a JSON response with a 201 Created status.
+func ImportGameHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Decode the JSON request body into an export document
+		var export models.GameExport
+		if err := decodeJSON(r, &export); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Recreate the game using the game service
+		game, err := gameService.ImportGame(export)
+		if err != nil {
+			// Return a 422 Unprocessable Entity status listing the problems if the game's cards are malformed,
+			// or a 400 Bad Request status if the document cannot be imported for another reason
+			writeDeckError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		// Encode the imported game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
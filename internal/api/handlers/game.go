@@ -2,7 +2,14 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"my-card-game/internal/api/models"
 	"my-card-game/internal/api/services"
+	"my-card-game/internal/auth"
+	"my-card-game/internal/render"
+	"my-card-game/internal/validate"
 	"net/http"
 
 	"github.com/gorilla/mux"
@@ -15,24 +22,37 @@ func CreateGameHandler(gameService *services.GameService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Define a struct to capture the incoming request payload
 		var req struct {
-			Name string `json:"name"`
+			Name string `json:"name" validate:"required,name,max=100"`
+			services.GameOptions
 		}
 
 		// Decode the JSON request body into the req struct
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			// Return a 400 Bad Request status if the payload is invalid
-			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
 			return
 		}
 
 		// Create a new game using the game service
-		game, err := gameService.CreateGame(req.Name)
+		game, err := gameService.CreateGame(req.Name, auth.FromRequest(r).PlayerName, req.GameOptions)
 		if err != nil {
 			// Return a 500 Internal Server Error status if game creation fails
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
 		// Set the response header to indicate JSON content
 		w.Header().Set("Content-Type", "application/json")
 
@@ -41,6 +61,130 @@ func CreateGameHandler(gameService *services.GameService) http.HandlerFunc {
 	}
 }
 
+// CreateGamesHandler handles the HTTP request to create several games at once.
+// It decodes an array of game specs, each shaped like a single game creation request, creates them with the GameService,
+// and returns a JSON array with one result per spec holding either the created game or the reason it was not created.
+func CreateGamesHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Decode the JSON request body into the list of game specs
+		var specs []services.GameSpec
+		if err := decodeJSON(r, &specs); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check every spec's fields, reporting them by their position in the batch
+		var fieldErrs validate.Errors
+		for i, spec := range specs {
+			var errs validate.Errors
+			if errors.As(validate.Struct(spec), &errs) {
+				for _, fieldErr := range errs {
+					fieldErr.Field = fmt.Sprintf("[%d].%s", i, fieldErr.Field)
+					fieldErrs = append(fieldErrs, fieldErr)
+				}
+			}
+		}
+		if len(fieldErrs) > 0 {
+			writeValidationError(w, fieldErrs)
+			return
+		}
+
+		// Create the games using the game service, all owned by the caller
+		results, err := gameService.CreateGames(specs, auth.FromRequest(r).PlayerName)
+		if err != nil {
+			// Return a 400 Bad Request status if the batch cannot be created
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		viewer := viewerFromRequest(r)
+		for _, result := range results {
+			if result.Game != nil {
+				result.Game.RedactFor(viewer)
+			}
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the per-game results as JSON and write them to the response
+		json.NewEncoder(w).Encode(results)
+	}
+}
+
+// CloneGameHandler handles the HTTP request to create a new game with the same configuration and players as an existing one.
+// The new game is returned as a JSON response with a 201 Created status.
+func CloneGameHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Clone the game using the game service, making the caller the new owner
+		game, err := gameService.CloneGame(gameID, auth.FromRequest(r).PlayerName)
+		if err != nil {
+			// Return a 404 Not Found status if the game to clone does not exist
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		// Encode the new game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
+
+// GetGameHandler handles the HTTP request to view a single game.
+// Hands the caller may not see are hidden, and the game is returned as a JSON response
+// along with links to the actions available in its current state. The response carries an ETag,
+// so polling clients can send If-None-Match and get a 304 Not Modified while nothing has changed. The fields query
+// parameter, such as ?fields=name,players,status, limits the response to the fields named and only those are loaded.
+func GetGameHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Load only the fields the client selected, if it selected any, so clients can skip the deck
+		fields := render.Fields(r)
+		projection, err := models.GameProjection(fields)
+		if err != nil {
+			// Return a 400 Bad Request status if a selected field does not exist
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Retrieve the game using the game service
+		game, err := gameService.GetGame(gameID, projection...)
+		if err != nil {
+			// Return a 404 Not Found status if the game does not exist
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Encode the selected fields of the game as JSON, or answer 304 Not Modified if the client's If-None-Match
+		// names the current version
+		render.JSON(w, r, render.Select(game, fields))
+	}
+}
+
 // DeleteGameHandler handles the HTTP request to delete an existing game.
 // It extracts the game ID from the URL, uses the GameService to delete the game,
 // and returns an appropriate HTTP status code based on the outcome.
@@ -51,7 +195,7 @@ func DeleteGameHandler(gameService *services.GameService) http.HandlerFunc {
 		gameID := vars["id"]
 
 		// Attempt to delete the game using the game service
-		if err := gameService.DeleteGame(gameID); err != nil {
+		if err := gameService.DeleteGame(gameID, viewerFromRequest(r)); err != nil {
 			// Return a 404 Not Found status if the game does not exist
 			http.Error(w, err.Error(), http.StatusNotFound)
 			return
@@ -64,6 +208,7 @@ func DeleteGameHandler(gameService *services.GameService) http.HandlerFunc {
 
 // AddDeckToGameHandler handles the HTTP request to add a new deck of cards to an existing game.
 // It uses the DeckService to create a new deck, then adds this deck to the specified game using the GameService.
+// A request body of {"cards": [...]} adds that custom deck instead, once it passes the deck integrity checks.
 // The updated game is returned as a JSON response.
 func AddDeckToGameHandler(gameService *services.GameService, deckService *services.DeckService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -71,17 +216,43 @@ func AddDeckToGameHandler(gameService *services.GameService, deckService *servic
 		vars := mux.Vars(r)
 		gameID := vars["id"]
 
-		// Create a new deck using the deck service
-		deck := deckService.CreateDeck()
+		// Define a struct to capture the optional custom deck; an empty body adds a standard deck
+		var req struct {
+			Cards []models.Card `json:"cards"`
+		}
+		if err := decodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		if req.Cards != nil && len(req.Cards) == 0 {
+			// Return a 400 Bad Request status if the custom deck has no cards
+			http.Error(w, "a custom deck needs at least one card", http.StatusBadRequest)
+			return
+		}
 
-		// Add the new deck to the specified game using the game service
-		game, err := gameService.AddDeckToGame(gameID, deck)
+		// Add the custom deck, or a new deck from the deck service, to the specified game using the game service
+		var game *models.Game
+		var err error
+		if req.Cards != nil {
+			game, err = gameService.AddCustomDeckToGame(gameID, req.Cards)
+		} else {
+			game, err = gameService.AddDeckToGame(gameID, deckService.CreateDeck())
+		}
 		if err != nil {
-			// Return a 500 Internal Server Error status if adding the deck to the game fails
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			// Return a 422 Unprocessable Entity status listing the problems if the deck is rejected,
+			// or a 500 Internal Server Error status if adding the deck to the game fails
+			writeDeckError(w, err, http.StatusInternalServerError)
 			return
 		}
 
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
 		// Set the response header to indicate JSON content
 		w.Header().Set("Content-Type", "application/json")
 
@@ -100,7 +271,7 @@ func ShuffleGameDeckHandler(gameService *services.GameService) http.HandlerFunc
 		gameID := vars["id"]
 
 		// Attempt to shuffle the game deck using the game service
-		err := gameService.ShuffleGameDeck(gameID)
+		err := gameService.ShuffleGameDeck(gameID, viewerFromRequest(r))
 		if err != nil {
 			// Return a 500 Internal Server Error status if shuffling fails
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -114,7 +285,7 @@ func ShuffleGameDeckHandler(gameService *services.GameService) http.HandlerFunc
 
 // DealCardToPlayerHandler handles the HTTP request to deal a card to a specific player in a game.
 // It decodes the request payload to get the player's name, uses the GameService to deal a card,
-// and returns the dealt card as a JSON response.
+// and returns the dealt card as a JSON response. Only the game's owner and admins may deal.
 func DealCardToPlayerHandler(gameService *services.GameService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Extract the game ID from the URL path variables
@@ -123,21 +294,32 @@ func DealCardToPlayerHandler(gameService *services.GameService) http.HandlerFunc
 
 		// Define a struct to capture the incoming request payload
 		var req struct {
-			PlayerName string `json:"player_name"`
+			PlayerName string `json:"player_name" validate:"required,player,max=32"`
 		}
 
 		// Decode the JSON request body into the req struct
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			// Return a 400 Bad Request status if the payload is invalid
-			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
 			return
 		}
 
 		// Deal a card to the specified player using the game service
-		card, err := gameService.DealCardToPlayer(gameID, req.PlayerName)
+		card, err := gameService.DealCardToPlayer(gameID, req.PlayerName, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller does not deal in the game
+			http.Error(w, "only the game owner can deal cards", http.StatusForbidden)
+			return
+		}
 		if err != nil {
-			// Return a 500 Internal Server Error status if dealing the card fails
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			// Return a 422 Unprocessable Entity status if the deal breaks the rules, or a 500 Internal Server Error status if dealing the card fails
+			writeActionError(w, err, http.StatusInternalServerError)
 			return
 		}
 
@@ -1,58 +1,175 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
 	"my-card-game/internal/api/services"
+	"my-card-game/internal/render"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 )
 
 // GetRemainingCardsCountBySuitHandler handles the HTTP request to get the count of how many cards
-// per suit are left undealt in the game deck. The counts for each suit are returned as a JSON response.
+// per suit are left undealt in the game deck. The counts for each suit are returned as JSON, or as CSV or
+// MessagePack when the Accept header prefers them.
+// The query parameters described on remainingCardsQuery narrow, order, and page the counts.
 func GetRemainingCardsCountBySuitHandler(gameService *services.GameService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Extract the game ID from the URL path variables
 		vars := mux.Vars(r)
 		gameID := vars["id"]
 
+		// Read the filters, ordering, and paging from the query parameters
+		query, err := remainingCardsQuery(r)
+		if err != nil {
+			// Return a 400 Bad Request status if the parameters are invalid
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		// Retrieve the count of remaining cards per suit
-		suitCounts, err := gameService.GetRemainingCardsCountBySuit(gameID)
+		suitCounts, err := gameService.GetRemainingCardsCountBySuit(gameID, query)
 		if err != nil {
 			// Return a 500 Internal Server Error status if retrieving the counts fails
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		// Set the response header to indicate JSON content
-		w.Header().Set("Content-Type", "application/json")
-
-		// Encode the suit counts as JSON and write it to the response
-		json.NewEncoder(w).Encode(suitCounts)
+		// Encode the suit counts in the format the client accepts
+		render.Write(w, r, suitCounts)
 	}
 }
 
 // GetRemainingCardsSortedHandler handles the HTTP request to get the count of each card (suit and value)
 // remaining in the game deck, sorted by suit (hearts, spades, clubs, diamonds) and face value from high
-// value to low value (King, Queen, Jack, 10….2, Ace with value of 1). The sorted counts are returned as a JSON response.
+// value to low value (King, Queen, Jack, 10….2, Ace with value of 1). The sorted counts are returned in the
+// format negotiated from the Accept header: JSON by default, CSV, or MessagePack.
+// The query parameters described on remainingCardsQuery narrow, reorder, and page the listing; suit_order
+// reorders the suits, aces moves aces above kings or below twos, and group=none lists the cards flat by value.
 func GetRemainingCardsSortedHandler(gameService *services.GameService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Extract the game ID from the URL path variables
 		vars := mux.Vars(r)
 		gameID := vars["id"]
 
+		// Read the filters, ordering, and paging from the query parameters
+		query, err := remainingCardsQuery(r)
+		if err != nil {
+			// Return a 400 Bad Request status if the parameters are invalid
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		// Retrieve the remaining cards sorted by suit and value
-		remainingCards, err := gameService.GetRemainingCardsSorted(gameID)
+		remainingCards, err := gameService.GetRemainingCardsSorted(gameID, query)
 		if err != nil {
 			// Return a 500 Internal Server Error status if retrieving the sorted cards fails
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		// Set the response header to indicate JSON content
-		w.Header().Set("Content-Type", "application/json")
+		// Encode the sorted remaining cards in the format the client accepts
+		render.Write(w, r, remainingCards)
+	}
+}
+
+// GetOddsHandler handles the HTTP request for the probability that the next card drawn from the game deck matches
+// the cards described by the query parameters: suit and value (comma-separated lists), min_value and max_value
+// (an inclusive range of values), and aces (high or low) to override where the game ranks aces within the range.
+// The odds are returned in the format negotiated from the Accept header.
+func GetOddsHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Read the cards to match from the query parameters
+		params := r.URL.Query()
+		query := services.DrawOddsQuery{
+			Suits:    splitList(params.Get("suit")),
+			Values:   splitList(params.Get("value")),
+			MinValue: params.Get("min_value"),
+			MaxValue: params.Get("max_value"),
+			Aces:     params.Get("aces"),
+		}
+		if err := query.Validate(); err != nil {
+			// Return a 400 Bad Request status if the parameters are invalid
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Work out the odds from the cards left in the deck
+		odds, err := gameService.GetDrawOdds(gameID, query)
+		if err != nil {
+			// Return a 500 Internal Server Error status if working out the odds fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
-		// Encode the sorted remaining cards as JSON and write it to the response
-		json.NewEncoder(w).Encode(remainingCards)
+		// Encode the odds in the format the client accepts
+		render.Write(w, r, odds)
+	}
+}
+
+// GetCountStatsHandler handles the HTTP request for the card-counting statistics of the game deck: the Hi-Lo running
+// count, the true count, and how many low, neutral, and high cards remain. The statistics are returned in the
+// format negotiated from the Accept header.
+func GetCountStatsHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Count the cards left in the deck
+		stats, err := gameService.GetCountStats(gameID)
+		if err != nil {
+			// Return a 500 Internal Server Error status if counting the cards fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Encode the statistics in the format the client accepts
+		render.Write(w, r, stats)
+	}
+}
+
+// remainingCardsQuery reads the remaining-cards query parameters: suit and value (comma-separated lists to filter by),
+// order (asc or desc), aces (high or low), suit_order (the four suits, comma-separated, from highest to lowest),
+// group (suit or none), and limit and offset for paging.
+func remainingCardsQuery(r *http.Request) (services.RemainingCardsQuery, error) {
+	params := r.URL.Query()
+	query := services.RemainingCardsQuery{
+		Suits:     splitList(params.Get("suit")),
+		Values:    splitList(params.Get("value")),
+		Order:     params.Get("order"),
+		Aces:      params.Get("aces"),
+		SuitOrder: splitList(params.Get("suit_order")),
+		Group:     params.Get("group"),
+	}
+
+	// Parse the paging parameters when they are given
+	for name, target := range map[string]*int{"limit": &query.Limit, "offset": &query.Offset} {
+		if value := params.Get(name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return query, errors.New(name + " must be a number")
+			}
+			*target = n
+		}
+	}
+
+	return query, query.Validate()
+}
+
+// splitList splits a comma-separated query parameter into its trimmed, non-empty items.
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
+	return items
 }
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/validate"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// AskHandler handles the HTTP request for a Go Fish player to ask another player for a card value.
+// It decodes the asking player, the target player, and the value from the request payload, and returns
+// the outcome of the ask (transfer or go fish) as a JSON response.
+func AskHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName string `json:"player_name" validate:"player,max=32"`
+			Target     string `json:"target" validate:"required,player,max=32"`
+			Value      string `json:"value" validate:"required,max=10"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Take the action as the caller's player
+		player, err := actingPlayer(r, req.PlayerName)
+		if err != nil {
+			writeActingPlayerError(w, err)
+			return
+		}
+
+		// Make the ask using the game service
+		result, err := gameService.AskForValue(gameID, player, req.Target, req.Value)
+		if err != nil {
+			// Return a 422 Unprocessable Entity status listing the broken rules if the ask is not allowed, or 400 for other errors
+			writeActionError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the ask result as JSON and write it to the response
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// GetBooksHandler handles the HTTP request to get the books each player has completed in Go Fish.
+// The players are returned as a JSON response, sorted by number of books in descending order.
+func GetBooksHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Retrieve the books using the game service
+		books, err := gameService.GetBooks(gameID)
+		if err != nil {
+			// Return a 500 Internal Server Error status if retrieving the books fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the books as JSON and write it to the response
+		json.NewEncoder(w).Encode(books)
+	}
+}
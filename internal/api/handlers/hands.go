@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// DealHandHandler handles the HTTP request for the game's owner to clear the table and deal the next hand.
+// The game with the new hand is returned as a JSON response.
+func DealHandHandler(gameService *services.GameService) http.HandlerFunc {
+	return handPhaseHandler(gameService.DealNextHand, http.StatusCreated)
+}
+
+// ShowdownHandHandler handles the HTTP request for the game's owner to end play in the current hand.
+// The updated game is returned as a JSON response.
+func ShowdownHandHandler(gameService *services.GameService) http.HandlerFunc {
+	return handPhaseHandler(gameService.ShowdownHand, http.StatusOK)
+}
+
+// handPhaseHandler builds the handler shared by the endpoints that move a hand on to its next phase.
+func handPhaseHandler(advance func(gameID string, viewer models.Viewer) (*models.Game, error), status int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Move the hand on using the game service
+		game, err := advance(gameID, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller does not own the game
+			http.Error(w, "only the game owner can run the hands", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			// Return a 409 Conflict status if the hand is not in a phase that can move on
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+
+		// Encode the updated game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
+
+// ScoreHandHandler handles the HTTP request for the game's owner to score the current hand after its showdown.
+// The hand's summary is returned as a JSON response.
+func ScoreHandHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Score the hand using the game service
+		summary, err := gameService.ScoreHand(gameID, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller does not own the game
+			http.Error(w, "only the game owner can run the hands", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			// Return a 409 Conflict status if the hand has not reached its showdown
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the hand's summary as JSON and write it to the response
+		json.NewEncoder(w).Encode(summary)
+	}
+}
+
+// GetHandsHandler handles the HTTP request to see the current hand's phase and the summaries of past hands.
+// The hand state is returned as a JSON response.
+func GetHandsHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Retrieve the hand state using the game service
+		state, err := gameService.GetHands(gameID)
+		if err != nil {
+			// Return a 404 Not Found status if the game does not exist
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the hand state as JSON and write it to the response
+		json.NewEncoder(w).Encode(state)
+	}
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"encoding/json"
+	"my-card-game/internal/api/services"
+	"net/http"
+)
+
+// HealthzHandler handles the liveness probe. It only reports that the process is up and serving requests.
+func HealthzHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the status as JSON and write it to the response
+		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	}
+}
+
+// ReadyzHandler handles the readiness probe. It checks that MongoDB is reachable and the games collection can be read,
+// returning 200 OK when the server can take traffic and 503 Service Unavailable, with the failing checks, when it cannot.
+func ReadyzHandler(healthService *services.HealthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Run the readiness checks using the health service
+		ready, checks := healthService.Readiness()
+
+		status := "ready"
+		code := http.StatusOK
+		if !ready {
+			// Report the server as unavailable so traffic is routed elsewhere until the database is back
+			status = "unavailable"
+			code = http.StatusServiceUnavailable
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+
+		// Encode the status and checks as JSON and write it to the response
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": status, "checks": checks})
+	}
+}
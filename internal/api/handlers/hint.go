@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// GetHintHandler handles the HTTP request for a suggested move on the caller's turn.
+// The suggestion comes from the game mode's strategy, such as basic strategy in blackjack, and only uses what the
+// caller can see. Asking for a hint is recorded in the game's event log and the caller's statistics. The hint is
+// returned as a JSON response.
+func GetHintHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Suggest the caller's move using the game service
+		hint, err := gameService.GetHint(gameID, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller is not a player seated in the game
+			http.Error(w, "only players seated in the game can ask for hints", http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, models.ErrNoMove) {
+			// Return a 409 Conflict status if the game is not waiting on a move from the caller
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			// Return a 400 Bad Request status if the game has no strategy to suggest a move from
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the hint as JSON and write it to the response
+		json.NewEncoder(w).Encode(hint)
+	}
+}
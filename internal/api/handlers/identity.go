@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/auth"
+	"net/http"
+)
+
+// Errors returned by actingPlayer.
+var (
+	errNoSession   = errors.New("a player session is required")
+	errNotYourName = errors.New("player_name does not match the caller's session")
+)
+
+// viewerFromRequest returns the viewer making the request, based on the identity resolved by the auth middleware.
+func viewerFromRequest(r *http.Request) models.Viewer {
+	identity := auth.FromRequest(r)
+	return models.Viewer{PlayerName: identity.PlayerName, Admin: identity.Admin}
+}
+
+// playerOrCaller returns the given player name, falling back to the caller's session identity when it is empty.
+// It only chooses whose data a read looks at; what the caller may see is checked against their viewer.
+func playerOrCaller(r *http.Request, playerName string) string {
+	if playerName != "" {
+		return playerName
+	}
+	return auth.FromRequest(r).PlayerName
+}
+
+// actingPlayer returns the player an action is taken as: the caller's session identity. A player_name in the
+// payload may be left out, but must match the session when given. Admins may act as any player they name.
+func actingPlayer(r *http.Request, playerName string) (string, error) {
+	identity := auth.FromRequest(r)
+	switch {
+	case identity.PlayerName != "":
+		if playerName != "" && playerName != identity.PlayerName {
+			return "", errNotYourName
+		}
+		return identity.PlayerName, nil
+	case identity.Admin && playerName != "":
+		return playerName, nil
+	default:
+		return "", errNoSession
+	}
+}
+
+// writeActingPlayerError writes the error of a request whose acting player could not be worked out: 401
+// Unauthorized without a session, or 403 Forbidden when the payload names someone else.
+func writeActingPlayerError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errNotYourName) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusUnauthorized)
+}
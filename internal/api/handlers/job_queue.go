@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/validate"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// SubmitJobHandler handles the HTTP request to queue long-running work, such as a game export
+// ({"kind": "export_game", "game_id": "..."}), a large simulation ({"kind": "simulate_game", "game_id": "...",
+// "runs": 20000}), or a rebuild of the player statistics ({"kind": "rebuild_player_stats"}). It returns at once
+// with 202 Accepted, the queued job as a JSON response, and the job's URL in the Location header to poll.
+func SubmitJobHandler(jobService *services.JobService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			Kind string `json:"kind" validate:"required,max=32"`
+			models.JobParams
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Queue the job using the job service
+		job, err := jobService.SubmitJob(req.Kind, req.JobParams, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller may not submit jobs of this kind
+			http.Error(w, "the caller may not submit "+req.Kind+" jobs", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			// Return a 400 Bad Request status if the job is not valid
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Point the caller at the job's status
+		if linkRouter != nil {
+			if route := linkRouter.Get(RouteJob); route != nil {
+				if url, err := route.URL("job_id", job.ID.Hex()); err == nil {
+					w.Header().Set("Location", url.String())
+				}
+			}
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Set the status code to 202 Accepted, as the job has been queued but not run
+		w.WriteHeader(http.StatusAccepted)
+
+		// Encode the queued job as JSON and write it to the response
+		json.NewEncoder(w).Encode(job)
+	}
+}
+
+// GetJobHandler handles the HTTP request to check on a queued job. The job is returned as a JSON response with
+// its status, and with its result once it has succeeded or its error once it has failed.
+func GetJobHandler(jobService *services.JobService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the job ID from the URL path variables
+		vars := mux.Vars(r)
+		jobID := vars["job_id"]
+
+		// Retrieve the job using the job service
+		job, err := jobService.GetJob(jobID, viewerFromRequest(r))
+		if errors.Is(err, services.ErrJobNotFound) {
+			// Return a 404 Not Found status if the job does not exist or belongs to someone else
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			// Return a 500 Internal Server Error status if the job cannot be loaded
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the job as JSON and write it to the response
+		json.NewEncoder(w).Encode(job)
+	}
+}
+
+// ListJobsHandler handles the HTTP request to list the caller's most recent jobs, newest first, without their
+// results. Admins see every job. The jobs are returned as a JSON response.
+func ListJobsHandler(jobService *services.JobService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Retrieve the jobs using the job service
+		jobs, err := jobService.ListJobs(viewerFromRequest(r))
+		if err != nil {
+			// Return a 500 Internal Server Error status if the jobs cannot be loaded
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the jobs as JSON and write them to the response
+		json.NewEncoder(w).Encode(jobs)
+	}
+}
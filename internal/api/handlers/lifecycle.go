@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"my-card-game/internal/api/services"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// StartGameHandler handles the HTTP request to start a game that is waiting in the lobby.
+// It uses the GameService to set up the game for its mode and returns the started game as a JSON response.
+func StartGameHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Start the game using the game service
+		game, err := gameService.StartGame(gameID)
+		if errors.Is(err, services.ErrNotEnoughPlayers) || errors.Is(err, services.ErrUnbalancedTeams) {
+			// Return a 409 Conflict status if the game is below its minimum number of players or its teams are unbalanced
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			// Return a 500 Internal Server Error status if starting the game fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the started game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
+
+// ResetGameHandler handles the HTTP request to reset a game for a new hand.
+// All dealt cards go back into a reshuffled deck while players stay seated. The updated game is returned as a JSON response.
+func ResetGameHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Reset the game using the game service
+		game, err := gameService.ResetGame(gameID, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller does not own the game
+			http.Error(w, "only the game owner can reset the game", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			// Return a 500 Internal Server Error status if resetting the game fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the updated game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"my-card-game/internal/api/models"
+
+	"github.com/gorilla/mux"
+)
+
+// Names of the routes that game responses link to. RegisterRoutes gives the routes these names
+// so the links are built from the router rather than from hardcoded URL templates.
+const (
+	RouteGame       = "game"
+	RouteAddPlayer  = "add-player"
+	RouteDealCard   = "deal-card"
+	RouteShuffle    = "shuffle"
+	RouteGameEvents = "events"
+	RouteJob        = "job"
+)
+
+// linkRouter is the router game links are generated from, set once at startup by SetLinkRouter.
+var linkRouter *mux.Router
+
+// SetLinkRouter sets the router whose named routes are used to build the _links of game responses.
+// Until it is called, game responses carry no links.
+func SetLinkRouter(r *mux.Router) {
+	linkRouter = r
+}
+
+// gameLinks lists the actions available in the game's current state: self and events always, add-player
+// while there are open seats, deal-card while there are cards and players, and shuffle while there is more
+// than one undealt card. Finished games only link to themselves and their events.
+func gameLinks(game *models.Game) map[string]models.Link {
+	if linkRouter == nil || game.ID.IsZero() {
+		return nil
+	}
+
+	finished := game.Status == models.StatusFinished
+	available := map[string]bool{
+		RouteGame:       true,
+		RouteGameEvents: true,
+		RouteAddPlayer:  !finished && !game.IsFull(""),
+		RouteDealCard:   !finished && game.CardsLeft() > 0 && len(game.Players) > 0,
+		RouteShuffle:    !finished && game.CardsLeft() > 1,
+	}
+
+	links := map[string]models.Link{}
+	for name, ok := range available {
+		if !ok {
+			continue
+		}
+		route := linkRouter.Get(name)
+		if route == nil {
+			continue
+		}
+		url, err := route.URL("id", game.ID.Hex())
+		if err != nil {
+			continue
+		}
+
+		method := "GET"
+		if methods, err := route.GetMethods(); err == nil && len(methods) > 0 {
+			method = methods[0]
+		}
+
+		rel := name
+		if name == RouteGame {
+			rel = "self"
+		}
+		links[rel] = models.Link{Href: url.String(), Method: method}
+	}
+	return links
+}
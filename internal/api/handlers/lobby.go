@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/render"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// openGamesRefresh is the least time between two listings sent on the open game stream,
+// so a burst of game changes is folded into a single refresh.
+const openGamesRefresh = time.Second
+
+// ListOpenGamesHandler handles the HTTP request to browse the public games that still have free seats,
+// with their current and maximum player counts, game mode, and host. The optional mode query parameter narrows
+// the list to one game mode and limit caps its length. The list is returned as JSON, or as CSV or MessagePack
+// when the Accept header prefers them; pollers can send If-None-Match to skip unchanged listings.
+func ListOpenGamesHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Read the mode and limit from the query parameters
+		mode, limit, err := openGamesQuery(r)
+		if err != nil {
+			// Return a 400 Bad Request status if the parameters are invalid
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Retrieve the open games using the game service
+		games, err := gameService.ListOpenGames(mode, limit)
+		if err != nil {
+			// Return a 400 Bad Request status if the listing cannot be produced
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Encode the open games in the format the client accepts
+		render.Write(w, r, games)
+	}
+}
+
+// StreamOpenGamesHandler handles the HTTP request to follow the open game listing in real time using server-sent events.
+// The current listing is sent straight away as an "open_games" event, and a fresh one follows whenever games change,
+// at most once a second and only when the listing actually differs. It takes the same query parameters as ListOpenGamesHandler.
+func StreamOpenGamesHandler(gameService *services.GameService, hub *services.UpdateHub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Read the mode and limit from the query parameters
+		mode, limit, err := openGamesQuery(r)
+		if err != nil {
+			// Return a 400 Bad Request status if the parameters are invalid
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Server-sent events need a response that can be flushed after every event
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		// The stream stays open indefinitely, so lift the server's write timeout for this response
+		http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+		// Subscribe to the changes of every game for as long as the client stays connected
+		updates, unsubscribe := hub.Subscribe(services.AllGames)
+		defer unsubscribe()
+
+		// Set the response headers for an event stream
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+
+		// Send the listing when it differs from the last one sent
+		var last []byte
+		send := func() {
+			games, err := gameService.ListOpenGames(mode, limit)
+			if err != nil {
+				return
+			}
+			data, err := json.Marshal(games)
+			if err != nil || bytes.Equal(data, last) {
+				return
+			}
+			last = data
+			fmt.Fprintf(w, "event: open_games\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+		send()
+
+		keepAlive := time.NewTicker(streamKeepAlive)
+		defer keepAlive.Stop()
+
+		// refresh fires once the refresh interval after the first change since the last listing, and is nil otherwise
+		var refresh <-chan time.Time
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-keepAlive.C:
+				fmt.Fprint(w, ": keep-alive\n\n")
+				flusher.Flush()
+			case _, ok := <-updates:
+				if !ok {
+					return
+				}
+				// Wait out the refresh interval so a burst of changes causes one listing
+				if refresh == nil {
+					refresh = time.After(openGamesRefresh)
+				}
+			case <-refresh:
+				refresh = nil
+				send()
+			}
+		}
+	}
+}
+
+// openGamesQuery reads the open game listing's query parameters: mode, to narrow the list to one game mode,
+// and limit, to cap its length.
+func openGamesQuery(r *http.Request) (string, int, error) {
+	params := r.URL.Query()
+	limit := 0
+	if value := params.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return "", 0, errors.New("limit must be a number")
+		}
+		limit = n
+	}
+	return params.Get("mode"), limit, nil
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/validate"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// KickPlayerHandler handles the HTTP request for the game's owner to kick a player out of a game.
+// The kicked player may rejoin later. The updated game is returned as a JSON response.
+func KickPlayerHandler(gameService *services.GameService) http.HandlerFunc {
+	return moderatePlayerHandler(gameService, false)
+}
+
+// BanPlayerHandler handles the HTTP request for the game's owner to ban a player from a game.
+// The banned player is removed and cannot rejoin. The updated game is returned as a JSON response.
+func BanPlayerHandler(gameService *services.GameService) http.HandlerFunc {
+	return moderatePlayerHandler(gameService, true)
+}
+
+// moderatePlayerHandler builds the handler shared by the kick and ban endpoints.
+func moderatePlayerHandler(gameService *services.GameService, ban bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName string `json:"player_name" validate:"required,player,max=32"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Kick or ban the player using the game service
+		game, err := gameService.KickPlayer(gameID, req.PlayerName, ban, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller does not own the game
+			http.Error(w, "only the game owner can kick or ban players", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			// Return a 500 Internal Server Error status if the player cannot be removed
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Encode the updated game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
+
+// PenaltyDrawHandler handles the HTTP request for the game's owner to make a player draw cards as a penalty,
+// outside the normal turn flow. The updated game is returned as a JSON response.
+func PenaltyDrawHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName string `json:"player_name" validate:"required,player,max=32"`
+			Count      int    `json:"count"`
+			Reason     string `json:"reason" validate:"max=200"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Deal the penalty cards using the game service
+		game, err := gameService.PenaltyDraw(gameID, req.PlayerName, req.Count, req.Reason, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller does not own the game
+			http.Error(w, "only the game owner can hand out penalties", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			// Return a 422 Unprocessable Entity status if the rules do not allow the penalty, or 500 otherwise
+			writeActionError(w, err, http.StatusInternalServerError)
+			return
+		}
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Encode the updated game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/validate"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// MakeMoveHandler handles the HTTP request to make a move in any game whose mode has registered rules.
+// The payload names the move's type, such as play_card, ask, hit, or battle, along with whatever the move needs:
+// the cards played, the player and value asked for, or the suit declared. The game's own rules check and apply
+// the move. The updated game is returned as a JSON response, with the round's scores if the move ended the round.
+func MakeMoveHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			Type         string        `json:"type" validate:"required,max=32"`
+			PlayerName   string        `json:"player_name" validate:"player,max=32"`
+			Target       string        `json:"target" validate:"max=32"`
+			Cards        []models.Card `json:"cards"`
+			Value        string        `json:"value" validate:"max=8"`
+			DeclaredSuit string        `json:"declared_suit" validate:"max=16"`
+			Amount       int           `json:"amount"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Take the action as the caller's player
+		player, err := actingPlayer(r, req.PlayerName)
+		if err != nil {
+			writeActingPlayerError(w, err)
+			return
+		}
+
+		// Make the move using the game service
+		move := models.Action{
+			Type:         req.Type,
+			Player:       player,
+			Target:       req.Target,
+			Cards:        req.Cards,
+			Value:        req.Value,
+			DeclaredSuit: req.DeclaredSuit,
+			Amount:       req.Amount,
+		}
+		outcome, err := gameService.MakeMove(gameID, move)
+		if err != nil {
+			// Return a 422 Unprocessable Entity status listing the broken rules if the move is not allowed, or 400 for other errors
+			writeActionError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		outcome.Game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(outcome.Game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the move's outcome as JSON and write it to the response
+		json.NewEncoder(w).Encode(outcome)
+	}
+}
@@ -2,16 +2,24 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"my-card-game/internal/api/models"
 	"my-card-game/internal/api/services"
+	"my-card-game/internal/auth"
+	"my-card-game/internal/render"
+	"my-card-game/internal/validate"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 )
 
 // AddPlayerHandler handles the HTTP request to add a player to a game.
 // It decodes the request payload to get the player's name and uses the GameService
-// to add the player to the specified game. The updated game is returned as a JSON response.
-func AddPlayerHandler(gameService *services.GameService) http.HandlerFunc {
+// to add the player to the specified game. Callers with a session join as their own player. An anonymous caller
+// joins with a player_name nobody has claimed yet, which claims the name and issues them a session for it.
+// The updated game is returned as a JSON response.
+func AddPlayerHandler(gameService *services.GameService, sessionService *services.SessionService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Extract the game ID from the URL path variables
 		vars := mux.Vars(r)
@@ -19,24 +27,85 @@ func AddPlayerHandler(gameService *services.GameService) http.HandlerFunc {
 
 		// Define a struct to capture the incoming request payload
 		var req struct {
-			PlayerName string `json:"player_name"`
+			PlayerName string `json:"player_name" validate:"player,max=32"`
+			Password   string `json:"password" validate:"max=72"`
+			Seat       int    `json:"seat"` // Seat to sit in; 0 takes the seat held for the player or the lowest open seat
 		}
 
 		// Decode the JSON request body into the req struct
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			// Return a 400 Bad Request status if the payload is invalid
-			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Join as the caller's player, or claim the name for an anonymous caller
+		playerName, err := actingPlayer(r, req.PlayerName)
+		claimed := false
+		if errors.Is(err, errNoSession) && req.PlayerName != "" {
+			playerName, err = req.PlayerName, sessionService.ClaimPlayer(req.PlayerName)
+			claimed = err == nil
+		}
+		if errors.Is(err, services.ErrNameTaken) {
+			// Return a 409 Conflict status if someone else already holds the name
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			writeActingPlayerError(w, err)
 			return
 		}
 
 		// Add the player to the specified game using the game service
-		game, err := gameService.AddPlayer(gameID, req.PlayerName)
+		game, err := gameService.AddPlayer(gameID, playerName, req.Password, req.Seat)
+		if errors.Is(err, services.ErrAlreadySeated) && auth.FromRequest(r).PlayerName == playerName {
+			// A seated player joining again with their own session is returning, so give them back their seat and hand
+			game, err = gameService.ResumePlayer(gameID, playerName)
+		}
+		if err != nil && claimed {
+			// Give the name back if the join it was claimed for failed
+			sessionService.ReleasePlayer(playerName)
+		}
+		if errors.Is(err, services.ErrWrongPassword) {
+			// Return a 403 Forbidden status if the game's password was not supplied or does not match
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, services.ErrGameFull) {
+			// Return a 409 Conflict status if the game has no free seats
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		if err != nil {
-			// Return a 500 Internal Server Error status if adding the player fails
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			// Return a 400 Bad Request or 409 Conflict status if the seat asked for cannot be had, or a
+			// 500 Internal Server Error status if adding the player fails
+			http.Error(w, err.Error(), seatErrorStatus(err, http.StatusInternalServerError))
 			return
 		}
 
+		// Issue a session to the player who just claimed their name, so they can act as that player
+		if claimed {
+			if err := issueSession(w, sessionService, playerName); err != nil {
+				// Return a 500 Internal Server Error status if the session cannot be issued
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			// The joining player can see their own hand in the response
+			r = r.WithContext(auth.WithIdentity(r.Context(), auth.Identity{PlayerName: playerName}))
+		}
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
 		// Set the response header to indicate JSON content
 		w.Header().Set("Content-Type", "application/json")
 
@@ -56,24 +125,43 @@ func RemovePlayerHandler(gameService *services.GameService) http.HandlerFunc {
 
 		// Define a struct to capture the incoming request payload
 		var req struct {
-			PlayerName string `json:"player_name"`
+			PlayerName string `json:"player_name" validate:"player,max=32"`
 		}
 
 		// Decode the JSON request body into the req struct
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			// Return a 400 Bad Request status if the payload is invalid
-			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Take the action as the caller's player
+		player, err := actingPlayer(r, req.PlayerName)
+		if err != nil {
+			writeActingPlayerError(w, err)
 			return
 		}
 
 		// Remove the player from the specified game using the game service
-		game, err := gameService.RemovePlayer(gameID, req.PlayerName)
+		game, err := gameService.RemovePlayer(gameID, player)
 		if err != nil {
 			// Return a 500 Internal Server Error status if removing the player fails
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
 		// Set the response header to indicate JSON content
 		w.Header().Set("Content-Type", "application/json")
 
@@ -84,17 +172,18 @@ func RemovePlayerHandler(gameService *services.GameService) http.HandlerFunc {
 
 // GetPlayerHandHandler handles the HTTP request to get the list of cards held by a specific player in a game.
 // It extracts the player's name from the query parameters, uses the GameService to retrieve the player's hand,
-// and returns the list of cards as a JSON response.
+// and returns the list of cards as a JSON response. Only the player, the game's owner, and admins may read a hand.
+// Like the other game reads it honors If-None-Match, returning 304 Not Modified when the hand is unchanged.
 func GetPlayerHandHandler(gameService *services.GameService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Extract the game ID from the URL path variables
 		vars := mux.Vars(r)
 		gameID := vars["id"]
 
-		// Get the player's name from the query parameters
-		playerName := r.URL.Query().Get("player_name")
+		// Get the player's name from the query parameters, defaulting to the caller
+		playerName := playerOrCaller(r, r.URL.Query().Get("player_name"))
 
-		// Check if the player's name is provided in the query parameters
+		// Check if the player's name is provided in the query parameters or the session
 		if playerName == "" {
 			// Return a 400 Bad Request status if the player name is not provided
 			http.Error(w, "player_name is required", http.StatusBadRequest)
@@ -102,42 +191,198 @@ func GetPlayerHandHandler(gameService *services.GameService) http.HandlerFunc {
 		}
 
 		// Get the player's hand using the game service
-		hand, err := gameService.GetPlayerHand(gameID, playerName)
+		hand, err := gameService.GetPlayerHand(gameID, playerName, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller may not see this hand
+			http.Error(w, "not allowed to view this player's hand", http.StatusForbidden)
+			return
+		}
 		if err != nil {
 			// Return a 500 Internal Server Error status if retrieving the hand fails
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		// Set the response header to indicate JSON content
-		w.Header().Set("Content-Type", "application/json")
-
-		// Encode the player's hand as JSON and write it to the response
-		json.NewEncoder(w).Encode(hand)
+		// Encode the player's hand as JSON, or answer 304 Not Modified if it has not changed since the client's copy
+		render.JSON(w, r, hand)
 	}
 }
 
 // GetPlayersWithHandValuesHandler handles the HTTP request to get the list of players in a game
-// along with the total value of all the cards each player holds. The list is sorted in descending order
-// based on the hand values. The sorted list is returned as a JSON response.
+// along with the total value of all the cards each player holds. The list is sorted best hand first unless the
+// order query parameter asks for asc or desc; limit keeps only the top players, min_value leaves out hands worth
+// less, and breakdown=true adds each card's value for the hands the caller may see.
+// The sorted list is returned as JSON, or as CSV or MessagePack if the Accept header asks for them.
 func GetPlayersWithHandValuesHandler(gameService *services.GameService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Extract the game ID from the URL path variables
 		vars := mux.Vars(r)
 		gameID := vars["id"]
 
-		// Retrieve the list of players with their hand values, sorted in descending order
-		playerHandValues, err := gameService.GetPlayersWithHandValues(gameID)
+		// Build the options from the query parameters
+		query := r.URL.Query()
+		opts := services.HandValueOptions{Order: query.Get("order"), Viewer: viewerFromRequest(r)}
+		if opts.Order != "" && opts.Order != services.OrderAscending && opts.Order != services.OrderDescending {
+			// Return a 400 Bad Request status if the order is not one the list can be sorted in
+			http.Error(w, "order must be asc or desc", http.StatusBadRequest)
+			return
+		}
+		if value := query.Get("limit"); value != "" {
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit < 0 {
+				// Return a 400 Bad Request status if the limit is not a positive number
+				http.Error(w, "limit must be a positive number", http.StatusBadRequest)
+				return
+			}
+			opts.Limit = limit
+		}
+		if value := query.Get("min_value"); value != "" {
+			minValue, err := strconv.Atoi(value)
+			if err != nil {
+				// Return a 400 Bad Request status if the minimum is not a number
+				http.Error(w, "min_value must be a number", http.StatusBadRequest)
+				return
+			}
+			opts.MinValue = &minValue
+		}
+		if value := query.Get("breakdown"); value != "" {
+			breakdown, err := strconv.ParseBool(value)
+			if err != nil {
+				// Return a 400 Bad Request status if the flag is not true or false
+				http.Error(w, "breakdown must be true or false", http.StatusBadRequest)
+				return
+			}
+			opts.Breakdown = breakdown
+		}
+
+		// Retrieve the list of players with their hand values, sorted and filtered as asked
+		playerHandValues, err := gameService.GetPlayersWithHandValues(gameID, opts)
 		if err != nil {
 			// Return a 500 Internal Server Error status if retrieving the hand values fails
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
+		// Encode the list of players with hand values in the format the client accepts
+		render.Write(w, r, playerHandValues)
+	}
+}
+
+// GetPlayerStatsHandler handles the HTTP request to view a player's profile: their career statistics across
+// finished games, such as games won, points scored, favorite mode, and win streaks. The fields query parameter
+// limits the response to the fields named. The statistics are returned as JSON, or as CSV or MessagePack when the
+// Accept header prefers them.
+func GetPlayerStatsHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the player's name from the URL path variables
+		vars := mux.Vars(r)
+		playerName := vars["name"]
+
+		// Validate the player's name
+		if err := validate.Field("name", playerName, "player,max=32"); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Load only the fields the client selected, if it selected any
+		fields := render.Fields(r)
+		projection, err := models.PlayerStatsProjection(fields)
+		if err != nil {
+			// Return a 400 Bad Request status if a selected field does not exist
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Retrieve the statistics using the game service
+		stats, err := gameService.GetPlayerStats(playerName, projection...)
+		if err != nil {
+			// Return a 500 Internal Server Error status if retrieving the statistics fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Encode the selected statistics in the format the client accepts
+		render.Write(w, r, render.Select(stats, fields))
+	}
+}
+
+// GetAchievementsHandler handles the HTTP request to list a player's achievements.
+// Every achievement is listed along with whether the player has unlocked it, and when and in which game.
+// The list is returned as a JSON response.
+func GetAchievementsHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the player's name from the URL path variables
+		vars := mux.Vars(r)
+		playerName := vars["name"]
+
+		// Validate the player's name
+		if err := validate.Field("name", playerName, "player,max=32"); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Retrieve the achievements using the game service
+		achievements, err := gameService.GetAchievements(playerName)
+		if err != nil {
+			// Return a 500 Internal Server Error status if retrieving the achievements fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the achievements as JSON and write them to the response
+		json.NewEncoder(w).Encode(achievements)
+	}
+}
+
+// RedrawHandler handles the HTTP request for a player to return cards from their hand and draw replacements,
+// as in draw poker. The player's new hand is returned as a JSON response.
+func RedrawHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName string        `json:"player_name" validate:"player,max=32"`
+			Cards      []models.Card `json:"cards"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Take the action as the caller's player
+		player, err := actingPlayer(r, req.PlayerName)
+		if err != nil {
+			writeActingPlayerError(w, err)
+			return
+		}
+
+		// Swap the cards using the game service
+		hand, err := gameService.RedrawCards(gameID, player, req.Cards)
+		if err != nil {
+			// Return a 422 Unprocessable Entity status if the redraw breaks the rules, or a 409 Conflict status otherwise
+			writeActionError(w, err, http.StatusConflict)
+			return
+		}
+
 		// Set the response header to indicate JSON content
 		w.Header().Set("Content-Type", "application/json")
 
-		// Encode the list of players with hand values as JSON and write it to the response
-		json.NewEncoder(w).Encode(playerHandValues)
+		// Encode the new hand as JSON and write it to the response
+		json.NewEncoder(w).Encode(hand)
 	}
 }
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"encoding/json"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/auth"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// presenceTimeout is how long after their last heartbeat a player is still shown as online, set once at startup
+// by SetPresenceTimeout.
+var presenceTimeout = time.Minute
+
+// SetPresenceTimeout sets how long after their last heartbeat a player is still shown as online.
+func SetPresenceTimeout(timeout time.Duration) {
+	presenceTimeout = timeout
+}
+
+// annotateGame fills in the parts of a game response that are derived rather than stored:
+// the links to the actions available, the presence of each player, the seating arrangement, the dealer of a
+// running game, and which cards on the table are wild.
+func annotateGame(game *models.Game) {
+	game.Links = gameLinks(game)
+	game.Table = game.SeatingChart()
+	game.MarkWildCards()
+	game.Presence = game.PlayerPresence(time.Now().UTC(), presenceTimeout)
+	if game.Status == models.StatusActive {
+		game.DealerName = game.Dealer()
+	}
+}
+
+// HeartbeatHandler handles the HTTP request a seated player's client sends periodically to show it is still there.
+// The caller is shown as online until the presence timeout passes without another heartbeat, and heartbeats count
+// as activity for the inactivity check. The response lists the presence of every player in the game.
+func HeartbeatHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Record the heartbeat of the calling player
+		game, err := gameService.Heartbeat(gameID, auth.FromRequest(r).PlayerName)
+		if err != nil {
+			// Return a 404 Not Found status if the game does not exist or the caller has no seat in it
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the presence of the game's players as JSON and write it to the response
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"presence":         game.PlayerPresence(time.Now().UTC(), presenceTimeout),
+			"presence_timeout": presenceTimeout.String(),
+		})
+	}
+}
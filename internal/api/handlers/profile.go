@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/auth"
+	"my-card-game/internal/validate"
+	"net/http"
+)
+
+// GetProfileHandler handles the HTTP request to retrieve the caller's profile, including how they are alerted
+// when it is their turn. The profile is returned as a JSON response.
+func GetProfileHandler(socialService *services.SocialService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Retrieve the profile using the social service
+		profile, err := socialService.GetProfile(auth.FromRequest(r).PlayerName)
+		if err != nil {
+			// Return a 500 Internal Server Error status if retrieving the profile fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the profile as JSON and write it to the response
+		json.NewEncoder(w).Encode(profile)
+	}
+}
+
+// SetNotificationPrefsHandler handles the HTTP request to choose how the caller is alerted when a game is waiting
+// on their move: whether they are alerted at all, on which channels, and the address of each channel.
+// The updated profile is returned as a JSON response.
+func SetNotificationPrefsHandler(socialService *services.SocialService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			TurnAlerts bool     `json:"turn_alerts"`
+			Channels   []string `json:"channels"`
+			Email      string   `json:"email" validate:"max=254"`
+			PushToken  string   `json:"push_token" validate:"max=4096"`
+			WebhookURL string   `json:"webhook_url" validate:"max=2048"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Save the preferences using the social service
+		prefs := models.NotificationPrefs{
+			TurnAlerts: req.TurnAlerts,
+			Channels:   req.Channels,
+			Email:      req.Email,
+			PushToken:  req.PushToken,
+			WebhookURL: req.WebhookURL,
+		}
+		profile, err := socialService.SetNotificationPrefs(auth.FromRequest(r).PlayerName, prefs)
+		if err != nil {
+			// Return a 400 Bad Request status if the preferences cannot be saved
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the updated profile as JSON and write it to the response
+		json.NewEncoder(w).Encode(profile)
+	}
+}
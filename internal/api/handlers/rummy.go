@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"encoding/json"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/validate"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// DeclareMeldHandler handles the HTTP request for a player to declare a meld (set or run) from their hand.
+// It decodes the player's name and the meld cards from the request payload, validates the meld using the
+// GameService, and returns the new meld as a JSON response.
+func DeclareMeldHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName string        `json:"player_name" validate:"player,max=32"`
+			Cards      []models.Card `json:"cards"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Take the action as the caller's player
+		player, err := actingPlayer(r, req.PlayerName)
+		if err != nil {
+			writeActingPlayerError(w, err)
+			return
+		}
+
+		// Declare the meld using the game service
+		meld, err := gameService.DeclareMeld(gameID, player, req.Cards)
+		if err != nil {
+			// Return a 422 Unprocessable Entity status listing the broken rules if the meld is not valid, or 400 for other errors
+			writeActionError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the new meld as JSON and write it to the response
+		json.NewEncoder(w).Encode(meld)
+	}
+}
+
+// LayOffHandler handles the HTTP request for a player to lay off cards onto an existing meld.
+// It extracts the meld ID from the URL, decodes the player's name and cards from the request payload,
+// and returns the extended meld as a JSON response.
+func LayOffHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID and meld ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+		meldID, err := strconv.Atoi(vars["meld_id"])
+		if err != nil {
+			// Return a 400 Bad Request status if the meld ID is not a number
+			http.Error(w, "invalid meld ID", http.StatusBadRequest)
+			return
+		}
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName string        `json:"player_name" validate:"player,max=32"`
+			Cards      []models.Card `json:"cards"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Take the action as the caller's player
+		player, err := actingPlayer(r, req.PlayerName)
+		if err != nil {
+			writeActingPlayerError(w, err)
+			return
+		}
+
+		// Lay off the cards using the game service
+		meld, err := gameService.LayOff(gameID, player, meldID, req.Cards)
+		if err != nil {
+			// Return a 422 Unprocessable Entity status listing the broken rules if the cards cannot be laid off, or 400 for other errors
+			writeActionError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the extended meld as JSON and write it to the response
+		json.NewEncoder(w).Encode(meld)
+	}
+}
+
+// GetMeldsHandler handles the HTTP request to get the melds laid down on the table.
+// The melds are returned as a JSON response.
+func GetMeldsHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Retrieve the melds using the game service
+		melds, err := gameService.GetMelds(gameID)
+		if err != nil {
+			// Return a 500 Internal Server Error status if retrieving the melds fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the melds as JSON and write it to the response
+		json.NewEncoder(w).Encode(melds)
+	}
+}
+
+// GetDeadwoodHandler handles the HTTP request to compute each player's deadwood points at the end of a round.
+// The players are returned as a JSON response, sorted from the least deadwood to the most.
+func GetDeadwoodHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Compute the deadwood using the game service
+		deadwood, err := gameService.GetDeadwood(gameID, viewerFromRequest(r))
+		if err != nil {
+			// Return a 500 Internal Server Error status if computing the deadwood fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the deadwood list as JSON and write it to the response
+		json.NewEncoder(w).Encode(deadwood)
+	}
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/auth"
+	"my-card-game/internal/validate"
+	"net/http"
+)
+
+// CreateSessionHandler handles the HTTP request to sign a player in.
+// It decodes the player's name and password from the request payload, checks them and issues a session token
+// using the SessionService, sets it as a cookie, and returns the token and its expiry as a JSON response.
+func CreateSessionHandler(sessionService *services.SessionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName string `json:"player_name" validate:"required,player,max=32"`
+			Password   string `json:"password" validate:"required,max=72"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Check the password and issue the session
+		token, session, err := sessionService.SignIn(req.PlayerName, req.Password)
+		if errors.Is(err, services.ErrBadCredentials) {
+			// Return a 401 Unauthorized status if the name and password do not match an account
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			// Return a 500 Internal Server Error status if the session cannot be issued
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Hand the token to the client and return the session details
+		writeSession(w, http.StatusOK, token, session)
+	}
+}
+
+// RegisterPlayerHandler handles the HTTP request to claim a player name with a password.
+// It decodes the player's name and password from the request payload, creates the account and issues a session
+// using the SessionService, and returns the token and its expiry as a JSON response.
+func RegisterPlayerHandler(sessionService *services.SessionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName string `json:"player_name" validate:"required,player,max=32"`
+			Password   string `json:"password" validate:"required,max=72"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Claim the name and issue the session
+		token, session, err := sessionService.Register(req.PlayerName, req.Password)
+		if errors.Is(err, services.ErrNameTaken) {
+			// Return a 409 Conflict status if someone already holds the name
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			// Return a 400 Bad Request status if the account cannot be created
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Hand the token to the client and return the session details
+		writeSession(w, http.StatusCreated, token, session)
+	}
+}
+
+// SetPasswordHandler handles the HTTP request to set or change the caller's password, so they can sign in again
+// once their session expires. This is how a player who claimed their name by joining a game keeps it.
+func SetPasswordHandler(sessionService *services.SessionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			Password string `json:"password" validate:"required,max=72"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Set the password using the session service
+		if err := sessionService.SetPassword(auth.FromRequest(r).PlayerName, req.Password); err != nil {
+			// Return a 400 Bad Request status if the password cannot be set
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Return a 204 No Content status once the password is set
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// MeHandler handles the HTTP request to get the caller's player identity.
+// It returns the player's name, the games they are currently seated in, and their career statistics as a JSON response.
+func MeHandler(gameService *services.GameService, sessionService *services.SessionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Only callers with a valid session have an identity
+		identity := auth.FromRequest(r)
+		if identity.PlayerName == "" {
+			// Return a 401 Unauthorized status if the caller has no session
+			http.Error(w, "no valid session", http.StatusUnauthorized)
+			return
+		}
+
+		// Look up the session to report when it expires
+		session, err := sessionService.GetSession(r.Context(), auth.SessionToken(r))
+		if err != nil {
+			// Return a 401 Unauthorized status if the session has gone away
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		// Find the games the player is seated in
+		games, err := gameService.GamesForPlayer(identity.PlayerName)
+		if err != nil {
+			// Return a 500 Internal Server Error status if retrieving the games fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Look up the player's career statistics
+		stats, err := gameService.GetPlayerStats(identity.PlayerName)
+		if err != nil {
+			// Return a 500 Internal Server Error status if retrieving the statistics fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the caller's identity as JSON and write it to the response
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"player_name": identity.PlayerName,
+			"expires_at":  session.ExpiresAt,
+			"games":       games,
+			"stats":       stats,
+		})
+	}
+}
+
+// writeSession hands a newly issued session to the client and writes its details as a JSON response with the
+// given status.
+func writeSession(w http.ResponseWriter, status int, token string, session *models.Session) {
+	setSessionToken(w, token, session)
+
+	// Set the response header to indicate JSON content
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	// Encode the session details as JSON and write them to the response
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"token":       token,
+		"player_name": session.PlayerName,
+		"expires_at":  session.ExpiresAt,
+	})
+}
+
+// issueSession creates a session for a player the caller has already been checked to hold the name of, and hands
+// the token to the client.
+func issueSession(w http.ResponseWriter, sessionService *services.SessionService, playerName string) error {
+	token, session, err := sessionService.CreateSession(playerName)
+	if err != nil {
+		return err
+	}
+	setSessionToken(w, token, session)
+	return nil
+}
+
+// setSessionToken hands a session token to the client, both as a cookie and in the X-Session-Token response
+// header.
+func setSessionToken(w http.ResponseWriter, token string, session *models.Session) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     auth.SessionCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  session.ExpiresAt,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	w.Header().Set("X-Session-Token", token)
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/validate"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// UpdateSettingsHandler handles the HTTP request to edit a game's settings while it is in the lobby.
+// Only the fields present in the request payload are changed. The updated game is returned as a JSON response.
+func UpdateSettingsHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Decode the JSON request body into the settings patch
+		var patch models.SettingsPatch
+		if err := decodeJSON(r, &patch); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Update the settings using the game service
+		game, err := gameService.UpdateSettings(gameID, patch, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller does not own the game
+			http.Error(w, "only the game owner can change the settings", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			// Return a 400 Bad Request status if the settings cannot be changed
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the updated game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
+
+// UpdateGameHandler handles the HTTP request to partially update a game's name, privacy, or settings.
+// Only the fields present in the request payload are changed. The updated game is returned as a JSON response.
+func UpdateGameHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Decode the JSON request body into the game patch
+		var patch services.GamePatch
+		if err := decodeJSON(r, &patch); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(patch); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Update the game using the game service
+		game, err := gameService.UpdateGame(gameID, patch, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller does not own the game
+			http.Error(w, "only the game owner can update the game", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			// Return a 400 Bad Request status if the update is not valid
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the updated game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
+
+// SetHouseRulesHandler handles the HTTP request to attach a house rules script to a game while it is in the lobby,
+// or to remove its house rules with an empty script. The updated game is returned as a JSON response.
+func SetHouseRulesHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Decode the JSON request body into the house rules request
+		var req struct {
+			Script string `json:"script"`
+		}
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Attach the house rules using the game service
+		game, err := gameService.SetHouseRules(gameID, req.Script, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller does not own the game
+			http.Error(w, "only the game owner can change the house rules", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			// Return a 400 Bad Request status if the script does not compile or the rules cannot be changed
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the updated game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
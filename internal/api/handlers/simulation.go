@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"my-card-game/internal/api/services"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// SimulateGameHandler handles the HTTP request to run Monte Carlo simulations of an active game.
+// The optional body sets how many continuations to run ({"runs": 5000}) and the seed to draw them from, to repeat
+// an earlier simulation. The response lists each player's chance of winning. Simulations see every hand, so only
+// callers allowed to see every hand, such as the dealer and admins, may run them.
+func SimulateGameHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Decode the JSON request body into the options; an empty body runs the default simulation
+		var opts services.SimulationOptions
+		if err := decodeJSON(r, &opts); err != nil && !errors.Is(err, io.EOF) {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Run the simulations using the game service
+		simulation, err := gameService.SimulateGame(gameID, opts, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller may not see every hand
+			http.Error(w, "only callers who can see every hand can simulate the game", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			// Return a 400 Bad Request status if the game cannot be simulated
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the simulation results as JSON and write it to the response
+		json.NewEncoder(w).Encode(simulation)
+	}
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/validate"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// CreateSnapshotHandler handles the HTTP request for the dealer to save a named snapshot of a game's state.
+// The new snapshot is returned as a JSON response with a 201 Created status.
+func CreateSnapshotHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			Name string `json:"name" validate:"required,name,max=100"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Take the snapshot using the game service
+		snapshot, err := gameService.CreateSnapshot(gameID, req.Name, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller is not the dealer
+			http.Error(w, "only the dealer can take snapshots", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			// Return a 400 Bad Request status if the snapshot cannot be taken
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		// Encode the snapshot as JSON and write it to the response
+		json.NewEncoder(w).Encode(snapshot)
+	}
+}
+
+// ListSnapshotsHandler handles the HTTP request to list the snapshots taken of a game.
+// The snapshots are returned as a JSON response, oldest first.
+func ListSnapshotsHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// List the snapshots using the game service
+		snapshots, err := gameService.ListSnapshots(gameID)
+		if err != nil {
+			// Return a 500 Internal Server Error status if listing the snapshots fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the snapshots as JSON and write it to the response
+		json.NewEncoder(w).Encode(snapshots)
+	}
+}
+
+// RollbackHandler handles the HTTP request for the dealer to roll a game back to a named snapshot.
+// The restored game is returned as a JSON response.
+func RollbackHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID and snapshot name from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+		name := vars["name"]
+
+		// Roll the game back using the game service
+		game, err := gameService.RollbackToSnapshot(gameID, name, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller is not the dealer
+			http.Error(w, "only the dealer can roll back the game", http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, models.ErrChecksumMismatch) {
+			// Return a 409 Conflict status if the snapshot was changed after it was taken
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			// Return a 500 Internal Server Error status if the rollback fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the restored game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
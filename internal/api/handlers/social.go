@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/auth"
+	"my-card-game/internal/validate"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// ListFriendsHandler handles the HTTP request to list the caller's friends and pending friend requests.
+// The list is returned as a JSON response.
+func ListFriendsHandler(socialService *services.SocialService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Retrieve the caller's friends using the social service
+		list, err := socialService.ListFriends(auth.FromRequest(r).PlayerName)
+		if err != nil {
+			// Return a 500 Internal Server Error status if retrieving the friends fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the friend list as JSON and write it to the response
+		json.NewEncoder(w).Encode(list)
+	}
+}
+
+// SendFriendRequestHandler handles the HTTP request to send a friend request from the caller to another player.
+// If that player had already sent the caller a request, it is accepted instead. The request is returned as a JSON response.
+func SendFriendRequestHandler(socialService *services.SocialService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName string `json:"player_name" validate:"required,player,max=32"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Send the request using the social service
+		request, err := socialService.SendFriendRequest(auth.FromRequest(r).PlayerName, req.PlayerName)
+		if err != nil {
+			// Return a 400 Bad Request status if the request cannot be sent
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		// Encode the friend request as JSON and write it to the response
+		json.NewEncoder(w).Encode(request)
+	}
+}
+
+// RespondToFriendRequestHandler handles the HTTP request to accept or decline a friend request sent to the caller.
+// The answer comes from the route: accept when true, decline otherwise. The request is returned as a JSON response.
+func RespondToFriendRequestHandler(socialService *services.SocialService, accept bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the friend request ID from the URL path variables
+		vars := mux.Vars(r)
+		requestID := vars["id"]
+
+		// Answer the request using the social service
+		request, err := socialService.RespondToFriendRequest(requestID, auth.FromRequest(r).PlayerName, accept)
+		if err != nil {
+			// Return a 404 Not Found status if there is no such pending request for the caller
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the friend request as JSON and write it to the response
+		json.NewEncoder(w).Encode(request)
+	}
+}
+
+// InviteToGameHandler handles the HTTP request to invite a friend to a game the caller is part of.
+// The recipient is notified in real time and can accept the invitation to join the game.
+// The invitation is returned as a JSON response.
+func InviteToGameHandler(socialService *services.SocialService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName string `json:"player_name" validate:"required,player,max=32"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Send the invitation using the social service
+		invitation, err := socialService.InviteToGame(gameID, auth.FromRequest(r).PlayerName, req.PlayerName)
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller is not part of the game
+			http.Error(w, "only the game's players can invite others", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			// Return a 400 Bad Request status if the invitation cannot be sent
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		// Encode the invitation as JSON and write it to the response
+		json.NewEncoder(w).Encode(invitation)
+	}
+}
+
+// ListInvitationsHandler handles the HTTP request to list the caller's pending game invitations.
+// The invitations are returned as a JSON response.
+func ListInvitationsHandler(socialService *services.SocialService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Retrieve the invitations using the social service
+		invitations, err := socialService.ListInvitations(auth.FromRequest(r).PlayerName)
+		if err != nil {
+			// Return a 500 Internal Server Error status if retrieving the invitations fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the invitations as JSON and write them to the response
+		json.NewEncoder(w).Encode(invitations)
+	}
+}
+
+// RespondToInvitationHandler handles the HTTP request to accept or decline a game invitation sent to the caller.
+// Accepting seats the caller in the game, which is returned as a JSON response; declining returns 204 No Content.
+func RespondToInvitationHandler(socialService *services.SocialService, accept bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the invitation ID from the URL path variables
+		vars := mux.Vars(r)
+		invitationID := vars["id"]
+
+		// Answer the invitation using the social service
+		game, err := socialService.RespondToInvitation(invitationID, auth.FromRequest(r).PlayerName, accept)
+		if errors.Is(err, services.ErrGameFull) {
+			// Return a 409 Conflict status if the game has no free seats
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			// Return a 400 Bad Request status if the invitation cannot be answered
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Return a 204 No Content status if the invitation was declined
+		if game == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		// Hide the hands the caller is not allowed to see
+		game.RedactFor(viewerFromRequest(r))
+
+		// Link the actions available in the game's current state and show which players are online
+		annotateGame(game)
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the joined game as JSON and write it to the response
+		json.NewEncoder(w).Encode(game)
+	}
+}
+
+// ListNotificationsHandler handles the HTTP request to list the caller's most recent notifications.
+// The notifications are returned as a JSON response.
+func ListNotificationsHandler(socialService *services.SocialService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Retrieve the notifications using the social service
+		notifications, err := socialService.ListNotifications(auth.FromRequest(r).PlayerName)
+		if err != nil {
+			// Return a 500 Internal Server Error status if retrieving the notifications fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the notifications as JSON and write them to the response
+		json.NewEncoder(w).Encode(notifications)
+	}
+}
+
+// StreamNotificationsHandler handles the HTTP request to receive the caller's notifications in real time using
+// server-sent events. Each friend request, accepted request, and game invitation is sent as a "notification" event
+// whose data is the JSON-encoded notification.
+func StreamNotificationsHandler(hub *services.NotificationHub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Server-sent events need a response that can be flushed after every event
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		// The stream stays open indefinitely, so lift the server's write timeout for this response
+		http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+		// Subscribe to the caller's notifications for as long as the client stays connected
+		notifications, unsubscribe := hub.Subscribe(auth.FromRequest(r).PlayerName)
+		defer unsubscribe()
+
+		// Set the response headers for an event stream
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		keepAlive := time.NewTicker(streamKeepAlive)
+		defer keepAlive.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-keepAlive.C:
+				fmt.Fprint(w, ": keep-alive\n\n")
+				flusher.Flush()
+			case notification, ok := <-notifications:
+				if !ok {
+					return
+				}
+				writeNotification(w, notification)
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// writeNotification writes one notification as a server-sent event.
+func writeNotification(w http.ResponseWriter, notification models.Notification) {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: notification\ndata: %s\n\n", data)
+}
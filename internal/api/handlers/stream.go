@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"my-card-game/internal/api/services"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// streamKeepAlive is how often a comment line is sent on an idle stream so proxies keep the connection open.
+const streamKeepAlive = 30 * time.Second
+
+// StreamGameHandler handles the HTTP request to follow a game's updates in real time using server-sent events.
+// Every change to the game is sent as a "game" event whose data is the JSON-encoded update,
+// with the hands the caller is not allowed to see removed.
+// A seated player's open stream is their connection to the game: when their last stream closes they are
+// marked as disconnected, and opening a stream again within the grace period resumes their seat.
+func StreamGameHandler(hub *services.UpdateHub, gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Server-sent events need a response that can be flushed after every event
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		// The stream stays open indefinitely, so lift the server's write timeout for this response
+		http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+		// Subscribe to the game's updates for as long as the client stays connected
+		updates, unsubscribe := hub.Subscribe(gameID)
+		defer unsubscribe()
+
+		// Set the response headers for an event stream
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		viewer := viewerFromRequest(r)
+
+		// Track the connection of a seated player; spectators have no seat to hold
+		if viewer.PlayerName != "" {
+			connected, err := gameService.ConnectPlayer(gameID, viewer.PlayerName)
+			if err != nil {
+				log.Printf("could not record the connection of %s to game %s: %v", viewer.PlayerName, gameID, err)
+			}
+			if connected {
+				defer func() {
+					if err := gameService.DisconnectPlayer(gameID, viewer.PlayerName); err != nil {
+						log.Printf("could not record the disconnection of %s from game %s: %v", viewer.PlayerName, gameID, err)
+					}
+				}()
+			}
+		}
+
+		keepAlive := time.NewTicker(streamKeepAlive)
+		defer keepAlive.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-keepAlive.C:
+				fmt.Fprint(w, ": keep-alive\n\n")
+				flusher.Flush()
+			case update, ok := <-updates:
+				if !ok {
+					return
+				}
+
+				// Hide the hands the caller is not allowed to see on a copy, since every subscriber shares the
+				// update; RedactFor replaces the copy's hand maps rather than deleting from the shared ones
+				if update.Game != nil {
+					game := *update.Game
+					game.RedactFor(viewer)
+					game.Presence = game.PlayerPresence(time.Now().UTC(), presenceTimeout)
+					update.Game = &game
+				}
+
+				data, err := json.Marshal(update)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "event: game\ndata: %s\n\n", data)
+				flusher.Flush()
+			}
+		}
+	}
+}
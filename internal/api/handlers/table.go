@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/validate"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// GetSeatingHandler handles the HTTP request to see the seating arrangement of a game's table, for clients that
+// draw the table. Every seat is listed in order with its player or reservation, and the seats holding the dealer
+// button and posting the blinds are marked in a running game. The seats are returned as a JSON response.
+func GetSeatingHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Retrieve the seating arrangement using the game service
+		seats, err := gameService.GetSeating(gameID)
+		if err != nil {
+			// Return a 404 Not Found status if the game does not exist
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the seats as JSON and write them to the response
+		json.NewEncoder(w).Encode(seats)
+	}
+}
+
+// ChangeSeatHandler handles the HTTP request for a seated player to move to another seat while the game is in
+// the lobby. The seat must be open or reserved for the player. The updated game is returned as a JSON response.
+func ChangeSeatHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID and seat number from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+		seat, err := strconv.Atoi(vars["seat"])
+		if err != nil {
+			// Return a 400 Bad Request status if the seat is not a number
+			http.Error(w, "invalid seat number", http.StatusBadRequest)
+			return
+		}
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName string `json:"player_name" validate:"player,max=32"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Take the action as the caller's player
+		player, err := actingPlayer(r, req.PlayerName)
+		if err != nil {
+			writeActingPlayerError(w, err)
+			return
+		}
+
+		// Move the player using the game service
+		game, err := gameService.ChangeSeat(gameID, player, seat)
+		if err != nil {
+			// Return a 409 Conflict status if the seat is taken or held for someone else, or 400 for other errors
+			http.Error(w, err.Error(), seatErrorStatus(err, http.StatusBadRequest))
+			return
+		}
+
+		writeSeatedGame(w, r, game)
+	}
+}
+
+// ReserveSeatHandler handles the HTTP request for the game's owner to hold a seat for a player who has not joined
+// yet. The player is given the seat when they join. The updated game is returned as a JSON response.
+func ReserveSeatHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID and seat number from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+		seat, err := strconv.Atoi(vars["seat"])
+		if err != nil {
+			// Return a 400 Bad Request status if the seat is not a number
+			http.Error(w, "invalid seat number", http.StatusBadRequest)
+			return
+		}
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName string `json:"player_name" validate:"required,player,max=32"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Reserve the seat using the game service
+		game, err := gameService.ReserveSeat(gameID, seat, req.PlayerName, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller does not own the game
+			http.Error(w, "only the game owner can reserve seats", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			// Return a 409 Conflict status if the seat is taken or held for someone else, or 400 for other errors
+			http.Error(w, err.Error(), seatErrorStatus(err, http.StatusBadRequest))
+			return
+		}
+
+		writeSeatedGame(w, r, game)
+	}
+}
+
+// ReleaseSeatHandler handles the HTTP request to drop the reservation on a seat, opening it to anyone. The game's
+// owner or the player the seat is held for can release it. The updated game is returned as a JSON response.
+func ReleaseSeatHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID and seat number from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+		seat, err := strconv.Atoi(vars["seat"])
+		if err != nil {
+			// Return a 400 Bad Request status if the seat is not a number
+			http.Error(w, "invalid seat number", http.StatusBadRequest)
+			return
+		}
+
+		// Release the seat using the game service
+		game, err := gameService.ReleaseSeat(gameID, seat, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller neither owns the game nor holds the seat
+			http.Error(w, "only the game owner or the player the seat is held for can release it", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			// Return a 400 Bad Request status if the seat is not reserved
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		writeSeatedGame(w, r, game)
+	}
+}
+
+// writeSeatedGame writes a game whose seating has changed, hiding the hands the caller may not see.
+func writeSeatedGame(w http.ResponseWriter, r *http.Request, game *models.Game) {
+	// Hide the hands the caller is not allowed to see
+	game.RedactFor(viewerFromRequest(r))
+
+	// Link the actions available in the game's current state and show which players are online
+	annotateGame(game)
+
+	// Set the response header to indicate JSON content
+	w.Header().Set("Content-Type", "application/json")
+
+	// Encode the updated game as JSON and write it to the response
+	json.NewEncoder(w).Encode(game)
+}
+
+// seatErrorStatus returns the status for an error taking or reserving a seat: 400 Bad Request for a seat that is
+// not at the table, 409 Conflict for one that is taken or held for someone else, and the given status for
+// anything else.
+func seatErrorStatus(err error, status int) int {
+	switch {
+	case errors.Is(err, models.ErrNoSuchSeat):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrSeatTaken), errors.Is(err, models.ErrSeatReserved), errors.Is(err, models.ErrTableFull):
+		return http.StatusConflict
+	default:
+		return status
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// turnResponse describes a game's turn order: whose turn it is, the players taking the turns after theirs,
+// and the queue those turns are taken from.
+type turnResponse struct {
+	Player       string   `json:"player"`
+	Next         []string `json:"next"`
+	Direction    string   `json:"direction"`
+	PendingSkips int      `json:"pending_skips"`
+	Order        []string `json:"order"`
+}
+
+// newTurnResponse builds the response for a turn order, looking ahead one full round of turns.
+func newTurnResponse(turn *models.TurnOrder) turnResponse {
+	return turnResponse{
+		Player:       turn.Player(),
+		Next:         turn.Next(len(turn.Queue) - 1),
+		Direction:    turn.Direction,
+		PendingSkips: turn.PendingSkips,
+		Order:        turn.Queue,
+	}
+}
+
+// GetTurnHandler handles the HTTP request to see whose turn it is in a game and who plays next.
+// The turn order is returned as a JSON response.
+func GetTurnHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Retrieve the turn order using the game service
+		turn, err := gameService.GetTurn(gameID)
+		if errors.Is(err, services.ErrNoTurnOrder) {
+			// Return a 409 Conflict status if the game is not being played in turns
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			// Return a 404 Not Found status if the game does not exist
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the turn order as JSON and write it to the response
+		json.NewEncoder(w).Encode(newTurnResponse(turn))
+	}
+}
+
+// SkipTurnHandler handles the HTTP request for the game's owner to skip the next player's turn.
+// The updated turn order is returned as a JSON response.
+func SkipTurnHandler(gameService *services.GameService) http.HandlerFunc {
+	return changeTurnHandler(gameService.SkipTurn)
+}
+
+// ReverseTurnHandler handles the HTTP request for the game's owner to reverse the direction of play.
+// The updated turn order is returned as a JSON response.
+func ReverseTurnHandler(gameService *services.GameService) http.HandlerFunc {
+	return changeTurnHandler(gameService.ReverseTurn)
+}
+
+// changeTurnHandler builds the handler shared by the skip and reverse endpoints.
+func changeTurnHandler(change func(gameID string, viewer models.Viewer) (*models.TurnOrder, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Change the turn order on behalf of the caller
+		turn, err := change(gameID, viewerFromRequest(r))
+		if errors.Is(err, services.ErrForbidden) {
+			// Return a 403 Forbidden status if the caller does not own the game
+			http.Error(w, "only the game owner can change the turn order", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			// Return a 409 Conflict status if the game is not being played in turns
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the updated turn order as JSON and write it to the response
+		json.NewEncoder(w).Encode(newTurnResponse(turn))
+	}
+}
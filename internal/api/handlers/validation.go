@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/validate"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// pathIDVars lists the path variables that always hold MongoDB ObjectIDs.
+var pathIDVars = []string{"id", "key_id", "job_id"}
+
+// writeValidationError writes a 400 Bad Request response listing the fields that failed validation.
+func writeValidationError(w http.ResponseWriter, err error) {
+	var fields validate.Errors
+	if !errors.As(err, &fields) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set the response header to indicate JSON content
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+
+	// Encode the field errors as JSON and write them to the response
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":  "invalid request",
+		"fields": fields,
+	})
+}
+
+// writeActionError writes the error of a rejected game action. An action that breaks the game's rules gets a
+// 422 Unprocessable Entity response listing the rules it breaks, and one aborted by the game's integrity check gets
+// a 500 Internal Server Error; any other error is written with the given status.
+func writeActionError(w http.ResponseWriter, err error, status int) {
+	// A change that broke the game's integrity was aborted and has raised an alert; its details stay server-side
+	var integrity *models.IntegrityError
+	if errors.As(err, &integrity) {
+		http.Error(w, "the action was aborted because it would leave the game in an inconsistent state", http.StatusInternalServerError)
+		return
+	}
+
+	var violations models.Violations
+	if !errors.As(err, &violations) {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	// Set the response header to indicate JSON content
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+
+	// Encode the violations as JSON and write them to the response
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":      "action not allowed",
+		"violations": violations,
+	})
+}
+
+// writeDeckError writes the error of a rejected deck. A deck that failed its integrity check gets a 422
+// Unprocessable Entity response listing every problem found; any other error is written with the given status.
+func writeDeckError(w http.ResponseWriter, err error, status int) {
+	var problems models.DeckError
+	if !errors.As(err, &problems) {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	// Set the response header to indicate JSON content
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+
+	// Encode the problems as JSON and write them to the response
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":    "invalid deck",
+		"problems": problems,
+	})
+}
+
+// ValidatePathIDs is middleware that rejects requests whose ID path variables are not well-formed ObjectIDs,
+// so malformed IDs are reported consistently before any handler runs.
+func ValidatePathIDs(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		for _, name := range pathIDVars {
+			if value, ok := vars[name]; ok {
+				if err := validate.Field(name, value, "required,objectid"); err != nil {
+					writeValidationError(w, err)
+					return
+				}
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/validate"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// JoinWaitlistHandler handles the HTTP request for a player to wait for a seat at a full game. The player is
+// seated automatically, and notified, once a seat opens for them. Their place in the queue is returned as a
+// JSON response.
+func JoinWaitlistHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			PlayerName string `json:"player_name" validate:"player,max=32"`
+			Password   string `json:"password" validate:"max=72"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Take the action as the caller's player
+		player, err := actingPlayer(r, req.PlayerName)
+		if err != nil {
+			writeActingPlayerError(w, err)
+			return
+		}
+
+		// Join the waitlist using the game service
+		status, err := gameService.JoinWaitlist(gameID, player, req.Password)
+		if errors.Is(err, services.ErrWrongPassword) {
+			// Return a 403 Forbidden status if the game's password was not supplied or does not match
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, services.ErrSeatsOpen) || errors.Is(err, services.ErrAlreadySeated) {
+			// Return a 409 Conflict status if the player can join the game directly or already has a seat
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			// Return a 400 Bad Request status if the player cannot wait for the game
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the player's place in the queue as JSON and write it to the response
+		json.NewEncoder(w).Encode(status)
+	}
+}
+
+// GetWaitlistPositionHandler handles the HTTP request to see a player's place in a game's waitlist.
+// The player defaults to the caller. Their place is returned as a JSON response.
+func GetWaitlistPositionHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Retrieve the player's place using the game service
+		status, err := gameService.GetWaitlistPosition(gameID, playerOrCaller(r, r.URL.Query().Get("player_name")))
+		if err != nil {
+			// Return a 404 Not Found status if the game does not exist or the player is not waiting for it
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the player's place in the queue as JSON and write it to the response
+		json.NewEncoder(w).Encode(status)
+	}
+}
+
+// LeaveWaitlistHandler handles the HTTP request for a player to stop waiting for a seat at a game.
+// The player defaults to the caller. It responds with 204 No Content once the player has left the queue.
+func LeaveWaitlistHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Take the action as the caller's player
+		player, err := actingPlayer(r, r.URL.Query().Get("player_name"))
+		if err != nil {
+			writeActingPlayerError(w, err)
+			return
+		}
+
+		// Leave the waitlist using the game service
+		if err := gameService.LeaveWaitlist(gameID, player); err != nil {
+			// Return a 404 Not Found status if the game does not exist or the player is not waiting for it
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
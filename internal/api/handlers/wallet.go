@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/auth"
+	"my-card-game/internal/validate"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// GetWalletHandler handles the HTTP request to retrieve the caller's wallet.
+// The balance and the most recent transactions are returned as a JSON response.
+func GetWalletHandler(walletService *services.WalletService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Retrieve the caller's wallet using the wallet service
+		statement, err := walletService.GetWallet(auth.FromRequest(r).PlayerName)
+		if err != nil {
+			// Return a 500 Internal Server Error status if retrieving the wallet fails
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the wallet as JSON and write it to the response
+		json.NewEncoder(w).Encode(statement)
+	}
+}
+
+// BuyInHandler handles the HTTP request for the caller to buy chips in a game they are seated in with chips from
+// their wallet. It decodes the amount from the request payload and returns the updated wallet as a JSON response.
+func BuyInHandler(walletService *services.WalletService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			Amount int `json:"amount" validate:"required"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Move the chips from the wallet to the table using the wallet service
+		wallet, err := walletService.BuyIn(gameID, auth.FromRequest(r).PlayerName, req.Amount)
+		if errors.Is(err, services.ErrInsufficientFunds) {
+			// Return a 409 Conflict status if the wallet cannot cover the buy-in
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			// Return a 400 Bad Request status if the buy-in fails
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the updated wallet as JSON and write it to the response
+		json.NewEncoder(w).Encode(wallet)
+	}
+}
+
+// CashOutHandler handles the HTTP request for the caller to move their whole stack in a game back into their wallet.
+// The updated wallet is returned as a JSON response.
+func CashOutHandler(walletService *services.WalletService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Move the chips from the table to the wallet using the wallet service
+		wallet, err := walletService.CashOut(gameID, auth.FromRequest(r).PlayerName)
+		if err != nil {
+			// Return a 400 Bad Request status if the cash-out fails
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the updated wallet as JSON and write it to the response
+		json.NewEncoder(w).Encode(wallet)
+	}
+}
+
+// AdminCreditWalletHandler handles the HTTP request to grant chips to a player's wallet.
+// It decodes the amount and an optional note from the request payload and returns the updated wallet as a JSON response.
+func AdminCreditWalletHandler(walletService *services.WalletService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the player's name from the URL path variables
+		vars := mux.Vars(r)
+		playerName := vars["name"]
+
+		// Validate the player's name
+		if err := validate.Field("name", playerName, "player,max=32"); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Define a struct to capture the incoming request payload
+		var req struct {
+			Amount int    `json:"amount" validate:"required"`
+			Note   string `json:"note" validate:"max=200"`
+		}
+
+		// Decode the JSON request body into the req struct
+		if err := decodeJSON(r, &req); err != nil {
+			// Return a 400 Bad Request status if the payload is invalid, or 413 if it is too large
+			writeDecodeError(w, err)
+			return
+		}
+
+		// Check the payload's fields before passing them to the service
+		if err := validate.Struct(req); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+
+		// Credit the wallet using the wallet service
+		wallet, err := walletService.Credit(playerName, req.Amount, req.Note)
+		if err != nil {
+			// Return a 400 Bad Request status if the credit fails
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the updated wallet as JSON and write it to the response
+		json.NewEncoder(w).Encode(wallet)
+	}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"encoding/json"
+	"my-card-game/internal/api/services"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// PlayWarBattleHandler handles the HTTP request to play the next battle of a War game.
+// The server flips both players' top cards, resolves any wars, and returns the battle result as a JSON response.
+func PlayWarBattleHandler(gameService *services.GameService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the game ID from the URL path variables
+		vars := mux.Vars(r)
+		gameID := vars["id"]
+
+		// Resolve the battle using the game service
+		result, err := gameService.PlayWarBattle(gameID)
+		if err != nil {
+			// Return a 422 Unprocessable Entity status if the battle breaks the rules, or a 500 Internal Server Error status if it cannot be played
+			writeActionError(w, err, http.StatusInternalServerError)
+			return
+		}
+
+		// Set the response header to indicate JSON content
+		w.Header().Set("Content-Type", "application/json")
+
+		// Encode the battle result as JSON and write it to the response
+		json.NewEncoder(w).Encode(result)
+	}
+}
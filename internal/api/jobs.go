@@ -0,0 +1,71 @@
+package api
+
+import (
+	"my-card-game/internal/config"
+	"my-card-game/internal/scheduler"
+)
+
+// registerJobs registers the background jobs the server runs on the scheduler.
+func registerJobs(jobs *scheduler.Scheduler, cfg *config.Config, svc *Services) error {
+	for _, job := range []scheduler.Job{
+		{
+			// Flag or remove players who have stopped acting
+			Name:     "inactivity-check",
+			Interval: cfg.InactivityCheckInterval,
+			Run: func() (int, error) {
+				return svc.Game.CheckInactivity(cfg.InactivityWindow, cfg.InactivityAction)
+			},
+		},
+		{
+			// Give up the seats of players who dropped out and did not return in time
+			Name:     "forfeit-disconnected-players",
+			Interval: cfg.InactivityCheckInterval,
+			Run: func() (int, error) {
+				return svc.Game.ForfeitDisconnectedPlayers(cfg.ReconnectGracePeriod)
+			},
+		},
+		{
+			// Make the moves of bots whose turn it is
+			Name:     "bot-turns",
+			Interval: cfg.BotTurnInterval,
+			Run:      svc.Game.PlayBotTurns,
+		},
+		{
+			// Alert players whose move a game has been waiting on
+			Name:     "turn-alerts",
+			Interval: cfg.TurnAlertCheckInterval,
+			Run: func() (int, error) {
+				return svc.Social.CheckTurnAlerts(cfg.TurnAlertDelay)
+			},
+		},
+		{
+			// Send recorded events to the message bus, if one is configured
+			Name:     "publish-events",
+			Interval: cfg.EventPublishInterval,
+			Run:      svc.Game.PublishEvents,
+		},
+		{
+			// Run the long-running work queued through the API
+			Name:     "run-queued-jobs",
+			Interval: cfg.JobPollInterval,
+			Run:      svc.Queue.RunQueuedJobs,
+		},
+		{
+			// Delete sessions that have expired
+			Name:     "purge-expired-sessions",
+			Interval: cfg.SessionPurgeInterval,
+			Run:      svc.Sessions.PurgeExpiredSessions,
+		},
+		{
+			// Move finished games out of the hot collections
+			Name:     "archive-finished-games",
+			Interval: cfg.ArchiveInterval,
+			Run:      svc.Game.ArchiveFinishedGames,
+		},
+	} {
+		if err := jobs.Register(job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Achievement identifiers.
+const (
+	AchievementFirstWin   = "first_win"
+	AchievementRoyalFlush = "royal_flush"
+	AchievementCenturion  = "centurion"
+)
+
+// CenturionGames is the number of finished games that unlocks the centurion achievement.
+const CenturionGames = 100
+
+// Achievement describes a milestone players can unlock.
+type Achievement struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Achievements lists every achievement that can be unlocked, in the order they are shown to players.
+var Achievements = []Achievement{
+	{ID: AchievementFirstWin, Name: "First win", Description: "Win a game"},
+	{ID: AchievementRoyalFlush, Name: "Royal flush", Description: "Be dealt the ace, king, queen, jack, and ten of one suit"},
+	{ID: AchievementCenturion, Name: "Centurion", Description: "Finish 100 games"},
+}
+
+// PlayerAchievement records an achievement a player has unlocked and the game they unlocked it in.
+// Its ID combines the player and the achievement, so each achievement can only be unlocked once per player.
+type PlayerAchievement struct {
+	ID          string             `bson:"_id" json:"-"`
+	PlayerName  string             `bson:"player_name" json:"player_name"`
+	Achievement string             `bson:"achievement" json:"achievement"`
+	GameID      primitive.ObjectID `bson:"game_id,omitempty" json:"game_id,omitempty"`
+	UnlockedAt  time.Time          `bson:"unlocked_at" json:"unlocked_at"`
+}
+
+// HasRoyalFlush reports whether the hand holds the ace, king, queen, jack, and ten of a single suit.
+func HasRoyalFlush(hand []Card) bool {
+	ranks := map[Suit]map[Rank]bool{}
+	for _, card := range hand {
+		if ranks[card.Suit] == nil {
+			ranks[card.Suit] = map[Rank]bool{}
+		}
+		ranks[card.Suit][card.Value] = true
+	}
+
+	for _, held := range ranks {
+		if held[RankAce] && held[RankKing] && held[RankQueen] && held[RankJack] && held[RankTen] {
+			return true
+		}
+	}
+	return false
+}
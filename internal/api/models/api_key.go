@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIKey represents an administrative API key.
+// Only a SHA-256 hash of the key is stored; the prefix is kept so operators can tell keys apart.
+// A key belongs to an organization and only grants admin rights within it; keys of the default organization
+// are platform keys, which grant them in every organization.
+type APIKey struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name      string             `bson:"name" json:"name"`
+	OrgID     string             `bson:"org_id,omitempty" json:"org_id,omitempty"`
+	Prefix    string             `bson:"prefix" json:"prefix"`
+	KeyHash   string             `bson:"key_hash" json:"-"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
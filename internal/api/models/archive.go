@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ArchivedGame is a finished game moved out of the games collection together with its event history.
+// When archives are compressed, the game and events are stored gzipped in Payload instead of in their own fields.
+type ArchivedGame struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	Game       *Game              `bson:"game,omitempty" json:"game"`
+	Events     []Event            `bson:"events,omitempty" json:"events"`
+	Compressed bool               `bson:"compressed" json:"compressed"`   // Whether the game and events are stored gzipped in Payload
+	Payload    []byte             `bson:"payload,omitempty" json:"-"`     // Gzipped BSON of the game and events when compressed
+	ArchivedAt time.Time          `bson:"archived_at" json:"archived_at"` // When the game was moved to the archive
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Actions recorded in the audit log.
+const (
+	AuditGameDeleted      = "game_deleted"
+	AuditGameForceDeleted = "game_force_deleted"
+	AuditGameForceEnded   = "game_force_ended"
+	AuditGameReset        = "game_reset"
+	AuditDeckShuffled     = "deck_shuffled"
+	AuditDeckPeeked       = "deck_peeked"
+	AuditRolledBack       = "rolled_back"
+	AuditPlayerKicked     = "player_kicked"
+	AuditPlayerBanned     = "player_banned"
+	AuditPenaltyDraw      = "penalty_draw"
+	AuditAPIKeyCreated    = "api_key_created"
+	AuditAPIKeyDeleted    = "api_key_deleted"
+	AuditIntegrity        = "integrity_violation"
+	AuditOrgCreated       = "org_created"
+)
+
+// AuditActorAdmin names the actor of actions taken with an admin API key rather than a player session.
+const AuditActorAdmin = "admin"
+
+// AuditEntry records an administrative or destructive action: who took it, when, what it was taken against,
+// and summaries of the affected state before and after.
+// Unlike game events, audit entries are kept when the game they describe is deleted or archived.
+type AuditEntry struct {
+	ID        primitive.ObjectID     `bson:"_id" json:"id"`
+	Action    string                 `bson:"action" json:"action"`
+	Actor     string                 `bson:"actor" json:"actor"`                         // Player who took the action, or AuditActorAdmin
+	Admin     bool                   `bson:"admin" json:"admin"`                         // Whether the actor had admin rights
+	GameID    *primitive.ObjectID    `bson:"game_id,omitempty" json:"game_id,omitempty"` // Game the action was taken on, if any
+	Target    string                 `bson:"target,omitempty" json:"target,omitempty"`   // Player, snapshot, API key, or organization acted on, if any
+	Before    map[string]interface{} `bson:"before,omitempty" json:"before,omitempty"`   // Summary of the state before the action
+	After     map[string]interface{} `bson:"after,omitempty" json:"after,omitempty"`     // Summary of the state after the action
+	Reason    string                 `bson:"reason,omitempty" json:"reason,omitempty"`
+	CreatedAt time.Time              `bson:"created_at" json:"created_at"`
+}
+
+// AuditActor returns the name recorded as the actor of an action taken by the viewer.
+func AuditActor(viewer Viewer) string {
+	if viewer.PlayerName == "" && viewer.Admin {
+		return AuditActorAdmin
+	}
+	return viewer.PlayerName
+}
+
+// AuditSummary summarises the parts of a game an audited action can change, without recording any cards.
+func (g *Game) AuditSummary() map[string]interface{} {
+	handSizes := map[string]int{}
+	for player, hand := range g.PlayerHands {
+		handSizes[player] = len(hand)
+	}
+	return map[string]interface{}{
+		"name":        g.Name,
+		"status":      g.Status,
+		"players":     append([]string{}, g.Players...),
+		"banned":      append([]string{}, g.Banned...),
+		"deck_size":   len(g.GameDeck),
+		"hand_sizes":  handSizes,
+		"hand_number": g.HandNumber,
+	}
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+// Strains a contract can be bid in, from lowest to highest.
+const (
+	StrainClubs    = "clubs"
+	StrainDiamonds = "diamonds"
+	StrainHearts   = "hearts"
+	StrainSpades   = "spades"
+	StrainNoTrump  = "notrump"
+)
+
+// strainRanks orders the strains, so a bid at the same level only outranks one in a lower strain.
+var strainRanks = map[string]int{
+	StrainClubs:    1,
+	StrainDiamonds: 2,
+	StrainHearts:   3,
+	StrainSpades:   4,
+	StrainNoTrump:  5,
+}
+
+// The lowest and highest levels a contract can be bid at: the tricks bid for beyond the first six.
+const (
+	MinBidLevel = 1
+	MaxBidLevel = 7
+)
+
+// Bid is one call in an auction: either a pass, or a bid to take Level tricks beyond six with Strain as trumps.
+type Bid struct {
+	Player   string    `bson:"player" json:"player"`
+	Pass     bool      `bson:"pass" json:"pass"`
+	Level    int       `bson:"level,omitempty" json:"level,omitempty"`
+	Strain   string    `bson:"strain,omitempty" json:"strain,omitempty"`
+	PlacedAt time.Time `bson:"placed_at" json:"placed_at"`
+}
+
+// String describes the bid, such as "pass" or "3 hearts".
+func (b Bid) String() string {
+	if b.Pass {
+		return "pass"
+	}
+	return strconv.Itoa(b.Level) + " " + b.Strain
+}
+
+// Outranks reports whether the bid is higher than another: at a higher level, or at the same level in a higher strain.
+func (b Bid) Outranks(other Bid) bool {
+	if b.Level != other.Level {
+		return b.Level > other.Level
+	}
+	return strainRanks[b.Strain] > strainRanks[other.Strain]
+}
+
+// IsValidStrain reports whether the strain is one a contract can be bid in.
+func IsValidStrain(strain string) bool {
+	_, ok := strainRanks[strain]
+	return ok
+}
+
+// Contract is the outcome of an auction: the final bid and the player who will play it.
+type Contract struct {
+	Level    int    `bson:"level" json:"level"`
+	Strain   string `bson:"strain" json:"strain"`
+	Declarer string `bson:"declarer" json:"declarer"`             // First player on the winning side to bid the contract's strain
+	Team     string `bson:"team,omitempty" json:"team,omitempty"` // Declarer's team, in a team game
+}
+
+// Auction is the bidding phase that decides a hand's contract, as in Bridge or Spades-style games.
+// Players call in turn, each either passing or bidding higher than the last bid. The auction closes once every
+// other player has passed in a row after a bid, or everyone has passed without bidding, which passes the hand out.
+type Auction struct {
+	Turn     TurnOrder `bson:"turn" json:"turn"`                             // Whose call it is
+	Bids     []Bid     `bson:"bids" json:"bids"`                             // Every call so far, passes included, in order
+	Passes   int       `bson:"passes" json:"passes"`                         // Passes in a row since the last bid, or since the start
+	Closed   bool      `bson:"closed" json:"closed"`                         // Whether the auction is over
+	Contract *Contract `bson:"contract,omitempty" json:"contract,omitempty"` // Contract won, once closed; nil if the hand was passed out
+}
+
+// NewAuction opens an auction among the players, with the player at index first calling first.
+func NewAuction(players []string, first int) *Auction {
+	return &Auction{Turn: *NewTurnOrder(players, first), Bids: []Bid{}}
+}
+
+// HighestBid returns the highest bid made so far and whether anyone has bid.
+func (a *Auction) HighestBid() (Bid, bool) {
+	for i := len(a.Bids) - 1; i >= 0; i-- {
+		if !a.Bids[i].Pass {
+			return a.Bids[i], true
+		}
+	}
+	return Bid{}, false
+}
+
+// PlaceBid applies a call the rules have checked to the game's auction, and closes the auction and determines the
+// contract once the call ends it. In a team game the declarer is the first player on the high bidder's team to have
+// bid the contract's strain; otherwise it is the high bidder.
+func (g *Game) PlaceBid(bid Bid) {
+	a := g.Auction
+	a.Bids = append(a.Bids, bid)
+	if bid.Pass {
+		a.Passes++
+	} else {
+		a.Passes = 0
+	}
+	a.Turn.Advance()
+
+	highest, anyBid := a.HighestBid()
+	switch {
+	case anyBid && a.Passes >= len(a.Turn.Queue)-1:
+		a.Closed = true
+		a.Contract = &Contract{Level: highest.Level, Strain: highest.Strain, Declarer: highest.Player}
+		for _, earlier := range a.Bids {
+			if !earlier.Pass && earlier.Strain == highest.Strain &&
+				(earlier.Player == highest.Player || g.sameTeam(earlier.Player, highest.Player)) {
+				a.Contract.Declarer = earlier.Player
+				break
+			}
+		}
+		a.Contract.Team = g.TeamOf(a.Contract.Declarer)
+	case !anyBid && a.Passes >= len(a.Turn.Queue):
+		// Everyone passed without bidding, so the hand is passed out
+		a.Closed = true
+	}
+}
@@ -0,0 +1,372 @@
+package models
+
+import "errors"
+
+// MaxBlackjackHands caps how many hands a player can hold at once by splitting pairs.
+const MaxBlackjackHands = 4
+
+// Statuses of a player's hand in blackjack. A hand is played until it stands, busts, or is surrendered.
+const (
+	HandStatusPlaying     = "playing"
+	HandStatusStood       = "stood"
+	HandStatusBusted      = "busted"
+	HandStatusSurrendered = "surrendered"
+)
+
+// PlayerHand is one of the hands a player holds in a mode where a player can play several hands at once, such as
+// the hands made by splitting a pair in blackjack. Each hand carries its own bet and is settled on its own.
+type PlayerHand struct {
+	Cards   []Card `bson:"cards" json:"cards"`
+	Bet     int    `bson:"bet" json:"bet"`
+	Status  string `bson:"status" json:"status"`
+	Doubled bool   `bson:"doubled,omitempty" json:"doubled,omitempty"` // The bet was doubled for a single last card
+	Split   bool   `bson:"split,omitempty" json:"split,omitempty"`     // The hand was made by splitting a pair, so 21 on it is not a blackjack
+	Outcome string `bson:"outcome,omitempty" json:"outcome,omitempty"` // How the hand fared against the house, once settled
+}
+
+// Value returns the hand's best blackjack total.
+func (h PlayerHand) Value() int {
+	return BlackjackScoring{}.HandValue(h.Cards)
+}
+
+// IsBlackjack reports whether the hand is a natural: 21 with its first two cards, not made by a split.
+func (h PlayerHand) IsBlackjack() bool {
+	return !h.Split && len(h.Cards) == 2 && h.Value() == 21
+}
+
+// DealBlackjack deals a hand of blackjack: two cards to each seated player, one at a time starting to the
+// dealer's left, and two to the house, the second of which stays face down until the house plays. Any bets the
+// players have already placed go on their hands. Players dealt a blackjack stand straight away, and the turn
+// moves on to the first player with a hand to play; if nobody has one, the house plays at once.
+func (g *Game) DealBlackjack() error {
+	if len(g.Players) == 0 {
+		return errors.New("blackjack requires at least one player")
+	}
+	if needed := 2*len(g.Players) + 2; len(g.GameDeck) < needed {
+		return errors.New("not enough cards in the game deck to deal blackjack")
+	}
+
+	// Deal the first card around the table, then the second, with the house taking the last card of each round
+	g.PlayerHands = map[string][]Card{}
+	g.DealerHand = nil
+	for round := 0; round < 2; round++ {
+		for _, player := range g.DealOrder() {
+			g.PlayerHands[player] = append(g.PlayerHands[player], g.GameDeck[0])
+			g.GameDeck = g.GameDeck[1:]
+		}
+		g.DealerHand = append(g.DealerHand, g.GameDeck[0])
+		g.GameDeck = g.GameDeck[1:]
+	}
+
+	g.Hands = map[string][]PlayerHand{}
+	for _, player := range g.Players {
+		hand := PlayerHand{Cards: append([]Card{}, g.PlayerHands[player]...), Bet: g.Bets[player], Status: HandStatusPlaying}
+		if hand.IsBlackjack() {
+			hand.Status = HandStatusStood
+		}
+		g.Hands[player] = []PlayerHand{hand}
+	}
+
+	if g.Turn == nil {
+		g.Turn = NewTurnOrder(g.Players, (g.DealerIndex+1)%len(g.Players))
+	}
+	g.passFinishedBlackjackTurns()
+	return nil
+}
+
+// CurrentHand returns the index of the player's first hand still being played, or -1 if they have none.
+func (g *Game) CurrentHand(playerName string) int {
+	for i, hand := range g.Hands[playerName] {
+		if hand.Status == HandStatusPlaying {
+			return i
+		}
+	}
+	return -1
+}
+
+// Hit deals the next card of the deck to the player's current hand. A hand that goes over 21 busts, and one
+// that reaches 21 stands.
+func (g *Game) Hit(playerName string) Card {
+	index := g.CurrentHand(playerName)
+	card := g.drawToHand(playerName, index)
+	g.finishBlackjackHand(playerName, index, false)
+	return card
+}
+
+// Stand ends play on the player's current hand.
+func (g *Game) Stand(playerName string) {
+	index := g.CurrentHand(playerName)
+	g.finishBlackjackHand(playerName, index, true)
+}
+
+// DoubleDown doubles the bet on the player's current hand, taking the extra chips from their stack, and deals
+// the hand exactly one more card before it stands.
+func (g *Game) DoubleDown(playerName string) Card {
+	index := g.CurrentHand(playerName)
+	hand := &g.Hands[playerName][index]
+	g.commitChips(playerName, hand.Bet)
+	hand.Bet *= 2
+	hand.Doubled = true
+
+	card := g.drawToHand(playerName, index)
+	g.finishBlackjackHand(playerName, index, true)
+	return card
+}
+
+// Split splits the pair of the player's current hand into two hands, each with one of the pair and the same bet,
+// taking the new hand's bet from their stack. Each hand is then dealt a second card, and the split hands are
+// played in turn.
+func (g *Game) Split(playerName string) []Card {
+	index := g.CurrentHand(playerName)
+	hands := g.Hands[playerName]
+	first := hands[index]
+	g.commitChips(playerName, first.Bet)
+
+	second := PlayerHand{Cards: []Card{first.Cards[1]}, Bet: first.Bet, Status: HandStatusPlaying, Split: true}
+	first.Cards = []Card{first.Cards[0]}
+	first.Split = true
+
+	hands = append(hands[:index+1], append([]PlayerHand{second}, hands[index+1:]...)...)
+	hands[index] = first
+	g.Hands[playerName] = hands
+
+	// Deal each of the split hands its second card
+	dealt := []Card{g.drawToHand(playerName, index), g.drawToHand(playerName, index+1)}
+	g.finishBlackjackHand(playerName, index+1, false)
+	g.finishBlackjackHand(playerName, index, false)
+	return dealt
+}
+
+// Surrender gives up the player's hand as their first decision, forfeiting half of its bet.
+func (g *Game) Surrender(playerName string) {
+	index := g.CurrentHand(playerName)
+	g.Hands[playerName][index].Status = HandStatusSurrendered
+	g.finishBlackjackHand(playerName, index, false)
+}
+
+// PlayDealer turns over the house's face-down card and, unless every player's hand has already busted or been
+// surrendered, draws to the house hand until it reaches 17 or more. The hand then moves to the showdown.
+func (g *Game) PlayDealer() {
+	contested := false
+	for _, hands := range g.Hands {
+		for _, hand := range hands {
+			if hand.Status == HandStatusStood {
+				contested = true
+			}
+		}
+	}
+
+	// The house stands on every 17
+	scoring := BlackjackScoring{}
+	for contested && scoring.HandValue(g.DealerHand) < 17 && len(g.GameDeck) > 0 {
+		g.DealerHand = append(g.DealerHand, g.GameDeck[0])
+		g.GameDeck = g.GameDeck[1:]
+	}
+	g.HandPhase = HandPhaseShowdown
+}
+
+// TakeInsurance stakes a side bet that the house's face-down card makes a blackjack, taking the chips from the
+// player's stack. The stake is settled with the hand.
+func (g *Game) TakeInsurance(playerName string, amount int) {
+	g.commitChips(playerName, amount)
+	if g.Insurance == nil {
+		g.Insurance = map[string]int{}
+	}
+	g.Insurance[playerName] += amount
+}
+
+// InsurancePayout returns the chips an insurance stake returns to the player, stake included: 2:1 if the house
+// hand is a blackjack, and nothing otherwise.
+func (g *Game) InsurancePayout(amount int) int {
+	if (PlayerHand{Cards: g.DealerHand}).IsBlackjack() {
+		return 3 * amount
+	}
+	return 0
+}
+
+// DealerUpCard returns the house's face-up card and whether the house has been dealt one.
+func (g *Game) DealerUpCard() (Card, bool) {
+	if len(g.DealerHand) == 0 {
+		return Card{}, false
+	}
+	return g.DealerHand[0], true
+}
+
+// drawToHand moves the top card of the deck into one of the player's hands, keeping PlayerHands holding every
+// card the player has across their hands.
+func (g *Game) drawToHand(playerName string, index int) Card {
+	card := g.GameDeck[0]
+	g.GameDeck = g.GameDeck[1:]
+	g.Hands[playerName][index].Cards = append(g.Hands[playerName][index].Cards, card)
+	g.syncPlayerHand(playerName)
+	return card
+}
+
+// finishBlackjackHand settles the status of one of the player's hands after a move: a hand over 21 busts, and one
+// that reaches 21 or that the player chose to stop on stands. Once the player has no hands left to play, the turn
+// passes on.
+func (g *Game) finishBlackjackHand(playerName string, index int, stand bool) {
+	hand := &g.Hands[playerName][index]
+	if hand.Status == HandStatusPlaying {
+		switch value := hand.Value(); {
+		case value > 21:
+			hand.Status = HandStatusBusted
+		case value == 21 || stand:
+			hand.Status = HandStatusStood
+		}
+	}
+	g.syncPlayerHand(playerName)
+
+	if g.CurrentHand(playerName) == -1 {
+		g.passFinishedBlackjackTurns()
+	}
+}
+
+// passFinishedBlackjackTurns moves the turn on past every player without a hand to play. Once no player has one,
+// the house plays its hand.
+func (g *Game) passFinishedBlackjackTurns() {
+	if g.Turn == nil {
+		return
+	}
+	for range g.Turn.Queue {
+		if g.CurrentHand(g.Turn.Player()) != -1 {
+			return
+		}
+		g.Turn.Advance()
+	}
+	if g.CurrentHand(g.Turn.Player()) == -1 {
+		g.PlayDealer()
+	}
+}
+
+// commitChips moves chips from the player's stack into their bets for the hand.
+func (g *Game) commitChips(playerName string, amount int) {
+	if amount == 0 {
+		return
+	}
+	if g.Bets == nil {
+		g.Bets = map[string]int{}
+	}
+	g.Chips[playerName] -= amount
+	g.Bets[playerName] += amount
+}
+
+// syncPlayerHand sets the player's entry in PlayerHands to every card across their hands, so the generic hand
+// endpoints, the shoe count, and clearing the table all see the cards a player holds.
+func (g *Game) syncPlayerHand(playerName string) {
+	cards := []Card{}
+	for _, hand := range g.Hands[playerName] {
+		cards = append(cards, hand.Cards...)
+	}
+	g.PlayerHands[playerName] = cards
+}
+
+// Outcomes of a player's hand against the house at the showdown.
+const (
+	OutcomeBlackjack = "blackjack" // A natural that the house did not match, paid 3:2
+	OutcomeWin       = "win"       // Beat the house, paid 1:1
+	OutcomePush      = "push"      // Tied with the house; the bet is returned
+	OutcomeLose      = "lose"      // Lost to the house, or busted
+	OutcomeSurrender = "surrender" // Surrendered; half the bet is returned
+)
+
+// BlackjackOutcome decides how one of a player's hands fared against the house's hand.
+func (g *Game) BlackjackOutcome(hand PlayerHand) string {
+	house := PlayerHand{Cards: g.DealerHand}
+	switch {
+	case hand.Status == HandStatusSurrendered:
+		return OutcomeSurrender
+	case hand.Status == HandStatusBusted:
+		return OutcomeLose
+	case hand.IsBlackjack() && house.IsBlackjack():
+		return OutcomePush
+	case hand.IsBlackjack():
+		return OutcomeBlackjack
+	case house.IsBlackjack():
+		return OutcomeLose
+	case house.Value() > 21 || hand.Value() > house.Value():
+		return OutcomeWin
+	case hand.Value() == house.Value():
+		return OutcomePush
+	default:
+		return OutcomeLose
+	}
+}
+
+// BlackjackPayout returns the chips a hand's bet returns to the player for an outcome, stake included.
+func BlackjackPayout(bet int, outcome string) int {
+	switch outcome {
+	case OutcomeBlackjack:
+		return bet + bet*3/2
+	case OutcomeWin:
+		return 2 * bet
+	case OutcomePush:
+		return bet
+	case OutcomeSurrender:
+		return bet / 2
+	default:
+		return 0
+	}
+}
+
+// settleBlackjack settles every player's hands and insurance against the house at the showdown, recording each
+// hand's outcome, and adds a line for each bet to the settlement. Hands played without a bet are given an outcome
+// but no line.
+func (g *Game) settleBlackjack(settlement *Settlement) {
+	for _, player := range g.Players {
+		hands := g.Hands[player]
+		for i := range hands {
+			hand := &hands[i]
+			hand.Outcome = g.BlackjackOutcome(*hand)
+			if hand.Bet == 0 {
+				continue
+			}
+			settlement.add(SettlementLine{
+				Kind:    SettleHand,
+				Player:  player,
+				Hand:    i,
+				Stake:   hand.Bet,
+				Outcome: hand.Outcome,
+				Payouts: map[string]int{player: BlackjackPayout(hand.Bet, hand.Outcome)},
+			})
+		}
+
+		if amount := g.Insurance[player]; amount > 0 {
+			outcome := OutcomeLose
+			if payout := g.InsurancePayout(amount); payout > 0 {
+				outcome = OutcomeWin
+			}
+			settlement.add(SettlementLine{
+				Kind:    SettleInsurance,
+				Player:  player,
+				Stake:   amount,
+				Outcome: outcome,
+				Payouts: map[string]int{player: g.InsurancePayout(amount)},
+			})
+		}
+	}
+}
+
+// blackjackValues values every seated player's blackjack hands at the showdown: a player's value is their best
+// total that did not bust, or their first hand's total if every hand busted. The winners are the players with a
+// hand that beat the house.
+func (g *Game) blackjackValues() (map[string]int, []string) {
+	values := map[string]int{}
+	winners := []string{}
+	for _, player := range g.Players {
+		won := false
+		for i, hand := range g.Hands[player] {
+			value := hand.Value()
+			if i == 0 || (value <= 21 && (values[player] > 21 || value > values[player])) {
+				values[player] = value
+			}
+			if outcome := g.BlackjackOutcome(hand); outcome == OutcomeWin || outcome == OutcomeBlackjack {
+				won = true
+			}
+		}
+		if won {
+			winners = append(winners, player)
+		}
+	}
+	return values, winners
+}
@@ -0,0 +1,121 @@
+package models
+
+// BlindLevel represents one step of a blind escalation schedule.
+// The level applies from the given hand number onwards until the next level starts.
+type BlindLevel struct {
+	FromHand   int `bson:"from_hand" json:"from_hand"`
+	SmallBlind int `bson:"small_blind" json:"small_blind"`
+	BigBlind   int `bson:"big_blind" json:"big_blind"`
+}
+
+// CurrentBlinds returns the small and big blind for the current hand.
+// The latest schedule level that has been reached overrides the game's base blind amounts.
+func (g *Game) CurrentBlinds() (int, int) {
+	small, big := g.SmallBlind, g.BigBlind
+	for _, level := range g.BlindSchedule {
+		if level.FromHand <= g.HandNumber {
+			small, big = level.SmallBlind, level.BigBlind
+		}
+	}
+	return small, big
+}
+
+// AdvanceButton moves the dealer button to the next player who still has chips.
+// On the first hand the button stays on the first eligible seat.
+func (g *Game) AdvanceButton() {
+	n := len(g.Players)
+	if n == 0 {
+		return
+	}
+
+	start := g.DealerIndex
+	if g.HandNumber > 1 {
+		start++
+	}
+	g.DealerIndex = g.nextSeatWithChips(start % n)
+}
+
+// BlindSeats returns the seat indexes of the small blind and big blind for the current button.
+// Heads-up, the dealer posts the small blind and the other player posts the big blind.
+func (g *Game) BlindSeats() (int, int) {
+	n := len(g.Players)
+	if g.activeSeatCount() == 2 {
+		return g.DealerIndex, g.nextSeatWithChips((g.DealerIndex + 1) % n)
+	}
+
+	small := g.nextSeatWithChips((g.DealerIndex + 1) % n)
+	big := g.nextSeatWithChips((small + 1) % n)
+	return small, big
+}
+
+// Dealer returns the name of the player holding the dealer button, or an empty string if there are no players.
+func (g *Game) Dealer() string {
+	if g.DealerIndex < 0 || g.DealerIndex >= len(g.Players) {
+		return ""
+	}
+	return g.Players[g.DealerIndex]
+}
+
+// DealOrder returns the seated players in the order cards are dealt: starting with the player to the dealer's left
+// and ending with the dealer.
+func (g *Game) DealOrder() []string {
+	n := len(g.Players)
+	order := make([]string, 0, n)
+	for i := 1; i <= n; i++ {
+		order = append(order, g.Players[(g.DealerIndex+i)%n])
+	}
+	return order
+}
+
+// Unseat removes a player from the seats, keeping the dealer button on the same player, or passing it to the
+// next seat if the dealer is the one leaving. It reports whether the player had a seat.
+func (g *Game) Unseat(playerName string) bool {
+	index := -1
+	players := []string{}
+	for i, player := range g.Players {
+		if player == playerName {
+			index = i
+			continue
+		}
+		players = append(players, player)
+	}
+	if index == -1 {
+		return false
+	}
+	g.Players = players
+	delete(g.SeatNumbers, playerName)
+
+	switch {
+	case len(players) == 0:
+		g.DealerIndex = 0
+	case index < g.DealerIndex:
+		g.DealerIndex--
+	case g.DealerIndex >= len(players):
+		g.DealerIndex = 0
+	}
+	return true
+}
+
+// nextSeatWithChips returns the first seat, starting at the given index, whose player still has chips.
+// If nobody has chips, the starting seat is returned.
+func (g *Game) nextSeatWithChips(from int) int {
+	n := len(g.Players)
+	for i := 0; i < n; i++ {
+		seat := (from + i) % n
+		if g.Chips[g.Players[seat]] > 0 {
+			return seat
+		}
+	}
+	return from
+}
+
+// activeSeatCount returns how many seated players still have chips.
+func (g *Game) activeSeatCount() int {
+	count := 0
+	for _, player := range g.Players {
+		if g.Chips[player] > 0 {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,98 @@
+package models
+
+import "errors"
+
+// Bot difficulties, from the weakest player to the strongest.
+const (
+	BotRandom     = "random"     // Makes any legal move
+	BotHeuristic  = "heuristic"  // Follows rules of thumb, shaped by its aggression and risk
+	BotSimulation = "simulation" // Plays out each legal move many times over and makes the one that wins most often
+)
+
+// BotModes are the game modes bots can be seated in: those where a player has choices to make on their turn.
+var BotModes = []string{ModeCrazyEights, ModeGoFish, ModeBlackjack}
+
+// ErrNoMove is returned when a move is asked for a player the game gives none, such as when it is not their turn,
+// or a Crazy Eights player can neither play nor draw and waits for the owner to skip their turn.
+var ErrNoMove = errors.New("the player has no move to make")
+
+// BotConfig is how a bot plays. Aggression and risk run from 0 to 1 and shape the heuristic strategy:
+//
+//   - Crazy Eights bots with an aggression above one half play an eight or wild card as soon as it switches play
+//     to a suit they hold more of, rather than keeping it back until nothing else plays.
+//   - Go Fish bots with an aggression above one half ask the opponent holding the most cards, rather than anyone.
+//   - Blackjack bots stand from 17, or from as low as 14 with no appetite for risk up to as high as 20 with the
+//     most, and with an aggression above one half double down on 10 or 11.
+//
+// Random bots ignore both, and simulation bots weigh every move by playing it out instead.
+type BotConfig struct {
+	Difficulty string  `bson:"difficulty" json:"difficulty"` // BotRandom, BotHeuristic, or BotSimulation
+	Aggression float64 `bson:"aggression" json:"aggression"` // How readily the bot presses an advantage, from 0 to 1
+	Risk       float64 `bson:"risk" json:"risk"`             // How much risk the bot takes on, from 0 to 1
+}
+
+// DefaultBotConfig is how bots play unless they are configured otherwise.
+var DefaultBotConfig = BotConfig{Difficulty: BotHeuristic, Aggression: 0.5, Risk: 0.5}
+
+// Validate checks that the difficulty is known and the aggression and risk lie between 0 and 1.
+func (c BotConfig) Validate() error {
+	if c.Difficulty != BotRandom && c.Difficulty != BotHeuristic && c.Difficulty != BotSimulation {
+		return errors.New(`difficulty must be "random", "heuristic", or "simulation"`)
+	}
+	if c.Aggression < 0 || c.Aggression > 1 {
+		return errors.New("aggression must be between 0 and 1")
+	}
+	if c.Risk < 0 || c.Risk > 1 {
+		return errors.New("risk must be between 0 and 1")
+	}
+	return nil
+}
+
+// BotPatch describes a partial update to a bot's configuration.
+// Only the fields that are present in the request are changed.
+type BotPatch struct {
+	Difficulty *string  `json:"difficulty"`
+	Aggression *float64 `json:"aggression"`
+	Risk       *float64 `json:"risk"`
+}
+
+// Apply copies the fields present in the patch onto the configuration.
+func (p BotPatch) Apply(c *BotConfig) {
+	if p.Difficulty != nil {
+		c.Difficulty = *p.Difficulty
+	}
+	if p.Aggression != nil {
+		c.Aggression = *p.Aggression
+	}
+	if p.Risk != nil {
+		c.Risk = *p.Risk
+	}
+}
+
+// IsBot reports whether the seated player is a bot.
+func (g *Game) IsBot(playerName string) bool {
+	_, ok := g.Bots[playerName]
+	return ok
+}
+
+// CanSeatBots checks that the game is played in a mode bots know how to play.
+func (g *Game) CanSeatBots() error {
+	for _, mode := range BotModes {
+		if g.Mode == mode {
+			return nil
+		}
+	}
+	return errors.New("bots can only play crazy eights, go fish, and blackjack")
+}
+
+// BotToMove returns the bot whose move the game is waiting on, or false if it is waiting on a person or on nobody.
+func (g *Game) BotToMove() (string, bool) {
+	if g.Status != StatusActive || g.Turn == nil || len(g.Bots) == 0 {
+		return "", false
+	}
+	if g.Mode == ModeBlackjack && g.HandPhase != HandPhasePlay {
+		return "", false
+	}
+	player := g.Turn.Player()
+	return player, g.IsBot(player)
+}
@@ -0,0 +1,181 @@
+package models
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// Suit is the suit of a card. Suits are stored and sent as their names, such as "Hearts"; the zero Suit is not a
+// suit and cannot be stored.
+type Suit uint8
+
+// The suits of a standard deck, and the suit jokers carry.
+const (
+	SuitHearts Suit = iota + 1
+	SuitDiamonds
+	SuitClubs
+	SuitSpades
+	SuitJoker
+)
+
+// Rank is the value of a card. Ranks are stored and sent as their names, such as "Ace", "10", or "Queen", and the
+// standard ranks are numbered by their face value with aces low, so a Rank converts directly to the card's points.
+// The zero Rank is not a rank and cannot be stored.
+type Rank uint8
+
+// The ranks of a standard deck, numbered by face value, and the rank of jokers.
+const (
+	RankAce Rank = iota + 1
+	RankTwo
+	RankThree
+	RankFour
+	RankFive
+	RankSix
+	RankSeven
+	RankEight
+	RankNine
+	RankTen
+	RankJack
+	RankQueen
+	RankKing
+	RankJoker
+)
+
+// suitNames and rankNames spell out each suit and rank as it appears in the API and the database.
+var (
+	suitNames = map[Suit]string{SuitHearts: "Hearts", SuitDiamonds: "Diamonds", SuitClubs: "Clubs", SuitSpades: "Spades", SuitJoker: "Joker"}
+	rankNames = map[Rank]string{
+		RankAce: "Ace", RankTwo: "2", RankThree: "3", RankFour: "4", RankFive: "5", RankSix: "6", RankSeven: "7",
+		RankEight: "8", RankNine: "9", RankTen: "10", RankJack: "Jack", RankQueen: "Queen", RankKing: "King", RankJoker: "Joker",
+	}
+)
+
+// ParseSuit returns the suit with the given name, including "Joker", or an error if there is no such suit.
+func ParseSuit(name string) (Suit, error) {
+	for suit, suitName := range suitNames {
+		if suitName == name {
+			return suit, nil
+		}
+	}
+	return 0, errors.New("invalid suit " + name)
+}
+
+// ParseRank returns the rank with the given name, including "Joker", or an error if there is no such rank.
+func ParseRank(name string) (Rank, error) {
+	for rank, rankName := range rankNames {
+		if rankName == name {
+			return rank, nil
+		}
+	}
+	return 0, errors.New("invalid card value " + name)
+}
+
+// String returns the suit's name, or "" if it is not a suit.
+func (s Suit) String() string {
+	return suitNames[s]
+}
+
+// IsStandard reports whether the suit is one of the four suits of a standard deck.
+func (s Suit) IsStandard() bool {
+	return s >= SuitHearts && s <= SuitSpades
+}
+
+// String returns the rank's name, or "" if it is not a rank.
+func (r Rank) String() string {
+	return rankNames[r]
+}
+
+// IsStandard reports whether the rank is one of the thirteen ranks of a standard deck.
+func (r Rank) IsStandard() bool {
+	return r >= RankAce && r <= RankKing
+}
+
+// IsValid reports whether the card is a standard card or a joker, which carries the joker suit and rank.
+func (c Card) IsValid() bool {
+	if c.Suit == SuitJoker || c.Value == RankJoker {
+		return c.Suit == SuitJoker && c.Value == RankJoker
+	}
+	return c.Suit.IsStandard() && c.Value.IsStandard()
+}
+
+// MarshalBSONValue stores the suit as its name, refusing values that are not suits.
+func (s Suit) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if s.String() == "" {
+		return 0, nil, errors.New("cannot store an invalid suit")
+	}
+	return bson.MarshalValue(s.String())
+}
+
+// UnmarshalBSONValue reads a suit stored by name.
+func (s *Suit) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	name, ok := bson.RawValue{Type: t, Value: data}.StringValueOK()
+	if !ok {
+		return errors.New("suits must be stored as strings")
+	}
+	suit, err := ParseSuit(name)
+	if err != nil {
+		return err
+	}
+	*s = suit
+	return nil
+}
+
+// MarshalBSONValue stores the rank as its name, refusing values that are not ranks.
+func (r Rank) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if r.String() == "" {
+		return 0, nil, errors.New("cannot store an invalid card value")
+	}
+	return bson.MarshalValue(r.String())
+}
+
+// UnmarshalBSONValue reads a rank stored by name.
+func (r *Rank) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	name, ok := bson.RawValue{Type: t, Value: data}.StringValueOK()
+	if !ok {
+		return errors.New("card values must be stored as strings")
+	}
+	rank, err := ParseRank(name)
+	if err != nil {
+		return err
+	}
+	*r = rank
+	return nil
+}
+
+// MarshalText writes the suit as its name, so suits read naturally in JSON, including as map keys.
+func (s Suit) MarshalText() ([]byte, error) {
+	if s.String() == "" {
+		return nil, errors.New("invalid suit")
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText reads a suit by name.
+func (s *Suit) UnmarshalText(text []byte) error {
+	suit, err := ParseSuit(string(text))
+	if err != nil {
+		return err
+	}
+	*s = suit
+	return nil
+}
+
+// MarshalText writes the rank as its name, so ranks read naturally in JSON, including as map keys.
+func (r Rank) MarshalText() ([]byte, error) {
+	if r.String() == "" {
+		return nil, errors.New("invalid card value")
+	}
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText reads a rank by name.
+func (r *Rank) UnmarshalText(text []byte) error {
+	rank, err := ParseRank(string(text))
+	if err != nil {
+		return err
+	}
+	*r = rank
+	return nil
+}
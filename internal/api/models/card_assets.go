@@ -0,0 +1,96 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// cardImageBaseURL is the base URL card images are served from, such as a CDN. Empty leaves image URLs out of responses.
+var cardImageBaseURL string
+
+// SetCardImageBaseURL sets the base URL that card image URLs in responses are built from.
+// It is called once at startup from the configuration.
+func SetCardImageBaseURL(baseURL string) {
+	cardImageBaseURL = strings.TrimRight(baseURL, "/")
+}
+
+// Code points of the aces in the Unicode Playing Cards block, per suit; the other ranks follow each ace.
+var glyphAces = map[Suit]rune{SuitSpades: 0x1F0A1, SuitHearts: 0x1F0B1, SuitDiamonds: 0x1F0C1, SuitClubs: 0x1F0D1}
+
+// Glyph returns the card's character from the Unicode Playing Cards block, such as 🂡 for the ace of spades,
+// or "" for a card that has none.
+func (c Card) Glyph() string {
+	if c.Suit == SuitJoker {
+		return "\U0001F0CF"
+	}
+	ace, ok := glyphAces[c.Suit]
+	if !ok || !c.Value.IsStandard() {
+		return ""
+	}
+
+	// Each value follows its suit's ace, except that the block has a knight between the jack and queen, which is skipped
+	offset := rune(c.Value - RankAce)
+	if c.Value >= RankQueen {
+		offset++
+	}
+	return string(ace + offset)
+}
+
+// ImageURL returns the URL of the card's image under the configured base URL, named after its card code,
+// or "" when no base URL is configured.
+func (c Card) ImageURL() string {
+	if cardImageBaseURL == "" {
+		return ""
+	}
+	return cardImageBaseURL + "/" + CardCode(c) + ".svg"
+}
+
+// cardJSON is the JSON form of a card: its suit and value, its two-character code, and the display metadata clients can render it with.
+type cardJSON struct {
+	Suit     string `json:"suit"`
+	Value    string `json:"value"`
+	Code     string `json:"code,omitempty"`
+	Glyph    string `json:"glyph,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+	Wild     bool   `json:"wild,omitempty"`
+}
+
+// MarshalJSON adds the card's code, its glyph, when configured its image URL, and whether it has been marked wild to its suit and value.
+func (c Card) MarshalJSON() ([]byte, error) {
+	return json.Marshal(cardJSON{Suit: c.Suit.String(), Value: c.Value.String(), Code: CardCode(c), Glyph: c.Glyph(), ImageURL: c.ImageURL(), Wild: c.wild})
+}
+
+// UnmarshalJSON reads a card given either as a code string such as "QH", or as an object with its suit and value
+// or with just its code. The display metadata is accepted, so clients can send back cards exactly as they received them,
+// but it is ignored. Cards that are not standard cards or jokers are refused.
+func (c *Card) UnmarshalJSON(data []byte) error {
+	// A bare string is a card code
+	var code string
+	if err := json.Unmarshal(data, &code); err == nil {
+		card, err := ParseCardCode(code)
+		if err != nil {
+			return err
+		}
+		*c = card
+		return nil
+	}
+
+	var card cardJSON
+	if err := json.Unmarshal(data, &card); err != nil {
+		return err
+	}
+	if card.Suit == "" && card.Value == "" && card.Code != "" {
+		parsed, err := ParseCardCode(card.Code)
+		if err != nil {
+			return err
+		}
+		*c = parsed
+		return nil
+	}
+	parsed, err := parseCard(card.Suit, card.Value)
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
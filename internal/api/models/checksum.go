@@ -0,0 +1,143 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+)
+
+// ChecksumFields are the stored fields a game's state checksum covers: who is playing, how far the game has got,
+// and where every card and chip is.
+var ChecksumFields = []string{
+	"players", "status", "winner", "hand_number", "card_manifest", "game_deck", "player_hands", "hands",
+	"dealer_hand", "discard_pile", "melds", "books", "chips", "bets", "insurance",
+}
+
+// ErrChecksumMismatch is returned when a game's state no longer matches the checksum stored with it, meaning it was
+// changed outside the server or damaged along the way.
+var ErrChecksumMismatch = errors.New("game state does not match its checksum")
+
+// checksumState is the canonical form of the state a checksum covers. Cards are written as their codes, and empty
+// maps and lists are always written the same way, so the same state always hashes the same however it was loaded.
+type checksumState struct {
+	Players      []string             `json:"players"`
+	Status       string               `json:"status"`
+	Winner       string               `json:"winner"`
+	HandNumber   int                  `json:"hand_number"`
+	CardManifest map[string]int       `json:"card_manifest"`
+	GameDeck     []string             `json:"game_deck"`
+	PlayerHands  map[string][]string  `json:"player_hands"`
+	Hands        map[string][]sumHand `json:"hands"`
+	DealerHand   []string             `json:"dealer_hand"`
+	DiscardPile  []string             `json:"discard_pile"`
+	Melds        []sumMeld            `json:"melds"`
+	Books        map[string][]string  `json:"books"`
+	Chips        map[string]int       `json:"chips"`
+	Bets         map[string]int       `json:"bets"`
+	Insurance    map[string]int       `json:"insurance"`
+}
+
+// sumHand is the canonical form of a blackjack hand.
+type sumHand struct {
+	Cards   []string `json:"cards"`
+	Bet     int      `json:"bet"`
+	Status  string   `json:"status"`
+	Doubled bool     `json:"doubled"`
+	Split   bool     `json:"split"`
+	Outcome string   `json:"outcome"`
+}
+
+// sumMeld is the canonical form of a meld.
+type sumMeld struct {
+	ID    int      `json:"id"`
+	Owner string   `json:"owner"`
+	Type  string   `json:"type"`
+	Cards []string `json:"cards"`
+}
+
+// StateChecksum returns a SHA-256 hash, in hex, of the state listed in ChecksumFields. The server stores it with
+// the game after every change, so clients can tell whether their view of a game has drifted from the server's and
+// audits can tell whether a game, a snapshot, or an export was changed behind the server's back.
+func (g *Game) StateChecksum() string {
+	state := checksumState{
+		Players:      append([]string{}, g.Players...),
+		Status:       g.Status,
+		Winner:       g.Winner,
+		HandNumber:   g.HandNumber,
+		CardManifest: copyCounts(g.CardManifest),
+		GameDeck:     cardCodes(g.GameDeck),
+		PlayerHands:  map[string][]string{},
+		Hands:        map[string][]sumHand{},
+		DealerHand:   cardCodes(g.DealerHand),
+		DiscardPile:  cardCodes(g.DiscardPile),
+		Melds:        []sumMeld{},
+		Books:        map[string][]string{},
+		Chips:        copyCounts(g.Chips),
+		Bets:         copyCounts(g.Bets),
+		Insurance:    copyCounts(g.Insurance),
+	}
+	for player, hand := range g.PlayerHands {
+		state.PlayerHands[player] = cardCodes(hand)
+	}
+	for player, hands := range g.Hands {
+		state.Hands[player] = []sumHand{}
+		for _, hand := range hands {
+			state.Hands[player] = append(state.Hands[player], sumHand{
+				Cards: cardCodes(hand.Cards), Bet: hand.Bet, Status: hand.Status,
+				Doubled: hand.Doubled, Split: hand.Split, Outcome: hand.Outcome,
+			})
+		}
+	}
+	for _, meld := range g.Melds {
+		state.Melds = append(state.Melds, sumMeld{ID: meld.ID, Owner: meld.Owner, Type: meld.Type, Cards: cardCodes(meld.Cards)})
+	}
+	for player, books := range g.Books {
+		state.Books[player] = append([]string{}, books...)
+	}
+
+	// Maps are encoded with their keys sorted, so the encoding is canonical; it cannot fail for these types
+	encoded, _ := json.Marshal(state)
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
+// UpdateChecksum stores the checksum of the game's current state on the game.
+func (g *Game) UpdateChecksum() {
+	g.Checksum = g.StateChecksum()
+}
+
+// VerifyChecksum checks the game's state against the checksum stored with it, returning ErrChecksumMismatch if they
+// differ. Games saved before checksums existed have none and pass.
+func (g *Game) VerifyChecksum() error {
+	if g.Checksum != "" && g.Checksum != g.StateChecksum() {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
+
+// cardCodes returns the codes of the given cards, as an empty list if there are none.
+func cardCodes(cards []Card) []string {
+	codes := make([]string, len(cards))
+	for i, card := range cards {
+		codes[i] = CardCode(card)
+	}
+	return codes
+}
+
+// copyCounts returns a copy of a map of counts, as an empty map if there is none.
+func copyCounts(counts map[string]int) map[string]int {
+	copied := make(map[string]int, len(counts))
+	for key, count := range counts {
+		copied[key] = count
+	}
+	return copied
+}
+
+// ChecksumReport compares the checksum stored with a game against one worked out from its current state.
+type ChecksumReport struct {
+	Checksum string `json:"checksum"`          // Checksum stored with the game after its last change
+	Computed string `json:"computed"`          // Checksum of the game's state as it is now
+	Match    bool   `json:"match"`             // The game's state is unchanged since the server last stored its checksum
+	Missing  bool   `json:"missing,omitempty"` // The game has no stored checksum yet, as it has not changed since checksums existed
+}
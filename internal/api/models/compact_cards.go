@@ -0,0 +1,147 @@
+package models
+
+import (
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// CompactCards is a list of cards that is stored in MongoDB as an array of card codes such as "QH" or "TS"
+// instead of one {suit, value} document per card, which keeps large multi-deck shoes small on disk and on the wire.
+// It is encoded to JSON like any other list of cards. Arrays stored in the older document form are still read.
+type CompactCards []Card
+
+// Single-letter codes for the standard suits and the face and ace values.
+var (
+	suitCodes  = map[Suit]string{SuitHearts: "H", SuitDiamonds: "D", SuitClubs: "C", SuitSpades: "S"}
+	valueCodes = map[Rank]string{RankAce: "A", RankTen: "T", RankJack: "J", RankQueen: "Q", RankKing: "K"}
+)
+
+// jokerCode is the code stored for a joker.
+const jokerCode = "JK"
+
+// CardCode returns the standard two-character code of a card: its value letter or digit followed by its suit letter,
+// such as "AS", "TD" (ten of diamonds), or "KH". Jokers are "JK", and cards that are neither have no code.
+// The codes are used in API payloads and inside CompactCards.
+func CardCode(card Card) string {
+	if !card.IsValid() {
+		return ""
+	}
+	if card.Suit == SuitJoker {
+		return jokerCode
+	}
+	if value, ok := valueCodes[card.Value]; ok {
+		return value + suitCodes[card.Suit]
+	}
+	return card.Value.String() + suitCodes[card.Suit]
+}
+
+// ParseCardCode turns a code produced by CardCode back into a card. Suit letters may be lower case,
+// and the ten may also be written "10", as in "10S". Codes written as a value and suit separated by a "|",
+// as older versions stored some cards, are read as long as they name a valid card.
+func ParseCardCode(code string) (Card, error) {
+	if strings.EqualFold(code, jokerCode) {
+		return Card{Suit: SuitJoker, Value: RankJoker}, nil
+	}
+	if value, suit, ok := strings.Cut(code, "|"); ok {
+		return parseCard(suit, value)
+	}
+	if len(code) < 2 {
+		return Card{}, errors.New("invalid card code " + code)
+	}
+
+	// The last letter is the suit and everything before it is the value
+	card := Card{}
+	value := strings.ToUpper(code[:len(code)-1])
+	for suit, letter := range suitCodes {
+		if letter == strings.ToUpper(code[len(code)-1:]) {
+			card.Suit = suit
+		}
+	}
+	for rank, letter := range valueCodes {
+		if letter == value {
+			card.Value = rank
+		}
+	}
+	if card.Value == 0 {
+		if rank, err := ParseRank(value); err == nil {
+			card.Value = rank
+		}
+	}
+	if !card.Suit.IsStandard() || !card.Value.IsStandard() {
+		return Card{}, errors.New("invalid card code " + code)
+	}
+	return card, nil
+}
+
+// parseCard returns the card with the named suit and value, or an error if they do not make a standard card or a joker.
+func parseCard(suit, value string) (Card, error) {
+	s, err := ParseSuit(suit)
+	if err != nil {
+		return Card{}, err
+	}
+	v, err := ParseRank(value)
+	if err != nil {
+		return Card{}, err
+	}
+	card := Card{Suit: s, Value: v}
+	if !card.IsValid() {
+		return Card{}, errors.New("invalid card " + value + " of " + suit)
+	}
+	return card, nil
+}
+
+// Codes returns the code of every card, in order; cards that are not valid have an empty code.
+func (c CompactCards) Codes() []string {
+	codes := make([]string, len(c))
+	for i, card := range c {
+		codes[i] = CardCode(card)
+	}
+	return codes
+}
+
+// MarshalBSONValue stores the cards as an array of card codes, refusing cards that are not valid.
+func (c CompactCards) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	for _, card := range c {
+		if !card.IsValid() {
+			return 0, nil, errors.New("cannot store an invalid card")
+		}
+	}
+	return bson.MarshalValue(c.Codes())
+}
+
+// UnmarshalBSONValue reads an array of card codes, or an array of {suit, value} documents written before cards were stored compactly.
+func (c *CompactCards) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	raw := bson.RawValue{Type: t, Value: data}
+	if t == bsontype.Null || t == bsontype.Undefined {
+		*c = nil
+		return nil
+	}
+
+	array, ok := raw.ArrayOK()
+	if !ok {
+		return errors.New("cards must be stored as an array")
+	}
+	values, err := array.Values()
+	if err != nil {
+		return err
+	}
+
+	cards := make(CompactCards, 0, len(values))
+	for _, value := range values {
+		var card Card
+		if code, isCode := value.StringValueOK(); isCode {
+			card, err = ParseCardCode(code)
+		} else {
+			err = value.Unmarshal(&card)
+		}
+		if err != nil {
+			return err
+		}
+		cards = append(cards, card)
+	}
+	*c = cards
+	return nil
+}
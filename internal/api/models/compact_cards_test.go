@@ -0,0 +1,28 @@
+package models
+
+import "testing"
+
+func FuzzParseCardCode(f *testing.F) {
+	for _, seed := range []string{"AS", "TD", "10h", "kc", "JK", "jk", "Ace|Hearts", "Joker|Joker", "1S", "X", "", "|", "Q|"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, code string) {
+		card, err := ParseCardCode(code)
+		if err != nil {
+			return
+		}
+
+		// Every code that parses names a real card, and the card's own code reads back as the same card
+		if !card.IsValid() {
+			t.Fatalf("ParseCardCode(%q) = %+v, which is not a valid card", code, card)
+		}
+		again, err := ParseCardCode(CardCode(card))
+		if err != nil {
+			t.Fatalf("ParseCardCode(%q) of the code of %+v: %v", CardCode(card), card, err)
+		}
+		if again != card {
+			t.Fatalf("ParseCardCode(%q) = %+v, want %+v", CardCode(card), again, card)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package models
+
+import "errors"
+
+// DealCrazyEights deals the opening hands of a Crazy Eights game and turns up the first discard.
+// The hands are sized by OpeningHandSize. If the turned-up card is an eight or another wild card it is returned
+// to the bottom of the deck and another card is turned up.
+func (g *Game) DealCrazyEights() error {
+	if len(g.Players) < 2 {
+		return errors.New("crazy eights requires at least two players")
+	}
+
+	// Deal the hands, keeping a card back for the starter
+	if err := g.DealOpeningHands(g.OpeningHandSize(), 1); err != nil {
+		return err
+	}
+
+	// Turn up the starter card, burying eights and other wild cards at the bottom of the deck
+	for i := 0; i < len(g.GameDeck) && g.PlaysAnywhere(g.GameDeck[0]); i++ {
+		g.GameDeck = append(g.GameDeck[1:], g.GameDeck[0])
+	}
+	g.DiscardPile = []Card{g.GameDeck[0]}
+	g.GameDeck = g.GameDeck[1:]
+	g.DeclaredSuit = ""
+
+	return nil
+}
+
+// PlayCrazyEight moves a card the player holds from their hand to the discard pile, along with the suit declared
+// if it is an eight or another wild card. A player who has played their last card wins the game; otherwise the turn passes on.
+func (g *Game) PlayCrazyEight(playerName string, card Card, declaredSuit string) {
+	hand, _ := RemoveCards(g.PlayerHands[playerName], []Card{card})
+	g.PlayerHands[playerName] = hand
+	g.DiscardPile = append(g.DiscardPile, card)
+	g.DeclaredSuit = ""
+	if g.PlaysAnywhere(card) {
+		g.DeclaredSuit = declaredSuit
+	}
+
+	if len(hand) == 0 {
+		g.Status = StatusFinished
+		g.Winner = playerName
+		g.DecideWinningTeam()
+	} else if g.Turn != nil {
+		g.Turn.Advance()
+	}
+}
+
+// TopDiscard returns the card on top of the discard pile and whether there is one.
+func (g *Game) TopDiscard() (Card, bool) {
+	if len(g.DiscardPile) == 0 {
+		return Card{}, false
+	}
+	return g.DiscardPile[len(g.DiscardPile)-1], true
+}
+
+// CanPlayOnDiscard reports whether a card can be played on the discard pile in Crazy Eights.
+// Eights, and any cards the game makes wild, can always be played; any other card must match the value of the
+// top card or the suit in play, which is the suit declared with the last wild card or otherwise the suit of the top card.
+func (g *Game) CanPlayOnDiscard(card Card) bool {
+	if g.PlaysAnywhere(card) {
+		return true
+	}
+
+	top, ok := g.TopDiscard()
+	if !ok {
+		return true
+	}
+
+	suit := top.Suit
+	if declared, err := ParseSuit(g.DeclaredSuit); err == nil {
+		suit = declared
+	}
+	return card.Suit == suit || card.Value == top.Value
+}
+
+// PlaysAnywhere reports whether a card is wild in Crazy Eights, where it can be played on any card and declares
+// the suit to follow: every eight is, along with the game's own wild cards.
+func (g *Game) PlaysAnywhere(card Card) bool {
+	return card.Value == RankEight || g.IsWild(card)
+}
+
+// HasPlayableCard reports whether the player holds any card that can be played on the discard pile.
+func (g *Game) HasPlayableCard(playerName string) bool {
+	for _, card := range g.PlayerHands[playerName] {
+		if g.CanPlayOnDiscard(card) {
+			return true
+		}
+	}
+	return false
+}
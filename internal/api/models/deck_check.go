@@ -0,0 +1,133 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxJokerCopies caps how many jokers a game may hold: two for each deck a game may shuffle together.
+const maxJokerCopies = 2 * maxDeckCount
+
+// DeckProblem describes one way a deck supplied from outside the server is malformed.
+type DeckProblem struct {
+	Card   string `json:"card"` // The card's code, or where the card was found if it has no code
+	Reason string `json:"reason"`
+}
+
+// DeckError lists the problems found with cards supplied from outside the server, such as a custom deck or an
+// imported game. It is the error returned when such cards are rejected.
+type DeckError []DeckProblem
+
+// Error joins the problems into one message.
+func (e DeckError) Error() string {
+	problems := make([]string, len(e))
+	for i, problem := range e {
+		problems[i] = problem.Card + ": " + problem.Reason
+	}
+	return "invalid deck: " + strings.Join(problems, "; ")
+}
+
+// pile is a named group of cards, named after where the cards were found so problems can point to them.
+type pile struct {
+	name  string
+	cards []Card
+}
+
+// CheckCustomDeck checks a deck a client supplies to add to the game, returning a DeckError listing every problem:
+// each card must be a standard card or a joker, jokers are only allowed in games that play with them, and adding
+// the deck must not leave the game with more copies of a card than maxDeckCount decks hold. Cards already in a
+// game with a card manifest count towards the limit.
+func (g *Game) CheckCustomDeck(cards []Card) error {
+	counts := map[string]int{}
+	for code, count := range g.CardManifest {
+		counts[code] = count
+	}
+	return g.checkPiles([]pile{{name: "cards", cards: cards}}, counts)
+}
+
+// CheckImportedCards checks every card of an imported game, returning a DeckError listing every problem: the cards
+// in the deck, the hands, the house's hand, the discard pile, and the melds must be standard cards or jokers, with
+// jokers only in games that play with them and no more copies of a card than maxDeckCount decks hold, and every
+// Go Fish book must be of a card value.
+func (g *Game) CheckImportedCards() error {
+	// List the players holding hands or books by name, so problems are listed the same way every time
+	handPlayers, bookPlayers := map[string]int{}, map[string]int{}
+	for player := range g.PlayerHands {
+		handPlayers[player] = 0
+	}
+	for player := range g.Books {
+		bookPlayers[player] = 0
+	}
+
+	piles := []pile{{name: "game_deck", cards: g.GameDeck}}
+	for _, player := range sortedKeys(handPlayers) {
+		piles = append(piles, pile{name: "player_hands." + player, cards: g.PlayerHands[player]})
+	}
+	for _, player := range g.Players {
+		for i, hand := range g.Hands[player] {
+			piles = append(piles, pile{name: fmt.Sprintf("hands.%s[%d]", player, i), cards: hand.Cards})
+		}
+	}
+	piles = append(piles, pile{name: "dealer_hand", cards: g.DealerHand}, pile{name: "discard_pile", cards: g.DiscardPile})
+	for i, meld := range g.Melds {
+		piles = append(piles, pile{name: fmt.Sprintf("melds[%d]", i), cards: meld.Cards})
+	}
+
+	problems := DeckError{}
+	if err := g.checkPiles(piles, map[string]int{}); err != nil {
+		problems = err.(DeckError)
+	}
+	for _, player := range sortedKeys(bookPlayers) {
+		for _, value := range g.Books[player] {
+			if !IsValidCardValue(value) {
+				problems = append(problems, DeckProblem{Card: "books." + player, Reason: "a book of " + value + " is not a book of a card value"})
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+// checkPiles checks the cards in the piles on top of the copies already counted in counts, which it updates.
+func (g *Game) checkPiles(piles []pile, counts map[string]int) error {
+	problems := DeckError{}
+
+	added := map[string]bool{}
+	for _, p := range piles {
+		for i, card := range p.cards {
+			if !card.IsValid() {
+				problems = append(problems, DeckProblem{Card: fmt.Sprintf("%s[%d]", p.name, i), Reason: "not a standard card or a joker"})
+				continue
+			}
+			code := CardCode(card)
+			counts[code]++
+			added[code] = true
+		}
+	}
+
+	// Only report on the cards being checked, not ones the game already held
+	for _, code := range sortedKeys(counts) {
+		if !added[code] {
+			continue
+		}
+		limit := maxDeckCount
+		if code == jokerCode {
+			if !g.Settings.Jokers {
+				problems = append(problems, DeckProblem{Card: code, Reason: "the game does not play with jokers"})
+				continue
+			}
+			limit = maxJokerCopies
+		}
+		if counts[code] > limit {
+			problems = append(problems, DeckProblem{Card: code, Reason: fmt.Sprintf("%d copies, but a game may hold at most %d", counts[code], limit)})
+		}
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
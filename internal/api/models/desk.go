@@ -6,20 +6,25 @@ type Deck struct {
 	Cards []Card `json:"cards"`
 }
 
+// Suits lists the four suits of a standard deck.
+var Suits = []string{"Hearts", "Diamonds", "Clubs", "Spades"}
+
+// IsValidSuit reports whether the given string is one of the four standard suits.
+func IsValidSuit(suit string) bool {
+	s, err := ParseSuit(suit)
+	return err == nil && s.IsStandard()
+}
+
 // NewDeck initializes a new deck of 52 cards.
 // The deck contains cards from all four suits (Hearts, Diamonds, Clubs, Spades)
 // and thirteen face values (Ace, 2-10, Jack, Queen, King).
 func NewDeck() *Deck {
-	// Define the suits and values for a standard deck of cards
-	suits := []string{"Hearts", "Diamonds", "Clubs", "Spades"}
-	values := []string{"Ace", "2", "3", "4", "5", "6", "7", "8", "9", "10", "Jack", "Queen", "King"}
-
 	var cards []Card
 
 	// Loop through each suit
-	for _, suit := range suits {
+	for suit := SuitHearts; suit <= SuitSpades; suit++ {
 		// Loop through each value
-		for _, value := range values {
+		for value := RankAce; value <= RankKing; value++ {
 			// Create a new card with the current suit and value, and add it to the deck
 			cards = append(cards, Card{Suit: suit, Value: value})
 		}
@@ -28,3 +33,12 @@ func NewDeck() *Deck {
 	// Return a pointer to a new Deck containing the initialized cards
 	return &Deck{Cards: cards}
 }
+
+// NewDeckWithJokers initializes a new deck of 52 cards, adding two jokers when jokers is true.
+func NewDeckWithJokers(jokers bool) *Deck {
+	deck := NewDeck()
+	if jokers {
+		deck.Cards = append(deck.Cards, Card{Suit: SuitJoker, Value: RankJoker}, Card{Suit: SuitJoker, Value: RankJoker})
+	}
+	return deck
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Event types recorded in a game's event log.
+const (
+	EventGameStarted  = "game_started"
+	EventBattle       = "battle"
+	EventGameOver     = "game_over"
+	EventCardPlayed   = "card_played"
+	EventCardDrawn    = "card_drawn"
+	EventAsk          = "ask"
+	EventBook         = "book_completed"
+	EventKicked       = "player_kicked"
+	EventBanned       = "player_banned"
+	EventInactive     = "player_inactive"
+	EventRolledBack   = "rolled_back"
+	EventGameReset    = "game_reset"
+	EventForceEnded   = "game_force_ended"
+	EventCardDealt    = "card_dealt"
+	EventAchievement  = "achievement_unlocked"
+	EventDisconnected = "player_disconnected"
+	EventReconnected  = "player_reconnected"
+	EventForfeited    = "player_forfeited"
+	EventTurnSkipped  = "turn_skipped"
+	EventReversed     = "direction_reversed"
+	EventPenaltyDraw  = "penalty_draw"
+	EventAuction      = "auction_opened"
+	EventBid          = "bid_placed"
+	EventContract     = "auction_closed"
+	EventHandDealt    = "hand_dealt"
+	EventShowdown     = "hand_showdown"
+	EventHandScored   = "hand_scored"
+	EventReshuffled   = "discards_reshuffled"
+	EventRedraw       = "cards_redrawn"
+	EventHit          = "hit"
+	EventStood        = "stood"
+	EventDoubledDown  = "doubled_down"
+	EventSplit        = "split"
+	EventSurrendered  = "surrendered"
+	EventHousePlayed  = "house_played"
+	EventInsurance    = "insurance_taken"
+	EventBuyIn        = "player_bought_in"
+	EventCashOut      = "player_cashed_out"
+	EventDeckShuffled = "deck_shuffled"
+	EventBotAdded     = "bot_added"
+	EventBotUpdated   = "bot_updated"
+	EventHint         = "hint_given"
+)
+
+// Event represents something that happened in a game.
+// Events are stored in their own collection so a game's history can be read back in order
+// without growing the game document itself.
+type Event struct {
+	ID        primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	GameID    primitive.ObjectID     `bson:"game_id" json:"game_id"`
+	Type      string                 `bson:"type" json:"type"`
+	Player    string                 `bson:"player,omitempty" json:"player,omitempty"`
+	Data      map[string]interface{} `bson:"data,omitempty" json:"data,omitempty"`
+	CreatedAt time.Time              `bson:"created_at" json:"created_at"`
+}
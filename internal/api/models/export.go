@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// ExportFormatVersion is the version of the game export format produced by this server.
+const ExportFormatVersion = 1
+
+// GameExport is a self-contained snapshot of a game, including every hand, the deck, and the event history.
+// It is used for backups and for moving games between environments.
+type GameExport struct {
+	Version    int       `json:"version"`     // Export format version, checked on import
+	ExportedAt time.Time `json:"exported_at"` // When the snapshot was taken
+	Game       Game      `json:"game"`
+	Events     []Event   `json:"events"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// derivedGameFields lists, for each field of a game response that is worked out by the API rather than stored,
+// the stored fields it is worked out from.
+var derivedGameFields = map[string][]string{
+	"_links":   {"status", "settings", "players", "seat_numbers", "reservations", "game_deck"},
+	"presence": {"players", "connections", "last_seen"},
+	"dealer":   {"status", "players", "dealer_index"},
+	"table":    {"status", "settings", "players", "seat_numbers", "reservations", "dealer_index", "chips", "hand_number", "small_blind", "big_blind", "blind_schedule"},
+}
+
+// HandViewFields are the stored fields CanViewHand needs to decide whether a viewer may see a hand. The seats
+// are among them, since an owner who is seated only sees their own hand.
+var HandViewFields = []string{"owner", "settings", "players"}
+
+// redactionFields are the stored fields RedactFor needs to decide which hands a viewer may see.
+var redactionFields = append([]string{"hand_phase"}, HandViewFields...)
+
+// GameProjection returns the stored fields to load for a game response limited to the given JSON fields, along
+// with the fields needed to hide hands and work out the derived fields asked for. It returns nil when no fields
+// are given, meaning the whole game, and an error naming any field a game response does not have.
+func GameProjection(fields []string) ([]string, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	projection, err := projectFields(reflect.TypeOf(Game{}), fields, derivedGameFields)
+	if err != nil {
+		return nil, err
+	}
+	return append(projection, redactionFields...), nil
+}
+
+// PlayerStatsProjection returns the stored fields to load for a player statistics response limited to the given
+// JSON fields. It returns nil when no fields are given, and an error naming any field the response does not have.
+func PlayerStatsProjection(fields []string) ([]string, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return projectFields(reflect.TypeOf(PlayerStats{}), fields, nil)
+}
+
+// projectFields maps JSON field names of a stored type to their BSON names, using derived for fields that are
+// not stored themselves.
+func projectFields(t reflect.Type, fields []string, derived map[string][]string) ([]string, error) {
+	stored := map[string]string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonName := tagName(field.Tag.Get("json"))
+		bsonName := tagName(field.Tag.Get("bson"))
+		if jsonName != "" && jsonName != "-" && bsonName != "" && bsonName != "-" {
+			stored[jsonName] = bsonName
+		}
+	}
+
+	projection := []string{}
+	for _, field := range fields {
+		if name, ok := stored[field]; ok {
+			projection = append(projection, name)
+		} else if names, ok := derived[field]; ok {
+			projection = append(projection, names...)
+		} else {
+			return nil, fmt.Errorf("unknown field %q", field)
+		}
+	}
+	return projection, nil
+}
+
+// tagName returns the name part of a struct tag such as "name,omitempty".
+func tagName(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	return name
+}
@@ -1,7 +1,7 @@
 package models
 
 import (
-	"math/rand"
+	"my-card-game/internal/entropy"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -9,35 +9,190 @@ import (
 
 // Game represents a card game.
 // It includes an ID, a name, a list of players, the game deck (cards available in the game),
-// and a map to track the cards held by each player.
+// a map to track the cards held by each player, and the betting state of the current hand.
 type Game struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	Name        string             `bson:"name" json:"name"`
-	Players     []string           `bson:"players" json:"players"` // This can be a slice of player IDs
-	GameDeck    []Card             `bson:"game_deck" json:"game_deck"`
-	PlayerHands map[string][]Card  `bson:"player_hands" json:"player_hands"`
+	ID                primitive.ObjectID      `bson:"_id,omitempty" json:"id,omitempty"`
+	OrgID             string                  `bson:"org_id,omitempty" json:"org_id,omitempty"` // Organization the game belongs to; empty for the default organization
+	Name              string                  `bson:"name" json:"name"`
+	Private           bool                    `bson:"private" json:"private"`                               // Private games are hidden from public game listings
+	PasswordHash      string                  `bson:"password_hash,omitempty" json:"-"`                     // bcrypt hash of the password needed to join, if the game has one
+	PasswordProtected bool                    `bson:"password_protected" json:"password_protected"`         // Joining the game requires its password
+	CreatedAt         time.Time               `bson:"created_at" json:"created_at"`                         // When the game was created
+	Settings          Settings                `bson:"settings" json:"settings"`                             // Per-game configuration chosen at creation
+	Owner             string                  `bson:"owner" json:"owner"`                                   // Player who created the game and deals it
+	Mode              string                  `bson:"mode" json:"mode"`                                     // Game mode being played, such as standard or gin_rummy
+	Status            string                  `bson:"status" json:"status"`                                 // Lifecycle status: lobby, active, or finished
+	Winner            string                  `bson:"winner,omitempty" json:"winner,omitempty"`             // Player who won the game, once it is finished
+	Teams             map[string][]string     `bson:"teams,omitempty" json:"teams,omitempty"`               // Players on each team, for games played in partnerships
+	WinningTeam       string                  `bson:"winning_team,omitempty" json:"winning_team,omitempty"` // Team that won the game, once a team game is finished
+	Players           []string                `bson:"players" json:"players"`                               // This can be a slice of player IDs
+	SeatNumbers       map[string]int          `bson:"seat_numbers,omitempty" json:"seat_numbers,omitempty"` // Table seat each player sits in; Players is kept in seat order
+	Bots              map[string]BotConfig    `bson:"bots,omitempty" json:"bots,omitempty"`                 // How each seated bot plays; players not listed are people
+	HouseRules        string                  `bson:"house_rules,omitempty" json:"house_rules,omitempty"`   // Script of house rules played on top of the mode's rules; see SetHouseRules
+	houseScript       *houseScript            // HouseRules compiled, once the game's rules are first looked up
+	Reservations      []SeatReservation       `bson:"reservations,omitempty" json:"reservations,omitempty"` // Seats held for players who have not sat down yet
+	Waitlist          []WaitlistEntry         `bson:"waitlist,omitempty" json:"waitlist,omitempty"`         // Players waiting for a seat to open, first in line first
+	GameDeck          CompactCards            `bson:"game_deck" json:"game_deck,omitempty"`                 // Undealt cards, stored as compact card codes; only admins are shown them, in RedactFor
+	CardManifest      map[string]int          `bson:"card_manifest" json:"-"`                               // Copies of each card put into the game, by card code; checked by CheckIntegrity
+	Checksum          string                  `bson:"checksum,omitempty" json:"checksum,omitempty"`         // Hash of the game's state as of its last change; see StateChecksum
+	Shuffles          []ShuffleRecord         `bson:"-" json:"-"`                                           // Shuffles made since the game was loaded, waiting to be logged
+	replay            *replayer               // Recorded shuffles to repeat, while the game is being replayed
+	entropy           entropy.Source          // Source the deck is shuffled with; see UseEntropy
+	PlayerHands       map[string][]Card       `bson:"player_hands" json:"player_hands"`
+	Chips             map[string]int          `bson:"chips" json:"chips"`                       // Chip stack held by each player
+	Bets              map[string]int          `bson:"bets" json:"bets"`                         // Chips each player has committed to the current hand
+	Folded            []string                `bson:"folded" json:"folded"`                     // Players who folded the current hand
+	Redraws           map[string]int          `bson:"redraws" json:"redraws,omitempty"`         // Redraws each player has taken in the current hand
+	Hands             map[string][]PlayerHand `bson:"hands" json:"hands,omitempty"`             // Each player's hands in blackjack, where splitting a pair makes several
+	DealerHand        []Card                  `bson:"dealer_hand" json:"dealer_hand,omitempty"` // The house's hand in blackjack
+	Insurance         map[string]int          `bson:"insurance" json:"insurance,omitempty"`     // Insurance each blackjack player has staked against the house's blackjack
+
+	DealerIndex   int           `bson:"dealer_index" json:"dealer_index"`                           // Seat index of the player holding the dealer button
+	HandNumber    int           `bson:"hand_number" json:"hand_number"`                             // Number of hands started in this game
+	SmallBlind    int           `bson:"small_blind" json:"small_blind"`                             // Base small blind amount
+	BigBlind      int           `bson:"big_blind" json:"big_blind"`                                 // Base big blind amount
+	BlindSchedule []BlindLevel  `bson:"blind_schedule" json:"blind_schedule"`                       // Optional blind escalation schedule for tournaments
+	Turn          *TurnOrder    `bson:"turn,omitempty" json:"turn,omitempty"`                       // Whose turn it is, once a turn-based game has started
+	TurnAlert     *TurnAlert    `bson:"turn_alert,omitempty" json:"-"`                              // The turn the player to move is alerted about; see CheckTurnAlerts
+	Auction       *Auction      `bson:"auction,omitempty" json:"auction,omitempty"`                 // Bidding for the current hand's contract, once an auction is opened
+	HandPhase     string        `bson:"hand_phase,omitempty" json:"hand_phase,omitempty"`           // Phase of the current hand, once hands are dealt with DealHand
+	HandStartedAt time.Time     `bson:"hand_started_at,omitempty" json:"hand_started_at,omitempty"` // When the current hand was dealt
+	HandHistory   []HandSummary `bson:"hand_history" json:"hand_history,omitempty"`                 // Summaries of the hands scored so far
+
+	Melds        []Meld              `bson:"melds" json:"melds"`                 // Melds laid down on the table in rummy games
+	DiscardPile  []Card              `bson:"discard_pile" json:"discard_pile"`   // Face-up discard pile; the last card is the top
+	DeclaredSuit string              `bson:"declared_suit" json:"declared_suit"` // Suit named by the last wild eight played in Crazy Eights
+	Books        map[string][]string `bson:"books" json:"books"`                 // Card values each player has completed as books in Go Fish
+
+	Banned       []string             `bson:"banned" json:"banned"`                       // Players banned from rejoining the game
+	LastActive   map[string]time.Time `bson:"last_active" json:"last_active"`             // When each player last acted in the game
+	LastSeen     map[string]time.Time `bson:"last_seen" json:"last_seen"`                 // When each player's client last sent a heartbeat
+	Inactive     []string             `bson:"inactive" json:"inactive"`                   // Players flagged as inactive by the inactivity check
+	Hinted       []string             `bson:"hinted,omitempty" json:"hinted,omitempty"`   // Players who asked for a hint during the game
+	Connections  map[string]int       `bson:"connections" json:"-"`                       // Open game streams of each seated player
+	Disconnected map[string]time.Time `bson:"disconnected" json:"disconnected,omitempty"` // When each player who dropped their connection was last connected
+
+	Links      map[string]Link   `bson:"-" json:"_links,omitempty"`   // Actions available in the game's current state; set by the API, never stored
+	Presence   map[string]string `bson:"-" json:"presence,omitempty"` // Whether each player is online or offline; set by the API, never stored
+	DealerName string            `bson:"-" json:"dealer,omitempty"`   // Player holding the dealer button in a running game; set by the API, never stored
+	Table      []Seat            `bson:"-" json:"table,omitempty"`    // Seating arrangement for drawing the table; set by the API, never stored
+	DeckSize   int               `bson:"-" json:"deck_size"`          // Undealt cards left, shown in place of the deck itself; set by RedactFor, never stored
+}
+
+// CardsLeft returns how many cards are left undealt, from the deck itself or, once RedactFor has hidden it, from
+// the count left in its place.
+func (g *Game) CardsLeft() int {
+	if g.GameDeck == nil {
+		return g.DeckSize
+	}
+	return len(g.GameDeck)
 }
 
+// Link points a client at a related resource or an action it can take, along with the HTTP method to use.
+type Link struct {
+	Href   string `json:"href"`
+	Method string `json:"method"`
+}
+
+// Game lifecycle statuses.
+// A game is created in the lobby, becomes active when it is started, and is finished once a winner is decided.
+const (
+	StatusLobby    = "lobby"
+	StatusActive   = "active"
+	StatusFinished = "finished"
+)
+
 // Card represents an individual playing card.
-// It includes the suit and value of the card.
+// It includes the suit and value of the card, and whether the game treats it as wild, which is only worked out for
+// responses and never stored. Cards that are not standard cards or jokers are refused when they are stored.
 type Card struct {
-	Suit  string `bson:"suit" json:"suit"`
-	Value string `bson:"value" json:"value"`
+	Suit  Suit `bson:"suit" json:"suit"`
+	Value Rank `bson:"value" json:"value"`
+	wild  bool
 }
 
 // AddDeckToGame adds a deck of cards to the game's deck.
 // The new deck is appended to the existing game deck.
 func (g *Game) AddDeckToGame(deck *Deck) {
 	g.GameDeck = append(g.GameDeck, deck.Cards...)
+	g.RecordCards(deck.Cards)
 }
 
-// ShuffleDeck shuffles the cards in the game deck using a custom shuffle algorithm.
-// The cards are shuffled in place using a random number generator.
+// ShuffleDeck shuffles the cards in the game deck with a Fisher–Yates shuffle, so every order of the deck is equally
+// likely. The cards are shuffled in place using random numbers drawn from the game's entropy source,
+// and the source used is noted in the game's pending shuffle records.
 func (g *Game) ShuffleDeck() {
-	rand.Seed(time.Now().UnixNano()) // Seed the random number generator with the current time
 	n := len(g.GameDeck)
-	for i := range g.GameDeck {
-		j := rand.Intn(n)                                           // Generate a random index between 0 and n-1
-		g.GameDeck[i], g.GameDeck[j] = g.GameDeck[j], g.GameDeck[i] // Swap the card at index i with the card at index j
+	if n == 0 {
+		return
+	}
+	deck := append(CompactCards{}, g.GameDeck...)
+	source := g.entropy
+	if source == nil {
+		source = entropy.Crypto()
+	}
+	if g.replay != nil {
+		source = g.replay.next(deck)
+	}
+	draw := entropy.NewDraw(source, 4*n)
+	for i := n - 1; i > 0; i-- {
+		j := draw.Intn(i + 1)                                       // Pick a card from the part of the deck not yet shuffled
+		g.GameDeck[i], g.GameDeck[j] = g.GameDeck[j], g.GameDeck[i] // Swap it into place at index i
 	}
+	g.noteShuffle(deck, draw)
+}
+
+// ReshuffleDiscards refills a deck that has run short of the cards needed, if the game's settings allow it:
+// every card of the discard pile except the top one is shuffled into the deck, leaving the top card in play.
+// It returns the number of cards moved, which is 0 if the policy is off, the deck has enough cards,
+// or there is nothing under the top discard to reshuffle.
+func (g *Game) ReshuffleDiscards(needed int) int {
+	if !g.Settings.ReshuffleDiscards || len(g.GameDeck) >= needed || len(g.DiscardPile) < 2 {
+		return 0
+	}
+
+	top := len(g.DiscardPile) - 1
+	moved := g.DiscardPile[:top]
+	g.GameDeck = append(g.GameDeck, moved...)
+	g.DiscardPile = []Card{g.DiscardPile[top]}
+	g.ShuffleDeck()
+	return len(moved)
+}
+
+// DrawFromDeck moves up to count cards from the top of the deck to the end of the player's hand and returns them.
+func (g *Game) DrawFromDeck(playerName string, count int) []Card {
+	if count > len(g.GameDeck) {
+		count = len(g.GameDeck)
+	}
+	if g.PlayerHands == nil {
+		g.PlayerHands = map[string][]Card{}
+	}
+	drawn := append([]Card{}, g.GameDeck[:count]...)
+	g.GameDeck = g.GameDeck[count:]
+	g.PlayerHands[playerName] = append(g.PlayerHands[playerName], drawn...)
+	return drawn
+}
+
+// Reset returns every dealt card to the game deck and clears the hands, melds, discard pile, bets, and books.
+// The deck is reshuffled and the scores, hand history, dealer button, and winner are reset, while players stay
+// seated with their chips. The game goes back to the lobby so it can be started again.
+func (g *Game) Reset() {
+	// Gather the cards back into the deck and clear the per-hand state
+	g.ClearTable()
+
+	// The books are cleared, so the cards collected into them go back into the deck too
+	g.GameDeck = append(g.GameDeck, g.MissingCards()...)
+	g.Books = map[string][]string{}
+
+	// Reset the scores and the hands played
+	g.HandPhase = ""
+	g.HandStartedAt = time.Time{}
+	g.HandHistory = nil
+	g.DealerIndex = 0
+	g.HandNumber = 0
+	g.Winner = ""
+	g.WinningTeam = ""
+	g.Status = StatusLobby
+
+	g.ShuffleDeck()
 }
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+// GameRules is how one game mode is played: which moves it allows, what each move does, when a round of it is
+// over, and how a finished round is scored. Each mode registers its rules with RegisterRules, and the generic
+// move endpoint plays every registered mode through them, so a new game is added by writing and registering its
+// rules, without changes to the handlers or the game service.
+type GameRules interface {
+	// ValidateMove checks a move against the mode's rules without changing the game, returning the rules it
+	// breaks as Violations, or nil if the move is allowed.
+	ValidateMove(g *Game, a Action) error
+
+	// ApplyMove makes a move ValidateMove allowed, changing the game, and returns the events to record for it,
+	// in order. It only fails if the game turns out to be in a state the move cannot be made from.
+	ApplyMove(g *Game, a Action) ([]MoveEvent, error)
+
+	// IsRoundOver reports whether the current round is over: the hand, in modes played a hand at a time, or
+	// otherwise the game.
+	IsRoundOver(g *Game) bool
+
+	// Score values each seated player at the end of a round and returns the players who won it.
+	Score(g *Game) (map[string]int, []string)
+}
+
+// MoveEvent is an event a move makes happen, to be recorded in the game's event log.
+type MoveEvent struct {
+	Type   string
+	Player string
+	Data   map[string]interface{}
+}
+
+// rulesRegistry maps each game mode to its rules.
+var rulesRegistry = map[string]GameRules{}
+
+// RegisterRules registers the rules of a game mode, making the mode valid for new games and playable through
+// the generic move endpoint. Modes register their rules when the package is initialized; registering a mode
+// twice panics, as that is a programming error.
+func RegisterRules(mode string, rules GameRules) {
+	if _, ok := rulesRegistry[mode]; ok {
+		panic(fmt.Sprintf("rules for game mode %q registered twice", mode))
+	}
+	rulesRegistry[mode] = rules
+}
+
+// RulesFor returns the registered rules of a game mode, or false if the mode has none.
+func RulesFor(mode string) (GameRules, bool) {
+	rules, ok := rulesRegistry[mode]
+	return rules, ok
+}
+
+// RegisteredModes lists the game modes with registered rules, in alphabetical order.
+func RegisteredModes() []string {
+	modes := make([]string, 0, len(rulesRegistry))
+	for mode := range rulesRegistry {
+		modes = append(modes, mode)
+	}
+	sort.Strings(modes)
+	return modes
+}
+
+// Rules returns the rules of the game's mode, with the game's house rules added if it has any, or an error if its
+// mode has none registered.
+func (g *Game) Rules() (GameRules, error) {
+	rules, ok := RulesFor(g.Mode)
+	if !ok {
+		return nil, fmt.Errorf("game mode %q has no rules registered for moves", g.Mode)
+	}
+	if g.HouseRules == "" {
+		return rules, nil
+	}
+	if g.houseScript == nil {
+		script, err := compileHouseRules(g.HouseRules)
+		if err != nil {
+			return nil, err
+		}
+		g.houseScript = script
+	}
+	return withHouseRules{GameRules: rules, script: g.houseScript}, nil
+}
+
+// checkMoveType returns a violation for a move that is not one of the mode's moves.
+func checkMoveType(a Action, moves ...string) error {
+	for _, move := range moves {
+		if a.Type == move {
+			return nil
+		}
+	}
+	return Violations{{Rule: RuleAction, Reason: a.Type + " is not a move in this game mode"}}
+}
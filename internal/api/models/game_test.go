@@ -0,0 +1,79 @@
+package models
+
+import (
+	"fmt"
+	"my-card-game/internal/entropy"
+	"strings"
+	"testing"
+)
+
+func TestShuffleDeckIsUniform(t *testing.T) {
+	// Shuffle a four-card deck many times from a fixed seed and count how often each of its 24 orders comes up
+	const shuffles = 48000
+	g := &Game{}
+	g.UseEntropy(entropy.Seeded(42))
+	cards := NewDeck().Cards[:4]
+	counts := map[string]int{}
+	for i := 0; i < shuffles; i++ {
+		g.GameDeck = append(g.GameDeck[:0], cards...)
+		g.ShuffleDeck()
+		counts[deckOrder(g.GameDeck)]++
+	}
+	g.Shuffles = nil
+
+	if len(counts) != 24 {
+		t.Fatalf("shuffles produced %d distinct orders, want 24", len(counts))
+	}
+
+	// Every order should come up about as often as the others: with 23 degrees of freedom, a chi-squared statistic
+	// above 49.73 has less than a 0.1% chance of coming from a uniform shuffle
+	expected := float64(shuffles) / 24
+	chiSquared := 0.0
+	for _, count := range counts {
+		diff := float64(count) - expected
+		chiSquared += diff * diff / expected
+	}
+	if chiSquared > 49.73 {
+		t.Errorf("chi-squared of the shuffled orders is %.2f, want at most 49.73; counts: %v", chiSquared, counts)
+	}
+}
+
+// benchDecks are the shoe sizes the deck benchmarks run with, from a single deck up to an eight-deck shoe.
+var benchDecks = []int{1, 2, 6, 8}
+
+func BenchmarkShuffleDeck(b *testing.B) {
+	for _, decks := range benchDecks {
+		b.Run(fmt.Sprintf("decks=%d", decks), func(b *testing.B) {
+			g := newBenchGame(decks, 6)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				g.ShuffleDeck()
+				g.Shuffles = nil
+			}
+		})
+	}
+}
+
+// newBenchGame returns an active game with the given number of players and a deck made of that many standard
+// decks, shuffled from a fixed seed so every run measures the same work.
+func newBenchGame(decks, players int) *Game {
+	g := &Game{Status: StatusActive, PlayerHands: map[string][]Card{}}
+	for i := 0; i < players; i++ {
+		g.Players = append(g.Players, fmt.Sprintf("player%d", i+1))
+	}
+	for i := 0; i < decks; i++ {
+		g.AddDeckToGame(NewDeck())
+	}
+	g.UseEntropy(entropy.Seeded(1))
+	return g
+}
+
+// deckOrder returns the codes of the cards in order, as one string.
+func deckOrder(cards []Card) string {
+	codes := make([]string, len(cards))
+	for i, card := range cards {
+		codes[i] = CardCode(card)
+	}
+	return strings.Join(codes, " ")
+}
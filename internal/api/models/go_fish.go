@@ -0,0 +1,170 @@
+package models
+
+import "errors"
+
+// goFishBookSize is the number of cards of the same value that make a book in Go Fish.
+const goFishBookSize = 4
+
+// DealGoFish deals the opening hands of a Go Fish game, sized by OpeningHandSize.
+// Any books dealt straight into a hand are collected immediately.
+func (g *Game) DealGoFish() error {
+	if len(g.Players) < 2 {
+		return errors.New("go fish requires at least two players")
+	}
+
+	if err := g.DealOpeningHands(g.OpeningHandSize(), 0); err != nil {
+		return err
+	}
+	g.Books = map[string][]string{}
+	for _, player := range g.Players {
+		g.Books[player] = []string{}
+		g.CollectBooks(player)
+	}
+
+	return nil
+}
+
+// GoFishAsk is what came of one ask in Go Fish.
+type GoFishAsk struct {
+	Received int      // Cards of the asked value handed over by the target; none means the asker went fishing
+	Drew     *Card    // Card drawn when fishing, unless the deck was empty
+	Lucky    bool     // Whether the card drawn was of the asked value
+	Books    []string // Values the asker completed books of
+	GameOver bool     // Whether the ask ended the game
+}
+
+// Ask plays one ask in Go Fish: the target hands over every card of the value, or the asker goes fishing and
+// draws from the deck. Completed books are collected and empty hands refilled. The asker keeps the turn while
+// they get the cards they asked for; otherwise it passes on, over any players left without cards to ask with.
+// The game ends when every card has been booked, with the most books winning.
+func (g *Game) Ask(asker, target, value string) GoFishAsk {
+	ask := GoFishAsk{}
+
+	// Transfer the matching cards, or go fish if the target has none
+	taken := g.TakeValue(target, value)
+	if len(taken) > 0 {
+		g.PlayerHands[asker] = append(g.PlayerHands[asker], taken...)
+		ask.Received = len(taken)
+	} else if len(g.GameDeck) > 0 {
+		drawn := g.DrawFromDeck(asker, 1)[0]
+		ask.Drew = &drawn
+		ask.Lucky = drawn.Value.String() == value
+	}
+
+	// Collect any books the asker has completed and keep everyone supplied with cards
+	ask.Books = g.CollectBooks(asker)
+	g.RefillEmptyHands()
+	for _, player := range g.Players {
+		g.CollectBooks(player)
+	}
+
+	if g.Turn != nil && ask.Received == 0 && !ask.Lucky {
+		g.Turn.Advance()
+		for i := 1; i < len(g.Turn.Queue) && len(g.PlayerHands[g.Turn.Player()]) == 0; i++ {
+			g.Turn.Advance()
+		}
+	}
+
+	// In a team game the team with the most books wins
+	if g.GoFishOver() {
+		g.Status = StatusFinished
+		g.Winner = g.MostBooks()
+		g.DecideWinningTeam()
+		ask.GameOver = true
+	}
+	return ask
+}
+
+// HoldsValue reports whether the player holds at least one card of the given value.
+func (g *Game) HoldsValue(playerName, value string) bool {
+	for _, card := range g.PlayerHands[playerName] {
+		if card.Value.String() == value {
+			return true
+		}
+	}
+	return false
+}
+
+// TakeValue removes every card of the given value from the player's hand and returns them.
+func (g *Game) TakeValue(playerName, value string) []Card {
+	taken := []Card{}
+	kept := []Card{}
+	for _, card := range g.PlayerHands[playerName] {
+		if card.Value.String() == value {
+			taken = append(taken, card)
+		} else {
+			kept = append(kept, card)
+		}
+	}
+	g.PlayerHands[playerName] = kept
+	return taken
+}
+
+// CollectBooks moves every complete book (four cards of the same value) out of the player's
+// hand and records it against the player. It returns the values of the books collected.
+func (g *Game) CollectBooks(playerName string) []string {
+	counts := map[string]int{}
+	for _, card := range g.PlayerHands[playerName] {
+		counts[card.Value.String()]++
+	}
+
+	collected := []string{}
+	for _, value := range CardValues {
+		for counts[value] >= goFishBookSize {
+			// Remove exactly one book's worth of cards of this value
+			removed := 0
+			kept := []Card{}
+			for _, card := range g.PlayerHands[playerName] {
+				if card.Value.String() == value && removed < goFishBookSize {
+					removed++
+					continue
+				}
+				kept = append(kept, card)
+			}
+			g.PlayerHands[playerName] = kept
+			counts[value] -= goFishBookSize
+			collected = append(collected, value)
+		}
+	}
+
+	if g.Books == nil {
+		g.Books = map[string][]string{}
+	}
+	g.Books[playerName] = append(g.Books[playerName], collected...)
+	return collected
+}
+
+// RefillEmptyHands gives a card from the deck to every player whose hand has run out, so play can continue.
+// Cards go out in deal order, so a short deck favours the players to the dealer's left.
+func (g *Game) RefillEmptyHands() {
+	for _, player := range g.DealOrder() {
+		if len(g.PlayerHands[player]) == 0 && len(g.GameDeck) > 0 {
+			g.PlayerHands[player] = append(g.PlayerHands[player], g.GameDeck[0])
+			g.GameDeck = g.GameDeck[1:]
+		}
+	}
+}
+
+// GoFishOver reports whether a Go Fish game has ended, which happens once the deck and every hand are empty.
+func (g *Game) GoFishOver() bool {
+	if len(g.GameDeck) > 0 {
+		return false
+	}
+	for _, player := range g.Players {
+		if len(g.PlayerHands[player]) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// MostBooks returns the player with the most books, preferring the earliest seat on a tie.
+func (g *Game) MostBooks() string {
+	winner := ""
+	for _, player := range g.Players {
+		if winner == "" || len(g.Books[player]) > len(g.Books[winner]) {
+			winner = player
+		}
+	}
+	return winner
+}
@@ -0,0 +1,188 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Phases of a hand. A hand is dealt straight into play, moves to the showdown once play ends, and is scored
+// before the next hand is dealt.
+const (
+	HandPhasePlay     = "play"
+	HandPhaseShowdown = "showdown"
+	HandPhaseScored   = "scored"
+)
+
+// HandSummary records how a completed hand went, kept in the game's hand history once the hand is scored.
+type HandSummary struct {
+	Number     int            `bson:"number" json:"number"`
+	Dealer     string         `bson:"dealer" json:"dealer"`
+	StartedAt  time.Time      `bson:"started_at" json:"started_at"`
+	ScoredAt   time.Time      `bson:"scored_at" json:"scored_at"`
+	Values     map[string]int `bson:"values" json:"values"`                             // Each player's hand value at the showdown, or books in Go Fish
+	Winners    []string       `bson:"winners" json:"winners"`                           // Players with the best value; several on a tie
+	Payouts    map[string]int `bson:"payouts,omitempty" json:"payouts,omitempty"`       // Chips paid to each player, if the hand was bet on
+	Settlement *Settlement    `bson:"settlement,omitempty" json:"settlement,omitempty"` // How each bet was resolved, if the hand was bet on
+	Contract   *Contract      `bson:"contract,omitempty" json:"contract,omitempty"`     // Contract bid for the hand, if it had an auction
+
+	Hands     map[string][]PlayerHand `bson:"hands,omitempty" json:"hands,omitempty"`           // Each player's settled hands, in blackjack
+	Insurance map[string]int          `bson:"insurance,omitempty" json:"insurance,omitempty"`   // Insurance each player staked, in blackjack; it pays 2:1 if the house hand is a blackjack
+	HouseHand []Card                  `bson:"house_hand,omitempty" json:"house_hand,omitempty"` // The house's final hand, in blackjack
+}
+
+// DealHand clears the table of the previous hand and deals the next one. The dealer button moves on, the cards
+// are gathered and reshuffled, and the opening hands are dealt for the game's mode; in modes without opening
+// hands, unless the settings choose a hand size, the players start empty-handed and are dealt cards one at a time.
+// The new hand goes straight into play. Blackjack can be dealt to a single player, who plays against the house,
+// and the bets placed since the last hand was scored are played on the new hand.
+func (g *Game) DealHand() error {
+	if g.Mode == ModeWar {
+		return errors.New("war is played as a single hand")
+	}
+	if g.HandPhase == HandPhasePlay || g.HandPhase == HandPhaseShowdown {
+		return errors.New("the current hand has not been scored")
+	}
+	if len(g.Players) < 2 && g.Mode != ModeBlackjack {
+		return errors.New("at least two players are needed to deal a hand")
+	}
+
+	// Blackjack bets are placed between hands, so they carry over onto the hand being dealt
+	wagers := g.Bets
+	g.ClearTable()
+	if g.Mode == ModeBlackjack && wagers != nil {
+		g.Bets = wagers
+	}
+	g.HandNumber++
+	g.AdvanceButton()
+	g.ShuffleDeck()
+
+	// Play starts to the dealer's left
+	g.Turn = NewTurnOrder(g.Players, (g.DealerIndex+1)%len(g.Players))
+	g.HandPhase = HandPhasePlay
+	g.HandStartedAt = time.Now().UTC()
+
+	switch g.Mode {
+	case ModeCrazyEights:
+		if err := g.DealCrazyEights(); err != nil {
+			return err
+		}
+	case ModeGoFish:
+		g.Books = map[string][]string{}
+		if err := g.DealGoFish(); err != nil {
+			return err
+		}
+	case ModeBlackjack:
+		if err := g.DealBlackjack(); err != nil {
+			return err
+		}
+	default:
+		if err := g.DealOpeningHands(g.OpeningHandSize(), 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OpeningHandSize returns how many cards each player is dealt when a game or hand starts: the hand size chosen
+// in the game's settings, or otherwise the mode's default. Crazy Eights deals seven cards to two players and
+// five to more, Go Fish seven to up to three players and five to more, and other modes deal no opening hands.
+func (g *Game) OpeningHandSize() int {
+	if g.Settings.HandSize > 0 {
+		return g.Settings.HandSize
+	}
+	switch g.Mode {
+	case ModeCrazyEights:
+		if len(g.Players) == 2 {
+			return 7
+		}
+		return 5
+	case ModeGoFish:
+		if len(g.Players) <= 3 {
+			return 7
+		}
+		return 5
+	}
+	return 0
+}
+
+// DealOpeningHands deals handSize cards to every seated player, one card at a time around the table starting to
+// the dealer's left, replacing any hands they held. The deck must hold enough cards for every hand plus reserve,
+// the cards the mode needs left over once the hands are dealt.
+func (g *Game) DealOpeningHands(handSize, reserve int) error {
+	if needed := handSize*len(g.Players) + reserve; len(g.GameDeck) < needed {
+		return fmt.Errorf("not enough cards in the game deck: %d players with %d cards each need %d, but the deck has %d",
+			len(g.Players), handSize, needed, len(g.GameDeck))
+	}
+
+	g.PlayerHands = map[string][]Card{}
+	order := g.DealOrder()
+	for i := 0; i < handSize; i++ {
+		for _, player := range order {
+			g.PlayerHands[player] = append(g.PlayerHands[player], g.GameDeck[0])
+			g.GameDeck = g.GameDeck[1:]
+		}
+	}
+	return nil
+}
+
+// ClearTable gathers every card on the table back into the deck: the players' hands, the melds, the discard
+// pile, and the house's blackjack hand. The per-hand state goes with them: the declared suit, the bets, folds,
+// redraws, and insurance, the turn order, and the auction.
+// Chips, books, and the hand history are kept.
+func (g *Game) ClearTable() {
+	for _, player := range g.Players {
+		g.GameDeck = append(g.GameDeck, g.PlayerHands[player]...)
+	}
+	for _, meld := range g.Melds {
+		g.GameDeck = append(g.GameDeck, meld.Cards...)
+	}
+	g.GameDeck = append(g.GameDeck, g.DiscardPile...)
+	g.GameDeck = append(g.GameDeck, g.DealerHand...)
+
+	g.PlayerHands = map[string][]Card{}
+	g.Hands = nil
+	g.DealerHand = nil
+	g.Insurance = nil
+	g.Melds = nil
+	g.DiscardPile = nil
+	g.DeclaredSuit = ""
+	g.Bets = map[string]int{}
+	g.Folded = nil
+	g.Redraws = nil
+	g.Turn = nil
+	g.Auction = nil
+}
+
+// HandValues values every seated player's hand at the showdown, and returns the values with the players holding
+// the best of them. In blackjack the players are valued by their best hand and win by beating the house. In Go Fish a player's value is the number of books they completed, and the most books wins;
+// otherwise hands are valued by the game's scoring strategy, and players who folded cannot win. When the game ranks
+// suits, equal values are won by the player holding the highest card instead of being shared.
+func (g *Game) HandValues() (map[string]int, []string) {
+	if g.Mode == ModeBlackjack {
+		return g.blackjackValues()
+	}
+
+	values := map[string]int{}
+	ranks := map[string]int{}
+	contenders := []string{}
+
+	strategy := g.ScoringStrategy()
+	for _, player := range g.Players {
+		if g.Mode == ModeGoFish {
+			values[player] = len(g.Books[player])
+			ranks[player] = values[player]
+		} else {
+			values[player] = strategy.HandValue(g.PlayerHands[player])
+			ranks[player] = values[player]
+			if strategy.LowestWins() {
+				ranks[player] = -ranks[player]
+			}
+			ranks[player] = g.rankWithSuits(ranks[player], g.PlayerHands[player])
+		}
+		if !g.hasFolded(player) {
+			contenders = append(contenders, player)
+		}
+	}
+	return values, BestRanked(contenders, ranks)
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+)
+
+func BenchmarkDealHand(b *testing.B) {
+	for _, decks := range benchDecks {
+		b.Run(fmt.Sprintf("decks=%d", decks), func(b *testing.B) {
+			g := newBenchGame(decks, 6)
+			g.Settings.HandSize = 5
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// Score the previous hand so the next one can be dealt
+				g.HandPhase = HandPhaseScored
+				g.Shuffles = nil
+				if err := g.DealHand(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDealOpeningHands(b *testing.B) {
+	for _, decks := range benchDecks {
+		b.Run(fmt.Sprintf("decks=%d", decks), func(b *testing.B) {
+			g := newBenchGame(decks, 6)
+			shoe := append([]Card{}, g.GameDeck...)
+			handSize := len(shoe) / len(g.Players)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// Deal out the whole shoe
+				g.GameDeck = append(g.GameDeck[:0], shoe...)
+				if err := g.DealOpeningHands(handSize, 0); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package models
+
+import (
+	"errors"
+	"my-card-game/internal/entropy"
+)
+
+// Strategies a hint can come from.
+const (
+	StrategyBasic     = "basic_strategy" // Blackjack basic strategy for a house that stands on every 17
+	StrategyHeuristic = "heuristic"      // The rules of thumb heuristic bots follow, played as DefaultBotConfig
+)
+
+// Hint is a move suggested to a player on their turn. The move is one the game's rules allow right now.
+type Hint struct {
+	Player       string `json:"player"`
+	Action       string `json:"action"`                  // Action type, such as play_card, ask, or hit
+	Card         *Card  `json:"card,omitempty"`          // Card to play in Crazy Eights
+	DeclaredSuit string `json:"declared_suit,omitempty"` // Suit to declare with an eight or wild card
+	Target       string `json:"target,omitempty"`        // Player to ask in Go Fish
+	Value        string `json:"value,omitempty"`         // Card value to ask for in Go Fish
+	Strategy     string `json:"strategy"`                // StrategyBasic or StrategyHeuristic
+	Reason       string `json:"reason"`                  // Why the move is suggested, in plain words
+}
+
+// SuggestMove suggests the player's next move, using only what the player can see. Blackjack players are told the
+// basic strategy play for their hand against the house's face-up card, taking the split, double, or surrender it
+// calls for only when the rules allow it. Crazy Eights and Go Fish players are told the move a heuristic bot would
+// make. It returns ErrNoMove if the game gives the player no move.
+func (g *Game) SuggestMove(playerName string, source entropy.Source) (Hint, error) {
+	if g.Mode == ModeBlackjack {
+		return g.basicStrategyHint(playerName)
+	}
+	if g.CanSeatBots() != nil {
+		return Hint{}, errors.New("hints are only given in crazy eights, go fish, and blackjack games")
+	}
+
+	action, err := g.ChooseAction(playerName, DefaultBotConfig, source)
+	if err != nil {
+		return Hint{}, err
+	}
+	hint := Hint{Player: playerName, Action: action.Type, Target: action.Target, Value: action.Value, Strategy: StrategyHeuristic}
+	switch {
+	case action.Type == ActionDraw:
+		hint.Reason = "no card in your hand plays on the top card"
+	case action.Type == ActionAsk:
+		hint.Reason = "you hold more cards of this value than of any other"
+	case g.PlaysAnywhere(action.Cards[0]):
+		hint.Card, hint.DeclaredSuit = &action.Cards[0], action.DeclaredSuit
+		hint.Reason = "only an eight or wild card plays; name the suit you hold most of"
+	default:
+		hint.Card = &action.Cards[0]
+		hint.Reason = "the card follows the top card, keeping your eights and wild cards back"
+	}
+	return hint, nil
+}
+
+// basicStrategyHint suggests the basic strategy play for the player's current blackjack hand.
+func (g *Game) basicStrategyHint(playerName string) (Hint, error) {
+	up, ok := g.DealerUpCard()
+	index := g.CurrentHand(playerName)
+	if !ok || index == -1 || g.CheckAction(Action{Type: ActionStand, Player: playerName}) != nil {
+		return Hint{}, ErrNoMove
+	}
+	hand := g.Hands[playerName][index]
+	allowed := func(actionType string) bool {
+		return g.CheckAction(Action{Type: actionType, Player: playerName}) == nil
+	}
+
+	action, reason := BasicStrategy(hand, up, allowed)
+	return Hint{Player: playerName, Action: action, Strategy: StrategyBasic, Reason: reason}, nil
+}
+
+// BasicStrategy returns the basic strategy play for a blackjack hand against the house's face-up card, for a
+// house that stands on every 17, along with the reason for it. Pairs are split, hands doubled, and hard 15 and 16
+// surrendered against the house's strongest cards only when allowed reports that the move is allowed; otherwise
+// the hand is played as the total it makes.
+func BasicStrategy(hand PlayerHand, up Card, allowed func(actionType string) bool) (string, string) {
+	house := BlackjackScoring{}.HandValue([]Card{up})
+	hard := 0
+	for _, card := range hand.Cards {
+		hard += countingValue(card.Value)
+	}
+	total := hand.Value()
+	soft := total != hard
+
+	// Split the pairs worth splitting
+	if len(hand.Cards) == 2 && hand.Cards[0].Value == hand.Cards[1].Value && allowed(ActionSplit) {
+		pair := countingValue(hand.Cards[0].Value)
+		split := false
+		switch pair {
+		case 1, 8:
+			split = true
+		case 9:
+			split = house <= 9 && house != 7
+		case 7, 3, 2:
+			split = house <= 7
+		case 6:
+			split = house <= 6
+		case 4:
+			split = house == 5 || house == 6
+		}
+		if split {
+			return ActionSplit, "split the pair against the house's " + up.Value.String()
+		}
+	}
+
+	// Give up half the bet on hard 16 against 9, 10, or an ace, and hard 15 against 10
+	if !soft && ((total == 16 && house >= 9) || (total == 15 && house == 10)) && allowed(ActionSurrender) {
+		return ActionSurrender, "the hand loses more often than not against the house's " + up.Value.String()
+	}
+
+	double := func(reason string) (string, string) {
+		if allowed(ActionDouble) {
+			return ActionDouble, reason
+		}
+		return ActionHit, "the hand is worth improving, but it cannot be doubled"
+	}
+	if soft {
+		switch {
+		case total >= 19:
+			return ActionStand, "a soft 19 or more is strong enough to stand"
+		case total == 18 && house >= 3 && house <= 6:
+			if allowed(ActionDouble) {
+				return ActionDouble, "a soft 18 cannot bust and the house is likely to"
+			}
+			return ActionStand, "a soft 18 beats what the house is likely to make"
+		case total == 18 && house <= 8:
+			return ActionStand, "a soft 18 beats what the house is likely to make"
+		case total == 17 && house >= 3 && house <= 6,
+			(total == 15 || total == 16) && house >= 4 && house <= 6,
+			(total == 13 || total == 14) && house >= 5 && house <= 6:
+			return double("a soft hand cannot bust on one card and the house is likely to")
+		default:
+			return ActionHit, "a soft hand cannot bust on one card"
+		}
+	}
+
+	switch {
+	case total >= 17:
+		return ActionStand, "a hard 17 or more is likely to bust on another card"
+	case total >= 13 && house <= 6:
+		return ActionStand, "leave the house to bust with its weak " + up.Value.String()
+	case total == 12 && house >= 4 && house <= 6:
+		return ActionStand, "leave the house to bust with its weak " + up.Value.String()
+	case total == 11 && house <= 10, total == 10 && house <= 9, total == 9 && house >= 3 && house <= 6:
+		return double("the next card is likely to make a strong hand")
+	default:
+		return ActionHit, "the hand is too weak to stand against the house's " + up.Value.String()
+	}
+}
@@ -0,0 +1,529 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.starlark.net/resolve"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+// House rules are a short Starlark script attached to a game that adds to its mode's rules, so a table can play its
+// own variant without changes to the server. The script can define two functions:
+//
+//	def reject(move, game): ...  # Returns the reason the move is refused, or None to allow it
+//	def bonus(player, game): ... # Returns the points added to the player's value when a round is scored
+//
+// reject is called once the mode's own rules allow a move; rules that refuse every move a player has leave the game
+// waiting on them, so they are best written against moves that have an alternative. bonus is called for each
+// player when a round is scored, and the round's winners are still decided by the mode. For example:
+//
+//	# No eight on an eight, and a bonus for going out on a long game
+//	def reject(move, game):
+//	    if move.cards and move.cards[0].value == "8" and game.top and game.top.value == "8":
+//	        return "an eight cannot be played on an eight"
+//
+//	def bonus(player, game):
+//	    return 10 if player.won and game.deck_size < 10 else 0
+//
+// The values the functions are given have the fields listed in houseFields. Scripts are sandboxed: they can only
+// read those fields and call the builtins in houseBuiltins, every run stops after maxHouseRulesSteps steps, and the
+// values a run builds with + * | and slicing are counted against maxHouseRulesMemory. Strings cannot be formatted
+// with %, and the dialect has no while loops or recursion, so a script cannot build a large value in one step either.
+
+// Limits on house rules scripts, which keep checking a move and scoring a round quick whatever a game's owner uploads.
+const (
+	maxHouseRulesSize   = 4096    // Longest script, in bytes
+	maxHouseRulesSteps  = 10000   // Most steps one run of the script or one of its functions can take
+	maxHouseRulesMemory = 1 << 16 // Most bytes one run can build, counted by houseSize
+)
+
+// houseFields are the fields of the values house rules functions are given. reject is given the move and the game;
+// bonus is given the player being scored and the game.
+var houseFields = map[string]bool{
+	"type":          true, // move: Move being made, such as play_card, draw, ask, or hit
+	"player":        true, // move: Player making the move
+	"target":        true, // move: Player asked for cards in Go Fish
+	"value":         true, // move: Card value asked for in Go Fish; card: the card's value; player: their value as the mode scores it
+	"amount":        true, // move: Chips bet, or cards drawn as a penalty
+	"cards":         true, // move: Cards played, as a list of cards
+	"hand_size":     true, // move: Cards in the moving player's hand, or in their current hand in blackjack
+	"suit":          true, // card: the card's suit
+	"name":          true, // player: Player being scored
+	"hand":          true, // player: Cards left in the player's hand
+	"books":         true, // player: Books the player completed in Go Fish
+	"won":           true, // player: Whether the player won the round
+	"mode":          true, // game: Game mode, such as crazy_eights
+	"deck_size":     true, // game: Cards left in the deck
+	"top":           true, // game: Top card of the discard pile, or the house's face-up card in blackjack; None if there is none
+	"declared_suit": true, // game: Suit named with the last wild card played in Crazy Eights
+}
+
+// houseBuiltins are the builtins house rules can call, besides None, True, and False. Builtins that build lists or
+// strings from other values, and print, are left out.
+var houseBuiltins = map[string]bool{
+	"None": true, "True": true, "False": true,
+	"abs": true, "all": true, "any": true, "bool": true, "fail": true, "int": true, "len": true, "max": true, "min": true,
+}
+
+// houseDialect is the Starlark dialect house rules are written in: without while loops, recursion, sets, or
+// statements outside functions other than definitions and assignments.
+var houseDialect = &syntax.FileOptions{}
+
+// houseGuards name the functions the operators that can build large values are replaced with, which count what
+// they build against the run's memory before building it.
+var houseGuards = map[syntax.Token]string{
+	syntax.PLUS:    "$plus",
+	syntax.STAR:    "$star",
+	syntax.PERCENT: "$percent",
+	syntax.PIPE:    "$pipe",
+}
+
+// houseAugmented maps the augmented assignments of the guarded operators to their operators.
+var houseAugmented = map[syntax.Token]syntax.Token{
+	syntax.PLUS_EQ:    syntax.PLUS,
+	syntax.STAR_EQ:    syntax.STAR,
+	syntax.PERCENT_EQ: syntax.PERCENT,
+	syntax.PIPE_EQ:    syntax.PIPE,
+}
+
+// houseSlice names the function slices are wrapped in, which counts them against the run's memory.
+const houseSlice = "$slice"
+
+// housePredeclared are the guards the compiled script calls. Their names are not identifiers, so scripts cannot
+// call or replace them.
+var housePredeclared = starlark.StringDict{
+	houseSlice: starlark.NewBuiltin(houseSlice, func(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, _ []starlark.Tuple) (starlark.Value, error) {
+		return args[0], useHouseMemory(thread, houseSize(args[0]))
+	}),
+}
+
+func init() {
+	for op, name := range houseGuards {
+		op := op
+		housePredeclared[name] = starlark.NewBuiltin(name, func(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, _ []starlark.Tuple) (starlark.Value, error) {
+			return guardedBinary(thread, op, args[0], args[1])
+		})
+	}
+}
+
+// houseMemoryKey is the thread local holding the bytes a run has built so far.
+const houseMemoryKey = "house_rules_memory"
+
+// houseScript is a compiled house rules script: the functions it defines, or nil for those it leaves out.
+type houseScript struct {
+	reject starlark.Callable
+	bonus  starlark.Callable
+}
+
+// compileHouseRules parses, checks, and runs a house rules script, returning the first problem found with the line
+// it is on. It refuses scripts over the size limit, scripts that read fields or call builtins house rules cannot,
+// and scripts whose top level fails or runs over the limits.
+func compileHouseRules(source string) (*houseScript, error) {
+	if len(source) > maxHouseRulesSize {
+		return nil, fmt.Errorf("house rules cannot be longer than %d bytes", maxHouseRulesSize)
+	}
+
+	f, err := houseDialect.Parse("house rules", source, 0)
+	if err != nil {
+		return nil, err
+	}
+	guardHouseStmts(f.Stmts)
+	program, err := starlark.FileProgram(f, housePredeclared.Has)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkHouseNames(f); err != nil {
+		return nil, err
+	}
+
+	globals, err := program.Init(newHouseThread(), housePredeclared)
+	if err != nil {
+		return nil, fmt.Errorf("house rules: %v", err)
+	}
+	globals.Freeze()
+
+	script := &houseScript{}
+	for name, fn := range map[string]*starlark.Callable{"reject": &script.reject, "bonus": &script.bonus} {
+		value, ok := globals[name]
+		if !ok {
+			continue
+		}
+		if *fn, ok = value.(starlark.Callable); !ok {
+			return nil, fmt.Errorf("house rules: %s must be a function, not a %s", name, value.Type())
+		}
+	}
+	if script.reject == nil && script.bonus == nil {
+		return nil, errors.New("house rules must define reject, bonus, or both")
+	}
+	return script, nil
+}
+
+// checkHouseNames returns an error for the first field or builtin in a resolved script that house rules cannot use.
+func checkHouseNames(f *syntax.File) error {
+	var err error
+	syntax.Walk(f, func(n syntax.Node) bool {
+		switch n := n.(type) {
+		case *syntax.DotExpr:
+			if !houseFields[n.Name.Name] {
+				err = fmt.Errorf("%s: house rules cannot use .%s", n.Name.NamePos, n.Name.Name)
+			}
+		case *syntax.Ident:
+			if binding, ok := n.Binding.(*resolve.Binding); ok && binding.Scope == resolve.Universal && !houseBuiltins[n.Name] {
+				err = fmt.Errorf("%s: house rules cannot use %s", n.NamePos, n.Name)
+			}
+		}
+		return err == nil
+	})
+	return err
+}
+
+// guardHouseStmts replaces the guarded operators and slices in the statements, and their augmented assignments,
+// with calls to their guards.
+func guardHouseStmts(stmts []syntax.Stmt) {
+	for _, stmt := range stmts {
+		switch s := stmt.(type) {
+		case *syntax.AssignStmt:
+			s.RHS = guardHouseExpr(s.RHS)
+			if op, ok := houseAugmented[s.Op]; ok {
+				// x op= y is assigned as x = x op y, reading the target as an expression
+				target := s.LHS
+				if id, ok := target.(*syntax.Ident); ok {
+					target = &syntax.Ident{NamePos: id.NamePos, Name: id.Name}
+				}
+				s.RHS = houseGuardCall(houseGuards[op], s.OpPos, guardHouseExpr(target), s.RHS)
+				s.Op = syntax.EQ
+			}
+		case *syntax.DefStmt:
+			guardHouseExprs(s.Params)
+			guardHouseStmts(s.Body)
+		case *syntax.ExprStmt:
+			s.X = guardHouseExpr(s.X)
+		case *syntax.ForStmt:
+			s.X = guardHouseExpr(s.X)
+			guardHouseStmts(s.Body)
+		case *syntax.WhileStmt:
+			s.Cond = guardHouseExpr(s.Cond)
+			guardHouseStmts(s.Body)
+		case *syntax.IfStmt:
+			s.Cond = guardHouseExpr(s.Cond)
+			guardHouseStmts(s.True)
+			guardHouseStmts(s.False)
+		case *syntax.ReturnStmt:
+			if s.Result != nil {
+				s.Result = guardHouseExpr(s.Result)
+			}
+		}
+	}
+}
+
+// guardHouseExprs guards each expression of a list in place.
+func guardHouseExprs(exprs []syntax.Expr) {
+	for i, e := range exprs {
+		exprs[i] = guardHouseExpr(e)
+	}
+}
+
+// guardHouseExpr returns the expression with the guarded operators and slices in it replaced by calls to their
+// guards. Keyword arguments and parameter defaults are written as x=y, so only their values are guarded.
+func guardHouseExpr(e syntax.Expr) syntax.Expr {
+	switch e := e.(type) {
+	case *syntax.BinaryExpr:
+		if e.Op == syntax.EQ {
+			e.Y = guardHouseExpr(e.Y)
+			return e
+		}
+		e.X, e.Y = guardHouseExpr(e.X), guardHouseExpr(e.Y)
+		if name, ok := houseGuards[e.Op]; ok {
+			return houseGuardCall(name, e.OpPos, e.X, e.Y)
+		}
+	case *syntax.SliceExpr:
+		e.X = guardHouseExpr(e.X)
+		for _, index := range []*syntax.Expr{&e.Lo, &e.Hi, &e.Step} {
+			if *index != nil {
+				*index = guardHouseExpr(*index)
+			}
+		}
+		return houseGuardCall(houseSlice, e.Lbrack, e)
+	case *syntax.CallExpr:
+		e.Fn = guardHouseExpr(e.Fn)
+		guardHouseExprs(e.Args)
+	case *syntax.Comprehension:
+		e.Body = guardHouseExpr(e.Body)
+		for _, clause := range e.Clauses {
+			switch clause := clause.(type) {
+			case *syntax.ForClause:
+				clause.X = guardHouseExpr(clause.X)
+			case *syntax.IfClause:
+				clause.Cond = guardHouseExpr(clause.Cond)
+			}
+		}
+	case *syntax.CondExpr:
+		e.Cond, e.True, e.False = guardHouseExpr(e.Cond), guardHouseExpr(e.True), guardHouseExpr(e.False)
+	case *syntax.DictExpr:
+		for _, entry := range e.List {
+			entry := entry.(*syntax.DictEntry)
+			entry.Key, entry.Value = guardHouseExpr(entry.Key), guardHouseExpr(entry.Value)
+		}
+	case *syntax.DictEntry:
+		e.Key, e.Value = guardHouseExpr(e.Key), guardHouseExpr(e.Value)
+	case *syntax.DotExpr:
+		e.X = guardHouseExpr(e.X)
+	case *syntax.IndexExpr:
+		e.X, e.Y = guardHouseExpr(e.X), guardHouseExpr(e.Y)
+	case *syntax.LambdaExpr:
+		guardHouseExprs(e.Params)
+		e.Body = guardHouseExpr(e.Body)
+	case *syntax.ListExpr:
+		guardHouseExprs(e.List)
+	case *syntax.ParenExpr:
+		e.X = guardHouseExpr(e.X)
+	case *syntax.TupleExpr:
+		guardHouseExprs(e.List)
+	case *syntax.UnaryExpr:
+		if e.X != nil {
+			e.X = guardHouseExpr(e.X)
+		}
+	}
+	return e
+}
+
+// houseGuardCall builds a call to the named guard with the arguments.
+func houseGuardCall(name string, pos syntax.Position, args ...syntax.Expr) syntax.Expr {
+	return &syntax.CallExpr{Fn: &syntax.Ident{NamePos: pos, Name: name}, Lparen: pos, Args: args, Rparen: pos}
+}
+
+// guardedBinary applies a guarded operator, once it has counted the size of the value it builds against the run's
+// memory.
+func guardedBinary(thread *starlark.Thread, op syntax.Token, x, y starlark.Value) (starlark.Value, error) {
+	size := houseSize(x) + houseSize(y)
+	switch op {
+	case syntax.STAR:
+		size = houseRepeatSize(x, y)
+	case syntax.PERCENT:
+		if _, ok := x.(starlark.String); ok {
+			return nil, errors.New("house rules cannot format strings with %")
+		}
+	}
+	if err := useHouseMemory(thread, size); err != nil {
+		return nil, err
+	}
+	return starlark.Binary(op, x, y)
+}
+
+// houseRepeatSize returns the size of x * y: a sequence repeated a number of times, or the product of two numbers.
+func houseRepeatSize(x, y starlark.Value) int {
+	if _, ok := x.(starlark.Int); ok {
+		x, y = y, x
+	}
+	n, ok := y.(starlark.Int)
+	if _, isInt := x.(starlark.Int); isInt || !ok {
+		return houseSize(x) + houseSize(y)
+	}
+	count, ok := n.Int64()
+	if !ok || count > maxHouseRulesMemory {
+		return maxHouseRulesMemory + 1
+	}
+	if count < 0 {
+		return 0
+	}
+	return houseSize(x) * int(count)
+}
+
+// houseSize estimates the bytes a value holds, not counting what its elements hold.
+func houseSize(v starlark.Value) int {
+	switch v := v.(type) {
+	case starlark.String:
+		return len(v)
+	case starlark.Bytes:
+		return len(v)
+	case starlark.Int:
+		return v.BigInt().BitLen()/8 + 1
+	case *starlark.Dict:
+		return 32 * v.Len()
+	case starlark.Indexable:
+		return 16 * v.Len()
+	}
+	return 16
+}
+
+// useHouseMemory counts size bytes against the memory of the thread's run, returning an error once the run has
+// built more than maxHouseRulesMemory bytes.
+func useHouseMemory(thread *starlark.Thread, size int) error {
+	used := thread.Local(houseMemoryKey).(*int)
+	if *used += size; *used > maxHouseRulesMemory {
+		return fmt.Errorf("house rules cannot use more than %d bytes of memory", maxHouseRulesMemory)
+	}
+	return nil
+}
+
+// newHouseThread returns a thread for one run of a house rules script or one of its functions, with its own step
+// and memory limits.
+func newHouseThread() *starlark.Thread {
+	thread := &starlark.Thread{Name: "house rules"}
+	thread.SetMaxExecutionSteps(maxHouseRulesSteps)
+	thread.SetLocal(houseMemoryKey, new(int))
+	return thread
+}
+
+// houseCard describes a card to house rules, or returns None for no card.
+func houseCard(card Card, ok bool) starlark.Value {
+	if !ok {
+		return starlark.None
+	}
+	return starlarkstruct.FromStringDict(starlark.String("card"), starlark.StringDict{
+		"value": starlark.String(card.Value.String()),
+		"suit":  starlark.String(card.Suit.String()),
+	})
+}
+
+// houseGame describes the game to house rules.
+func houseGame(g *Game) starlark.Value {
+	top, ok := g.TopDiscard()
+	if g.Mode == ModeBlackjack {
+		top, ok = g.DealerUpCard()
+	}
+	return starlarkstruct.FromStringDict(starlark.String("game"), starlark.StringDict{
+		"mode":          starlark.String(g.Mode),
+		"deck_size":     starlark.MakeInt(len(g.GameDeck)),
+		"top":           houseCard(top, ok),
+		"declared_suit": starlark.String(g.DeclaredSuit),
+	})
+}
+
+// check returns the move as a Violation if the script's reject function refuses it, or nil if it allows it. A
+// reject function that fails refuses the move with the reason it failed.
+func (s *houseScript) check(g *Game, a Action) error {
+	if s.reject == nil {
+		return nil
+	}
+
+	cards := make([]starlark.Value, len(a.Cards))
+	for i, card := range a.Cards {
+		cards[i] = houseCard(card, true)
+	}
+	handSize := len(g.PlayerHands[a.Player])
+	if g.Mode == ModeBlackjack {
+		if index := g.CurrentHand(a.Player); index != -1 {
+			handSize = len(g.Hands[a.Player][index].Cards)
+		}
+	}
+	move := starlarkstruct.FromStringDict(starlark.String("move"), starlark.StringDict{
+		"type":      starlark.String(a.Type),
+		"player":    starlark.String(a.Player),
+		"target":    starlark.String(a.Target),
+		"value":     starlark.String(a.Value),
+		"amount":    starlark.MakeInt(a.Amount),
+		"cards":     starlark.NewList(cards),
+		"hand_size": starlark.MakeInt(handSize),
+	})
+
+	result, err := starlark.Call(newHouseThread(), s.reject, starlark.Tuple{move, houseGame(g)}, nil)
+	reason := ""
+	switch result := result.(type) {
+	case nil:
+		reason = "house rules failed: " + err.Error()
+	case starlark.NoneType:
+	case starlark.String:
+		reason = string(result)
+	default:
+		reason = fmt.Sprintf("house rules returned a %s from reject, not a reason", result.Type())
+	}
+	if strings.TrimSpace(reason) == "" {
+		return nil
+	}
+	return Violations{{Rule: RuleHouse, Reason: reason}}
+}
+
+// score adds each player's bonus to their value. The bonus function sees the value the mode scored; a bonus
+// function that fails, or returns something other than a whole number, gives the player no bonus.
+func (s *houseScript) score(g *Game, values map[string]int, winners []string) {
+	if s.bonus == nil {
+		return
+	}
+	game := houseGame(g)
+	for _, player := range g.Players {
+		won := false
+		for _, winner := range winners {
+			won = won || winner == player
+		}
+		scored := starlarkstruct.FromStringDict(starlark.String("player"), starlark.StringDict{
+			"name":  starlark.String(player),
+			"value": starlark.MakeInt(values[player]),
+			"hand":  starlark.MakeInt(len(g.PlayerHands[player])),
+			"books": starlark.MakeInt(len(g.Books[player])),
+			"won":   starlark.Bool(won),
+		})
+
+		result, err := starlark.Call(newHouseThread(), s.bonus, starlark.Tuple{scored, game}, nil)
+		if err != nil {
+			continue
+		}
+		if points, ok := result.(starlark.Int); ok {
+			if bonus, ok := points.Int64(); ok {
+				values[player] += int(bonus)
+			}
+		}
+	}
+}
+
+// withHouseRules plays a mode's rules with a game's house rules added: moves the mode allows must also pass the
+// reject rules, and the bonus rules add to the values a round is scored with.
+type withHouseRules struct {
+	GameRules
+	script *houseScript
+}
+
+// ValidateMove checks the move against the mode's rules, then against the house rules.
+func (r withHouseRules) ValidateMove(g *Game, a Action) error {
+	if err := r.GameRules.ValidateMove(g, a); err != nil {
+		return err
+	}
+	return r.script.check(g, a)
+}
+
+// Score scores the round as the mode does, then adds the house rules' bonuses.
+func (r withHouseRules) Score(g *Game) (map[string]int, []string) {
+	values, winners := r.GameRules.Score(g)
+	if values == nil {
+		values = map[string]int{}
+	}
+	r.script.score(g, values, winners)
+	return values, winners
+}
+
+// CheckHouseRules returns the game's house rules a move breaks as Violations, or nil if it breaks none or the game
+// has no house rules. Moves made through Rules are checked already; this is for the actions played outside them.
+func (g *Game) CheckHouseRules(a Action) error {
+	if g.HouseRules == "" {
+		return nil
+	}
+	rules, err := g.Rules()
+	if err != nil {
+		return err
+	}
+	return rules.(withHouseRules).script.check(g, a)
+}
+
+// SetHouseRules attaches a house rules script to the game, replacing any it had, or removes its house rules if
+// source is empty. It returns an error if the script does not compile, or if the game's mode is not played
+// through registered rules for house rules to add to.
+func (g *Game) SetHouseRules(source string) error {
+	if strings.TrimSpace(source) == "" {
+		g.HouseRules, g.houseScript = "", nil
+		return nil
+	}
+	if _, ok := RulesFor(g.Mode); !ok {
+		return fmt.Errorf("house rules cannot be added to %q games", g.Mode)
+	}
+
+	script, err := compileHouseRules(source)
+	if err != nil {
+		return err
+	}
+	g.HouseRules, g.houseScript = source, script
+	return nil
+}
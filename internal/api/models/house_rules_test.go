@@ -0,0 +1,138 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// exampleHouseRules is the example from the house rules documentation.
+const exampleHouseRules = `
+# No eight on an eight, and a bonus for going out on a long game
+def reject(move, game):
+    if move.cards and move.cards[0].value == "8" and game.top and game.top.value == "8":
+        return "an eight cannot be played on an eight"
+
+def bonus(player, game):
+    return 10 if player.won and game.deck_size < 10 else 0
+`
+
+func TestHouseRulesCheckMoves(t *testing.T) {
+	eight := Card{Suit: SuitSpades, Value: RankEight}
+	tests := []struct {
+		name   string
+		script string
+		top    Card
+		cards  []Card
+		want   string
+	}{
+		{
+			name:   "the example refuses an eight on an eight",
+			script: exampleHouseRules,
+			top:    Card{Suit: SuitHearts, Value: RankEight},
+			cards:  []Card{eight},
+			want:   "an eight cannot be played on an eight",
+		},
+		{
+			name:   "the example allows an eight on anything else",
+			script: exampleHouseRules,
+			top:    Card{Suit: SuitHearts, Value: RankNine},
+			cards:  []Card{eight},
+		},
+		{
+			name:   "a script over the step limit refuses the move",
+			script: "def reject(move, game):\n    r = [0] * 100\n    for a in r:\n        for b in r:\n            for c in r:\n                pass\n",
+			cards:  []Card{eight},
+			want:   "house rules failed: Starlark computation cancelled: too many steps",
+		},
+		{
+			name:   "a script over the memory limit refuses the move",
+			script: "def reject(move, game):\n    s = \"x\"\n    for i in [0] * 20:\n        s += s\n    return s[:1]\n",
+			cards:  []Card{eight},
+			want:   "house rules failed: house rules cannot use more than 65536 bytes of memory",
+		},
+		{
+			name:   "a repeat over the memory limit is refused before it is built",
+			script: "def reject(move, game):\n    return \"x\" * 1000000000\n",
+			cards:  []Card{eight},
+			want:   "house rules failed: house rules cannot use more than 65536 bytes of memory",
+		},
+		{
+			name:   "slices are counted against the memory limit",
+			script: "def reject(move, game):\n    s = \"x\" * 60000\n    t = s[1:]\n    return t[:1]\n",
+			cards:  []Card{eight},
+			want:   "house rules failed: house rules cannot use more than 65536 bytes of memory",
+		},
+		{
+			name:   "a recursive script refuses the move",
+			script: "def reject(move, game):\n    return reject(move, game)\n",
+			cards:  []Card{eight},
+			want:   "house rules failed: function reject called recursively",
+		},
+		{
+			name:   "strings cannot be formatted",
+			script: "def reject(move, game):\n    return \"%s\" % move.type\n",
+			cards:  []Card{eight},
+			want:   "house rules failed: house rules cannot format strings with %",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script, err := compileHouseRules(tt.script)
+			if err != nil {
+				t.Fatalf("compileHouseRules: %v", err)
+			}
+			g := &Game{Mode: ModeCrazyEights, DiscardPile: []Card{tt.top}}
+			err = script.check(g, Action{Type: ActionPlayCard, Player: "alice", Cards: tt.cards})
+			got := ""
+			if err != nil {
+				got = err.(Violations)[0].Reason
+			}
+			if got != tt.want {
+				t.Errorf("check() refused the move with %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHouseRulesScoreBonuses(t *testing.T) {
+	script, err := compileHouseRules(exampleHouseRules)
+	if err != nil {
+		t.Fatalf("compileHouseRules: %v", err)
+	}
+	g := &Game{Mode: ModeCrazyEights, Players: []string{"alice", "bob"}, GameDeck: NewDeck().Cards[:5]}
+	values := map[string]int{"alice": 3, "bob": 7}
+	script.score(g, values, []string{"alice"})
+
+	want := map[string]int{"alice": 13, "bob": 7}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("score() = %v, want %v", values, want)
+	}
+}
+
+func TestCompileHouseRulesRefusesUnsafeScripts(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   string
+	}{
+		{name: "no functions", script: "x = 1\n", want: "must define reject, bonus, or both"},
+		{name: "string methods", script: "def reject(move, game):\n    return move.type.upper()\n", want: "cannot use .upper"},
+		{name: "builtins that build values", script: "def reject(move, game):\n    return str(list(move.cards))\n", want: "cannot use str"},
+		{name: "print", script: "def reject(move, game):\n    print(move)\n", want: "cannot use print"},
+		{name: "while loops", script: "def reject(move, game):\n    while True:\n        pass\n", want: "while"},
+		{name: "a failing top level", script: "def bonus(player, game):\n    return 1\nfail(\"no\")\n", want: "fail: no"},
+		{name: "reject is not a function", script: "reject = 1\n", want: "reject must be a function"},
+		{name: "oversized", script: "#" + strings.Repeat("x", maxHouseRulesSize), want: "cannot be longer than"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compileHouseRules(tt.script)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("compileHouseRules() = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
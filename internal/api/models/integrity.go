@@ -0,0 +1,142 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// IntegrityError lists the invariants a game's state breaks. The server checks them after every change to a game,
+// so a change that would lose, duplicate, or forge cards, or leave a player owing chips, is never saved.
+type IntegrityError struct {
+	Violations []string
+}
+
+// Error implements the error interface, joining the violations into one message.
+func (e *IntegrityError) Error() string {
+	return "game integrity check failed: " + strings.Join(e.Violations, "; ")
+}
+
+// RecordCards adds cards to the manifest of every card put into the game. Games created before the manifest
+// existed have none and are left without one, so their cards go unchecked.
+func (g *Game) RecordCards(cards []Card) {
+	if g.CardManifest == nil {
+		return
+	}
+	for _, card := range cards {
+		g.CardManifest[CardCode(card)]++
+	}
+}
+
+// CheckIntegrity verifies the invariants every game must keep, returning an IntegrityError listing those it breaks:
+// no player's chip stack, bet, or insurance is negative, and, for games with a card manifest, the cards in the
+// deck, the hands, the house's hand, the discard pile, the melds, and the books add up to the cards put into the
+// game, with no card held in more copies than were added.
+func (g *Game) CheckIntegrity() error {
+	violations := []string{}
+
+	for _, player := range sortedKeys(g.Chips) {
+		if g.Chips[player] < 0 {
+			violations = append(violations, fmt.Sprintf("%s has a negative chip stack of %d", player, g.Chips[player]))
+		}
+	}
+	for _, player := range sortedKeys(g.Bets) {
+		if g.Bets[player] < 0 {
+			violations = append(violations, fmt.Sprintf("%s has a negative bet of %d", player, g.Bets[player]))
+		}
+	}
+	for _, player := range sortedKeys(g.Insurance) {
+		if g.Insurance[player] < 0 {
+			violations = append(violations, fmt.Sprintf("%s has negative insurance of %d", player, g.Insurance[player]))
+		}
+	}
+
+	if g.CardManifest != nil {
+		violations = append(violations, g.checkCards()...)
+	}
+
+	if len(violations) > 0 {
+		return &IntegrityError{Violations: violations}
+	}
+	return nil
+}
+
+// MissingCards returns the cards in the manifest that are not in play anywhere in the game, such as the cards
+// collected into Go Fish books. Games without a manifest have none.
+func (g *Game) MissingCards() []Card {
+	if g.CardManifest == nil {
+		return nil
+	}
+	located := g.locatedCards()
+
+	missing := []Card{}
+	for _, code := range sortedKeys(g.CardManifest) {
+		card, err := ParseCardCode(code)
+		if err != nil {
+			continue
+		}
+		for i := located[code]; i < g.CardManifest[code]; i++ {
+			missing = append(missing, card)
+		}
+	}
+	return missing
+}
+
+// checkCards compares the cards in play against the manifest. Go Fish books only record their value, so their
+// cards count towards the total but cannot be matched to particular copies.
+func (g *Game) checkCards() []string {
+	violations := []string{}
+	located := g.locatedCards()
+
+	added, inPlay := 0, 0
+	for _, count := range g.CardManifest {
+		added += count
+	}
+	for _, count := range located {
+		inPlay += count
+	}
+	for _, books := range g.Books {
+		inPlay += len(books) * goFishBookSize
+	}
+	if inPlay != added {
+		violations = append(violations, fmt.Sprintf("%d cards are in play but %d were added to the game", inPlay, added))
+	}
+
+	for _, code := range sortedKeys(located) {
+		if located[code] > g.CardManifest[code] {
+			violations = append(violations, fmt.Sprintf("%d copies of %s are in play but %d were added to the game", located[code], code, g.CardManifest[code]))
+		}
+	}
+	return violations
+}
+
+// locatedCards counts the copies of each card held anywhere in the game, by card code.
+func (g *Game) locatedCards() map[string]int {
+	located := map[string]int{}
+	count := func(cards []Card) {
+		for _, card := range cards {
+			located[CardCode(card)]++
+		}
+	}
+
+	count(g.GameDeck)
+	for _, hand := range g.PlayerHands {
+		count(hand)
+	}
+	count(g.DealerHand)
+	count(g.DiscardPile)
+	for _, meld := range g.Melds {
+		count(meld.Cards)
+	}
+	return located
+}
+
+// sortedKeys returns the keys of a map in sorted order, so violations are listed the same way every time.
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
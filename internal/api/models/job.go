@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kinds of work the job queue runs in the background.
+const (
+	JobExportGame         = "export_game"          // Export a live or archived game with its event history
+	JobSimulateGame       = "simulate_game"        // Run Monte Carlo simulations of an active game
+	JobRebuildPlayerStats = "rebuild_player_stats" // Recount every player's career statistics from the archive
+)
+
+// Job statuses. A job is queued when it is submitted, running while a worker has it, and succeeded or failed once
+// it is done.
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// JobParams holds what a job works on. Only the fields its kind uses are set.
+type JobParams struct {
+	GameID string `bson:"game_id,omitempty" json:"game_id,omitempty"` // Game to export or simulate
+	Runs   int    `bson:"runs,omitempty" json:"runs,omitempty"`       // Continuations to simulate; zero runs the default number
+	Seed   *int64 `bson:"seed,omitempty" json:"seed,omitempty"`       // Seed to draw the continuations from; nil draws a fresh one
+}
+
+// Job is a piece of long-running work queued to run in the background, so the request that asked for it can
+// return at once with the job's ID and the caller can poll the job for its result.
+type Job struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	Kind       string             `bson:"kind" json:"kind"`
+	Params     JobParams          `bson:"params" json:"params"`
+	Status     string             `bson:"status" json:"status"`
+	Owner      string             `bson:"owner,omitempty" json:"owner,omitempty"`             // Player who submitted the job; they and admins can read it
+	Viewer     Viewer             `bson:"viewer" json:"-"`                                    // Identity the job runs with
+	Attempts   int                `bson:"attempts" json:"attempts"`                           // Times a worker has started the job
+	Result     json.RawMessage    `bson:"result,omitempty" json:"result,omitempty"`           // What the job produced, as JSON, once it has succeeded
+	Error      string             `bson:"error,omitempty" json:"error,omitempty"`             // Why the job failed, once it has
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`                       // When the job was submitted
+	StartedAt  *time.Time         `bson:"started_at,omitempty" json:"started_at,omitempty"`   // When a worker last started the job
+	FinishedAt *time.Time         `bson:"finished_at,omitempty" json:"finished_at,omitempty"` // When the job succeeded or failed; finished jobs are deleted a week later
+	LeaseUntil *time.Time         `bson:"lease_until,omitempty" json:"-"`                     // When the running worker's hold on the job lapses, letting another worker retry it
+}
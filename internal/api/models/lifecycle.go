@@ -0,0 +1,80 @@
+package models
+
+import "time"
+
+// SetUpTable sets a game leaving the lobby up for play according to its game mode and marks it active.
+// War games get a shuffled deck split between the two players, Crazy Eights games get their opening hands and
+// starter card, Go Fish games get their opening hands, blackjack deals its first hand against the house, and
+// other modes are dealt opening hands of the size chosen in the settings, if any. Modes that deal add a shoe
+// built from the settings if no cards have been added yet.
+func (g *Game) SetUpTable() error {
+	switch g.Mode {
+	case ModeWar:
+		// War uses a full shoe split between the two players
+		g.prepareShoe()
+		if err := g.DealWarPiles(); err != nil {
+			return err
+		}
+	case ModeCrazyEights:
+		// Crazy Eights deals opening hands and turns up the first discard
+		g.prepareShoe()
+		if err := g.DealCrazyEights(); err != nil {
+			return err
+		}
+	case ModeGoFish:
+		// Go Fish deals opening hands and collects any books dealt straight away
+		g.prepareShoe()
+		if err := g.DealGoFish(); err != nil {
+			return err
+		}
+	case ModeBlackjack:
+		// Blackjack deals the first hand against the house, playing any bets placed in the lobby
+		g.prepareShoe()
+		g.HandNumber++
+		g.HandPhase = HandPhasePlay
+		g.HandStartedAt = time.Now().UTC()
+		if err := g.DealBlackjack(); err != nil {
+			return err
+		}
+	default:
+		// Other modes only deal opening hands when the settings choose a hand size, such as 13 cards for hearts
+		if handSize := g.OpeningHandSize(); handSize > 0 {
+			g.prepareShoe()
+			if err := g.DealOpeningHands(handSize, 0); err != nil {
+				return err
+			}
+		}
+	}
+	g.Status = StatusActive
+
+	// Players take turns starting to the dealer's left, unless the deal has already started the turns;
+	// war battles are fought by both players at once
+	if g.Mode != ModeWar && g.Turn == nil && len(g.Players) > 0 {
+		g.Turn = NewTurnOrder(g.Players, (g.DealerIndex+1)%len(g.Players))
+	}
+	return nil
+}
+
+// LeaveTable takes a player out of the game: off their seat, keeping the dealer button in place, out of the turn
+// order, passing the turn on if it was theirs, and off their team. Their cards go back to the bottom of the deck.
+func (g *Game) LeaveTable(playerName string) {
+	g.Unseat(playerName)
+
+	g.GameDeck = append(g.GameDeck, g.PlayerHands[playerName]...)
+	delete(g.PlayerHands, playerName)
+	delete(g.Hands, playerName)
+	delete(g.Insurance, playerName)
+
+	if g.Turn != nil {
+		g.Turn.Remove(playerName)
+	}
+	g.RemoveFromTeams(playerName)
+}
+
+// prepareShoe adds a shoe built from the settings if no cards have been added yet, and shuffles the deck.
+func (g *Game) prepareShoe() {
+	if len(g.GameDeck) == 0 {
+		g.AddDeckToGame(g.NewShoe())
+	}
+	g.ShuffleDeck()
+}
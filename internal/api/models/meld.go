@@ -0,0 +1,102 @@
+package models
+
+import (
+	"errors"
+	"sort"
+)
+
+// Meld types recognised in rummy games.
+const (
+	MeldSet = "set" // Three or four cards of the same value in different suits
+	MeldRun = "run" // Three or more consecutive cards of the same suit, aces low
+)
+
+// Meld represents a set or run laid down on the table by a player.
+// Other players may later lay off cards onto it as long as it remains valid.
+type Meld struct {
+	ID    int    `bson:"id" json:"id"`
+	Owner string `bson:"owner" json:"owner"`
+	Type  string `bson:"type" json:"type"`
+	Cards []Card `bson:"cards" json:"cards"`
+}
+
+// ClassifyMeld validates a group of cards as a rummy meld and returns its type.
+// Runs are returned sorted in rank order so they read naturally on the table.
+func ClassifyMeld(cards []Card) (string, []Card, error) {
+	if len(cards) < 3 {
+		return "", nil, errors.New("a meld needs at least three cards")
+	}
+
+	if isSet(cards) {
+		return MeldSet, cards, nil
+	}
+
+	if run, ok := asRun(cards); ok {
+		return MeldRun, run, nil
+	}
+
+	return "", nil, errors.New("cards do not form a valid set or run")
+}
+
+// Deadwood returns the deadwood points of the unmelded cards in a hand.
+// Aces count 1, number cards their face value, and face cards 10.
+func Deadwood(hand []Card) int {
+	points := 0
+	for _, card := range hand {
+		points += countingValue(card.Value)
+	}
+	return points
+}
+
+// RemoveCards removes the given cards from a hand, one copy per requested card.
+// It reports false and leaves the hand untouched if any card is not held.
+func RemoveCards(hand []Card, cards []Card) ([]Card, bool) {
+	remaining := append([]Card{}, hand...)
+	for _, card := range cards {
+		found := false
+		for i, held := range remaining {
+			if held == card {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return hand, false
+		}
+	}
+	return remaining, true
+}
+
+// isSet reports whether the cards share a value and all have different suits.
+func isSet(cards []Card) bool {
+	if len(cards) > 4 {
+		return false
+	}
+	suits := map[Suit]bool{}
+	for _, card := range cards {
+		if card.Value != cards[0].Value || suits[card.Suit] {
+			return false
+		}
+		suits[card.Suit] = true
+	}
+	return true
+}
+
+// asRun reports whether the cards form a run and returns them in ascending rank order.
+func asRun(cards []Card) ([]Card, bool) {
+	run := append([]Card{}, cards...)
+	sort.Slice(run, func(i, j int) bool {
+		return faceValue(run[i].Value) < faceValue(run[j].Value)
+	})
+
+	for i, card := range run {
+		if card.Suit != run[0].Suit || faceValue(card.Value) == 0 {
+			return nil, false
+		}
+		if i > 0 && faceValue(card.Value) != faceValue(run[i-1].Value)+1 {
+			return nil, false
+		}
+	}
+	return run, true
+}
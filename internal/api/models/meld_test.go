@@ -0,0 +1,51 @@
+package models
+
+import "testing"
+
+func FuzzClassifyMeld(f *testing.F) {
+	f.Add([]byte{0, 5, 10}, false)      // Three aces of different suits
+	f.Add([]byte{0, 5, 10, 15}, false)  // All four aces
+	f.Add([]byte{5, 10, 15}, false)     // Ace, two, and three of hearts
+	f.Add([]byte{10, 20, 4}, true)      // A run with a joker in it
+	f.Add([]byte{1, 2, 3}, false)       // Mixed suits and values
+	f.Add([]byte{0, 4, 4, 4}, true)     // One natural card and three jokers
+	f.Add([]byte{60, 55, 50, 45}, true) // A run counting down
+
+	f.Fuzz(func(t *testing.T, data []byte, jokersWild bool) {
+		cards := fuzzCards(data)
+		g := &Game{}
+		if jokersWild {
+			g.Settings.WildCards = []string{"Joker"}
+		}
+
+		meldType, ordered, err := g.ClassifyMeld(cards)
+		if err != nil {
+			return
+		}
+
+		// A meld is a set or run of at least three cards, laid out from exactly the cards it was given
+		if meldType != MeldSet && meldType != MeldRun {
+			t.Fatalf("ClassifyMeld(%v) returned meld type %q", cards, meldType)
+		}
+		if len(ordered) < 3 {
+			t.Fatalf("ClassifyMeld(%v) made a meld of %d cards", cards, len(ordered))
+		}
+		if remaining, ok := RemoveCards(cards, ordered); !ok || len(remaining) != 0 {
+			t.Fatalf("ClassifyMeld(%v) laid out %v, which are not the cards it was given", cards, ordered)
+		}
+	})
+}
+
+// fuzzCards turns each byte into a standard card or a joker, so fuzz targets only build hands that could be dealt.
+func fuzzCards(data []byte) []Card {
+	cards := make([]Card, 0, len(data))
+	for _, b := range data {
+		suit := Suit(b%5) + SuitHearts
+		if suit == SuitJoker {
+			cards = append(cards, Card{Suit: SuitJoker, Value: RankJoker})
+			continue
+		}
+		cards = append(cards, Card{Suit: suit, Value: Rank(b/5%13) + RankAce})
+	}
+	return cards
+}
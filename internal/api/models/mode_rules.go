@@ -0,0 +1,185 @@
+package models
+
+// The rules of the modes played move by move, registered for the generic move endpoint.
+func init() {
+	RegisterRules(ModeWar, warRules{})
+	RegisterRules(ModeCrazyEights, crazyEightsRules{})
+	RegisterRules(ModeGoFish, goFishRules{})
+	RegisterRules(ModeBlackjack, blackjackRules{})
+}
+
+// warRules plays War: the only move is a battle, which nobody in particular fights.
+type warRules struct{}
+
+// ValidateMove allows a battle while both players hold cards.
+func (warRules) ValidateMove(g *Game, a Action) error {
+	if err := checkMoveType(a, ActionBattle); err != nil {
+		return err
+	}
+	return g.CheckAction(a)
+}
+
+// ApplyMove fights one battle.
+func (warRules) ApplyMove(g *Game, a Action) ([]MoveEvent, error) {
+	result, err := g.ResolveBattle()
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]interface{}{"flips": result.Flips, "wars": result.Wars, "cards_won": result.CardsWon}
+	return []MoveEvent{{Type: EventBattle, Player: result.Winner, Data: data}}, nil
+}
+
+// IsRoundOver reports whether one player holds every card.
+func (warRules) IsRoundOver(g *Game) bool {
+	return g.Status == StatusFinished
+}
+
+// Score values each player by the cards in their pile; the player holding them all wins.
+func (warRules) Score(g *Game) (map[string]int, []string) {
+	values := map[string]int{}
+	for _, player := range g.Players {
+		values[player] = len(g.PlayerHands[player])
+	}
+	return values, g.simulatedWinner()
+}
+
+// crazyEightsRules plays Crazy Eights: players play a card on the discard pile, or draw when they cannot.
+type crazyEightsRules struct{}
+
+// ValidateMove checks a play against the top of the discard pile, and a draw as it is checked when made: once any
+// empty deck has been refilled from the discard pile.
+func (crazyEightsRules) ValidateMove(g *Game, a Action) error {
+	if err := checkMoveType(a, ActionPlayCard, ActionDraw); err != nil {
+		return err
+	}
+	if a.Type == ActionDraw {
+		refilled := g.simulationCopy()
+		refilled.ReshuffleDiscards(1)
+		return refilled.CheckAction(a)
+	}
+	return g.CheckAction(a)
+}
+
+// ApplyMove plays the card or draws one, refilling an empty deck from the discard pile first. The drawn card is
+// not recorded, so other players do not see it.
+func (crazyEightsRules) ApplyMove(g *Game, a Action) ([]MoveEvent, error) {
+	if a.Type == ActionDraw {
+		events := []MoveEvent{}
+		if reshuffled := g.ReshuffleDiscards(1); reshuffled > 0 {
+			events = append(events, MoveEvent{Type: EventReshuffled, Data: map[string]interface{}{"cards": reshuffled}})
+		}
+		g.DrawFromDeck(a.Player, 1)
+		return append(events, MoveEvent{Type: EventCardDrawn, Player: a.Player}), nil
+	}
+
+	g.PlayCrazyEight(a.Player, a.Cards[0], a.DeclaredSuit)
+	data := map[string]interface{}{"card": a.Cards[0]}
+	if g.DeclaredSuit != "" {
+		data["declared_suit"] = g.DeclaredSuit
+	}
+	return []MoveEvent{{Type: EventCardPlayed, Player: a.Player, Data: data}}, nil
+}
+
+// IsRoundOver reports whether a player has emptied their hand.
+func (crazyEightsRules) IsRoundOver(g *Game) bool {
+	return g.Status == StatusFinished
+}
+
+// Score values each player by the points they scored, which go to the player who went out.
+func (crazyEightsRules) Score(g *Game) (map[string]int, []string) {
+	return g.FinalPoints(), g.simulatedWinner()
+}
+
+// goFishRules plays Go Fish: players ask an opponent for a value they hold.
+type goFishRules struct{}
+
+// ValidateMove checks an ask against the asker's hand.
+func (goFishRules) ValidateMove(g *Game, a Action) error {
+	if err := checkMoveType(a, ActionAsk); err != nil {
+		return err
+	}
+	return g.CheckAction(a)
+}
+
+// ApplyMove makes the ask, recording it and any books it completed. A card drawn when fishing is not recorded, so
+// other players do not see it.
+func (goFishRules) ApplyMove(g *Game, a Action) ([]MoveEvent, error) {
+	result := g.Ask(a.Player, a.Target, a.Value)
+	data := map[string]interface{}{"target": a.Target, "value": a.Value, "received": result.Received, "went_fishing": result.Received == 0}
+	events := []MoveEvent{{Type: EventAsk, Player: a.Player, Data: data}}
+	for _, book := range result.Books {
+		events = append(events, MoveEvent{Type: EventBook, Player: a.Player, Data: map[string]interface{}{"value": book}})
+	}
+	return events, nil
+}
+
+// IsRoundOver reports whether every card has been booked.
+func (goFishRules) IsRoundOver(g *Game) bool {
+	return g.Status == StatusFinished
+}
+
+// Score values each player by the books they completed; the most books wins.
+func (goFishRules) Score(g *Game) (map[string]int, []string) {
+	return g.HandValues()
+}
+
+// BlackjackEvents maps each blackjack move to the event recorded when a player makes it.
+var BlackjackEvents = map[string]string{
+	ActionHit:       EventHit,
+	ActionStand:     EventStood,
+	ActionDouble:    EventDoubledDown,
+	ActionSplit:     EventSplit,
+	ActionSurrender: EventSurrendered,
+}
+
+// blackjackRules plays blackjack a hand at a time: players make their moves on their current hand, and the house
+// plays once nobody has a hand left to play.
+type blackjackRules struct{}
+
+// ValidateMove checks a move against the player's current hand.
+func (blackjackRules) ValidateMove(g *Game, a Action) error {
+	if err := checkMoveType(a, ActionHit, ActionStand, ActionDouble, ActionSplit, ActionSurrender); err != nil {
+		return err
+	}
+	return g.CheckAction(a)
+}
+
+// ApplyMove makes the move, recording the cards it deals, which are played face up, and the house's play if the
+// move ended the hand.
+func (blackjackRules) ApplyMove(g *Game, a Action) ([]MoveEvent, error) {
+	hand := g.CurrentHand(a.Player)
+	var dealt []Card
+	switch a.Type {
+	case ActionHit:
+		dealt = []Card{g.Hit(a.Player)}
+	case ActionStand:
+		g.Stand(a.Player)
+	case ActionDouble:
+		dealt = []Card{g.DoubleDown(a.Player)}
+	case ActionSplit:
+		dealt = g.Split(a.Player)
+	case ActionSurrender:
+		g.Surrender(a.Player)
+	}
+
+	data := map[string]interface{}{"hand": hand}
+	if len(dealt) > 0 {
+		data["cards"] = dealt
+	}
+	events := []MoveEvent{{Type: BlackjackEvents[a.Type], Player: a.Player, Data: data}}
+	if g.HandPhase == HandPhaseShowdown {
+		data := map[string]interface{}{"hand": g.HandNumber, "cards": g.DealerHand, "value": PlayerHand{Cards: g.DealerHand}.Value()}
+		events = append(events, MoveEvent{Type: EventHousePlayed, Data: data})
+	}
+	return events, nil
+}
+
+// IsRoundOver reports whether the house has played, leaving the hand ready to be scored.
+func (blackjackRules) IsRoundOver(g *Game) bool {
+	return g.HandPhase == HandPhaseShowdown || g.HandPhase == HandPhaseScored
+}
+
+// Score values each player by their best hand; every player who beats the house wins.
+func (blackjackRules) Score(g *Game) (map[string]int, []string) {
+	return g.HandValues()
+}
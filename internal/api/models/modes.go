@@ -0,0 +1,24 @@
+package models
+
+// Game modes supported by the server.
+// ModeStandard is the free-form game driven entirely by the generic deal and hand endpoints.
+const (
+	ModeStandard    = "standard"
+	ModeGinRummy    = "gin_rummy"
+	ModeWar         = "war"
+	ModeCrazyEights = "crazy_eights"
+	ModeGoFish      = "go_fish"
+	ModeBlackjack   = "blackjack"
+)
+
+// IsValidMode reports whether the given game mode is supported: the free-form standard game, gin rummy, or a mode
+// with rules registered by RegisterRules. An empty mode is accepted and treated as ModeStandard.
+func IsValidMode(mode string) bool {
+	switch mode {
+	case "", ModeStandard, ModeGinRummy:
+		return true
+	default:
+		_, ok := RulesFor(mode)
+		return ok
+	}
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// Organization is a community or product hosted on the deployment. Each organization's games, players, sessions,
+// and statistics are kept apart from every other organization's, and its API keys only reach its own data.
+type Organization struct {
+	ID        string    `bson:"_id" json:"id"` // Short ID sent in the X-Org-ID header, such as "acme"
+	Name      string    `bson:"name" json:"name"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
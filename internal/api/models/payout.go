@@ -0,0 +1,108 @@
+package models
+
+// Kinds of bet resolved in a settlement.
+const (
+	SettlePot       = "pot"       // A main or side pot contested between players
+	SettleHand      = "hand"      // A blackjack hand's bet against the house
+	SettleInsurance = "insurance" // A blackjack insurance stake against the house
+)
+
+// SettlementLine records how one bet was resolved at the end of a hand.
+type SettlementLine struct {
+	Kind     string         `bson:"kind" json:"kind"`
+	Player   string         `bson:"player,omitempty" json:"player,omitempty"`     // Player whose bet it was, for bets against the house
+	Hand     int            `bson:"hand,omitempty" json:"hand,omitempty"`         // Which of the player's hands the bet was on, for blackjack hands
+	Stake    int            `bson:"stake" json:"stake"`                           // The pot's size, or the chips the player bet
+	Outcome  string         `bson:"outcome,omitempty" json:"outcome,omitempty"`   // How a bet against the house fared
+	Eligible []string       `bson:"eligible,omitempty" json:"eligible,omitempty"` // Players who could win a pot
+	Winners  []string       `bson:"winners,omitempty" json:"winners,omitempty"`   // Players who won a pot
+	Payouts  map[string]int `bson:"payouts" json:"payouts"`                       // Chips paid to each player, stakes returned included
+}
+
+// Settlement is the detailed record of how the bets of a hand were resolved and applied to the chip stacks:
+// a line for every bet, with the chips each player staked, was paid, and won or lost overall.
+type Settlement struct {
+	Lines  []SettlementLine `bson:"lines" json:"lines"`
+	Staked map[string]int   `bson:"staked" json:"staked"`
+	Paid   map[string]int   `bson:"paid" json:"paid"`
+	Net    map[string]int   `bson:"net" json:"net"`
+}
+
+// SettleBets resolves every bet of the current hand, credits the payouts to the players' chip stacks, and clears
+// the betting state for the next hand. In blackjack each hand and insurance stake is settled against the house:
+// blackjacks pay 3:2, wins 1:1, pushes return the bet, surrenders return half, and insurance pays 2:1 against a
+// house blackjack. Otherwise the main pot and side pots go to the best hands among the players eligible for each,
+// ties split a pot evenly, and the odd chips of a split go one at a time to the winners nearest the dealer's left.
+// It returns nil if no bets were placed.
+func (g *Game) SettleBets() *Settlement {
+	settlement := &Settlement{Staked: map[string]int{}, Paid: map[string]int{}, Net: map[string]int{}}
+	for player, amount := range g.Bets {
+		if amount > 0 {
+			settlement.Staked[player] = amount
+		}
+	}
+
+	if g.Mode == ModeBlackjack {
+		g.settleBlackjack(settlement)
+	} else {
+		g.settlePots(settlement)
+	}
+
+	// Apply the payouts to the chip stacks and work out what each player won or lost
+	if g.Chips == nil {
+		g.Chips = map[string]int{}
+	}
+	for player, amount := range settlement.Paid {
+		g.Chips[player] += amount
+	}
+	for _, totals := range []map[string]int{settlement.Staked, settlement.Paid} {
+		for player := range totals {
+			settlement.Net[player] = settlement.Paid[player] - settlement.Staked[player]
+		}
+	}
+
+	// Clear the betting state for the next hand
+	g.Bets = map[string]int{}
+	g.Folded = []string{}
+
+	if len(settlement.Lines) == 0 {
+		return nil
+	}
+	return settlement
+}
+
+// settlePots awards each pot separately, so all-in players only win what they covered, to the best hands among
+// the players eligible for it, and adds a line for each pot to the settlement.
+func (g *Game) settlePots(settlement *Settlement) {
+	pm := NewPotManager(g)
+
+	// Rank every player by the value of their hand, negating penalty scores so higher ranks always win, and
+	// breaking ties by high card if the game ranks suits
+	strategy := g.ScoringStrategy()
+	ranks := map[string]int{}
+	for player, hand := range g.PlayerHands {
+		ranks[player] = strategy.HandValue(hand)
+		if strategy.LowestWins() {
+			ranks[player] = -ranks[player]
+		}
+		ranks[player] = g.rankWithSuits(ranks[player], hand)
+	}
+
+	for _, pot := range pm.Pots() {
+		settlement.add(SettlementLine{
+			Kind:     SettlePot,
+			Stake:    pot.Amount,
+			Eligible: pot.Eligible,
+			Winners:  BestRanked(pot.Eligible, ranks),
+			Payouts:  pm.Distribute([]Pot{pot}, ranks),
+		})
+	}
+}
+
+// add appends a line to the settlement and adds its payouts to the players' totals.
+func (s *Settlement) add(line SettlementLine) {
+	s.Lines = append(s.Lines, line)
+	for player, amount := range line.Payouts {
+		s.Paid[player] += amount
+	}
+}
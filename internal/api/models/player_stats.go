@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// PlayerStats holds a player's career statistics, built up from every finished game they were seated in.
+type PlayerStats struct {
+	PlayerName    string         `bson:"_id" json:"player_name"`
+	OrgID         string         `bson:"org_id,omitempty" json:"org_id,omitempty"` // Organization the player plays in; empty for the default organization
+	GamesPlayed   int            `bson:"games_played" json:"games_played"`
+	GamesWon      int            `bson:"games_won" json:"games_won"`
+	TotalPoints   int            `bson:"total_points" json:"total_points"`     // Points scored across all games; see FinalPoints
+	ModeCounts    map[string]int `bson:"mode_counts" json:"mode_counts"`       // Finished games per game mode
+	FavoriteMode  string         `bson:"favorite_mode" json:"favorite_mode"`   // Mode the player has finished the most games of
+	CurrentStreak int            `bson:"current_streak" json:"current_streak"` // Consecutive wins up to the latest game
+	BestStreak    int            `bson:"best_streak" json:"best_streak"`       // Longest run of consecutive wins
+	HintedGames   int            `bson:"hinted_games" json:"hinted_games"`     // Finished games in which the player asked for a hint
+	HintedWins    int            `bson:"hinted_wins" json:"hinted_wins"`       // Games won after asking for a hint, also counted in GamesWon
+	LastPlayed    time.Time      `bson:"last_played,omitempty" json:"last_played,omitempty"`
+}
+
+// FinalPoints returns the points each seated player scored in a finished game.
+// In Go Fish a player scores one point per completed book. In other modes the winner scores the combined value
+// of the cards left in the other players' hands under the game's scoring strategy, as in Crazy Eights and rummy,
+// and everyone else scores nothing. The hands of the winner's partners in a team game do not count.
+func (g *Game) FinalPoints() map[string]int {
+	points := map[string]int{}
+	for _, player := range g.Players {
+		points[player] = 0
+	}
+
+	if g.Mode == ModeGoFish {
+		for player, books := range g.Books {
+			points[player] = len(books)
+		}
+		return points
+	}
+
+	if g.Winner != "" {
+		strategy := g.ScoringStrategy()
+		for player, hand := range g.PlayerHands {
+			if player != g.Winner && !g.sameTeam(player, g.Winner) {
+				points[g.Winner] += strategy.HandValue(hand)
+			}
+		}
+	}
+	return points
+}
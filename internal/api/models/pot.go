@@ -0,0 +1,159 @@
+package models
+
+import "sort"
+
+// Pot represents a pot of chips contested at showdown.
+// The main pot is contested by every player still in the hand, while side pots are
+// only contested by the players who put enough chips in to cover them.
+type Pot struct {
+	Amount   int      `bson:"amount" json:"amount"`
+	Eligible []string `bson:"eligible" json:"eligible"`
+}
+
+// PotManager splits the chips committed during a hand into a main pot and side pots.
+// It works from the total contribution of each player and the set of players who folded.
+type PotManager struct {
+	Contributions map[string]int
+	Folded        map[string]bool
+	SeatOrder     []string
+}
+
+// NewPotManager creates a PotManager from the game's current bets and folded players.
+// The seats in dealing order, starting to the dealer's left, are used as the seat order, so pot eligibility is
+// reported consistently and the odd chips of a split pot go to the winners nearest the dealer's left.
+func NewPotManager(g *Game) *PotManager {
+	folded := make(map[string]bool, len(g.Folded))
+	for _, player := range g.Folded {
+		folded[player] = true
+	}
+
+	return &PotManager{
+		Contributions: g.Bets,
+		Folded:        folded,
+		SeatOrder:     g.DealOrder(),
+	}
+}
+
+// Pots computes the main pot and any side pots.
+// Each distinct contribution level of a player still in the hand caps a pot; every player
+// contributes up to that level, but only players who reached it are eligible to win it.
+// Chips from folded players are added to the pots without making those players eligible.
+func (pm *PotManager) Pots() []Pot {
+	// Collect the distinct contribution levels of the players still in the hand
+	levelSet := map[int]bool{}
+	for player, amount := range pm.Contributions {
+		if amount > 0 && !pm.Folded[player] {
+			levelSet[amount] = true
+		}
+	}
+	levels := make([]int, 0, len(levelSet))
+	for level := range levelSet {
+		levels = append(levels, level)
+	}
+	sort.Ints(levels)
+
+	pots := []Pot{}
+	previous := 0
+	for _, level := range levels {
+		pot := Pot{Eligible: []string{}}
+
+		// Every player contributes the slice of their bet that falls between the previous level and this one
+		for _, player := range pm.players() {
+			amount := pm.Contributions[player]
+			if amount > previous {
+				pot.Amount += minInt(amount, level) - previous
+			}
+			// Only players still in the hand who covered this level can win the pot
+			if amount >= level && !pm.Folded[player] {
+				pot.Eligible = append(pot.Eligible, player)
+			}
+		}
+
+		pots = append(pots, pot)
+		previous = level
+	}
+
+	// Folded players may have put in more than any remaining player; those chips go to the last pot
+	if len(pots) > 0 {
+		for _, player := range pm.players() {
+			if amount := pm.Contributions[player]; amount > previous {
+				pots[len(pots)-1].Amount += amount - previous
+			}
+		}
+	}
+
+	return pots
+}
+
+// Distribute awards each pot to the eligible players with the best hand.
+// The ranks map holds a comparable strength for every player (higher wins). Ties split the pot
+// evenly, with any odd chips handed out one at a time in seat order, starting from the dealer's left.
+// It returns the chips won by each player across all pots.
+func (pm *PotManager) Distribute(pots []Pot, ranks map[string]int) map[string]int {
+	winnings := map[string]int{}
+	for _, pot := range pots {
+		winners := BestRanked(pot.Eligible, ranks)
+		if len(winners) == 0 {
+			continue
+		}
+
+		// Split the pot evenly and hand the remainder out in seat order
+		share := pot.Amount / len(winners)
+		remainder := pot.Amount % len(winners)
+		for i, winner := range winners {
+			winnings[winner] += share
+			if i < remainder {
+				winnings[winner]++
+			}
+		}
+	}
+	return winnings
+}
+
+// BestRanked returns the players with the highest rank, preserving the order in which they were given.
+func BestRanked(players []string, ranks map[string]int) []string {
+	winners := []string{}
+	best := 0
+	for _, player := range players {
+		rank := ranks[player]
+		switch {
+		case len(winners) == 0 || rank > best:
+			winners = []string{player}
+			best = rank
+		case rank == best:
+			winners = append(winners, player)
+		}
+	}
+	return winners
+}
+
+// players returns every contributor in seat order, followed by any contributors
+// who are no longer seated (for example players who left mid-hand), sorted by name.
+func (pm *PotManager) players() []string {
+	seen := map[string]bool{}
+	ordered := []string{}
+	for _, player := range pm.SeatOrder {
+		if _, ok := pm.Contributions[player]; ok && !seen[player] {
+			ordered = append(ordered, player)
+			seen[player] = true
+		}
+	}
+
+	extra := []string{}
+	for player := range pm.Contributions {
+		if !seen[player] {
+			extra = append(extra, player)
+		}
+	}
+	sort.Strings(extra)
+
+	return append(ordered, extra...)
+}
+
+// minInt returns the smaller of two integers.
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
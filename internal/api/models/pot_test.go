@@ -0,0 +1,185 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPotManagerPots(t *testing.T) {
+	tests := []struct {
+		name          string
+		contributions map[string]int
+		folded        []string
+		seats         []string
+		want          []Pot
+	}{
+		{
+			name:          "no bets",
+			contributions: map[string]int{},
+			seats:         []string{"alice", "bob"},
+			want:          []Pot{},
+		},
+		{
+			name:          "equal bets make a single pot",
+			contributions: map[string]int{"alice": 40, "bob": 40, "carol": 40},
+			seats:         []string{"alice", "bob", "carol"},
+			want:          []Pot{{Amount: 120, Eligible: []string{"alice", "bob", "carol"}}},
+		},
+		{
+			name:          "multi-way all-ins with different stacks",
+			contributions: map[string]int{"alice": 25, "bob": 50, "carol": 100, "dave": 100},
+			seats:         []string{"alice", "bob", "carol", "dave"},
+			want: []Pot{
+				{Amount: 100, Eligible: []string{"alice", "bob", "carol", "dave"}},
+				{Amount: 75, Eligible: []string{"bob", "carol", "dave"}},
+				{Amount: 100, Eligible: []string{"carol", "dave"}},
+			},
+		},
+		{
+			name:          "eligibility follows seat order",
+			contributions: map[string]int{"alice": 100, "bob": 10, "carol": 100},
+			seats:         []string{"carol", "alice", "bob"},
+			want: []Pot{
+				{Amount: 30, Eligible: []string{"carol", "alice", "bob"}},
+				{Amount: 180, Eligible: []string{"carol", "alice"}},
+			},
+		},
+		{
+			name:          "folded player put in more than an all-in player",
+			contributions: map[string]int{"alice": 30, "bob": 100, "carol": 60},
+			folded:        []string{"carol"},
+			seats:         []string{"alice", "bob", "carol"},
+			want: []Pot{
+				{Amount: 90, Eligible: []string{"alice", "bob"}},
+				{Amount: 100, Eligible: []string{"bob"}},
+			},
+		},
+		{
+			name:          "folded player put in more than everyone still in the hand",
+			contributions: map[string]int{"alice": 30, "bob": 30, "carol": 80},
+			folded:        []string{"carol"},
+			seats:         []string{"alice", "bob", "carol"},
+			want:          []Pot{{Amount: 140, Eligible: []string{"alice", "bob"}}},
+		},
+		{
+			name:          "players who left mid-hand still pay into the pots",
+			contributions: map[string]int{"alice": 50, "bob": 50, "zed": 20},
+			folded:        []string{"zed"},
+			seats:         []string{"alice", "bob"},
+			want:          []Pot{{Amount: 120, Eligible: []string{"alice", "bob"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pm := newTestPotManager(tt.contributions, tt.folded, tt.seats)
+			got := pm.Pots()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Pots() = %+v, want %+v", got, tt.want)
+			}
+
+			// Every chip put in ends up in exactly one pot
+			total, potted := 0, 0
+			for _, amount := range tt.contributions {
+				total += amount
+			}
+			for _, pot := range got {
+				potted += pot.Amount
+			}
+			if potted != total {
+				t.Errorf("pots hold %d chips, want %d", potted, total)
+			}
+		})
+	}
+}
+
+func TestPotManagerDistribute(t *testing.T) {
+	tests := []struct {
+		name          string
+		contributions map[string]int
+		folded        []string
+		seats         []string
+		ranks         map[string]int
+		want          map[string]int
+	}{
+		{
+			name:          "best hand takes the only pot",
+			contributions: map[string]int{"alice": 40, "bob": 40, "carol": 40},
+			seats:         []string{"alice", "bob", "carol"},
+			ranks:         map[string]int{"alice": 1, "bob": 3, "carol": 2},
+			want:          map[string]int{"bob": 120},
+		},
+		{
+			name:          "main pot and side pot go to different players",
+			contributions: map[string]int{"alice": 50, "bob": 100, "carol": 100},
+			seats:         []string{"alice", "bob", "carol"},
+			ranks:         map[string]int{"alice": 3, "bob": 2, "carol": 1},
+			want:          map[string]int{"alice": 150, "bob": 100},
+		},
+		{
+			name:          "best hand wins every pot it is eligible for",
+			contributions: map[string]int{"alice": 25, "bob": 50, "carol": 100, "dave": 100},
+			seats:         []string{"alice", "bob", "carol", "dave"},
+			ranks:         map[string]int{"alice": 1, "bob": 4, "carol": 3, "dave": 2},
+			want:          map[string]int{"bob": 175, "carol": 100},
+		},
+		{
+			name:          "folded player cannot win even with the best hand",
+			contributions: map[string]int{"alice": 30, "bob": 100, "carol": 60},
+			folded:        []string{"carol"},
+			seats:         []string{"alice", "bob", "carol"},
+			ranks:         map[string]int{"alice": 2, "bob": 1, "carol": 9},
+			want:          map[string]int{"alice": 90, "bob": 100},
+		},
+		{
+			name:          "odd chip goes to the first winner in seat order",
+			contributions: map[string]int{"alice": 50, "bob": 50, "carol": 1},
+			folded:        []string{"carol"},
+			seats:         []string{"carol", "bob", "alice"},
+			ranks:         map[string]int{"alice": 5, "bob": 5},
+			want:          map[string]int{"bob": 51, "alice": 50},
+		},
+		{
+			name:          "three-way split hands out the odd chips one at a time",
+			contributions: map[string]int{"alice": 30, "bob": 30, "carol": 30, "dave": 2},
+			folded:        []string{"dave"},
+			seats:         []string{"alice", "bob", "carol", "dave"},
+			ranks:         map[string]int{"alice": 7, "bob": 7, "carol": 7},
+			want:          map[string]int{"alice": 31, "bob": 31, "carol": 30},
+		},
+		{
+			name:          "split main pot with the side pot to one player",
+			contributions: map[string]int{"alice": 25, "bob": 75, "carol": 75},
+			seats:         []string{"alice", "bob", "carol"},
+			ranks:         map[string]int{"alice": 4, "bob": 4, "carol": 1},
+			want:          map[string]int{"alice": 38, "bob": 137},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pm := newTestPotManager(tt.contributions, tt.folded, tt.seats)
+			got := pm.Distribute(pm.Pots(), tt.ranks)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Distribute() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPotManagerDistributeSkipsPotsWithoutEligiblePlayers(t *testing.T) {
+	pm := &PotManager{}
+	got := pm.Distribute([]Pot{{Amount: 10, Eligible: []string{}}}, map[string]int{})
+	if len(got) != 0 {
+		t.Errorf("Distribute() = %v, want no winnings", got)
+	}
+}
+
+// newTestPotManager builds a PotManager from contributions, the players who folded, and the seat order.
+func newTestPotManager(contributions map[string]int, folded, seats []string) *PotManager {
+	foldedSet := map[string]bool{}
+	for _, player := range folded {
+		foldedSet[player] = true
+	}
+	return &PotManager{Contributions: contributions, Folded: foldedSet, SeatOrder: seats}
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Presence statuses shown for each seated player in game responses.
+const (
+	PresenceOnline  = "online"
+	PresenceOffline = "offline"
+)
+
+// PlayerPresence reports whether each seated player is online: they have a game stream open, or their client
+// has sent a heartbeat within the timeout. Everyone else is offline.
+func (g *Game) PlayerPresence(now time.Time, timeout time.Duration) map[string]string {
+	presence := make(map[string]string, len(g.Players))
+	for _, player := range g.Players {
+		presence[player] = PresenceOffline
+		if g.Connections[player] > 0 {
+			presence[player] = PresenceOnline
+		} else if lastSeen, ok := g.LastSeen[player]; ok && now.Sub(lastSeen) <= timeout {
+			presence[player] = PresenceOnline
+		}
+	}
+	return presence
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Channels turn alerts can be delivered on, outside the app.
+const (
+	ChannelEmail   = "email"   // An email to the player's address
+	ChannelPush    = "push"    // A push notification to the player's device
+	ChannelWebhook = "webhook" // A JSON POST to a URL the player chose
+)
+
+// NotificationPrefs holds how a player wants to be told it is their turn. Turn alerts suit slow,
+// correspondence-style games, where players come and go between moves.
+type NotificationPrefs struct {
+	TurnAlerts bool     `bson:"turn_alerts" json:"turn_alerts"`                     // Whether the player is told when a game is waiting on their move
+	Channels   []string `bson:"channels" json:"channels"`                           // Channels the alerts go out on, besides the in-app notification
+	Email      string   `bson:"email,omitempty" json:"email,omitempty"`             // Address emails are sent to
+	PushToken  string   `bson:"push_token,omitempty" json:"push_token,omitempty"`   // Device token push notifications are sent to
+	WebhookURL string   `bson:"webhook_url,omitempty" json:"webhook_url,omitempty"` // https URL webhooks are posted to
+}
+
+// Address returns where the player is reached on the channel, or "" if they have not given an address for it.
+func (p NotificationPrefs) Address(channel string) string {
+	switch channel {
+	case ChannelEmail:
+		return p.Email
+	case ChannelPush:
+		return p.PushToken
+	case ChannelWebhook:
+		return p.WebhookURL
+	default:
+		return ""
+	}
+}
+
+// Check checks that every channel is known, is listed once, and has the address it delivers to.
+func (p NotificationPrefs) Check() error {
+	seen := map[string]bool{}
+	for _, channel := range p.Channels {
+		switch channel {
+		case ChannelEmail, ChannelPush, ChannelWebhook:
+		default:
+			return errors.New(`channels may only be "email", "push", or "webhook"`)
+		}
+		if seen[channel] {
+			return errors.New("channels may only list each channel once")
+		}
+		seen[channel] = true
+		if p.Address(channel) == "" {
+			switch channel {
+			case ChannelEmail:
+				return errors.New("email is required to be alerted by email")
+			case ChannelPush:
+				return errors.New("push_token is required to be alerted by push notification")
+			default:
+				return errors.New("webhook_url is required to be alerted by webhook")
+			}
+		}
+	}
+	return nil
+}
+
+// PlayerProfile holds a player's preferences, kept apart from their statistics, which are recounted from the
+// archive.
+type PlayerProfile struct {
+	Player        string            `bson:"_id" json:"player"`
+	Notifications NotificationPrefs `bson:"notifications" json:"notifications"`
+	UpdatedAt     time.Time         `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+// TurnAlert tracks the turn a game is waiting on, so the player to move is alerted once the turn has gone on
+// long enough, and only once.
+type TurnAlert struct {
+	Player string    `bson:"player"` // Player whose turn it is
+	Since  time.Time `bson:"since"`  // When the turn was first seen waiting on them
+	Sent   bool      `bson:"sent"`   // Whether they have been alerted
+}
+
+// PlayerToMove returns the person whose move the active game is waiting on, or false if it is waiting on a bot or
+// on nobody.
+func (g *Game) PlayerToMove() (string, bool) {
+	if g.Status != StatusActive || g.Turn == nil {
+		return "", false
+	}
+	if g.Mode == ModeBlackjack && g.HandPhase != HandPhasePlay {
+		return "", false
+	}
+	player := g.Turn.Player()
+	return player, player != "" && !g.IsBot(player)
+}
@@ -0,0 +1,288 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"my-card-game/internal/entropy"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// errNotReplayable is returned for events whose effect on the cards is not recorded in enough detail to repeat.
+var errNotReplayable = errors.New("the event does not record enough to be replayed")
+
+// ReplayReport is the outcome of re-simulating a game from its shuffle seeds and event log.
+type ReplayReport struct {
+	GameID      primitive.ObjectID `json:"game_id"`
+	Verified    bool               `json:"verified"`              // Whether the replay reached the recorded state
+	Events      int                `json:"events"`                // Events replayed
+	Shuffles    int                `json:"shuffles"`              // Shuffles repeated from their recorded seeds
+	Unsupported []string           `json:"unsupported,omitempty"` // Why the game could not be replayed, if it could not
+	Mismatches  []string           `json:"mismatches,omitempty"`  // Where the replay parted from the recorded game
+}
+
+// replayer hands a game being replayed the seeds of its recorded shuffles, in the order they were made,
+// noting anywhere the replay parts from the record.
+type replayer struct {
+	records    []ShuffleRecord
+	used       int
+	mismatches []string
+}
+
+// next returns the source for the next shuffle of deck, checking the deck is the one the recorded shuffle was
+// made from. A shuffle past the end of the record draws from an empty seed, so it falls back and is reported.
+func (r *replayer) next(deck CompactCards) entropy.Source {
+	r.used++
+	if r.used > len(r.records) {
+		r.mismatch("shuffle %d was never recorded", r.used)
+		return entropy.Replay(nil)
+	}
+	record := r.records[r.used-1]
+	if !sameCards(deck, record.Deck) {
+		r.mismatch("the deck before shuffle %d differs from the recorded deck", r.used)
+	}
+	return entropy.Replay(record.Seed)
+}
+
+// check reports a shuffle that needed more random bytes than were recorded for it.
+func (r *replayer) check(draw *entropy.Draw) {
+	if draw.Fallback() != nil && r.used <= len(r.records) {
+		r.mismatch("shuffle %d drew more random bytes than were recorded", r.used)
+	}
+}
+
+func (r *replayer) mismatch(format string, args ...interface{}) {
+	r.mismatches = append(r.mismatches, fmt.Sprintf(format, args...))
+}
+
+// Replay re-simulates a game from its shuffle records and event log, both oldest first, and checks it ends in
+// the recorded game's state: the same deck, hands, discard pile, house hand, books, status, and winner.
+// The game is rebuilt from its mode, settings, and the seats it started with, with the deck the first recorded
+// shuffle was made from; every shuffle repeats the bytes recorded for it. Gin rummy is not replayed, as melds
+// are not logged, and neither are games with redraws or rollbacks.
+func Replay(recorded *Game, events []Event, shuffles []ShuffleRecord) *ReplayReport {
+	report := &ReplayReport{GameID: recorded.ID}
+	if recorded.Mode == ModeGinRummy {
+		report.Unsupported = append(report.Unsupported, "gin rummy melds are not recorded in the event log")
+		return report
+	}
+	if len(shuffles) == 0 {
+		report.Unsupported = append(report.Unsupported, "the game has no recorded shuffles to start the deck from")
+		return report
+	}
+
+	// Seat the players the game started with, falling back to the recorded seats for games started before
+	// the seats were recorded
+	r := &replayer{records: shuffles}
+	g := &Game{
+		ID:           recorded.ID,
+		Owner:        recorded.Owner,
+		Players:      append([]string{}, recorded.Players...),
+		Teams:        recorded.Teams,
+		DealerIndex:  recorded.DealerIndex,
+		Mode:         recorded.Mode,
+		Settings:     recorded.Settings,
+		Status:       StatusLobby,
+		GameDeck:     append([]Card{}, shuffles[0].Deck...),
+		PlayerHands:  map[string][]Card{},
+		CardManifest: map[string]int{},
+		replay:       r,
+	}
+	for _, event := range events {
+		if event.Type != EventGameStarted {
+			continue
+		}
+		if players := []string{}; eventField(event, "players", &players) == nil {
+			g.Players = players
+		}
+		if teams := map[string][]string{}; eventField(event, "teams", &teams) == nil && len(teams) > 0 {
+			g.Teams = teams
+		}
+		eventField(event, "dealer_index", &g.DealerIndex)
+		break
+	}
+
+	// Repeat every event, stopping at the first that cannot be repeated
+	for i, event := range events {
+		err := g.replayEvent(event)
+		if errors.Is(err, errNotReplayable) {
+			report.Unsupported = append(report.Unsupported, fmt.Sprintf("event %d (%s): %v", i+1, event.Type, err))
+			return report
+		}
+		if err != nil {
+			r.mismatch("event %d (%s) could not be repeated: %v", i+1, event.Type, err)
+			break
+		}
+		report.Events++
+	}
+	report.Shuffles = r.used
+	if r.used < len(r.records) {
+		r.mismatch("%d recorded shuffles were never made", len(r.records)-r.used)
+	}
+
+	// Compare the replayed table with the recorded one
+	if !sameCards(g.GameDeck, recorded.GameDeck) {
+		r.mismatch("the deck differs")
+	}
+	players := map[string]int{}
+	for _, game := range []*Game{g, recorded} {
+		for player := range game.PlayerHands {
+			players[player]++
+		}
+		for player := range game.Books {
+			players[player]++
+		}
+	}
+	for _, player := range sortedKeys(players) {
+		if !sameCards(g.PlayerHands[player], recorded.PlayerHands[player]) {
+			r.mismatch("%s's hand differs", player)
+		}
+		if fmt.Sprint(g.Books[player]) != fmt.Sprint(recorded.Books[player]) {
+			r.mismatch("%s's books differ", player)
+		}
+	}
+	if !sameCards(g.DiscardPile, recorded.DiscardPile) {
+		r.mismatch("the discard pile differs")
+	}
+	if !sameCards(g.DealerHand, recorded.DealerHand) {
+		r.mismatch("the house's hand differs")
+	}
+	if g.Status != recorded.Status {
+		r.mismatch("the status is %q, but %q was recorded", g.Status, recorded.Status)
+	}
+	if g.Winner != recorded.Winner {
+		r.mismatch("the winner is %q, but %q was recorded", g.Winner, recorded.Winner)
+	}
+
+	report.Mismatches = r.mismatches
+	report.Verified = len(report.Mismatches) == 0
+	return report
+}
+
+// replayEvent repeats the effect an event had on the game's cards. Events that did not touch the cards,
+// such as bets and achievements, are passed over.
+func (g *Game) replayEvent(event Event) error {
+	player := event.Player
+	switch event.Type {
+	case EventGameStarted:
+		return g.SetUpTable()
+	case EventBattle:
+		if err := g.CheckAction(Action{Type: ActionBattle}); err != nil {
+			return err
+		}
+		_, err := g.ResolveBattle()
+		return err
+	case EventCardPlayed:
+		var card Card
+		var declaredSuit string
+		if err := eventField(event, "card", &card); err != nil {
+			return err
+		}
+		if _, ok := event.Data["declared_suit"]; ok {
+			if err := eventField(event, "declared_suit", &declaredSuit); err != nil {
+				return err
+			}
+		}
+		if err := g.CheckAction(Action{Type: ActionPlayCard, Player: player, Cards: []Card{card}, DeclaredSuit: declaredSuit}); err != nil {
+			return err
+		}
+		g.PlayCrazyEight(player, card, declaredSuit)
+	case EventCardDrawn:
+		if err := g.CheckAction(Action{Type: ActionDraw, Player: player}); err != nil {
+			return err
+		}
+		g.DrawFromDeck(player, 1)
+	case EventAsk:
+		var target, value string
+		if err := eventField(event, "target", &target); err != nil {
+			return err
+		}
+		if err := eventField(event, "value", &value); err != nil {
+			return err
+		}
+		if err := g.CheckAction(Action{Type: ActionAsk, Player: player, Target: target, Value: value}); err != nil {
+			return err
+		}
+		g.Ask(player, target, value)
+	case EventHit:
+		g.Hit(player)
+	case EventStood:
+		g.Stand(player)
+	case EventDoubledDown:
+		g.DoubleDown(player)
+	case EventSplit:
+		g.Split(player)
+	case EventSurrendered:
+		g.Surrender(player)
+	case EventHandDealt:
+		return g.DealHand()
+	case EventReshuffled:
+		// The reshuffle was recorded because the deck ran short, so it is forced here
+		var cards int
+		if err := eventField(event, "cards", &cards); err != nil {
+			return err
+		}
+		if moved := g.ReshuffleDiscards(len(g.GameDeck) + 1); moved != cards {
+			return fmt.Errorf("%d cards were reshuffled, but %d were recorded", moved, cards)
+		}
+	case EventPenaltyDraw:
+		var count int
+		if err := eventField(event, "count", &count); err != nil {
+			return err
+		}
+		g.DrawFromDeck(player, count)
+	case EventCardDealt:
+		var code string
+		if err := eventField(event, "card", &code); err != nil {
+			return err
+		}
+		dealt := g.DrawFromDeck(player, 1)
+		if len(dealt) == 0 || CardCode(dealt[0]) != code {
+			return fmt.Errorf("the card dealt is not the recorded %s", code)
+		}
+	case EventGameReset:
+		g.Reset()
+	case EventDeckShuffled:
+		g.ShuffleDeck()
+	case EventKicked, EventBanned, EventForfeited:
+		g.LeaveTable(player)
+	case EventInactive:
+		var action string
+		if eventField(event, "action", &action) == nil && action == "remove" {
+			g.LeaveTable(player)
+		}
+	case EventForceEnded:
+		g.Status = StatusFinished
+	case EventRedraw, EventRolledBack:
+		return errNotReplayable
+	}
+	return nil
+}
+
+// eventField decodes the named field of an event's data into out, whether the data was read back from
+// MongoDB or is still as it was recorded.
+func eventField(event Event, key string, out interface{}) error {
+	value, ok := event.Data[key]
+	if !ok {
+		return fmt.Errorf("the event has no %s", key)
+	}
+	raw, err := bson.Marshal(bson.M{key: value})
+	if err != nil {
+		return err
+	}
+	return bson.Raw(raw).Lookup(key).Unmarshal(out)
+}
+
+// sameCards reports whether two lists hold the same cards in the same order.
+func sameCards(a, b []Card) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
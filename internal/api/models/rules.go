@@ -0,0 +1,530 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Actions players take to change a game, as checked by CheckAction.
+const (
+	ActionDeal      = "deal"
+	ActionPlayCard  = "play_card"
+	ActionDraw      = "draw"
+	ActionAsk       = "ask"
+	ActionBet       = "bet"
+	ActionFold      = "fold"
+	ActionMeld      = "meld"
+	ActionLayOff    = "lay_off"
+	ActionBattle    = "battle"
+	ActionPenalty   = "penalty"
+	ActionBid       = "bid"
+	ActionRedraw    = "redraw"
+	ActionHit       = "hit"
+	ActionStand     = "stand"
+	ActionDouble    = "double"
+	ActionSplit     = "split"
+	ActionSurrender = "surrender"
+	ActionInsure    = "insure"
+)
+
+// MaxPenaltyCards is the most cards a single penalty can make a player draw.
+const MaxPenaltyCards = 10
+
+// Rules an action can break, reported in each Violation.
+const (
+	RuleAction     = "action"      // The action is not one the rules know about
+	RuleMode       = "mode"        // The action is not part of the game's mode
+	RulePhase      = "phase"       // The game is not in a phase that allows the action
+	RuleSeated     = "seated"      // The acting or targeted player has no seat in the game
+	RuleTurn       = "turn"        // It is another player's turn
+	RuleHoldsCards = "holds_cards" // The player does not hold the cards the action needs
+	RulePlay       = "play"        // The play does not satisfy the rules of the game's mode
+	RuleDeck       = "deck"        // The deck has no cards for the action
+	RuleInput      = "input"       // The action's parameters are malformed
+	RuleHouse      = "house"       // The action breaks one of the game's house rules
+)
+
+// Violation is one rule an action breaks, with a reason the player can read.
+type Violation struct {
+	Rule   string `json:"rule"`
+	Reason string `json:"reason"`
+}
+
+// Violations lists the rules an action breaks. It is the error returned for an action the rules reject.
+type Violations []Violation
+
+// Error joins the reasons of the violations into one message.
+func (v Violations) Error() string {
+	reasons := make([]string, len(v))
+	for i, violation := range v {
+		reasons[i] = violation.Reason
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// Action describes a move a player wants to make. Only the fields the action type uses need to be set.
+type Action struct {
+	Type         string
+	Player       string // Player taking the action; empty for actions no single player takes, such as a war battle
+	Target       string // Player asked for cards in Go Fish
+	Cards        []Card // Cards played, melded, or laid off
+	Value        string // Card value asked for in Go Fish
+	DeclaredSuit string // Suit named when playing a wild eight
+	MeldID       int    // Meld the cards are laid off on
+	Amount       int    // Chips bet, or cards drawn as a penalty
+	Bid          Bid    // Call made in an auction
+}
+
+// actionRule holds the rules of one action type: the mode and phases it is allowed in, whether the acting player
+// needs a seat and their turn, and the action's own rules, checked once the common ones pass.
+type actionRule struct {
+	mode         string   // Mode the action belongs to; empty allows every mode
+	modeReason   string   // Reason given when the game is in another mode
+	phases       []string // Statuses the action is allowed in
+	seated       bool     // Whether the acting player must have a seat
+	turn         bool     // Whether the action must be taken on the player's turn, once the game keeps a turn order
+	betweenHands bool     // Whether the action's own check decides if it is allowed once the hand is over
+	check        func(g *Game, a Action) Violations
+}
+
+// Phases shared by several actions. Games created before statuses existed have no status and count as lobby games.
+var (
+	phasesActive     = []string{StatusActive}
+	phasesUnfinished = []string{StatusLobby, StatusActive}
+)
+
+// actionRules maps every action type to its rules.
+var actionRules = map[string]actionRule{
+	ActionDeal: {
+		phases: phasesUnfinished,
+		seated: true,
+	},
+	ActionPlayCard: {
+		mode:       ModeCrazyEights,
+		modeReason: "cards can only be played in crazy eights games",
+		phases:     phasesActive,
+		seated:     true,
+		turn:       true,
+		check:      checkPlayCard,
+	},
+	ActionDraw: {
+		mode:       ModeCrazyEights,
+		modeReason: "cards can only be drawn in crazy eights games",
+		phases:     phasesActive,
+		seated:     true,
+		turn:       true,
+		check:      checkDraw,
+	},
+	ActionAsk: {
+		mode:       ModeGoFish,
+		modeReason: "asking is only allowed in go fish games",
+		phases:     phasesActive,
+		seated:     true,
+		turn:       true,
+		check:      checkAsk,
+	},
+	ActionBet: {
+		phases:       phasesUnfinished,
+		seated:       true,
+		betweenHands: true,
+		check:        checkBet,
+	},
+	ActionFold: {
+		phases: phasesUnfinished,
+		seated: true,
+		check:  checkFold,
+	},
+	ActionMeld: {
+		mode:       ModeGinRummy,
+		modeReason: "melds can only be declared in gin rummy games",
+		phases:     phasesUnfinished,
+		seated:     true,
+		check:      checkMeld,
+	},
+	ActionLayOff: {
+		mode:       ModeGinRummy,
+		modeReason: "cards can only be laid off in gin rummy games",
+		phases:     phasesUnfinished,
+		seated:     true,
+		check:      checkLayOff,
+	},
+	ActionBattle: {
+		mode:       ModeWar,
+		modeReason: "battles can only be fought in war games",
+		phases:     phasesActive,
+		check:      checkBattle,
+	},
+	ActionPenalty: {
+		phases: phasesActive,
+		seated: true,
+		check:  checkPenalty,
+	},
+	ActionBid: {
+		phases: phasesActive,
+		seated: true,
+		check:  checkBid,
+	},
+	ActionRedraw: {
+		phases: phasesActive,
+		seated: true,
+		check:  checkRedraw,
+	},
+	ActionHit:       blackjackRule,
+	ActionStand:     blackjackRule,
+	ActionDouble:    blackjackRule,
+	ActionSplit:     blackjackRule,
+	ActionSurrender: blackjackRule,
+	ActionInsure: {
+		mode:       ModeBlackjack,
+		modeReason: "insurance can only be taken in blackjack games",
+		phases:     phasesActive,
+		seated:     true,
+		check:      checkInsure,
+	},
+}
+
+// blackjackRule holds the rules shared by the moves a blackjack player makes on their hand.
+var blackjackRule = actionRule{
+	mode:       ModeBlackjack,
+	modeReason: "this move can only be made in blackjack games",
+	phases:     phasesActive,
+	seated:     true,
+	turn:       true,
+	check:      checkBlackjackMove,
+}
+
+// CheckAction checks an action against the game's rules before it is applied, and returns the rules it breaks
+// as Violations, or nil if the action is allowed. The rules every action shares (the game's mode and phase,
+// the phase of the current hand, and the acting player's seat and turn) are checked first; the action's own rules are only checked once those
+// pass, since they assume a seated player in a running game.
+func (g *Game) CheckAction(a Action) error {
+	rule, ok := actionRules[a.Type]
+	if !ok {
+		return Violations{{Rule: RuleAction, Reason: "unknown action " + a.Type}}
+	}
+
+	var violations Violations
+	if rule.mode != "" && g.Mode != rule.mode {
+		violations = append(violations, Violation{Rule: RuleMode, Reason: rule.modeReason})
+	}
+	if !g.inPhase(rule.phases) {
+		reason := "game is not active"
+		if g.Status == StatusFinished {
+			reason = "game has already finished"
+		}
+		violations = append(violations, Violation{Rule: RulePhase, Reason: reason})
+	} else if (g.HandPhase == HandPhaseShowdown || g.HandPhase == HandPhaseScored) && !rule.betweenHands {
+		violations = append(violations, Violation{Rule: RulePhase, Reason: "the hand is over; the next hand must be dealt first"})
+	}
+	if rule.seated && !g.isSeated(a.Player) {
+		violations = append(violations, Violation{Rule: RuleSeated, Reason: "player not found in the game"})
+	}
+	if rule.turn && g.Turn != nil && g.Turn.Player() != a.Player {
+		violations = append(violations, Violation{Rule: RuleTurn, Reason: "it is " + g.Turn.Player() + "'s turn"})
+	}
+	if len(violations) == 0 && rule.check != nil {
+		violations = rule.check(g, a)
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return violations
+}
+
+// inPhase reports whether the game's status is one of the phases.
+func (g *Game) inPhase(phases []string) bool {
+	status := g.Status
+	if status == "" {
+		status = StatusLobby
+	}
+	for _, phase := range phases {
+		if phase == status {
+			return true
+		}
+	}
+	return false
+}
+
+// isSeated reports whether the player has a seat in the game.
+func (g *Game) isSeated(playerName string) bool {
+	if playerName == "" {
+		return false
+	}
+	for _, player := range g.Players {
+		if player == playerName {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPlayCard checks a Crazy Eights play: one card the player holds that follows the top of the discard pile,
+// with a declared suit when it is a wild eight or another wild card.
+func checkPlayCard(g *Game, a Action) Violations {
+	if len(a.Cards) != 1 {
+		return Violations{{Rule: RuleInput, Reason: "exactly one card must be played"}}
+	}
+	card := a.Cards[0]
+
+	var violations Violations
+	if !g.CanPlayOnDiscard(card) {
+		violations = append(violations, Violation{Rule: RulePlay, Reason: "card does not match the suit or value of the top card"})
+	}
+	if g.PlaysAnywhere(card) && !IsValidSuit(a.DeclaredSuit) {
+		violations = append(violations, Violation{Rule: RuleInput, Reason: "a valid declared_suit is required when playing an eight or wild card"})
+	}
+	if _, ok := RemoveCards(g.PlayerHands[a.Player], a.Cards); !ok {
+		violations = append(violations, Violation{Rule: RuleHoldsCards, Reason: "player does not hold that card"})
+	}
+	return violations
+}
+
+// checkDraw checks a Crazy Eights draw: players must play a card if they are able to, and need a card to draw.
+func checkDraw(g *Game, a Action) Violations {
+	var violations Violations
+	if g.HasPlayableCard(a.Player) {
+		violations = append(violations, Violation{Rule: RulePlay, Reason: "player has a playable card and cannot draw"})
+	}
+	if len(g.GameDeck) == 0 {
+		violations = append(violations, Violation{Rule: RuleDeck, Reason: "no cards left to draw"})
+	}
+	return violations
+}
+
+// checkAsk checks a Go Fish ask: another seated player, for a value the asker already holds.
+func checkAsk(g *Game, a Action) Violations {
+	var violations Violations
+	if a.Player == a.Target {
+		violations = append(violations, Violation{Rule: RulePlay, Reason: "players cannot ask themselves"})
+	} else if !g.isSeated(a.Target) {
+		violations = append(violations, Violation{Rule: RuleSeated, Reason: "target player not found in the game"})
+	}
+	if !IsValidCardValue(a.Value) {
+		violations = append(violations, Violation{Rule: RuleInput, Reason: "invalid card value"})
+	} else if !g.HoldsValue(a.Player, a.Value) {
+		violations = append(violations, Violation{Rule: RuleHoldsCards, Reason: "players can only ask for a value they already hold"})
+	}
+	return violations
+}
+
+// checkBet checks a bet: a positive amount from a player still in the hand with chips to bet.
+// Bets larger than the player's stack are allowed and put the player all-in. Blackjack bets are placed on the next
+// hand, so they are only taken between hands, once the last hand has been scored.
+func checkBet(g *Game, a Action) Violations {
+	if g.HandPhase == HandPhaseShowdown || (g.HandPhase == HandPhaseScored && g.Mode != ModeBlackjack) {
+		return Violations{{Rule: RulePhase, Reason: "the hand is over; the next hand must be dealt first"}}
+	}
+
+	var violations Violations
+	if g.Mode == ModeBlackjack && g.HandPhase == HandPhasePlay {
+		violations = append(violations, Violation{Rule: RulePhase, Reason: "blackjack bets are placed before the hand is dealt"})
+	}
+	if a.Amount <= 0 {
+		violations = append(violations, Violation{Rule: RuleInput, Reason: "bet amount must be positive"})
+	}
+	if g.hasFolded(a.Player) {
+		violations = append(violations, Violation{Rule: RulePlay, Reason: "player has folded"})
+	} else if g.Chips[a.Player] == 0 {
+		violations = append(violations, Violation{Rule: RulePlay, Reason: "player has no chips left to bet"})
+	}
+	return violations
+}
+
+// checkFold checks a fold: players can only fold a hand they are still in.
+func checkFold(g *Game, a Action) Violations {
+	if g.hasFolded(a.Player) {
+		return Violations{{Rule: RulePlay, Reason: "player has already folded"}}
+	}
+	return nil
+}
+
+// checkMeld checks a Gin Rummy meld: cards the player holds that form a set or run.
+func checkMeld(g *Game, a Action) Violations {
+	var violations Violations
+	if _, _, err := g.ClassifyMeld(a.Cards); err != nil {
+		violations = append(violations, Violation{Rule: RulePlay, Reason: err.Error()})
+	}
+	if _, ok := RemoveCards(g.PlayerHands[a.Player], a.Cards); !ok {
+		violations = append(violations, Violation{Rule: RuleHoldsCards, Reason: "player does not hold all of the meld cards"})
+	}
+	return violations
+}
+
+// checkLayOff checks a Gin Rummy lay-off: cards the player holds that extend an existing meld into a valid meld
+// of the same type.
+func checkLayOff(g *Game, a Action) Violations {
+	if len(a.Cards) == 0 {
+		return Violations{{Rule: RuleInput, Reason: "no cards to lay off"}}
+	}
+
+	var violations Violations
+	if meld := g.meldByID(a.MeldID); meld == nil {
+		violations = append(violations, Violation{Rule: RuleInput, Reason: "meld not found"})
+	} else if meldType, _, err := g.ClassifyMeld(append(append([]Card{}, meld.Cards...), a.Cards...)); err != nil || meldType != meld.Type {
+		violations = append(violations, Violation{Rule: RulePlay, Reason: "cards cannot be laid off on this meld"})
+	}
+	if _, ok := RemoveCards(g.PlayerHands[a.Player], a.Cards); !ok {
+		violations = append(violations, Violation{Rule: RuleHoldsCards, Reason: "player does not hold all of the cards to lay off"})
+	}
+	return violations
+}
+
+// checkBattle checks a War battle: it is fought between the first two seated players.
+func checkBattle(g *Game, a Action) Violations {
+	if len(g.Players) < 2 {
+		return Violations{{Rule: RuleSeated, Reason: "a battle needs two players"}}
+	}
+	return nil
+}
+
+// checkPenalty checks a penalty draw: a number of cards up to MaxPenaltyCards that the deck can cover.
+// Penalties are dealt outside the turn flow, so they are not checked against the turn order.
+func checkPenalty(g *Game, a Action) Violations {
+	if a.Amount < 1 || a.Amount > MaxPenaltyCards {
+		return Violations{{Rule: RuleInput, Reason: fmt.Sprintf("a penalty must be between 1 and %d cards", MaxPenaltyCards)}}
+	}
+	if len(g.GameDeck) < a.Amount {
+		return Violations{{Rule: RuleDeck, Reason: fmt.Sprintf("only %d cards left to draw", len(g.GameDeck))}}
+	}
+	return nil
+}
+
+// checkBid checks a call in an auction: made in turn while the auction is open, and either a pass or a bid
+// at a valid level and strain that outranks the highest bid so far.
+func checkBid(g *Game, a Action) Violations {
+	if g.Auction == nil || g.Auction.Closed {
+		return Violations{{Rule: RulePhase, Reason: "no auction is open"}}
+	}
+	if player := g.Auction.Turn.Player(); player != a.Player {
+		return Violations{{Rule: RuleTurn, Reason: "it is " + player + "'s call"}}
+	}
+	if a.Bid.Pass {
+		return nil
+	}
+
+	var violations Violations
+	if a.Bid.Level < MinBidLevel || a.Bid.Level > MaxBidLevel {
+		violations = append(violations, Violation{Rule: RuleInput, Reason: fmt.Sprintf("bid level must be between %d and %d", MinBidLevel, MaxBidLevel)})
+	}
+	if !IsValidStrain(a.Bid.Strain) {
+		violations = append(violations, Violation{Rule: RuleInput, Reason: "invalid strain"})
+	}
+	if highest, ok := g.Auction.HighestBid(); ok && len(violations) == 0 && !a.Bid.Outranks(highest) {
+		violations = append(violations, Violation{Rule: RulePlay, Reason: "bid must be higher than " + highest.String()})
+	}
+	return violations
+}
+
+// checkRedraw checks a redraw: the game's settings must leave the player a redraw this hand, and the player must
+// hold the cards returned, within the settings' limit, with enough cards in the deck to replace them.
+func checkRedraw(g *Game, a Action) Violations {
+	if g.Settings.MaxRedraws == 0 {
+		return Violations{{Rule: RulePlay, Reason: "redraws are not allowed in this game"}}
+	}
+	if g.Redraws[a.Player] >= g.Settings.MaxRedraws {
+		return Violations{{Rule: RulePlay, Reason: "player has no redraws left this hand"}}
+	}
+	if len(a.Cards) == 0 {
+		return Violations{{Rule: RuleInput, Reason: "no cards to redraw"}}
+	}
+
+	var violations Violations
+	if limit := g.Settings.MaxRedrawCards; limit > 0 && len(a.Cards) > limit {
+		violations = append(violations, Violation{Rule: RuleInput, Reason: fmt.Sprintf("at most %d cards can be redrawn at once", limit)})
+	}
+	if _, ok := RemoveCards(g.PlayerHands[a.Player], a.Cards); !ok {
+		violations = append(violations, Violation{Rule: RuleHoldsCards, Reason: "player does not hold all of the cards to redraw"})
+	}
+	if len(g.GameDeck) < len(a.Cards) {
+		violations = append(violations, Violation{Rule: RuleDeck, Reason: fmt.Sprintf("only %d cards left to draw", len(g.GameDeck))})
+	}
+	return violations
+}
+
+// checkBlackjackMove checks a blackjack move on the player's current hand. Doubling down needs a hand of two cards,
+// splitting a pair of the same value, and surrendering must be the player's first decision; doubling and splitting
+// put up another bet of the hand's size, and every move but standing and surrendering needs cards in the deck.
+func checkBlackjackMove(g *Game, a Action) Violations {
+	index := g.CurrentHand(a.Player)
+	if index == -1 {
+		return Violations{{Rule: RulePlay, Reason: "player has no hand left to play"}}
+	}
+	hand := g.Hands[a.Player][index]
+
+	var violations Violations
+	needed := 0
+	switch a.Type {
+	case ActionHit:
+		needed = 1
+	case ActionDouble:
+		needed = 1
+		if len(hand.Cards) != 2 {
+			violations = append(violations, Violation{Rule: RulePlay, Reason: "players can only double down on their first two cards"})
+		}
+	case ActionSplit:
+		needed = 2
+		if len(hand.Cards) != 2 || hand.Cards[0].Value != hand.Cards[1].Value {
+			violations = append(violations, Violation{Rule: RulePlay, Reason: "only a pair can be split"})
+		}
+		if len(g.Hands[a.Player]) >= MaxBlackjackHands {
+			violations = append(violations, Violation{Rule: RulePlay, Reason: fmt.Sprintf("a player can hold at most %d hands", MaxBlackjackHands)})
+		}
+	case ActionSurrender:
+		if len(g.Hands[a.Player]) != 1 || len(hand.Cards) != 2 {
+			violations = append(violations, Violation{Rule: RulePlay, Reason: "players can only surrender as their first decision"})
+		}
+	}
+	if (a.Type == ActionDouble || a.Type == ActionSplit) && g.Chips[a.Player] < hand.Bet {
+		violations = append(violations, Violation{Rule: RulePlay, Reason: "player does not have the chips to match the bet"})
+	}
+	if len(g.GameDeck) < needed {
+		violations = append(violations, Violation{Rule: RuleDeck, Reason: fmt.Sprintf("only %d cards left to draw", len(g.GameDeck))})
+	}
+	return violations
+}
+
+// checkInsure checks an insurance bet: offered only while the house shows an ace, to players who have not yet made
+// a move on their hand, for up to half of their bet. Insurance is offered to every player at once, so it is not
+// checked against the turn order.
+func checkInsure(g *Game, a Action) Violations {
+	if up, ok := g.DealerUpCard(); !ok || up.Value != RankAce {
+		return Violations{{Rule: RulePlay, Reason: "insurance is only offered when the house shows an ace"}}
+	}
+	hands := g.Hands[a.Player]
+	if len(hands) != 1 || len(hands[0].Cards) != 2 || !(hands[0].Status == HandStatusPlaying || hands[0].IsBlackjack()) {
+		return Violations{{Rule: RulePlay, Reason: "insurance must be taken before the player's first move"}}
+	}
+	if g.Insurance[a.Player] > 0 {
+		return Violations{{Rule: RulePlay, Reason: "player has already taken insurance"}}
+	}
+
+	var violations Violations
+	if limit := hands[0].Bet / 2; a.Amount < 1 || a.Amount > limit {
+		violations = append(violations, Violation{Rule: RuleInput, Reason: fmt.Sprintf("insurance must be between 1 and %d chips, half the bet", limit)})
+	}
+	if g.Chips[a.Player] < a.Amount {
+		violations = append(violations, Violation{Rule: RulePlay, Reason: "player does not have the chips for the insurance"})
+	}
+	return violations
+}
+
+// hasFolded reports whether the player has folded the current hand.
+func (g *Game) hasFolded(playerName string) bool {
+	for _, player := range g.Folded {
+		if player == playerName {
+			return true
+		}
+	}
+	return false
+}
+
+// meldByID returns the meld on the table with the given ID, or nil if there is none.
+func (g *Game) meldByID(id int) *Meld {
+	for i := range g.Melds {
+		if g.Melds[i].ID == id {
+			return &g.Melds[i]
+		}
+	}
+	return nil
+}
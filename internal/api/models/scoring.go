@@ -0,0 +1,363 @@
+package models
+
+// Ace scoring modes supported by a game.
+// AceLow counts aces as 1, AceHigh counts them as 14, and AceFlexible counts them as
+// 1 or 11, whichever gives the best blackjack total without going over 21.
+const (
+	AceLow      = "low"
+	AceHigh     = "high"
+	AceFlexible = "flexible"
+)
+
+// Scoring modes that select how a game values the cards in a hand.
+const (
+	ScoringStandard  = "standard"
+	ScoringBlackjack = "blackjack"
+	ScoringHearts    = "hearts"
+	ScoringCribbage  = "cribbage"
+	ScoringCustom    = "custom"
+	ScoringFacesTen  = "faces_ten" // Tens and face cards count 10, with aces by the ace mode as 1 or 11
+)
+
+// JokerValue is the value of a joker, which is also its suit.
+const JokerValue = "Joker"
+
+// CardValues lists every card value in a standard deck, from lowest to highest with aces low.
+var CardValues = []string{"Ace", "2", "3", "4", "5", "6", "7", "8", "9", "10", "Jack", "Queen", "King"}
+
+// ScoringStrategy values a player's hand for a particular kind of game.
+// LowestWins reports whether a lower hand value is better, as with penalty points in Hearts.
+type ScoringStrategy interface {
+	HandValue(hand []Card) int
+	LowestWins() bool
+}
+
+// IsValidAceMode reports whether the given ace scoring mode is supported.
+// An empty mode is accepted and treated as AceLow.
+func IsValidAceMode(mode string) bool {
+	switch mode {
+	case "", AceLow, AceHigh, AceFlexible:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValidScoringMode reports whether the given scoring mode is supported.
+// An empty mode is accepted and treated as ScoringStandard.
+func IsValidScoringMode(mode string) bool {
+	switch mode {
+	case "", ScoringStandard, ScoringBlackjack, ScoringHearts, ScoringCribbage, ScoringCustom, ScoringFacesTen:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValidCardValue reports whether the given string is the value of a card in a standard deck.
+func IsValidCardValue(value string) bool {
+	rank, err := ParseRank(value)
+	return err == nil && rank.IsStandard()
+}
+
+// AceRanksHigh reports whether aces sort above kings for the game's ace scoring mode.
+func (g *Game) AceRanksHigh() bool {
+	return g.Settings.AceMode == AceHigh || g.Settings.AceMode == AceFlexible
+}
+
+// ValueRank returns where a card value ranks from low to high: aces are 1, or 14 when they rank high, and the
+// other values are their face value with jacks, queens, and kings as 11, 12, and 13. Jokers and unknown values are 0.
+func ValueRank(value Rank, acesHigh bool) int {
+	if value == RankAce && acesHigh {
+		return 14
+	}
+	return faceValue(value)
+}
+
+// ScoringStrategy returns the scoring strategy selected by the game's configuration.
+// Games without a scoring mode use the standard strategy with the game's ace mode, and blackjack games are always
+// scored by blackjack rules. Games with wild cards let them stand in for whichever card values the hand best.
+func (g *Game) ScoringStrategy() ScoringStrategy {
+	strategy := g.baseScoringStrategy()
+	if len(g.Settings.WildCards) > 0 {
+		return WildScoring{Strategy: strategy, IsWild: g.IsWild}
+	}
+	return strategy
+}
+
+// baseScoringStrategy returns the scoring strategy for the game's scoring mode, without wild cards.
+func (g *Game) baseScoringStrategy() ScoringStrategy {
+	if g.Mode == ModeBlackjack {
+		return BlackjackScoring{}
+	}
+	switch g.Settings.ScoringMode {
+	case ScoringBlackjack:
+		return BlackjackScoring{}
+	case ScoringHearts:
+		return HeartsScoring{}
+	case ScoringCribbage:
+		return CribbageScoring{}
+	case ScoringCustom:
+		return CustomScoring{Values: g.Settings.CustomValues, AceMode: g.Settings.AceMode}
+	case ScoringFacesTen:
+		return FacesTenScoring{AceMode: g.Settings.AceMode}
+	default:
+		return StandardScoring{AceMode: g.Settings.AceMode}
+	}
+}
+
+// StandardScoring values cards by rank: number cards at face value, Jack 11, Queen 12, King 13,
+// and aces according to the ace scoring mode.
+type StandardScoring struct {
+	AceMode string
+}
+
+// HandValue returns the total rank value of the hand.
+// In flexible mode one ace is counted as 11 instead of 1 when doing so keeps the total at or below 21.
+func (s StandardScoring) HandValue(hand []Card) int {
+	total := 0
+	hasAce := false
+	for _, card := range hand {
+		total += s.CardValue(card)
+		if card.Value == RankAce {
+			hasAce = true
+		}
+	}
+
+	// Promote a single ace from 1 to 11 if it does not bust the hand
+	if s.AceMode == AceFlexible && hasAce && total+10 <= 21 {
+		total += 10
+	}
+
+	return total
+}
+
+// CardValue returns the rank value of a single card.
+func (s StandardScoring) CardValue(card Card) int {
+	// Aces count as 14 when they rank high and 1 otherwise (flexible aces are promoted per hand)
+	if card.Value == RankAce && s.AceMode == AceHigh {
+		return 14
+	}
+	return faceValue(card.Value)
+}
+
+// LowestWins reports false: the highest standard total wins.
+func (s StandardScoring) LowestWins() bool {
+	return false
+}
+
+// BlackjackScoring values hands by blackjack rules: number cards at face value, face cards 10,
+// and aces 1 or 11, whichever gives the best total without going over 21.
+type BlackjackScoring struct{}
+
+// HandValue returns the best blackjack total of the hand.
+func (BlackjackScoring) HandValue(hand []Card) int {
+	total := 0
+	hasAce := false
+	for _, card := range hand {
+		total += countingValue(card.Value)
+		if card.Value == RankAce {
+			hasAce = true
+		}
+	}
+
+	// Only one ace can ever be promoted to 11 without busting
+	if hasAce && total+10 <= 21 {
+		total += 10
+	}
+
+	return total
+}
+
+// LowestWins reports false: the highest blackjack total wins.
+func (BlackjackScoring) LowestWins() bool {
+	return false
+}
+
+// FacesTenScoring values cards as blackjack and cribbage count them: number cards at face value, and tens,
+// jacks, queens, and kings all 10. Aces follow the ace scoring mode, counting 1 when low, 11 when high, and
+// 1 or 11 when flexible, whichever gives the best total without going over 21. Unlike blackjack, a hand over
+// 21 is not bust; the highest total still wins.
+type FacesTenScoring struct {
+	AceMode string
+}
+
+// HandValue returns the total value of the hand.
+func (s FacesTenScoring) HandValue(hand []Card) int {
+	total := 0
+	hasAce := false
+	for _, card := range hand {
+		total += s.CardValue(card)
+		if card.Value == RankAce {
+			hasAce = true
+		}
+	}
+
+	// Promote a single ace from 1 to 11 if it does not take the hand over 21
+	if s.AceMode == AceFlexible && hasAce && total+10 <= 21 {
+		total += 10
+	}
+
+	return total
+}
+
+// CardValue returns the value of a single card, with flexible aces counted as 1.
+func (s FacesTenScoring) CardValue(card Card) int {
+	if card.Value == RankAce && s.AceMode == AceHigh {
+		return 11
+	}
+	return countingValue(card.Value)
+}
+
+// LowestWins reports false: the highest total wins.
+func (FacesTenScoring) LowestWins() bool {
+	return false
+}
+
+// HiLoValue returns the card's tag in the Hi-Lo counting system: +1 for the low cards 2 to 6, -1 for tens,
+// face cards, and aces, and 0 for 7 to 9 and jokers. A complete deck tags to 0 overall.
+func HiLoValue(card Card) int {
+	switch rank := faceValue(card.Value); {
+	case rank >= 2 && rank <= 6:
+		return 1
+	case rank >= 10 || rank == 1:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// HeartsScoring values hands by Hearts penalty points: one point per heart and 13 for the Queen of Spades.
+type HeartsScoring struct{}
+
+// HandValue returns the penalty points held in the hand.
+func (HeartsScoring) HandValue(hand []Card) int {
+	points := 0
+	for _, card := range hand {
+		switch {
+		case card.Suit == SuitHearts:
+			points++
+		case card.Suit == SuitSpades && card.Value == RankQueen:
+			points += 13
+		}
+	}
+	return points
+}
+
+// LowestWins reports true: the player with the fewest penalty points wins.
+func (HeartsScoring) LowestWins() bool {
+	return true
+}
+
+// CribbageScoring values hands by cribbage counting: fifteens, pairs, runs, and flushes.
+type CribbageScoring struct{}
+
+// HandValue returns the cribbage points in the hand.
+// Every combination of cards adding up to 15 scores 2, every pair scores 2, every run of three
+// or more consecutive ranks scores its length (once per distinct run), and a hand of four or more
+// cards of the same suit scores one point per card.
+func (CribbageScoring) HandValue(hand []Card) int {
+	points := 0
+
+	// Fifteens: count the subsets summing to 15 with a subset-sum table
+	ways := make([]int, 16)
+	ways[0] = 1
+	for _, card := range hand {
+		value := countingValue(card.Value)
+		for sum := 15; sum >= value; sum-- {
+			ways[sum] += ways[sum-value]
+		}
+	}
+	points += 2 * ways[15]
+
+	// Pairs: every pair of cards sharing a rank scores 2
+	rankCounts := make([]int, 14)
+	for _, card := range hand {
+		rankCounts[faceValue(card.Value)]++
+	}
+	for _, count := range rankCounts {
+		points += count * (count - 1)
+	}
+
+	// Runs: each maximal sequence of three or more ranks scores its length for every distinct run it forms
+	for rank := 1; rank <= 13; {
+		if rankCounts[rank] == 0 {
+			rank++
+			continue
+		}
+		length, combinations := 0, 1
+		for rank <= 13 && rankCounts[rank] > 0 {
+			length++
+			combinations *= rankCounts[rank]
+			rank++
+		}
+		if length >= 3 {
+			points += length * combinations
+		}
+	}
+
+	// Flush: four or more cards all of the same suit
+	if len(hand) >= 4 {
+		flush := true
+		for _, card := range hand[1:] {
+			if card.Suit != hand[0].Suit {
+				flush = false
+				break
+			}
+		}
+		if flush {
+			points += len(hand)
+		}
+	}
+
+	return points
+}
+
+// LowestWins reports false: the player with the most cribbage points wins.
+func (CribbageScoring) LowestWins() bool {
+	return false
+}
+
+// CustomScoring values cards from an explicit map of card value to points, set in the game's settings for
+// house rules such as {"Queen": 20, "Ace": 15}. Jokers are valued by a "Joker" entry. Values missing from the
+// map fall back to standard scoring.
+type CustomScoring struct {
+	Values  map[string]int
+	AceMode string
+}
+
+// HandValue returns the total of the mapped value of each card.
+func (s CustomScoring) HandValue(hand []Card) int {
+	fallback := StandardScoring{AceMode: s.AceMode}
+	total := 0
+	for _, card := range hand {
+		if value, ok := s.Values[card.Value.String()]; ok {
+			total += value
+		} else {
+			total += fallback.CardValue(card)
+		}
+	}
+	return total
+}
+
+// LowestWins reports false: the highest custom total wins.
+func (CustomScoring) LowestWins() bool {
+	return false
+}
+
+// faceValue returns the rank of a card value with aces low: Ace 1, number cards at face value,
+// Jack 11, Queen 12, and King 13. Jokers count as 0.
+func faceValue(value Rank) int {
+	if !value.IsStandard() {
+		return 0
+	}
+	return int(value)
+}
+
+// countingValue returns the value of a card when face cards count as 10 and aces as 1.
+func countingValue(value Rank) int {
+	if rank := faceValue(value); rank < 10 {
+		return rank
+	}
+	return 10
+}
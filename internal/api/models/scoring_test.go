@@ -0,0 +1,38 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+)
+
+func BenchmarkHandValues(b *testing.B) {
+	modes := []struct {
+		name  string
+		setup func(g *Game)
+	}{
+		{"standard", func(g *Game) {}},
+		{"flexible aces", func(g *Game) { g.Settings.AceMode = AceFlexible }},
+		{"blackjack", func(g *Game) { g.Settings.ScoringMode = ScoringBlackjack }},
+		{"cribbage", func(g *Game) { g.Settings.ScoringMode = ScoringCribbage }},
+		{"wild twos", func(g *Game) { g.Settings.WildCards = []string{"2"} }},
+	}
+
+	for _, mode := range modes {
+		for _, decks := range benchDecks {
+			b.Run(fmt.Sprintf("%s/decks=%d", mode.name, decks), func(b *testing.B) {
+				// Every player holds an even share of the shuffled shoe
+				g := newBenchGame(decks, 6)
+				mode.setup(g)
+				g.ShuffleDeck()
+				if err := g.DealOpeningHands(len(g.GameDeck)/len(g.Players), 0); err != nil {
+					b.Fatal(err)
+				}
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					g.HandValues()
+				}
+			})
+		}
+	}
+}
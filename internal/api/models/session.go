@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Session represents an authenticated player session.
+// Only a SHA-256 hash of the session token is stored, so a leaked database cannot be used to
+// impersonate players.
+type Session struct {
+	TokenHash  string    `bson:"_id" json:"-"`
+	PlayerName string    `bson:"player_name" json:"player_name"`
+	OrgID      string    `bson:"org_id,omitempty" json:"org_id,omitempty"` // Organization the player signed in to; empty for the default organization
+	CreatedAt  time.Time `bson:"created_at" json:"created_at"`
+	ExpiresAt  time.Time `bson:"expires_at" json:"expires_at"`
+}
+
+// PlayerAccount claims a player name, so sessions for the name are only issued to whoever claimed it.
+// A name is claimed when it is registered with a password, or when an anonymous caller first joins a game with
+// it; a name claimed by joining has no password until its player sets one, and can only be used through the
+// session it was given.
+type PlayerAccount struct {
+	PlayerName   string    `bson:"_id" json:"player_name"`
+	PasswordHash string    `bson:"password_hash,omitempty" json:"-"` // bcrypt hash of the password signing in needs; empty until one is set
+	CreatedAt    time.Time `bson:"created_at" json:"created_at"`
+}
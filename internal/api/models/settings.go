@@ -0,0 +1,244 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Hand visibility rules that control who may look at a player's hand.
+const (
+	VisibilityPrivate = "private" // Players see their own hand; admins, and the game's owner when not seated, see every hand
+	VisibilityOwnOnly = "own"     // Players see only their own hand, not even the owner sees the others
+	VisibilityOpen    = "open"    // Every hand is visible to everyone, for open-handed games and teaching
+)
+
+// maxDeckCount caps how many decks a single game may shuffle together.
+const maxDeckCount = 8
+
+// maxTableSeats caps how many seats a game's table may have.
+const maxTableSeats = 16
+
+// maxCustomCardValue caps the points a custom value map may give a card, either way.
+const maxCustomCardValue = 1000
+
+// Settings holds the per-game configuration chosen when the game is created.
+// Settings can be edited while the game is in the lobby and are consumed by the deal,
+// shuffle, and scoring logic in place of hardcoded behaviour.
+type Settings struct {
+	DeckCount         int            `bson:"deck_count" json:"deck_count"`                     // Decks shuffled together when the game starts
+	Jokers            bool           `bson:"jokers" json:"jokers"`                             // Whether each deck includes two jokers
+	ScoringMode       string         `bson:"scoring_mode" json:"scoring_mode"`                 // Scoring strategy used to value hands
+	AceMode           string         `bson:"ace_mode" json:"ace_mode"`                         // How aces are scored: low, high, or flexible
+	CustomValues      map[string]int `bson:"custom_values" json:"custom_values"`               // Points per card value, such as {"Queen": 20, "Joker": 50}, used by the custom scoring mode
+	SuitOrder         []string       `bson:"suit_order,omitempty" json:"suit_order,omitempty"` // Suits from highest to lowest, used to break ties; empty means suits do not rank
+	WildCards         []string       `bson:"wild_cards,omitempty" json:"wild_cards,omitempty"` // Card values such as "2" or "Joker", or card codes such as "JS", that stand in for any card
+	TurnTimer         int            `bson:"turn_timer" json:"turn_timer"`                     // Seconds a player has to act; 0 means no limit
+	HandSize          int            `bson:"hand_size" json:"hand_size"`                       // Cards dealt to each player at the start; 0 uses the mode's default
+	Visibility        string         `bson:"visibility" json:"visibility"`                     // Who may look at players' hands
+	MinPlayers        int            `bson:"min_players" json:"min_players"`                   // Players needed before the game can start
+	MaxPlayers        int            `bson:"max_players" json:"max_players"`                   // Most players allowed to join; 0 means no limit
+	TableSeats        int            `bson:"table_seats" json:"table_seats"`                   // Seats at the table; 0 uses max_players, or a seat per player if neither is set
+	ReshuffleDiscards bool           `bson:"reshuffle_discards" json:"reshuffle_discards"`     // Shuffle the discard pile back in when the deck runs out
+	MaxRedraws        int            `bson:"max_redraws" json:"max_redraws"`                   // Redraws each player may take per hand; 0 disables redraws
+	MaxRedrawCards    int            `bson:"max_redraw_cards" json:"max_redraw_cards"`         // Most cards returned in one redraw; 0 allows the whole hand
+}
+
+// SettingsPatch describes a partial update to a game's settings.
+// Only the fields that are present in the request are changed.
+type SettingsPatch struct {
+	DeckCount         *int            `json:"deck_count"`
+	Jokers            *bool           `json:"jokers"`
+	ScoringMode       *string         `json:"scoring_mode"`
+	AceMode           *string         `json:"ace_mode"`
+	CustomValues      *map[string]int `json:"custom_values"`
+	SuitOrder         *[]string       `json:"suit_order"`
+	WildCards         *[]string       `json:"wild_cards"`
+	TurnTimer         *int            `json:"turn_timer"`
+	HandSize          *int            `json:"hand_size"`
+	Visibility        *string         `json:"visibility"`
+	MinPlayers        *int            `json:"min_players"`
+	MaxPlayers        *int            `json:"max_players"`
+	TableSeats        *int            `json:"table_seats"`
+	ReshuffleDiscards *bool           `json:"reshuffle_discards"`
+	MaxRedraws        *int            `json:"max_redraws"`
+	MaxRedrawCards    *int            `json:"max_redraw_cards"`
+}
+
+// ApplyDefaults fills in the default value of every setting that was left empty.
+func (s *Settings) ApplyDefaults() {
+	if s.DeckCount == 0 {
+		s.DeckCount = 1
+	}
+	if s.ScoringMode == "" {
+		s.ScoringMode = ScoringStandard
+	}
+	if s.AceMode == "" {
+		// Counting tens for face cards goes with aces worth 1 or 11
+		s.AceMode = AceLow
+		if s.ScoringMode == ScoringFacesTen {
+			s.AceMode = AceFlexible
+		}
+	}
+	if s.Visibility == "" {
+		s.Visibility = VisibilityPrivate
+	}
+}
+
+// Validate checks that every setting holds a supported value.
+func (s *Settings) Validate() error {
+	if s.DeckCount < 0 || s.DeckCount > maxDeckCount {
+		return errors.New("deck_count must be between 1 and 8")
+	}
+	if !IsValidScoringMode(s.ScoringMode) {
+		return errors.New("invalid scoring mode")
+	}
+	if !IsValidAceMode(s.AceMode) {
+		return errors.New("invalid ace mode")
+	}
+
+	// Custom scoring needs a value map that only refers to real card values, or jokers, with sensible points
+	if s.ScoringMode == ScoringCustom && len(s.CustomValues) == 0 {
+		return errors.New("custom scoring requires custom_values")
+	}
+	for value, points := range s.CustomValues {
+		if !IsValidCardValue(value) && value != JokerValue {
+			return errors.New("invalid card value in custom_values: " + value)
+		}
+		if points < -maxCustomCardValue || points > maxCustomCardValue {
+			return fmt.Errorf("custom_values[%s] must be between %d and %d", value, -maxCustomCardValue, maxCustomCardValue)
+		}
+	}
+
+	if err := ValidateSuitOrder(s.SuitOrder); err != nil {
+		return err
+	}
+	if err := ValidateWildCards(s.WildCards); err != nil {
+		return err
+	}
+
+	if s.TurnTimer < 0 {
+		return errors.New("turn_timer cannot be negative")
+	}
+	if s.HandSize < 0 {
+		return errors.New("hand_size cannot be negative")
+	}
+
+	if s.MaxRedraws < 0 || s.MaxRedrawCards < 0 {
+		return errors.New("redraw limits cannot be negative")
+	}
+
+	// The shoe must hold a hand for every player the game needs before it can start
+	if players := s.MinPlayers; s.HandSize > 0 {
+		if players < 2 {
+			players = 2
+		}
+		if s.HandSize*players > s.ShoeSize() {
+			return fmt.Errorf("hand_size is too large: %d players with %d cards each need more than the %d cards in the shoe",
+				players, s.HandSize, s.ShoeSize())
+		}
+	}
+	switch s.Visibility {
+	case "", VisibilityPrivate, VisibilityOwnOnly, VisibilityOpen:
+	default:
+		return errors.New("invalid visibility rule")
+	}
+
+	// Validate the player limits; a maximum of 0 means the game has no limit
+	if s.MinPlayers < 0 || s.MaxPlayers < 0 {
+		return errors.New("player limits cannot be negative")
+	}
+	if s.MaxPlayers > 0 && s.MaxPlayers < s.MinPlayers {
+		return errors.New("max_players cannot be less than min_players")
+	}
+
+	// The table needs a seat for every player it can hold
+	if s.TableSeats < 0 || s.TableSeats > maxTableSeats {
+		return fmt.Errorf("table_seats must be between 0 and %d", maxTableSeats)
+	}
+	if s.TableSeats > 0 && (s.TableSeats < s.MaxPlayers || s.TableSeats < s.MinPlayers) {
+		return errors.New("table_seats cannot be less than max_players or min_players")
+	}
+
+	return nil
+}
+
+// ShoeSize returns the number of cards in a shoe built from the settings: the decks shuffled together,
+// with their jokers if the game plays with them.
+func (s *Settings) ShoeSize() int {
+	count := s.DeckCount
+	if count == 0 {
+		count = 1
+	}
+	size := 52
+	if s.Jokers {
+		size += 2
+	}
+	return count * size
+}
+
+// Apply copies every field present in the patch onto the settings.
+func (p SettingsPatch) Apply(s *Settings) {
+	if p.DeckCount != nil {
+		s.DeckCount = *p.DeckCount
+	}
+	if p.Jokers != nil {
+		s.Jokers = *p.Jokers
+	}
+	if p.ScoringMode != nil {
+		s.ScoringMode = *p.ScoringMode
+	}
+	if p.AceMode != nil {
+		s.AceMode = *p.AceMode
+	}
+	if p.CustomValues != nil {
+		s.CustomValues = *p.CustomValues
+	}
+	if p.SuitOrder != nil {
+		s.SuitOrder = *p.SuitOrder
+	}
+	if p.WildCards != nil {
+		s.WildCards = *p.WildCards
+	}
+	if p.TurnTimer != nil {
+		s.TurnTimer = *p.TurnTimer
+	}
+	if p.HandSize != nil {
+		s.HandSize = *p.HandSize
+	}
+	if p.Visibility != nil {
+		s.Visibility = *p.Visibility
+	}
+	if p.MinPlayers != nil {
+		s.MinPlayers = *p.MinPlayers
+	}
+	if p.MaxPlayers != nil {
+		s.MaxPlayers = *p.MaxPlayers
+	}
+	if p.TableSeats != nil {
+		s.TableSeats = *p.TableSeats
+	}
+	if p.ReshuffleDiscards != nil {
+		s.ReshuffleDiscards = *p.ReshuffleDiscards
+	}
+	if p.MaxRedraws != nil {
+		s.MaxRedraws = *p.MaxRedraws
+	}
+	if p.MaxRedrawCards != nil {
+		s.MaxRedrawCards = *p.MaxRedrawCards
+	}
+}
+
+// NewShoe builds the starting deck for the game from its settings: DeckCount standard decks,
+// each with two jokers if the game plays with jokers.
+func (g *Game) NewShoe() *Deck {
+	count := g.Settings.DeckCount
+	if count == 0 {
+		count = 1
+	}
+
+	shoe := &Deck{}
+	for i := 0; i < count; i++ {
+		shoe.Cards = append(shoe.Cards, NewDeckWithJokers(g.Settings.Jokers).Cards...)
+	}
+	return shoe
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"my-card-game/internal/entropy"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UseEntropy sets the source the game's deck is shuffled with. Services hand every game they load the source they
+// were built with; a game given none shuffles with crypto/rand.
+func (g *Game) UseEntropy(source entropy.Source) {
+	g.entropy = source
+}
+
+// ShuffleFisherYates is the method decks are shuffled with, as noted in shuffle records. Records that note no
+// method are replayed with it too.
+const ShuffleFisherYates = "fisher_yates"
+
+// ShuffleRecord notes where the randomness of one shuffle came from, for compliance. Records are kept in their
+// own log, which outlives the game they describe. The deck before the shuffle and the bytes the shuffle drew are
+// kept too, so the game can be replayed, but never shown, as they reveal the order of the deck.
+type ShuffleRecord struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	GameID     primitive.ObjectID `bson:"game_id" json:"game_id"`
+	Source     string             `bson:"source" json:"source"`                         // Entropy source the shuffle drew from
+	Method     string             `bson:"method,omitempty" json:"method,omitempty"`     // How the deck was shuffled; see ShuffleFisherYates
+	Fallback   string             `bson:"fallback,omitempty" json:"fallback,omitempty"` // Why the configured source was not used, if it failed
+	Cards      int                `bson:"cards" json:"cards"`                           // Number of cards shuffled
+	Deck       CompactCards       `bson:"deck" json:"-"`                                // The deck as it was before the shuffle
+	Seed       []byte             `bson:"seed" json:"-"`                                // Every random byte the shuffle drew
+	ShuffledAt time.Time          `bson:"shuffled_at" json:"shuffled_at"`
+}
+
+// noteShuffle adds a record of a shuffle of deck to the game's pending shuffle records, to be logged when the game
+// is saved. A shuffle made while the game is being replayed checks that it drew only the recorded bytes instead.
+func (g *Game) noteShuffle(deck CompactCards, draw *entropy.Draw) {
+	if g.replay != nil {
+		g.replay.check(draw)
+	}
+	record := ShuffleRecord{
+		GameID:     g.ID,
+		Source:     draw.Source(),
+		Method:     ShuffleFisherYates,
+		Cards:      len(g.GameDeck),
+		Deck:       deck,
+		Seed:       draw.Bytes(),
+		ShuffledAt: time.Now().UTC(),
+	}
+	if err := draw.Fallback(); err != nil {
+		record.Fallback = err.Error()
+	}
+	g.Shuffles = append(g.Shuffles, record)
+}
@@ -0,0 +1,292 @@
+package models
+
+import (
+	"errors"
+	"my-card-game/internal/entropy"
+)
+
+// maxSimulatedTurns caps the turns one simulated continuation may take, so a game that cannot finish, such as a War
+// game passing the same cards back and forth, is given up on as undecided.
+const maxSimulatedTurns = 5000
+
+// blackjackStandValue is the total at which simulated blackjack players stop drawing, as the house does.
+const blackjackStandValue = 17
+
+// CanSimulate reports whether the game can be played out by SimulateContinuation: it must be under way.
+func (g *Game) CanSimulate() error {
+	if g.Status != StatusActive {
+		return errors.New("only active games can be simulated")
+	}
+	if len(g.Players) == 0 {
+		return errors.New("the game has no players")
+	}
+	return nil
+}
+
+// SimulateContinuation plays one random continuation of the game on a copy, leaving the game itself untouched,
+// and returns the players who won it, or none if it was undecided. The cards nobody can see are shuffled first,
+// so each continuation deals them out differently: the deck, the house's face-down card in blackjack, and the
+// piles in War. The copy is then played on by the mode's rules, with every player following a simple strategy:
+//
+//   - War battles are fought until one player holds every card.
+//   - Blackjack players draw to 17 and the house plays its hand; every player who beats the house wins.
+//   - Crazy Eights players play the first card they can, keeping eights and wild cards back, and draw when they
+//     cannot play; the first to empty their hand wins.
+//   - Go Fish players ask a random opponent for the value they hold most of; the most books wins.
+//   - Other modes are decided by the showdown, once hands short of the game's hand size are dealt up to it.
+func (g *Game) SimulateContinuation(source entropy.Source) []string {
+	sim := g.simulationCopy()
+	draw := entropy.NewDraw(source, 4*(len(sim.GameDeck)+1))
+	shuffleCards(sim.GameDeck, draw)
+
+	switch sim.Mode {
+	case ModeWar:
+		return sim.simulateWar(draw)
+	case ModeBlackjack:
+		return sim.simulateBlackjack(draw)
+	case ModeCrazyEights:
+		return sim.simulateCrazyEights()
+	case ModeGoFish:
+		return sim.simulateGoFish(draw)
+	default:
+		return sim.simulateShowdown()
+	}
+}
+
+// simulationCopy copies the parts of the game a continuation plays on, so the copy can be changed freely.
+func (g *Game) simulationCopy() *Game {
+	sim := &Game{
+		ID:           g.ID,
+		Mode:         g.Mode,
+		Settings:     g.Settings,
+		Status:       g.Status,
+		Teams:        g.Teams,
+		Players:      append([]string{}, g.Players...),
+		DealerIndex:  g.DealerIndex,
+		HandPhase:    g.HandPhase,
+		GameDeck:     append([]Card{}, g.GameDeck...),
+		PlayerHands:  map[string][]Card{},
+		Hands:        map[string][]PlayerHand{},
+		DealerHand:   append([]Card{}, g.DealerHand...),
+		DiscardPile:  append([]Card{}, g.DiscardPile...),
+		DeclaredSuit: g.DeclaredSuit,
+		Books:        map[string][]string{},
+		Folded:       append([]string{}, g.Folded...),
+		Chips:        copyCounts(g.Chips),
+		Bets:         copyCounts(g.Bets),
+		HouseRules:   g.HouseRules,
+		houseScript:  g.houseScript,
+	}
+	for player, hand := range g.PlayerHands {
+		sim.PlayerHands[player] = append([]Card{}, hand...)
+	}
+	for player, hands := range g.Hands {
+		for _, hand := range hands {
+			hand.Cards = append([]Card{}, hand.Cards...)
+			sim.Hands[player] = append(sim.Hands[player], hand)
+		}
+	}
+	for player, books := range g.Books {
+		sim.Books[player] = append([]string{}, books...)
+	}
+	if g.Turn != nil {
+		turn := *g.Turn
+		turn.Queue = append([]string{}, g.Turn.Queue...)
+		sim.Turn = &turn
+	} else {
+		sim.Turn = NewTurnOrder(sim.Players, (sim.DealerIndex+1)%len(sim.Players))
+	}
+	return sim
+}
+
+// shuffleCards shuffles cards in place with a Fisher-Yates shuffle, drawing from draw.
+func shuffleCards(cards []Card, draw *entropy.Draw) {
+	for i := len(cards) - 1; i > 0; i-- {
+		j := draw.Intn(i + 1)
+		cards[i], cards[j] = cards[j], cards[i]
+	}
+}
+
+// simulateWar shuffles both piles, which neither player can see into, and fights battles until the game is won.
+func (g *Game) simulateWar(draw *entropy.Draw) []string {
+	for _, player := range g.Players {
+		shuffleCards(g.PlayerHands[player], draw)
+	}
+	for turn := 0; turn < maxSimulatedTurns && g.Status == StatusActive; turn++ {
+		if _, err := g.ResolveBattle(); err != nil {
+			return nil
+		}
+	}
+	return g.simulatedWinner()
+}
+
+// simulateBlackjack puts the house's face-down card back into the deck and deals it a new one, plays out every
+// player's hands, drawing to 17, and lets the house play its hand.
+func (g *Game) simulateBlackjack(draw *entropy.Draw) []string {
+	if g.HandPhase == HandPhasePlay && len(g.DealerHand) > 1 && len(g.GameDeck) > 0 {
+		g.GameDeck = append(g.GameDeck, g.DealerHand[1:]...)
+		shuffleCards(g.GameDeck, draw)
+		g.DealerHand = append(g.DealerHand[:1], g.GameDeck[0])
+		g.GameDeck = g.GameDeck[1:]
+	}
+
+	for turn := 0; turn < maxSimulatedTurns && g.HandPhase == HandPhasePlay; turn++ {
+		player := g.Turn.Player()
+		index := g.CurrentHand(player)
+		if index == -1 {
+			g.passFinishedBlackjackTurns()
+			continue
+		}
+		if g.Hands[player][index].Value() < blackjackStandValue && len(g.GameDeck) > 0 {
+			g.Hit(player)
+		} else {
+			g.Stand(player)
+		}
+	}
+	if g.HandPhase == HandPhasePlay {
+		g.PlayDealer()
+	}
+
+	_, winners := g.HandValues()
+	return winners
+}
+
+// simulateCrazyEights plays turns until a player empties their hand. A player who cannot play draws, refilling
+// the deck from the discard pile if the game allows it, and passes once there is nothing left to draw.
+func (g *Game) simulateCrazyEights() []string {
+	for turn := 0; turn < maxSimulatedTurns && g.Status == StatusActive; turn++ {
+		player := g.Turn.Player()
+		if card, ok := g.simulatedCrazyEight(player); ok {
+			g.PlayCrazyEight(player, card, g.simulatedSuit(player, card))
+			continue
+		}
+
+		g.ReshuffleDiscards(1)
+		if len(g.GameDeck) == 0 {
+			g.Turn.Advance()
+			continue
+		}
+		g.DrawFromDeck(player, 1)
+	}
+	return g.simulatedWinner()
+}
+
+// simulatedCrazyEight picks the card a simulated player plays: the first card they can play that is not an eight
+// or a wild card, or else the first of those.
+func (g *Game) simulatedCrazyEight(player string) (Card, bool) {
+	var wild *Card
+	for _, card := range g.PlayerHands[player] {
+		if !g.CanPlayOnDiscard(card) {
+			continue
+		}
+		if !g.PlaysAnywhere(card) {
+			return card, true
+		}
+		if wild == nil {
+			held := card
+			wild = &held
+		}
+	}
+	if wild != nil {
+		return *wild, true
+	}
+	return Card{}, false
+}
+
+// simulatedSuit picks the suit a simulated player declares with an eight or a wild card: the suit they hold most
+// of once the card is played, or the card's own suit if they hold no other.
+func (g *Game) simulatedSuit(player string, played Card) string {
+	counts := map[Suit]int{}
+	best := played.Suit
+	for _, card := range g.PlayerHands[player] {
+		if card == played || !card.Suit.IsStandard() {
+			continue
+		}
+		counts[card.Suit]++
+		if counts[card.Suit] > counts[best] {
+			best = card.Suit
+		}
+	}
+	if !best.IsStandard() {
+		best = SuitSpades
+	}
+	return best.String()
+}
+
+// simulateGoFish plays asks until every card has been booked.
+func (g *Game) simulateGoFish(draw *entropy.Draw) []string {
+	for turn := 0; turn < maxSimulatedTurns && g.Status == StatusActive; turn++ {
+		asker := g.Turn.Player()
+		value, targets := g.simulatedAsk(asker)
+		if value == "" || len(targets) == 0 {
+			if g.GoFishOver() {
+				break
+			}
+			g.Turn.Advance()
+			continue
+		}
+		g.Ask(asker, targets[draw.Intn(len(targets))], value)
+	}
+	if !g.GoFishOver() {
+		return nil
+	}
+	_, winners := g.HandValues()
+	return winners
+}
+
+// simulatedAsk picks what a simulated Go Fish player asks for: the value they hold most of, from any opponent
+// still holding cards.
+func (g *Game) simulatedAsk(asker string) (string, []string) {
+	counts := map[string]int{}
+	value := ""
+	for _, card := range g.PlayerHands[asker] {
+		name := card.Value.String()
+		counts[name]++
+		if value == "" || counts[name] > counts[value] {
+			value = name
+		}
+	}
+
+	targets := []string{}
+	for _, player := range g.Players {
+		if player != asker && len(g.PlayerHands[player]) > 0 {
+			targets = append(targets, player)
+		}
+	}
+	return value, targets
+}
+
+// simulateShowdown deals hands short of the game's hand size up to it, in deal order, and scores the showdown.
+func (g *Game) simulateShowdown() []string {
+	size := g.OpeningHandSize()
+	for _, player := range g.DealOrder() {
+		if short := size - len(g.PlayerHands[player]); short > 0 && !g.hasFolded(player) {
+			g.DrawFromDeck(player, short)
+		}
+	}
+	_, winners := g.HandValues()
+	return winners
+}
+
+// simulatedWinner returns the winner of a finished continuation, or none if it was given up on.
+func (g *Game) simulatedWinner() []string {
+	if g.Status != StatusFinished || g.Winner == "" {
+		return nil
+	}
+	return []string{g.Winner}
+}
+
+// Simulation sums up many simulated continuations of a game.
+type Simulation struct {
+	Runs      int               `json:"runs"`
+	Seed      int64             `json:"seed"`      // Seed the continuations were drawn from; the same seed and runs repeat them
+	Undecided int               `json:"undecided"` // Continuations given up on, or that nobody won
+	Players   []SimulatedPlayer `json:"players"`   // Every seated player, in seat order
+}
+
+// SimulatedPlayer is how one player fared across the simulated continuations of a game.
+type SimulatedPlayer struct {
+	Player         string  `json:"player"`
+	Wins           float64 `json:"wins"`            // Continuations won, with a win shared by several players counted as a share
+	WinProbability float64 `json:"win_probability"` // Wins as a fraction of every continuation
+}
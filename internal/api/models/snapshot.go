@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Snapshot is a named copy of a game's full state that the game can later be rolled back to.
+type Snapshot struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	GameID    primitive.ObjectID `bson:"game_id" json:"game_id"`
+	Name      string             `bson:"name" json:"name"`
+	Game      Game               `bson:"game" json:"-"` // The saved game state; not returned when listing snapshots
+	CreatedBy string             `bson:"created_by" json:"created_by"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
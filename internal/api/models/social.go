@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Statuses of friend requests and game invitations.
+const (
+	RequestPending  = "pending"
+	RequestAccepted = "accepted"
+	RequestDeclined = "declined"
+)
+
+// Friendship links two players. It starts as a pending request from one player to the other
+// and becomes a friendship once the recipient accepts it.
+type Friendship struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	From       string             `bson:"from" json:"from"`
+	To         string             `bson:"to" json:"to"`
+	Status     string             `bson:"status" json:"status"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	AcceptedAt *time.Time         `bson:"accepted_at,omitempty" json:"accepted_at,omitempty"`
+}
+
+// Invitation asks a player to join a game. Accepting it seats the player in the game.
+type Invitation struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	GameID    primitive.ObjectID `bson:"game_id" json:"game_id"`
+	GameName  string             `bson:"game_name" json:"game_name"`
+	From      string             `bson:"from" json:"from"`
+	To        string             `bson:"to" json:"to"`
+	Status    string             `bson:"status" json:"status"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// Notification types delivered to players.
+const (
+	NotifyFriendRequest  = "friend_request"
+	NotifyFriendAccepted = "friend_accepted"
+	NotifyGameInvite     = "game_invite"
+	NotifyWaitlistSeated = "waitlist_seated"
+	NotifyYourTurn       = "your_turn"
+)
+
+// Notification tells a player about something that concerns them, such as a friend request or a game invitation.
+// Notifications are stored so they can be listed later, and pushed to the player's real-time stream as they are created.
+type Notification struct {
+	ID        primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	Player    string                 `bson:"player" json:"player"`
+	Type      string                 `bson:"type" json:"type"`
+	Data      map[string]interface{} `bson:"data,omitempty" json:"data,omitempty"`
+	CreatedAt time.Time              `bson:"created_at" json:"created_at"`
+}
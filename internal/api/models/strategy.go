@@ -0,0 +1,284 @@
+package models
+
+import (
+	"math"
+	"my-card-game/internal/entropy"
+)
+
+// botSimulationBudget caps the continuations a simulation bot plays out to choose one move, shared among its moves.
+const botSimulationBudget = 1000
+
+// minBotSimulationRuns is the fewest continuations a simulation bot plays out for each move, however many it has.
+const minBotSimulationRuns = 20
+
+// ChooseAction picks the move a player makes on their turn when played by a bot with the given configuration, as
+// an action the game's rules allow. The moves a bot considers are the plays and draws of Crazy Eights, the asks of
+// Go Fish, and hitting, standing, and doubling down in blackjack; bots never split or surrender. It returns
+// ErrNoMove if the game gives the player no move.
+func (g *Game) ChooseAction(playerName string, cfg BotConfig, source entropy.Source) (Action, error) {
+	actions := g.LegalActions(playerName)
+	if len(actions) == 0 {
+		return Action{}, ErrNoMove
+	}
+
+	draw := entropy.NewDraw(source, 16)
+	switch cfg.Difficulty {
+	case BotRandom:
+		return actions[draw.Intn(len(actions))], nil
+	case BotSimulation:
+		return g.simulatedChoice(playerName, actions, source), nil
+	default:
+		return g.heuristicChoice(playerName, actions, cfg, draw), nil
+	}
+}
+
+// LegalActions lists the moves a bot considers for the player, in a fixed order: in Crazy Eights, each card they
+// can play, with each suit it can declare, or else a draw; in Go Fish, each value they hold asked of each opponent;
+// and in blackjack, hitting, standing, and doubling down on their current hand. Only the moves the game's rules
+// allow right now are listed.
+func (g *Game) LegalActions(playerName string) []Action {
+	candidates := []Action{}
+	switch g.Mode {
+	case ModeCrazyEights:
+		seen := map[string]bool{}
+		for _, card := range g.PlayerHands[playerName] {
+			if seen[CardCode(card)] {
+				continue
+			}
+			seen[CardCode(card)] = true
+			if !g.PlaysAnywhere(card) {
+				candidates = append(candidates, Action{Type: ActionPlayCard, Player: playerName, Cards: []Card{card}})
+				continue
+			}
+			for _, suit := range Suits {
+				candidates = append(candidates, Action{Type: ActionPlayCard, Player: playerName, Cards: []Card{card}, DeclaredSuit: suit})
+			}
+		}
+	case ModeGoFish:
+		seen := map[string]bool{}
+		for _, card := range g.PlayerHands[playerName] {
+			value := card.Value.String()
+			if seen[value] {
+				continue
+			}
+			seen[value] = true
+			for _, target := range g.Players {
+				candidates = append(candidates, Action{Type: ActionAsk, Player: playerName, Target: target, Value: value})
+			}
+		}
+	case ModeBlackjack:
+		for _, actionType := range []string{ActionHit, ActionStand, ActionDouble} {
+			candidates = append(candidates, Action{Type: actionType, Player: playerName})
+		}
+	}
+
+	if g.Mode == ModeCrazyEights {
+		candidates = append(candidates, Action{Type: ActionDraw, Player: playerName})
+	}
+
+	// Keep the moves the mode's rules allow
+	rules, err := g.Rules()
+	if err != nil {
+		return nil
+	}
+	legal := []Action{}
+	for _, action := range candidates {
+		if rules.ValidateMove(g, action) == nil {
+			legal = append(legal, action)
+		}
+	}
+	return legal
+}
+
+// heuristicChoice picks a move by the rules of thumb described on BotConfig.
+func (g *Game) heuristicChoice(playerName string, actions []Action, cfg BotConfig, draw *entropy.Draw) Action {
+	aggressive := cfg.Aggression > 0.5
+	switch g.Mode {
+	case ModeCrazyEights:
+		// Play the card the simulated players would, unless an aggressive bot can switch to a better suit
+		card, ok := g.simulatedCrazyEight(playerName)
+		if !ok {
+			return actions[0]
+		}
+		suit := g.simulatedSuit(playerName, card)
+		if aggressive && !g.PlaysAnywhere(card) {
+			for _, action := range actions {
+				wild := action.Cards[0]
+				if !g.PlaysAnywhere(wild) {
+					continue
+				}
+				better := g.simulatedSuit(playerName, wild)
+				if declared, _ := ParseSuit(better); g.suitCount(playerName, declared, wild) > g.suitCount(playerName, g.suitInPlay(), wild) {
+					card, suit = wild, better
+				}
+				break
+			}
+		}
+		for _, action := range actions {
+			if action.Cards[0] == card && (!g.PlaysAnywhere(card) || action.DeclaredSuit == suit) {
+				return action
+			}
+		}
+	case ModeGoFish:
+		// Ask for the value held most of, of the opponent with the most cards or of anyone
+		value, targets := g.simulatedAsk(playerName)
+		if len(targets) == 0 {
+			return actions[0]
+		}
+		target := targets[draw.Intn(len(targets))]
+		if aggressive {
+			target = targets[0]
+			for _, player := range targets {
+				if len(g.PlayerHands[player]) > len(g.PlayerHands[target]) {
+					target = player
+				}
+			}
+		}
+		for _, action := range actions {
+			if action.Value == value && action.Target == target {
+				return action
+			}
+		}
+	case ModeBlackjack:
+		// Double down on 10 or 11 when aggressive, and otherwise hit until the bot's standing total
+		value := g.Hands[playerName][g.CurrentHand(playerName)].Value()
+		standAt := blackjackStandValue + int(math.Round((cfg.Risk-0.5)*6))
+		choice := ActionStand
+		if aggressive && (value == 10 || value == 11) && hasActionType(actions, ActionDouble) {
+			choice = ActionDouble
+		} else if value < standAt && hasActionType(actions, ActionHit) {
+			choice = ActionHit
+		}
+		for _, action := range actions {
+			if action.Type == choice {
+				return action
+			}
+		}
+	}
+	return actions[0]
+}
+
+// suitInPlay returns the suit a Crazy Eights card must follow: the suit declared with the last wild card, or else
+// the suit of the top card.
+func (g *Game) suitInPlay() Suit {
+	if declared, err := ParseSuit(g.DeclaredSuit); err == nil {
+		return declared
+	}
+	top, _ := g.TopDiscard()
+	return top.Suit
+}
+
+// suitCount counts the cards of a suit the player holds, leaving out the card they are about to play.
+func (g *Game) suitCount(playerName string, suit Suit, played Card) int {
+	count := 0
+	for _, card := range g.PlayerHands[playerName] {
+		if card != played && card.Suit == suit {
+			count++
+		}
+	}
+	return count
+}
+
+// hasActionType reports whether any of the actions is of the given type.
+func hasActionType(actions []Action, actionType string) bool {
+	for _, action := range actions {
+		if action.Type == actionType {
+			return true
+		}
+	}
+	return false
+}
+
+// simulatedChoice picks the move that wins most often when played out. Each move is played on a copy of the game
+// as the player sees it, with the cards they cannot see dealt out afresh, and the game then played on by
+// SimulateContinuation; the budget of continuations is shared among the moves. The first move in LegalActions'
+// order wins a tie.
+func (g *Game) simulatedChoice(playerName string, actions []Action, source entropy.Source) Action {
+	if len(actions) == 1 {
+		return actions[0]
+	}
+	runs := botSimulationBudget / len(actions)
+	if runs < minBotSimulationRuns {
+		runs = minBotSimulationRuns
+	}
+
+	best, bestWins := 0, -1.0
+	for i, action := range actions {
+		wins := 0.0
+		for run := 0; run < runs; run++ {
+			sim := g.playerView(playerName, source)
+			sim.applyAction(action)
+			// Blackjack players each play the house, so a win is never shared with another winner
+			winners := sim.SimulateContinuation(source)
+			for _, winner := range winners {
+				if winner == playerName && sim.Mode == ModeBlackjack {
+					wins++
+				} else if winner == playerName {
+					wins += 1 / float64(len(winners))
+				}
+			}
+		}
+		if wins > bestWins {
+			best, bestWins = i, wins
+		}
+	}
+	return actions[best]
+}
+
+// playerView copies the game as the player sees it: the cards they have not seen are shuffled together and dealt
+// back out in the same numbers, since the player cannot tell which of them lie where. Those are the deck, and the
+// other players' hands, or in blackjack, where hands are dealt face up, the house's face-down card.
+func (g *Game) playerView(playerName string, source entropy.Source) *Game {
+	sim := g.simulationCopy()
+
+	unseen := append([]Card{}, sim.GameDeck...)
+	hole := 0
+	if sim.Mode == ModeBlackjack {
+		if sim.HandPhase == HandPhasePlay && len(sim.DealerHand) > 1 {
+			hole = len(sim.DealerHand) - 1
+			unseen = append(unseen, sim.DealerHand[1:]...)
+		}
+	} else {
+		for _, player := range sim.Players {
+			if player != playerName {
+				unseen = append(unseen, sim.PlayerHands[player]...)
+			}
+		}
+	}
+	shuffleCards(unseen, entropy.NewDraw(source, 4*(len(unseen)+1)))
+
+	sim.GameDeck = append([]Card{}, unseen[:len(sim.GameDeck)]...)
+	unseen = unseen[len(sim.GameDeck):]
+	if hole > 0 {
+		sim.DealerHand = append(sim.DealerHand[:1], unseen[:hole]...)
+		return sim
+	}
+	for _, player := range sim.Players {
+		if player == playerName {
+			continue
+		}
+		count := len(sim.PlayerHands[player])
+		sim.PlayerHands[player] = append([]Card{}, unseen[:count]...)
+		unseen = unseen[count:]
+	}
+	return sim
+}
+
+// applyAction makes a move LegalActions listed on a simulation copy of the game.
+func (g *Game) applyAction(a Action) {
+	switch a.Type {
+	case ActionPlayCard:
+		g.PlayCrazyEight(a.Player, a.Cards[0], a.DeclaredSuit)
+	case ActionDraw:
+		g.ReshuffleDiscards(1)
+		g.DrawFromDeck(a.Player, 1)
+	case ActionAsk:
+		g.Ask(a.Player, a.Target, a.Value)
+	case ActionHit:
+		g.Hit(a.Player)
+	case ActionStand:
+		g.Stand(a.Player)
+	case ActionDouble:
+		g.DoubleDown(a.Player)
+	}
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"testing"
+
+	"my-card-game/internal/entropy"
+)
+
+// fuzzModes are the modes FuzzGameActions plays, the ones LegalActions lists moves for.
+var fuzzModes = []string{ModeCrazyEights, ModeGoFish, ModeBlackjack}
+
+func FuzzGameActions(f *testing.F) {
+	f.Add(uint8(0), false, []byte{0, 1, 2, 3, 4, 5, 6, 7})      // Crazy Eights with a single deck
+	f.Add(uint8(0), true, []byte{255, 255, 255, 255, 255, 255}) // Crazy Eights drawing with two decks
+	f.Add(uint8(1), false, []byte{0, 3, 9, 27, 81, 243})        // Go Fish
+	f.Add(uint8(2), false, []byte{0, 0, 1, 1, 2, 2, 0, 1})      // Blackjack, hitting, standing, and doubling down
+	f.Add(uint8(2), true, []byte{2, 2, 2, 2, 2, 2, 2, 2, 2})    // Blackjack played over several hands
+
+	f.Fuzz(func(t *testing.T, mode uint8, twoDecks bool, moves []byte) {
+		g := &Game{
+			Mode:         fuzzModes[int(mode)%len(fuzzModes)],
+			Status:       StatusLobby,
+			Players:      []string{"alice", "bob", "carol"},
+			Chips:        map[string]int{"alice": 100, "bob": 100, "carol": 100},
+			CardManifest: map[string]int{},
+		}
+		g.Settings.ReshuffleDiscards = true
+		if twoDecks {
+			g.Settings.DeckCount = 2
+		}
+		g.UseEntropy(entropy.Seeded(int64(len(moves))))
+		placeFuzzBets(g)
+		if err := g.SetUpTable(); err != nil {
+			t.Fatalf("SetUpTable: %v", err)
+		}
+		decks, cards := g.Settings.DeckCount, len(g.NewShoe().Cards)
+		if decks == 0 {
+			decks = 1
+		}
+		checkFuzzInvariants(t, g, decks, cards)
+
+		// Each byte picks one of the moves the game allows the player whose turn it is
+		for step, b := range moves {
+			if g.Status != StatusActive || g.Turn == nil {
+				return
+			}
+			if g.HandPhase == HandPhaseShowdown {
+				// A blackjack hand that has reached its showdown is settled before the next one is dealt
+				g.SettleBets()
+				g.HandPhase = HandPhaseScored
+				placeFuzzBets(g)
+				if err := g.DealHand(); err != nil {
+					t.Fatalf("step %d: DealHand: %v", step, err)
+				}
+			} else {
+				player := g.Turn.Player()
+				actions := g.LegalActions(player)
+				if len(actions) == 0 {
+					return
+				}
+				action := actions[int(b)%len(actions)]
+				if err := g.CheckAction(action); err != nil {
+					t.Fatalf("step %d: %s listed %+v as legal, but the rules refuse it: %v", step, player, action, err)
+				}
+				g.applyAction(action)
+			}
+			checkFuzzInvariants(t, g, decks, cards)
+		}
+	})
+}
+
+// placeFuzzBets has every player with the chips for it bet 10 on the next hand of blackjack.
+func placeFuzzBets(g *Game) {
+	if g.Mode != ModeBlackjack {
+		return
+	}
+	for _, player := range g.Players {
+		if g.Chips[player] >= 10 {
+			g.commitChips(player, 10)
+		}
+	}
+}
+
+// checkFuzzInvariants fails the test if the game has gained or lost cards since it was dealt, holds more copies
+// of a card than the decks it was dealt from, or has a negative count anywhere, and otherwise if it breaks the
+// invariants CheckIntegrity checks.
+func checkFuzzInvariants(t *testing.T, g *Game, decks, cards int) {
+	t.Helper()
+
+	located := g.locatedCards()
+	total := 0
+	for code, count := range located {
+		if count > decks {
+			t.Fatalf("%d copies of %s are in play with %d decks", count, code, decks)
+		}
+		total += count
+	}
+	for _, books := range g.Books {
+		total += len(books) * goFishBookSize
+	}
+	if total != cards {
+		t.Fatalf("%d cards are in play, want %d", total, cards)
+	}
+
+	for player, chips := range g.Chips {
+		if chips < 0 {
+			t.Fatalf("%s has %d chips", player, chips)
+		}
+	}
+	for player, bet := range g.Bets {
+		if bet < 0 {
+			t.Fatalf("%s has a bet of %d", player, bet)
+		}
+	}
+	for player, hands := range g.Hands {
+		for _, hand := range hands {
+			if hand.Bet < 0 {
+				t.Fatalf("%s has a hand with a bet of %d", player, hand.Bet)
+			}
+		}
+	}
+
+	if err := g.CheckIntegrity(); err != nil {
+		t.Fatal(err)
+	}
+}
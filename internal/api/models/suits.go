@@ -0,0 +1,84 @@
+package models
+
+import "errors"
+
+// StandardSuitOrder is the usual ranking of suits for games that break ties by suit, from highest to lowest.
+var StandardSuitOrder = []string{"Spades", "Hearts", "Diamonds", "Clubs"}
+
+// ValidateSuitOrder checks that a suit order names each of the four suits exactly once. An empty order is valid
+// and means suits do not rank.
+func ValidateSuitOrder(order []string) error {
+	if len(order) == 0 {
+		return nil
+	}
+	if len(order) != len(Suits) {
+		return errors.New("suit_order must list all four suits")
+	}
+	seen := map[string]bool{}
+	for _, suit := range order {
+		if !IsValidSuit(suit) {
+			return errors.New("invalid suit in suit_order: " + suit)
+		}
+		if seen[suit] {
+			return errors.New("suit_order lists " + suit + " more than once")
+		}
+		seen[suit] = true
+	}
+	return nil
+}
+
+// RanksSuits reports whether the game ranks suits to break ties between cards of the same value.
+func (g *Game) RanksSuits() bool {
+	return len(g.Settings.SuitOrder) > 0
+}
+
+// SuitRank returns where a suit ranks in the game's suit order, from 1 for the lowest suit up to 4 for the highest.
+// It returns 0 for jokers, and for every suit when the game does not rank suits.
+func (g *Game) SuitRank(suit Suit) int {
+	for i, s := range g.Settings.SuitOrder {
+		if s == suit.String() {
+			return len(g.Settings.SuitOrder) - i
+		}
+	}
+	return 0
+}
+
+// CompareCards compares two cards by value, ranking aces by the game's ace mode, and then by suit if the game ranks
+// suits. It returns a positive number if a ranks higher, a negative number if b does, and 0 if they tie.
+func (g *Game) CompareCards(a, b Card) int {
+	acesHigh := g.AceRanksHigh()
+	if diff := ValueRank(a.Value, acesHigh) - ValueRank(b.Value, acesHigh); diff != 0 {
+		return diff
+	}
+	return g.SuitRank(a.Suit) - g.SuitRank(b.Suit)
+}
+
+// HighCard returns the highest card in a hand by CompareCards, or false if the hand is empty.
+func (g *Game) HighCard(hand []Card) (Card, bool) {
+	if len(hand) == 0 {
+		return Card{}, false
+	}
+	high := hand[0]
+	for _, card := range hand[1:] {
+		if g.CompareCards(card, high) > 0 {
+			high = card
+		}
+	}
+	return high, true
+}
+
+// rankWithSuits folds a hand's high card into its showdown rank when the game ranks suits, so that hands of equal
+// rank are won by the one holding the highest card, by value and then suit. Games that do not rank suits keep the
+// rank as it is and split ties.
+func (g *Game) rankWithSuits(rank int, hand []Card) int {
+	if !g.RanksSuits() {
+		return rank
+	}
+
+	// The high card's position among all 14 values and 4 suits stays below 100, so it never outweighs the rank
+	tieBreak := 0
+	if high, ok := g.HighCard(hand); ok {
+		tieBreak = ValueRank(high.Value, g.AceRanksHigh())*len(Suits) + g.SuitRank(high.Suit)
+	}
+	return rank*100 + tieBreak
+}
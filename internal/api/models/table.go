@@ -0,0 +1,276 @@
+package models
+
+import "errors"
+
+// Errors returned when a player cannot take or reserve the seat they asked for.
+var (
+	ErrNoSuchSeat   = errors.New("no such seat at the table")
+	ErrSeatTaken    = errors.New("the seat is taken")
+	ErrSeatReserved = errors.New("the seat is reserved for another player")
+	ErrTableFull    = errors.New("the table has no open seats")
+)
+
+// Seat is one position at a game's table, as shown to clients that draw the table.
+type Seat struct {
+	Number      int    `json:"number"`                 // Position at the table, numbered clockwise from 1
+	Player      string `json:"player,omitempty"`       // Player sitting in the seat, if it is taken
+	ReservedFor string `json:"reserved_for,omitempty"` // Player the seat is held for, if it is reserved
+	Button      bool   `json:"button,omitempty"`       // The seat holds the dealer button in a running game
+	Blind       string `json:"blind,omitempty"`        // Blind the seat posts in the current hand: small or big
+}
+
+// SeatReservation holds a seat for a player who has not sat down yet. Nobody else can take the seat until the
+// reservation is released, and the player is given the seat when they join.
+type SeatReservation struct {
+	Seat   int    `bson:"seat" json:"seat"`
+	Player string `bson:"player" json:"player"`
+}
+
+// TableSize returns the number of seats at the game's table: the table_seats setting, or max_players when it
+// is not set. It returns 0 for a table without a fixed size, which gains a seat for every player who joins.
+func (g *Game) TableSize() int {
+	if g.Settings.TableSeats > 0 {
+		return g.Settings.TableSeats
+	}
+	return g.Settings.MaxPlayers
+}
+
+// PlayerSeats returns the seat number of every seated player. Players seated before seats were numbered, or
+// without a recorded seat, are given the lowest free seats in the order they sit.
+func (g *Game) PlayerSeats() map[string]int {
+	seats := map[string]int{}
+	taken := map[int]bool{}
+	for _, player := range g.Players {
+		if number, ok := g.SeatNumbers[player]; ok && number > 0 && !taken[number] {
+			seats[player] = number
+			taken[number] = true
+		}
+	}
+	for _, reservation := range g.Reservations {
+		taken[reservation.Seat] = true
+	}
+
+	next := 1
+	for _, player := range g.Players {
+		if _, ok := seats[player]; ok {
+			continue
+		}
+		for taken[next] {
+			next++
+		}
+		seats[player] = next
+		taken[next] = true
+	}
+	return seats
+}
+
+// ReservedSeat returns the seat held for the player, or 0 if none is.
+func (g *Game) ReservedSeat(playerName string) int {
+	for _, reservation := range g.Reservations {
+		if reservation.Player == playerName {
+			return reservation.Seat
+		}
+	}
+	return 0
+}
+
+// HasOpenSeat reports whether the player could sit down: either a seat is held for them, or the table has a
+// seat that is neither taken nor reserved.
+func (g *Game) HasOpenSeat(playerName string) bool {
+	if g.ReservedSeat(playerName) > 0 {
+		return true
+	}
+	return g.openSeat() > 0
+}
+
+// SitDown seats a player at the given seat number, or at the seat reserved for them, or else the lowest open seat
+// when seat is 0. The players are kept in seat order, which is the order turns and blinds go around the table, so
+// the player joins the turn order of a running game between their neighbours. The dealer button stays with the
+// player holding it.
+func (g *Game) SitDown(playerName string, seat int) error {
+	if seat == 0 {
+		seat = g.ReservedSeat(playerName)
+	}
+	if seat == 0 {
+		seat = g.openSeat()
+		if seat == 0 {
+			return ErrTableFull
+		}
+	}
+	if err := g.checkSeat(playerName, seat); err != nil {
+		return err
+	}
+
+	// Number every seat so the new player's position is fixed relative to the others, and use up their reservation
+	seats := g.PlayerSeats()
+	seats[playerName] = seat
+	g.SeatNumbers = seats
+	g.releaseReservations(playerName)
+
+	// Sit the player between their neighbours, keeping the button on the same player
+	index := 0
+	for index < len(g.Players) && seats[g.Players[index]] < seat {
+		index++
+	}
+	if len(g.Players) > 0 && index <= g.DealerIndex {
+		g.DealerIndex++
+	}
+	g.Players = append(g.Players[:index], append([]string{playerName}, g.Players[index:]...)...)
+
+	// Players in a running game take their turns in seat order too
+	if g.Turn != nil {
+		at := 0
+		for at < len(g.Turn.Queue) && seats[g.Turn.Queue[at]] < seat {
+			at++
+		}
+		g.Turn.InsertAt(playerName, at)
+	}
+	return nil
+}
+
+// MoveSeat moves a seated player to another seat, which must be open or reserved for them.
+func (g *Game) MoveSeat(playerName string, seat int) error {
+	if !g.isSeated(playerName) {
+		return errors.New("player not found in the game")
+	}
+	if g.PlayerSeats()[playerName] == seat {
+		return nil
+	}
+	if err := g.checkSeat(playerName, seat); err != nil {
+		return err
+	}
+
+	// Stand the player up and sit them down again, keeping the button with the player holding it
+	dealer := g.Dealer()
+	g.Unseat(playerName)
+	if g.Turn != nil {
+		g.Turn.Remove(playerName)
+	}
+	if err := g.SitDown(playerName, seat); err != nil {
+		return err
+	}
+	for i, player := range g.Players {
+		if player == dealer {
+			g.DealerIndex = i
+		}
+	}
+	return nil
+}
+
+// ReserveSeat holds a seat for a player who is not seated yet, replacing any seat already held for them.
+func (g *Game) ReserveSeat(seat int, playerName string) error {
+	if g.isSeated(playerName) {
+		return errors.New("player already in the game")
+	}
+	if err := g.checkSeat(playerName, seat); err != nil {
+		return err
+	}
+	g.releaseReservations(playerName)
+	g.Reservations = append(g.Reservations, SeatReservation{Seat: seat, Player: playerName})
+	return nil
+}
+
+// ReleaseSeat drops the reservation on a seat, returning the player it was held for, or an empty string if the
+// seat was not reserved.
+func (g *Game) ReleaseSeat(seat int) string {
+	for i, reservation := range g.Reservations {
+		if reservation.Seat == seat {
+			g.Reservations = append(g.Reservations[:i], g.Reservations[i+1:]...)
+			return reservation.Player
+		}
+	}
+	return ""
+}
+
+// SeatingChart lays out the game's table for display: every seat in order with the player sitting in it or the
+// player it is held for, and, in a running game, the dealer button and the blinds.
+func (g *Game) SeatingChart() []Seat {
+	seats := g.PlayerSeats()
+	size := g.TableSize()
+	for _, number := range seats {
+		if number > size {
+			size = number
+		}
+	}
+	for _, reservation := range g.Reservations {
+		if reservation.Seat > size {
+			size = reservation.Seat
+		}
+	}
+
+	chart := make([]Seat, size)
+	for i := range chart {
+		chart[i].Number = i + 1
+	}
+	for player, number := range seats {
+		chart[number-1].Player = player
+	}
+	for _, reservation := range g.Reservations {
+		chart[reservation.Seat-1].ReservedFor = reservation.Player
+	}
+
+	// Mark the button and, if the game plays with blinds, the seats posting them
+	if g.Status == StatusActive && len(g.Players) > 0 {
+		if dealer := g.Dealer(); dealer != "" {
+			chart[seats[dealer]-1].Button = true
+		}
+		if _, big := g.CurrentBlinds(); big > 0 && len(g.Players) > 1 {
+			small, big := g.BlindSeats()
+			chart[seats[g.Players[small]]-1].Blind = "small"
+			chart[seats[g.Players[big]]-1].Blind = "big"
+		}
+	}
+	return chart
+}
+
+// checkSeat reports why the player cannot have the seat: it is not at the table, someone is sitting in it, or it
+// is held for someone else.
+func (g *Game) checkSeat(playerName string, seat int) error {
+	if seat < 1 || (g.TableSize() > 0 && seat > g.TableSize()) {
+		return ErrNoSuchSeat
+	}
+	for player, number := range g.PlayerSeats() {
+		if number == seat && player != playerName {
+			return ErrSeatTaken
+		}
+	}
+	for _, reservation := range g.Reservations {
+		if reservation.Seat == seat && reservation.Player != playerName {
+			return ErrSeatReserved
+		}
+	}
+	return nil
+}
+
+// openSeat returns the lowest seat that is neither taken nor reserved, or 0 if the table is full.
+func (g *Game) openSeat() int {
+	taken := map[int]bool{}
+	for _, number := range g.PlayerSeats() {
+		taken[number] = true
+	}
+	for _, reservation := range g.Reservations {
+		taken[reservation.Seat] = true
+	}
+	seat := 1
+	for taken[seat] {
+		seat++
+	}
+	if size := g.TableSize(); size > 0 && seat > size {
+		return 0
+	}
+	return seat
+}
+
+// releaseReservations drops every seat held for the player.
+func (g *Game) releaseReservations(playerName string) {
+	reservations := []SeatReservation{}
+	for _, reservation := range g.Reservations {
+		if reservation.Player != playerName {
+			reservations = append(reservations, reservation)
+		}
+	}
+	g.Reservations = reservations
+	if len(reservations) == 0 {
+		g.Reservations = nil
+	}
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// TeamOf returns the team the player plays for, or an empty string if the game has no teams or the player
+// is not on one.
+func (g *Game) TeamOf(playerName string) string {
+	for team, members := range g.Teams {
+		for _, member := range members {
+			if member == playerName {
+				return team
+			}
+		}
+	}
+	return ""
+}
+
+// sameTeam reports whether two different players are partners on the same team.
+func (g *Game) sameTeam(a, b string) bool {
+	team := g.TeamOf(a)
+	return a != b && team != "" && team == g.TeamOf(b)
+}
+
+// CheckTeams checks that a game's teams can be played: at least two teams of the same size, made up of
+// exactly the players seated in the game. Games without teams always pass.
+func (g *Game) CheckTeams() error {
+	if len(g.Teams) == 0 {
+		return nil
+	}
+	if len(g.Teams) < 2 {
+		return errors.New("a team game needs at least two teams")
+	}
+
+	size := -1
+	seen := map[string]string{}
+	for _, team := range g.TeamNames() {
+		members := g.Teams[team]
+		if len(members) == 0 {
+			return fmt.Errorf("team %s has no players", team)
+		}
+		if size != -1 && len(members) != size {
+			return errors.New("teams must all have the same number of players")
+		}
+		size = len(members)
+
+		for _, member := range members {
+			if other, ok := seen[member]; ok {
+				return fmt.Errorf("player %s is on both team %s and team %s", member, other, team)
+			}
+			seen[member] = team
+			if !g.isSeated(member) {
+				return fmt.Errorf("player %s on team %s is not seated in the game", member, team)
+			}
+		}
+	}
+
+	for _, player := range g.Players {
+		if _, ok := seen[player]; !ok {
+			return fmt.Errorf("player %s is not on a team", player)
+		}
+	}
+	return nil
+}
+
+// TeamNames returns the names of the game's teams in alphabetical order.
+func (g *Game) TeamNames() []string {
+	names := make([]string, 0, len(g.Teams))
+	for team := range g.Teams {
+		names = append(names, team)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TeamScores returns each team's score: the sum of its players' points as counted by FinalPoints.
+func (g *Game) TeamScores() map[string]int {
+	points := g.FinalPoints()
+	scores := map[string]int{}
+	for team, members := range g.Teams {
+		scores[team] = 0
+		for _, member := range members {
+			scores[team] += points[member]
+		}
+	}
+	return scores
+}
+
+// DecideWinningTeam sets the winning team of a finished team game. In Go Fish the team with the most books
+// between its players wins, and the player with the most books on that team is named the winner; in other
+// modes the winner's team wins. Games without teams, or without a winner, have no winning team.
+func (g *Game) DecideWinningTeam() {
+	g.WinningTeam = ""
+	if len(g.Teams) == 0 {
+		return
+	}
+
+	if g.Mode == ModeGoFish {
+		scores := g.TeamScores()
+		for _, team := range g.TeamNames() {
+			if g.WinningTeam == "" || scores[team] > scores[g.WinningTeam] {
+				g.WinningTeam = team
+			}
+		}
+
+		g.Winner = ""
+		for _, member := range g.Teams[g.WinningTeam] {
+			if g.Winner == "" || len(g.Books[member]) > len(g.Books[g.Winner]) {
+				g.Winner = member
+			}
+		}
+		return
+	}
+
+	if g.Winner != "" {
+		g.WinningTeam = g.TeamOf(g.Winner)
+	}
+}
+
+// Won reports whether the player won the game, either outright or as a partner on the winning team.
+func (g *Game) Won(playerName string) bool {
+	if g.WinningTeam != "" {
+		return g.TeamOf(playerName) == g.WinningTeam
+	}
+	return g.Winner != "" && playerName == g.Winner
+}
+
+// RemoveFromTeams takes a player who has left the game off their team.
+func (g *Game) RemoveFromTeams(playerName string) {
+	team := g.TeamOf(playerName)
+	if team == "" {
+		return
+	}
+	members := []string{}
+	for _, member := range g.Teams[team] {
+		if member != playerName {
+			members = append(members, member)
+		}
+	}
+	g.Teams[team] = members
+}
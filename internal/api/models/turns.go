@@ -0,0 +1,165 @@
+package models
+
+// Directions the turn order can move in around the table.
+const (
+	DirectionClockwise        = "clockwise"
+	DirectionCounterclockwise = "counterclockwise"
+)
+
+// TurnOrder is the queue of players taking turns in a game. Queue holds the players in clockwise seat order and
+// Current is the index of the player whose turn it is. Turns move to the next player in Direction, passing over
+// any players skipped by PendingSkips.
+type TurnOrder struct {
+	Queue        []string `bson:"queue" json:"queue"`
+	Current      int      `bson:"current" json:"current"`
+	Direction    string   `bson:"direction" json:"direction"`
+	PendingSkips int      `bson:"pending_skips" json:"pending_skips"` // Players the next turn change passes over
+}
+
+// NewTurnOrder creates a clockwise turn order over the players, starting with the player at index first.
+func NewTurnOrder(players []string, first int) *TurnOrder {
+	if first < 0 || first >= len(players) {
+		first = 0
+	}
+	return &TurnOrder{
+		Queue:     append([]string{}, players...),
+		Current:   first,
+		Direction: DirectionClockwise,
+	}
+}
+
+// Player returns the player whose turn it is, or an empty string if the queue is empty.
+func (t *TurnOrder) Player() string {
+	if len(t.Queue) == 0 {
+		return ""
+	}
+	return t.Queue[t.Current]
+}
+
+// Next returns the players who will take the next n turns, in order, after the current player,
+// taking the direction and any pending skips into account.
+func (t *TurnOrder) Next(n int) []string {
+	upcoming := []string{}
+	if len(t.Queue) == 0 {
+		return upcoming
+	}
+
+	// Play the turns out on a copy so the order itself is untouched
+	preview := *t
+	for i := 0; i < n; i++ {
+		preview.Advance()
+		upcoming = append(upcoming, preview.Player())
+	}
+	return upcoming
+}
+
+// Advance ends the current turn and moves to the next player in the direction of play,
+// passing over as many players as there are pending skips.
+func (t *TurnOrder) Advance() {
+	if len(t.Queue) == 0 {
+		return
+	}
+	t.Current = t.step(t.Current, 1+t.PendingSkips)
+	t.PendingSkips = 0
+}
+
+// Skip makes the next turn change pass over one more player.
+func (t *TurnOrder) Skip() {
+	t.PendingSkips++
+}
+
+// Reverse flips the direction of play. The current player keeps their turn.
+func (t *TurnOrder) Reverse() {
+	if t.Direction == DirectionCounterclockwise {
+		t.Direction = DirectionClockwise
+	} else {
+		t.Direction = DirectionCounterclockwise
+	}
+}
+
+// Insert adds a player who joins mid-game. They take the seat just behind the current player in the direction
+// of play, so they take their first turn once everyone already playing has had theirs.
+func (t *TurnOrder) Insert(playerName string) {
+	if t.contains(playerName) {
+		return
+	}
+	if len(t.Queue) == 0 {
+		t.Queue = []string{playerName}
+		t.Current = 0
+		return
+	}
+
+	// Clockwise, the seat behind the current player is just before them in the queue; counterclockwise, just after
+	at := t.Current
+	if t.Direction == DirectionCounterclockwise {
+		at = t.Current + 1
+	}
+	t.InsertAt(playerName, at)
+}
+
+// InsertAt adds a player who joins mid-game at the given position in the queue, as when they sit down in a
+// particular seat. The current player keeps their turn.
+func (t *TurnOrder) InsertAt(playerName string, at int) {
+	if t.contains(playerName) {
+		return
+	}
+	if at < 0 || at > len(t.Queue) {
+		at = len(t.Queue)
+	}
+	t.Queue = append(t.Queue[:at], append([]string{playerName}, t.Queue[at:]...)...)
+	if len(t.Queue) > 1 && at <= t.Current {
+		t.Current++
+	}
+}
+
+// Remove takes a player who leaves the game out of the queue. If it was their turn, the turn passes to the next
+// player in the direction of play.
+func (t *TurnOrder) Remove(playerName string) {
+	index := -1
+	for i, player := range t.Queue {
+		if player == playerName {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return
+	}
+
+	wasCurrent := index == t.Current
+	t.Queue = append(t.Queue[:index], t.Queue[index+1:]...)
+	if len(t.Queue) == 0 {
+		t.Current = 0
+		return
+	}
+
+	switch {
+	case index < t.Current:
+		t.Current--
+	case wasCurrent && t.Direction == DirectionCounterclockwise:
+		// The next player counterclockwise sits just before the removed one
+		t.Current = (index - 1 + len(t.Queue)) % len(t.Queue)
+	case wasCurrent:
+		// The next player clockwise has moved into the removed player's index
+		t.Current = index % len(t.Queue)
+	}
+}
+
+// step returns the index n seats away from index in the direction of play.
+func (t *TurnOrder) step(index, n int) int {
+	size := len(t.Queue)
+	if t.Direction == DirectionCounterclockwise {
+		n = -n
+	}
+	return ((index+n)%size + size) % size
+}
+
+// contains reports whether the player is in the queue.
+func (t *TurnOrder) contains(playerName string) bool {
+	for _, player := range t.Queue {
+		if player == playerName {
+			return true
+		}
+	}
+	return false
+}
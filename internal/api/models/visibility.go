@@ -0,0 +1,58 @@
+package models
+
+// Viewer identifies who is looking at a game, so private information such as hands can be hidden.
+// An empty PlayerName is an anonymous viewer.
+type Viewer struct {
+	PlayerName string
+	Admin      bool
+}
+
+// CanViewHand reports whether the viewer may see the given player's hand under the game's visibility rule.
+// By default players may see their own hand, and admins may see every hand. The game's owner may see every hand
+// while they only deal; an owner who is also seated as a player only sees their own.
+func (g *Game) CanViewHand(viewer Viewer, playerName string) bool {
+	switch {
+	case g.Settings.Visibility == VisibilityOpen || viewer.Admin:
+		return true
+	case viewer.PlayerName == "":
+		return false
+	case g.Settings.Visibility == VisibilityOwnOnly:
+		return viewer.PlayerName == playerName
+	default:
+		return viewer.PlayerName == playerName || (viewer.PlayerName == g.Owner && !g.isSeated(g.Owner))
+	}
+}
+
+// RedactFor removes every hand the viewer is not allowed to see from the game, the house's face-down card
+// in blackjack until the house plays, and, for everyone but admins, the undealt deck, whose order would tell
+// which cards come next; DeckSize counts the undealt cards instead. It is applied to full-game responses before
+// they are sent to the client.
+// The hands are replaced with new maps rather than deleted from, so a shallow copy of a game shared with other
+// viewers, such as a published update, can be redacted without changing what the others see.
+func (g *Game) RedactFor(viewer Viewer) {
+	playerHands := make(map[string][]Card, len(g.PlayerHands))
+	for player, hand := range g.PlayerHands {
+		if g.CanViewHand(viewer, player) {
+			playerHands[player] = hand
+		}
+	}
+	if g.PlayerHands != nil {
+		g.PlayerHands = playerHands
+	}
+	if g.Hands != nil {
+		hands := make(map[string][]PlayerHand, len(g.Hands))
+		for player, split := range g.Hands {
+			if g.CanViewHand(viewer, player) {
+				hands[player] = split
+			}
+		}
+		g.Hands = hands
+	}
+	g.DeckSize = g.CardsLeft()
+	if !viewer.Admin {
+		g.GameDeck = nil
+	}
+	if g.HandPhase == HandPhasePlay && len(g.DealerHand) > 1 && !viewer.Admin {
+		g.DealerHand = g.DealerHand[:1]
+	}
+}
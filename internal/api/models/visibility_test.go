@@ -0,0 +1,89 @@
+package models
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestHandViewFieldsLimitSeatedOwnerToOwnHand(t *testing.T) {
+	tests := []struct {
+		name    string
+		players []string
+		want    []string
+	}{
+		{name: "seated owner", players: []string{"alice", "bob", "carol"}, want: []string{"alice"}},
+		{name: "dealing owner", players: []string{"bob", "carol"}, want: []string{"bob", "carol"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Load the game the way the hand reads do: the hands and the fields the visibility check needs
+			g := projectGame(t, newHandsGame(tt.players), append([]string{"player_hands"}, HandViewFields...))
+
+			visible := []string{}
+			for player := range g.PlayerHands {
+				if g.CanViewHand(Viewer{PlayerName: "alice"}, player) {
+					visible = append(visible, player)
+				}
+			}
+			sort.Strings(visible)
+			if !reflect.DeepEqual(visible, tt.want) {
+				t.Errorf("owner can view the hands of %v, want %v", visible, tt.want)
+			}
+		})
+	}
+}
+
+func TestGameProjectionRedactsForSeatedOwner(t *testing.T) {
+	fields, err := GameProjection([]string{"player_hands"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := projectGame(t, newHandsGame([]string{"alice", "bob", "carol"}), fields)
+
+	g.RedactFor(Viewer{PlayerName: "alice"})
+	if len(g.PlayerHands) != 1 || g.PlayerHands["alice"] == nil {
+		t.Errorf("seated owner was sent the hands %v, want only their own", g.PlayerHands)
+	}
+}
+
+// newHandsGame returns a game owned by alice with the given players seated, each holding one card.
+func newHandsGame(players []string) *Game {
+	g := &Game{Owner: "alice", Status: StatusActive, Players: players, PlayerHands: map[string][]Card{}}
+	deck := NewDeck().Cards
+	for i, player := range players {
+		g.PlayerHands[player] = []Card{deck[i]}
+	}
+	return g
+}
+
+// projectGame stores the game and loads back only the given fields, as a projected read from the database does.
+func projectGame(t *testing.T, g *Game, fields []string) *Game {
+	t.Helper()
+	data, err := bson.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := bson.M{}
+	if err := bson.Unmarshal(data, &stored); err != nil {
+		t.Fatal(err)
+	}
+	projected := bson.M{}
+	for _, field := range fields {
+		if value, ok := stored[field]; ok {
+			projected[field] = value
+		}
+	}
+	data, err = bson.Marshal(projected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := &Game{}
+	if err := bson.Unmarshal(data, loaded); err != nil {
+		t.Fatal(err)
+	}
+	return loaded
+}
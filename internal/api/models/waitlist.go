@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// WaitlistEntry is a player waiting for a seat at a full game.
+type WaitlistEntry struct {
+	Player   string    `bson:"player" json:"player"`
+	JoinedAt time.Time `bson:"joined_at" json:"joined_at"`
+}
+
+// IsFull reports whether the player could not sit down in the game now: it already has its maximum number of
+// players, or no seat is open or held for them.
+func (g *Game) IsFull(playerName string) bool {
+	if g.Settings.MaxPlayers > 0 && len(g.Players) >= g.Settings.MaxPlayers {
+		return true
+	}
+	return !g.HasOpenSeat(playerName)
+}
+
+// WaitlistPosition returns the player's place in the game's waitlist, counting from 1, or 0 if they are not waiting.
+func (g *Game) WaitlistPosition(playerName string) int {
+	for i, entry := range g.Waitlist {
+		if entry.Player == playerName {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// SeatWaitlist sits waiting players down, in the order they joined the waitlist, for as long as the game has room
+// for the next of them, and returns the players seated. Players who were seated or banned since they joined the
+// waitlist lose their place. Finished games seat nobody.
+func (g *Game) SeatWaitlist() []string {
+	seated := []string{}
+	if g.Status == StatusFinished {
+		return seated
+	}
+	for len(g.Waitlist) > 0 {
+		next := g.Waitlist[0].Player
+		if g.isSeated(next) || g.isBanned(next) {
+			g.Waitlist = g.Waitlist[1:]
+			continue
+		}
+		if g.IsFull(next) || g.SitDown(next, 0) != nil {
+			break
+		}
+		g.Waitlist = g.Waitlist[1:]
+		seated = append(seated, next)
+	}
+	return seated
+}
+
+// isBanned reports whether the player is banned from the game.
+func (g *Game) isBanned(playerName string) bool {
+	for _, player := range g.Banned {
+		if player == playerName {
+			return true
+		}
+	}
+	return false
+}
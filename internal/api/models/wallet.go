@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kinds of wallet transaction.
+const (
+	WalletCredit  = "credit"   // Chips granted to the wallet by an admin
+	WalletBuyIn   = "buy_in"   // Chips moved from the wallet onto a game's table
+	WalletCashOut = "cash_out" // Chips moved from a game's table back into the wallet
+)
+
+// Wallet holds a player's balance of virtual chips outside any game. Players buy into wagered games from their
+// wallet and cash their stack back out into it. A balance never goes below zero.
+type Wallet struct {
+	PlayerName string    `bson:"_id" json:"player_name"`
+	Balance    int       `bson:"balance" json:"balance"`
+	UpdatedAt  time.Time `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+// WalletTransaction records one change to a player's wallet balance. Amount is positive for chips paid into the
+// wallet and negative for chips taken out of it.
+type WalletTransaction struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	PlayerName   string              `bson:"player_name" json:"player_name"`
+	Kind         string              `bson:"kind" json:"kind"`
+	Amount       int                 `bson:"amount" json:"amount"`
+	BalanceAfter int                 `bson:"balance_after" json:"balance_after"`
+	GameID       *primitive.ObjectID `bson:"game_id,omitempty" json:"game_id,omitempty"` // Game bought into or cashed out of
+	Note         string              `bson:"note,omitempty" json:"note,omitempty"`
+	CreatedAt    time.Time           `bson:"created_at" json:"created_at"`
+}
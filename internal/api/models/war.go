@@ -0,0 +1,125 @@
+package models
+
+import "errors"
+
+// warFaceDown is the number of cards each player puts face down when a battle ends in a war.
+const warFaceDown = 3
+
+// BattleResult describes the outcome of a single War battle, including any wars fought to break ties.
+// Flips lists the face-up card each player turned over in every round of the battle.
+type BattleResult struct {
+	Flips      []map[string]Card `json:"flips"`
+	Wars       int               `json:"wars"`
+	Winner     string            `json:"winner"`
+	CardsWon   int               `json:"cards_won"`
+	GameOver   bool              `json:"game_over"`
+	GameWinner string            `json:"game_winner,omitempty"`
+}
+
+// DealWarPiles splits the game deck between the two players as face-down piles.
+// Cards are dealt alternately, starting with the first player; the top of each pile is index 0.
+func (g *Game) DealWarPiles() error {
+	if len(g.Players) != 2 {
+		return errors.New("war requires exactly two players")
+	}
+	if len(g.GameDeck) == 0 {
+		return errors.New("no cards in the game deck")
+	}
+
+	g.PlayerHands = map[string][]Card{g.Players[0]: {}, g.Players[1]: {}}
+	for i, card := range g.GameDeck {
+		player := g.Players[i%2]
+		g.PlayerHands[player] = append(g.PlayerHands[player], card)
+	}
+	g.GameDeck = []Card{}
+	return nil
+}
+
+// ResolveBattle plays one battle of War.
+// Both players flip their top card and the higher rank (aces high) takes every card in play,
+// which goes to the bottom of the winner's pile. Equal ranks go to the higher suit if the game
+// ranks suits, and otherwise start a war: each player puts three cards face down and flips
+// another, repeating until the tie is broken. A player who runs out of cards during a war loses
+// the battle. When one player ends up holding every card the game is marked as finished.
+func (g *Game) ResolveBattle() (*BattleResult, error) {
+	if g.Mode != ModeWar {
+		return nil, errors.New("battles can only be fought in war games")
+	}
+	if g.Status != StatusActive {
+		return nil, errors.New("game is not active")
+	}
+
+	first, second := g.Players[0], g.Players[1]
+	result := &BattleResult{Flips: []map[string]Card{}}
+	pot := []Card{}
+
+	for {
+		// A player with no card to flip loses the battle
+		if len(g.PlayerHands[first]) == 0 || len(g.PlayerHands[second]) == 0 {
+			result.Winner = first
+			if len(g.PlayerHands[first]) == 0 {
+				result.Winner = second
+			}
+			break
+		}
+
+		// Both players flip their top card
+		firstCard, secondCard := g.PlayerHands[first][0], g.PlayerHands[second][0]
+		g.PlayerHands[first] = g.PlayerHands[first][1:]
+		g.PlayerHands[second] = g.PlayerHands[second][1:]
+		pot = append(pot, firstCard, secondCard)
+		result.Flips = append(result.Flips, map[string]Card{first: firstCard, second: secondCard})
+
+		// Cards of the same value go to war, unless the game ranks suits to settle them
+		firstRank, secondRank := WarRank(firstCard), WarRank(secondCard)
+		if firstRank == secondRank {
+			firstRank, secondRank = g.SuitRank(firstCard.Suit), g.SuitRank(secondCard.Suit)
+		}
+		if firstRank > secondRank {
+			result.Winner = first
+			break
+		}
+		if secondRank > firstRank {
+			result.Winner = second
+			break
+		}
+
+		// Tie: each player puts up to three cards face down, keeping one back to flip if they can
+		result.Wars++
+		for _, player := range []string{first, second} {
+			count := warFaceDown
+			if len(g.PlayerHands[player])-1 < count {
+				count = len(g.PlayerHands[player]) - 1
+			}
+			if count > 0 {
+				pot = append(pot, g.PlayerHands[player][:count]...)
+				g.PlayerHands[player] = g.PlayerHands[player][count:]
+			}
+		}
+	}
+
+	// The winner takes every card in play to the bottom of their pile
+	g.PlayerHands[result.Winner] = append(g.PlayerHands[result.Winner], pot...)
+	result.CardsWon = len(pot)
+
+	// The game ends once one player holds every card
+	for _, player := range g.Players {
+		if len(g.PlayerHands[player]) == 0 {
+			g.Status = StatusFinished
+			g.Winner = result.Winner
+			g.DecideWinningTeam()
+			result.GameOver = true
+			result.GameWinner = result.Winner
+		}
+	}
+
+	return result, nil
+}
+
+// WarRank returns the rank of a card in War, where aces are high.
+func WarRank(card Card) int {
+	if card.Value == RankAce {
+		return 14
+	}
+	return faceValue(card.Value)
+}
@@ -0,0 +1,53 @@
+package models
+
+import "testing"
+
+func FuzzResolveBattle(f *testing.F) {
+	f.Add([]byte{60}, []byte{5}, false)                         // Ace beats two
+	f.Add([]byte{5, 1, 2, 3, 10}, []byte{6, 7, 8, 9, 5}, false) // A war that is won
+	f.Add([]byte{5, 1}, []byte{6}, false)                       // A war the second player runs out of cards in
+	f.Add([]byte{5}, []byte{6}, true)                           // A tie settled by suit
+	f.Add([]byte{}, []byte{0, 1}, false)                        // The first player has no cards left
+
+	f.Fuzz(func(t *testing.T, first, second []byte, rankSuits bool) {
+		g := &Game{
+			Mode:    ModeWar,
+			Status:  StatusActive,
+			Players: []string{"alice", "bob"},
+			PlayerHands: map[string][]Card{
+				"alice": fuzzCards(first),
+				"bob":   fuzzCards(second),
+			},
+		}
+		if rankSuits {
+			g.Settings.SuitOrder = []string{"Spades", "Hearts", "Diamonds", "Clubs", "Joker"}
+		}
+		before := len(first) + len(second)
+
+		result, err := g.ResolveBattle()
+		if err != nil {
+			t.Fatalf("ResolveBattle: %v", err)
+		}
+
+		// The battle moves cards between the piles without making or losing any
+		alice, bob := len(g.PlayerHands["alice"]), len(g.PlayerHands["bob"])
+		if alice+bob != before {
+			t.Fatalf("the piles hold %d cards after the battle, want %d", alice+bob, before)
+		}
+		if result.Winner != "alice" && result.Winner != "bob" {
+			t.Fatalf("the battle was won by %q", result.Winner)
+		}
+		if result.CardsWon > before {
+			t.Fatalf("the winner took %d cards of %d", result.CardsWon, before)
+		}
+
+		// The game is over exactly when one player holds every card
+		over := alice == 0 || bob == 0
+		if result.GameOver != over || (g.Status == StatusFinished) != over {
+			t.Fatalf("game over is %v with piles of %d and %d cards", result.GameOver, alice, bob)
+		}
+		if over && g.Winner != result.Winner {
+			t.Fatalf("game winner is %q, want %q", g.Winner, result.Winner)
+		}
+	})
+}
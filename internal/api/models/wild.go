@@ -0,0 +1,183 @@
+package models
+
+import (
+	"errors"
+	"sort"
+)
+
+// ValidateWildCards checks that every wild card entry is a card value, such as "2" or "Joker", which makes every
+// card of that value wild, or a card code, such as "JS", which makes that one card wild.
+func ValidateWildCards(wildCards []string) error {
+	for _, wild := range wildCards {
+		if IsValidCardValue(wild) || wild == JokerValue {
+			continue
+		}
+		if _, err := ParseCardCode(wild); err != nil {
+			return errors.New("invalid card in wild_cards: " + wild)
+		}
+	}
+	return nil
+}
+
+// IsWild reports whether the game treats a card as wild: its value or the card itself is one of the game's wild cards.
+func (g *Game) IsWild(card Card) bool {
+	for _, wild := range g.Settings.WildCards {
+		if wild == card.Value.String() {
+			return true
+		}
+		if code, err := ParseCardCode(wild); err == nil && code.Suit == card.Suit && code.Value == card.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkWildCards flags the wild cards in the game's hands, melds, and discard pile, so responses show clients which
+// cards to render as wild. It does nothing if the game has no wild cards.
+func (g *Game) MarkWildCards() {
+	if len(g.Settings.WildCards) == 0 {
+		return
+	}
+	for _, hand := range g.PlayerHands {
+		g.markWild(hand)
+	}
+	for _, hands := range g.Hands {
+		for _, hand := range hands {
+			g.markWild(hand.Cards)
+		}
+	}
+	for _, meld := range g.Melds {
+		g.markWild(meld.Cards)
+	}
+	g.markWild(g.DealerHand)
+	g.markWild(g.DiscardPile)
+}
+
+// MarkWild flags the wild cards among the given cards in place and returns them.
+func (g *Game) MarkWild(cards []Card) []Card {
+	g.markWild(cards)
+	return cards
+}
+
+// markWild flags the wild cards among the given cards in place.
+func (g *Game) markWild(cards []Card) {
+	for i := range cards {
+		cards[i].wild = g.IsWild(cards[i])
+	}
+}
+
+// WildScoring values hands in which some cards are wild: each wild card stands in for whichever card, itself
+// included, gives the hand the best value under the game's own scoring strategy. The wild cards are chosen one
+// at a time, each taking the card that best improves the hand together with the cards chosen before it.
+type WildScoring struct {
+	Strategy ScoringStrategy
+	IsWild   func(Card) bool
+}
+
+// HandValue returns the best value of the hand with its wild cards standing in for other cards.
+func (s WildScoring) HandValue(hand []Card) int {
+	natural, wild := s.split(hand)
+	if len(wild) == 0 {
+		return s.Strategy.HandValue(hand)
+	}
+
+	candidates := NewDeck().Cards
+	best := 0
+	for _, card := range wild {
+		// Consider the wild card as itself first, then as every card of a standard deck
+		chosen := card
+		best = s.Strategy.HandValue(withCard(natural, card))
+		for _, candidate := range candidates {
+			value := s.Strategy.HandValue(withCard(natural, candidate))
+			if (s.Strategy.LowestWins() && value < best) || (!s.Strategy.LowestWins() && value > best) {
+				chosen, best = candidate, value
+			}
+		}
+		natural = withCard(natural, chosen)
+	}
+	return best
+}
+
+// LowestWins reports whether the underlying strategy treats lower values as better.
+func (s WildScoring) LowestWins() bool {
+	return s.Strategy.LowestWins()
+}
+
+// split separates the natural cards of a hand from its wild ones.
+func (s WildScoring) split(hand []Card) ([]Card, []Card) {
+	natural, wild := []Card{}, []Card{}
+	for _, card := range hand {
+		if s.IsWild(card) {
+			wild = append(wild, card)
+		} else {
+			natural = append(natural, card)
+		}
+	}
+	return natural, wild
+}
+
+// withCard returns a copy of the cards with one more card added, leaving the original slice untouched.
+func withCard(cards []Card, card Card) []Card {
+	return append(append(make([]Card, 0, len(cards)+1), cards...), card)
+}
+
+// ClassifyMeld validates a group of cards as a rummy meld and returns its type, as the package-level ClassifyMeld
+// does, letting the game's wild cards stand in for any card a set or run is missing. Cards that form a meld on
+// their own are classified as they are; otherwise the wild cards fill the gaps in a run and then extend it, high
+// end first, and a meld needs at least one natural card.
+func (g *Game) ClassifyMeld(cards []Card) (string, []Card, error) {
+	// Wild cards cannot make up for a meld that is too short
+	meldType, ordered, err := ClassifyMeld(cards)
+	if err == nil || len(g.Settings.WildCards) == 0 || len(cards) < 3 {
+		return meldType, ordered, err
+	}
+
+	natural, wild := WildScoring{IsWild: g.IsWild}.split(cards)
+	if len(wild) == 0 || len(natural) == 0 {
+		return "", nil, err
+	}
+	if len(cards) <= 4 && isSet(natural) {
+		return MeldSet, append(natural, wild...), nil
+	}
+	if run, ok := asWildRun(natural, wild); ok {
+		return MeldRun, run, nil
+	}
+	return "", nil, err
+}
+
+// asWildRun places wild cards in the gaps of a run of natural cards, and then beyond its ends, and returns the run
+// in ascending rank order. It reports false if the natural cards are not of one suit with distinct values, or
+// the wild cards cannot close the gaps or fit on the run within the thirteen values.
+func asWildRun(natural, wild []Card) ([]Card, bool) {
+	if len(natural)+len(wild) > 13 {
+		return nil, false
+	}
+	sorted := append([]Card{}, natural...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return faceValue(sorted[i].Value) < faceValue(sorted[j].Value)
+	})
+
+	// Walk up from the lowest natural card, filling every missing value with a wild card
+	run := []Card{}
+	for i, card := range sorted {
+		if card.Suit != sorted[0].Suit || faceValue(card.Value) == 0 {
+			return nil, false
+		}
+		if i > 0 {
+			gap := faceValue(card.Value) - faceValue(sorted[i-1].Value) - 1
+			if gap < 0 || gap > len(wild) {
+				return nil, false
+			}
+			run = append(run, wild[:gap]...)
+			wild = wild[gap:]
+		}
+		run = append(run, card)
+	}
+
+	// Extend the run upwards to the king with the wild cards left over, then downwards
+	for high := faceValue(sorted[len(sorted)-1].Value); len(wild) > 0 && high < 13; high++ {
+		run = append(run, wild[0])
+		wild = wild[1:]
+	}
+	return append(append([]Card{}, wild...), run...), true
+}
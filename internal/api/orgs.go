@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/auth"
+	"my-card-game/internal/config"
+	"my-card-game/internal/db"
+	"net/http"
+	"sync"
+)
+
+// orgRouter serves each organization from routes and services of its own, so a request can only reach the data
+// of the organization it acts in. An organization's routes are built the first time it is asked for.
+type orgRouter struct {
+	cfg     *config.Config
+	base    *Services // The default organization's services, whose API keys and organizations every organization shares
+	mu      sync.Mutex
+	routers map[string]http.Handler
+}
+
+// newOrgRouter creates an orgRouter for the deployment whose default organization has the services base.
+func newOrgRouter(cfg *config.Config, base *Services) *orgRouter {
+	return &orgRouter{cfg: cfg, base: base, routers: map[string]http.Handler{}}
+}
+
+// ServeHTTP serves the request with the routes of the organization it acts in, named by its X-Org-ID header or
+// its API key. Requests naming an organization that does not exist receive a 404 Not Found response.
+func (o *orgRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	router, err := o.routes(r.Context(), auth.RequestedOrg(r, o.base.APIKeys))
+	if errors.Is(err, services.ErrOrgNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	router.ServeHTTP(w, r)
+}
+
+// routes returns the organization's routes, building them and starting its services the first time.
+func (o *orgRouter) routes(ctx context.Context, org string) (http.Handler, error) {
+	o.mu.Lock()
+	router, ok := o.routers[org]
+	o.mu.Unlock()
+	if ok {
+		return router, nil
+	}
+
+	// Only organizations that have been created are served
+	exists, err := o.base.Orgs.OrgExists(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, services.ErrOrgNotFound
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if router, ok := o.routers[org]; ok {
+		return router, nil
+	}
+	svc := o.base
+	if org != db.DefaultOrg {
+		svc = NewOrgServices(o.cfg, org, o.base.Orgs)
+	}
+	router = newOrgRoutes(o.cfg, svc)
+	o.routers[org] = router
+	return router, nil
+}
+
+// startAll builds the routes of the default organization and every organization created so far, so their
+// background jobs run even before they are asked for.
+func (o *orgRouter) startAll() {
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	if _, err := o.routes(ctx, db.DefaultOrg); err != nil {
+		log.Fatalf("could not start the default organization: %v", err)
+	}
+	orgs, err := o.base.Orgs.ListOrgs()
+	if err != nil {
+		log.Printf("could not list the organizations to start: %v", err)
+		return
+	}
+	for _, org := range orgs {
+		if _, err := o.routes(ctx, org.ID); err != nil {
+			log.Printf("could not start organization %s: %v", org.ID, err)
+		}
+	}
+}
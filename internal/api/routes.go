@@ -1,30 +1,237 @@
 package api
 
 import (
+	"context"
+	"my-card-game/internal/accesslog"
 	"my-card-game/internal/api/handlers"
 	"my-card-game/internal/api/services"
+	"my-card-game/internal/auth"
+	"my-card-game/internal/config"
+	"my-card-game/internal/db"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 )
 
-func RegisterRoutes(r *mux.Router) {
-	// Initialize services here instead of as global variables
-	gameService := services.NewGameService()
-	deckService := services.NewDeckService()
+// APIV1Prefix is the path prefix every version 1 route is served under.
+const APIV1Prefix = "/api/v1"
+
+// RegisterRoutes registers the HTTP API on r, starting with the default organization's services svc. Each
+// organization is served by routes of its own, built on its own services by newOrgRoutes. Each API version is
+// registered on its own subrouter under its path prefix by its own function, sharing the organization's services
+// and background workers, so a future version can be added next to version 1 without disturbing it.
+// The unversioned paths used before versioning keep being served by version 1, with headers announcing their
+// deprecation.
+func RegisterRoutes(r *mux.Router, cfg *config.Config, svc *Services) {
+	// Health probes are neither part of the versioned API nor of any organization
+	r.HandleFunc("/healthz", handlers.HealthzHandler()).Methods("GET")
+	r.HandleFunc("/readyz", handlers.ReadyzHandler(svc.Health)).Methods("GET")
+
+	// Version 1 of the API, served to each organization from its own routes, built for every organization now so
+	// their background jobs run
+	orgs := newOrgRouter(cfg, svc)
+	orgs.startAll()
+	r.PathPrefix(APIV1Prefix).Handler(orgs)
+
+	// Serve the legacy unversioned paths from version 1. Paths already under /api/ are left alone
+	// so a request that matches no versioned route is not rewritten again.
+	legacy := handlers.LegacyPaths(APIV1Prefix, cfg.LegacySunset, r)
+	r.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		return !strings.HasPrefix(req.URL.Path, "/api/")
+	}).Handler(legacy)
+}
+
+// newOrgRoutes starts an organization's real-time hubs and background jobs and builds its routes on its services.
+func newOrgRoutes(cfg *config.Config, svc *Services) http.Handler {
+	// Follow the games collection's change stream so real-time subscribers see changes from every replica
+	updateHub := services.NewUpdateHub()
+	svc.Game.WatchGames(context.Background(), updateHub)
+
+	// Follow new notifications so players' real-time streams get them from every replica
+	notificationHub := services.NewNotificationHub()
+	svc.Social.WatchNotifications(context.Background(), notificationHub)
+
+	// Run the background jobs, such as the inactivity check and session cleanup
+	svc.Jobs.Start(context.Background())
+
+	// Version 1 of the API
+	r := mux.NewRouter()
+	registerV1(r.PathPrefix(APIV1Prefix).Subrouter(), cfg, svc, updateHub, notificationHub)
+	return r
+}
+
+// registerV1 registers version 1 of the API and its middleware for the organization of svc on r, which is
+// expected to be a subrouter under APIV1Prefix.
+func registerV1(r *mux.Router, cfg *config.Config, svc *Services, updateHub *services.UpdateHub, notificationHub *services.NotificationHub) {
+	// Use the shared services instead of global variables
+	gameService := svc.Game
+	deckService := svc.Deck
+	sessionService := svc.Sessions
+	keyService := svc.APIKeys
+	socialService := svc.Social
+	walletService := svc.Wallets
+	jobService := svc.Queue
+
+	// Resolve the caller's identity in the organization from their session or API key for every request
+	r.Use(auth.Middleware(svc.Org, sessionService, keyService))
+
+	// Record the matched route and caller for the access log
+	r.Use(accesslog.Annotate)
+
+	// Cap request bodies, allowing larger ones for game imports
+	r.Use(handlers.LimitRequestBody(cfg.MaxRequestBytes, map[string]int64{APIV1Prefix + "/games/import": cfg.MaxImportBytes}))
+
+	// Reject malformed game and key IDs in the path before any handler runs
+	r.Use(handlers.ValidatePathIDs)
+
+	// Build the _links of game responses from the named routes below, which every organization shares, and show
+	// players as online for a while after each heartbeat; both are set once, with the default organization's routes
+	if svc.Org == db.DefaultOrg {
+		handlers.SetLinkRouter(r)
+		handlers.SetPresenceTimeout(cfg.PresenceTimeout)
+	}
 
 	// Add other routes here...
 
+	r.HandleFunc("/players", handlers.RegisterPlayerHandler(sessionService)).Methods("POST")
+	r.HandleFunc("/sessions", handlers.CreateSessionHandler(sessionService)).Methods("POST")
+	r.HandleFunc("/me/password", auth.RequirePlayer(handlers.SetPasswordHandler(sessionService))).Methods("PUT")
+	r.HandleFunc("/me", handlers.MeHandler(gameService, sessionService)).Methods("GET")
+	r.HandleFunc("/players/{name}/stats", handlers.GetPlayerStatsHandler(gameService)).Methods("GET")
+	r.HandleFunc("/players/{name}/achievements", handlers.GetAchievementsHandler(gameService)).Methods("GET")
 	r.HandleFunc("/games", handlers.CreateGameHandler(gameService)).Methods("POST")
-	r.HandleFunc("/games/{id}", handlers.DeleteGameHandler(gameService)).Methods("DELETE")
+	r.HandleFunc("/games/batch", handlers.CreateGamesHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/import", auth.RequireAdmin(handlers.ImportGameHandler(gameService))).Methods("POST")
+	r.HandleFunc("/games/open", handlers.ListOpenGamesHandler(gameService)).Methods("GET")
+	r.HandleFunc("/games/open/stream", handlers.StreamOpenGamesHandler(gameService, updateHub)).Methods("GET")
+	r.HandleFunc("/games/{id}", handlers.GetGameHandler(gameService)).Methods("GET").Name(handlers.RouteGame)
+	r.HandleFunc("/games/{id}", auth.RequireAdmin(handlers.DeleteGameHandler(gameService))).Methods("DELETE")
+	r.HandleFunc("/games/{id}", handlers.UpdateGameHandler(gameService)).Methods("PATCH")
 	r.HandleFunc("/decks", handlers.CreateDeckHandler(deckService)).Methods("POST")
 	r.HandleFunc("/games/{id}/add-deck", handlers.AddDeckToGameHandler(gameService, deckService)).Methods("POST")
-	r.HandleFunc("/games/{id}/add-player", handlers.AddPlayerHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/add-player", handlers.AddPlayerHandler(gameService, sessionService)).Methods("POST").Name(handlers.RouteAddPlayer)
 	r.HandleFunc("/games/{id}/remove-player", handlers.RemovePlayerHandler(gameService)).Methods("POST")
-	r.HandleFunc("/games/{id}/shuffle", handlers.ShuffleGameDeckHandler(gameService)).Methods("POST")
-	r.HandleFunc("/games/{id}/deal-card", handlers.DealCardToPlayerHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/bots", handlers.AddBotHandler(gameService, sessionService)).Methods("POST")
+	r.HandleFunc("/games/{id}/bots/{name}", handlers.UpdateBotHandler(gameService)).Methods("PATCH")
+	r.HandleFunc("/games/{id}/seats", handlers.GetSeatingHandler(gameService)).Methods("GET")
+	r.HandleFunc("/games/{id}/seats/{seat}", handlers.ChangeSeatHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/seats/{seat}/reservation", handlers.ReserveSeatHandler(gameService)).Methods("PUT")
+	r.HandleFunc("/games/{id}/seats/{seat}/reservation", handlers.ReleaseSeatHandler(gameService)).Methods("DELETE")
+	r.HandleFunc("/games/{id}/waitlist", handlers.JoinWaitlistHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/waitlist", handlers.GetWaitlistPositionHandler(gameService)).Methods("GET")
+	r.HandleFunc("/games/{id}/waitlist", handlers.LeaveWaitlistHandler(gameService)).Methods("DELETE")
+	r.HandleFunc("/games/{id}/shuffle", handlers.ShuffleGameDeckHandler(gameService)).Methods("POST").Name(handlers.RouteShuffle)
+	r.HandleFunc("/games/{id}/deck/peek", handlers.PeekDeckHandler(gameService)).Methods("GET")
+	r.HandleFunc("/games/{id}/deal-card", handlers.DealCardToPlayerHandler(gameService)).Methods("POST").Name(handlers.RouteDealCard)
 	r.HandleFunc("/games/{id}/player-hand", handlers.GetPlayerHandHandler(gameService)).Methods("GET")
 	r.HandleFunc("/games/{id}/player-hand-values", handlers.GetPlayersWithHandValuesHandler(gameService)).Methods("GET")
 	r.HandleFunc("/games/{id}/remaining-cards-suit-count", handlers.GetRemainingCardsCountBySuitHandler(gameService)).Methods("GET")
 	r.HandleFunc("/games/{id}/remaining-cards-sorted", handlers.GetRemainingCardsSortedHandler(gameService)).Methods("GET")
+	r.HandleFunc("/games/{id}/odds", handlers.GetOddsHandler(gameService)).Methods("GET")
+	r.HandleFunc("/games/{id}/simulate", handlers.SimulateGameHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/hint", auth.RequirePlayer(handlers.GetHintHandler(gameService))).Methods("GET")
+	r.HandleFunc("/games/{id}/count", handlers.GetCountStatsHandler(gameService)).Methods("GET")
+	r.HandleFunc("/games/{id}/chips", auth.RequireAdmin(handlers.SetPlayerChipsHandler(gameService))).Methods("POST")
+	r.HandleFunc("/games/{id}/bet", handlers.PlaceBetHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/fold", handlers.FoldHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/buy-in", auth.RequirePlayer(handlers.BuyInHandler(walletService))).Methods("POST")
+	r.HandleFunc("/games/{id}/cash-out", auth.RequirePlayer(handlers.CashOutHandler(walletService))).Methods("POST")
+	r.HandleFunc("/wallet", auth.RequirePlayer(handlers.GetWalletHandler(walletService))).Methods("GET")
+	r.HandleFunc("/games/{id}/pots", handlers.GetPotsHandler(gameService)).Methods("GET")
+	r.HandleFunc("/games/{id}/showdown", handlers.ShowdownHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/blinds", handlers.ConfigureBlindsHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/start-hand", handlers.StartHandHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/melds", handlers.DeclareMeldHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/melds", handlers.GetMeldsHandler(gameService)).Methods("GET")
+	r.HandleFunc("/games/{id}/melds/{meld_id}/lay-off", handlers.LayOffHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/deadwood", handlers.GetDeadwoodHandler(gameService)).Methods("GET")
+	r.HandleFunc("/games/{id}/settings", handlers.UpdateSettingsHandler(gameService)).Methods("PATCH")
+	r.HandleFunc("/games/{id}/house-rules", handlers.SetHouseRulesHandler(gameService)).Methods("PUT")
+	r.HandleFunc("/games/{id}/teams", handlers.SetTeamsHandler(gameService)).Methods("PUT")
+	r.HandleFunc("/games/{id}/teams", handlers.GetTeamsHandler(gameService)).Methods("GET")
+	r.HandleFunc("/games/{id}/start", handlers.StartGameHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/reset", handlers.ResetGameHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/checksum", handlers.GetChecksumHandler(gameService)).Methods("GET")
+	r.HandleFunc("/games/{id}/events", handlers.GetEventsHandler(gameService)).Methods("GET").Name(handlers.RouteGameEvents)
+	r.HandleFunc("/games/{id}/stream", handlers.StreamGameHandler(updateHub, gameService)).Methods("GET")
+	r.HandleFunc("/games/{id}/heartbeat", auth.RequirePlayer(handlers.HeartbeatHandler(gameService))).Methods("POST")
+	r.HandleFunc("/games/{id}/turn", handlers.GetTurnHandler(gameService)).Methods("GET")
+	r.HandleFunc("/games/{id}/turn/skip", handlers.SkipTurnHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/turn/reverse", handlers.ReverseTurnHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/hands", handlers.DealHandHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/hands", handlers.GetHandsHandler(gameService)).Methods("GET")
+	r.HandleFunc("/games/{id}/hands/showdown", handlers.ShowdownHandHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/hands/score", handlers.ScoreHandHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/auction", handlers.OpenAuctionHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/auction", handlers.GetAuctionHandler(gameService)).Methods("GET")
+	r.HandleFunc("/games/{id}/bids", handlers.PlaceBidHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/war/battle", handlers.PlayWarBattleHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/blackjack/hit", handlers.HitHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/blackjack/stand", handlers.StandHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/blackjack/double", handlers.DoubleDownHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/blackjack/split", handlers.SplitHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/blackjack/surrender", handlers.SurrenderHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/blackjack/insurance", handlers.InsuranceHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/moves", handlers.MakeMoveHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/play-card", handlers.PlayCardHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/draw", handlers.DrawCardHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/redraw", handlers.RedrawHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/ask", handlers.AskHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/books", handlers.GetBooksHandler(gameService)).Methods("GET")
+	r.HandleFunc("/games/{id}/kick", handlers.KickPlayerHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/ban", handlers.BanPlayerHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/penalty", handlers.PenaltyDrawHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/clone", handlers.CloneGameHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/snapshots", handlers.CreateSnapshotHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/snapshots", handlers.ListSnapshotsHandler(gameService)).Methods("GET")
+	r.HandleFunc("/games/{id}/snapshots/{name}/rollback", handlers.RollbackHandler(gameService)).Methods("POST")
+	r.HandleFunc("/games/{id}/export", auth.RequireAdmin(handlers.ExportGameHandler(gameService))).Methods("GET")
+	r.HandleFunc("/archive/games/{id}", handlers.GetArchivedGameHandler(gameService)).Methods("GET")
+	r.HandleFunc("/jobs", handlers.SubmitJobHandler(jobService)).Methods("POST")
+	r.HandleFunc("/jobs", handlers.ListJobsHandler(jobService)).Methods("GET")
+	r.HandleFunc("/jobs/{job_id}", handlers.GetJobHandler(jobService)).Methods("GET").Name(handlers.RouteJob)
+
+	// Friends, game invitations, notifications, and turn alert preferences, all acting as the caller's player session
+	r.HandleFunc("/friends", auth.RequirePlayer(handlers.ListFriendsHandler(socialService))).Methods("GET")
+	r.HandleFunc("/friends/requests", auth.RequirePlayer(handlers.SendFriendRequestHandler(socialService))).Methods("POST")
+	r.HandleFunc("/friends/requests/{id}/accept", auth.RequirePlayer(handlers.RespondToFriendRequestHandler(socialService, true))).Methods("POST")
+	r.HandleFunc("/friends/requests/{id}/decline", auth.RequirePlayer(handlers.RespondToFriendRequestHandler(socialService, false))).Methods("POST")
+	r.HandleFunc("/games/{id}/invite", auth.RequirePlayer(handlers.InviteToGameHandler(socialService))).Methods("POST")
+	r.HandleFunc("/invitations", auth.RequirePlayer(handlers.ListInvitationsHandler(socialService))).Methods("GET")
+	r.HandleFunc("/invitations/{id}/accept", auth.RequirePlayer(handlers.RespondToInvitationHandler(socialService, true))).Methods("POST")
+	r.HandleFunc("/invitations/{id}/decline", auth.RequirePlayer(handlers.RespondToInvitationHandler(socialService, false))).Methods("POST")
+	r.HandleFunc("/notifications", auth.RequirePlayer(handlers.ListNotificationsHandler(socialService))).Methods("GET")
+	r.HandleFunc("/notifications/stream", auth.RequirePlayer(handlers.StreamNotificationsHandler(notificationHub))).Methods("GET")
+	r.HandleFunc("/me/profile", auth.RequirePlayer(handlers.GetProfileHandler(socialService))).Methods("GET")
+	r.HandleFunc("/me/profile/notifications", auth.RequirePlayer(handlers.SetNotificationPrefsHandler(socialService))).Methods("PUT")
+
+	// Administrative routes, all requiring an API key
+	admin := r.PathPrefix("/admin").Subrouter()
+	admin.HandleFunc("/api-keys", auth.RequireAdmin(handlers.CreateAPIKeyHandler(keyService))).Methods("POST")
+	admin.HandleFunc("/api-keys", auth.RequireAdmin(handlers.ListAPIKeysHandler(keyService))).Methods("GET")
+	admin.HandleFunc("/api-keys/{key_id}", auth.RequireAdmin(handlers.DeleteAPIKeyHandler(keyService))).Methods("DELETE")
+	admin.HandleFunc("/games", auth.RequireAdmin(handlers.AdminListGamesHandler(gameService))).Methods("GET")
+	admin.HandleFunc("/games/stats", auth.RequireAdmin(handlers.AdminStatsHandler(gameService))).Methods("GET")
+	admin.HandleFunc("/games/{id}", auth.RequireAdmin(handlers.AdminRawGameHandler(gameService))).Methods("GET")
+	admin.HandleFunc("/games/{id}", auth.RequireAdmin(handlers.AdminDeleteGameHandler(gameService))).Methods("DELETE")
+	admin.HandleFunc("/games/{id}/shuffles", auth.RequireAdmin(handlers.AdminShuffleLogHandler(gameService))).Methods("GET")
+	admin.HandleFunc("/games/{id}/replay", auth.RequireAdmin(handlers.AdminVerifyReplayHandler(gameService))).Methods("GET")
+	admin.HandleFunc("/games/{id}/end", auth.RequireAdmin(handlers.AdminForceEndGameHandler(gameService))).Methods("POST")
+	admin.HandleFunc("/diagnostics", auth.RequireAdmin(handlers.DiagnosticsHandler(time.Now()))).Methods("GET")
+	admin.HandleFunc("/audit", auth.RequireAdmin(handlers.AuditLogHandler(svc.Audit))).Methods("GET")
+	admin.HandleFunc("/wallets/{name}/credit", auth.RequireAdmin(handlers.AdminCreditWalletHandler(walletService))).Methods("POST")
+	admin.HandleFunc("/jobs", auth.RequireAdmin(handlers.JobsHandler(svc.Jobs))).Methods("GET")
+	admin.HandleFunc("/orgs", auth.RequirePlatformAdmin(handlers.CreateOrgHandler(svc.Orgs))).Methods("POST")
+	admin.HandleFunc("/orgs", auth.RequirePlatformAdmin(handlers.ListOrgsHandler(svc.Orgs))).Methods("GET")
 
+	// Runtime profiling from net/http/pprof, behind the same API key
+	admin.HandleFunc("/debug/pprof/", auth.RequireAdmin(pprof.Index)).Methods("GET")
+	admin.HandleFunc("/debug/pprof/cmdline", auth.RequireAdmin(pprof.Cmdline)).Methods("GET")
+	admin.HandleFunc("/debug/pprof/profile", auth.RequireAdmin(pprof.Profile)).Methods("GET")
+	admin.HandleFunc("/debug/pprof/symbol", auth.RequireAdmin(pprof.Symbol)).Methods("GET", "POST")
+	admin.HandleFunc("/debug/pprof/trace", auth.RequireAdmin(pprof.Trace)).Methods("GET")
+	admin.HandleFunc("/debug/pprof/{profile}", auth.RequireAdmin(handlers.PprofProfileHandler())).Methods("GET")
 }
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"log"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/config"
+	"my-card-game/internal/db"
+	"my-card-game/internal/entropy"
+	"my-card-game/internal/eventbus"
+	"my-card-game/internal/notify"
+	"my-card-game/internal/scheduler"
+)
+
+// Services holds the service layer of one organization, shared by its REST routes and, for the default
+// organization, the gRPC server, so both APIs work on the same service instances. Every service only reaches
+// the organization's own data.
+type Services struct {
+	Org      string
+	Game     *services.GameService
+	Deck     *services.DeckService
+	Sessions *services.SessionService
+	APIKeys  *services.APIKeyService
+	Health   *services.HealthService
+	Social   *services.SocialService
+	Audit    *services.AuditService
+	Wallets  *services.WalletService
+	Queue    *services.JobService // Long-running work submitted through the API, run by the run-queued-jobs job
+	Orgs     *services.OrgService // The organizations on the deployment, shared by every organization's services
+	Jobs     *scheduler.Scheduler // Background jobs; started when the organization's routes are built
+}
+
+// NewServices initializes the default organization's service layer from the configuration, along with the
+// settings shared by every organization.
+func NewServices(cfg *config.Config) *Services {
+	// Point card image URLs at the configured image host
+	models.SetCardImageBaseURL(cfg.CardImageBaseURL)
+
+	return NewOrgServices(cfg, db.DefaultOrg, services.NewOrgService())
+}
+
+// NewOrgServices initializes the service layer of one organization from the configuration.
+func NewOrgServices(cfg *config.Config, org string, orgs *services.OrgService) *Services {
+	// Shuffle with the configured entropy source
+	source, err := entropy.New(entropy.Config{
+		Kind:    cfg.ShuffleEntropy,
+		Device:  cfg.ShuffleEntropyDevice,
+		URL:     cfg.ShuffleEntropyURL,
+		Timeout: cfg.ShuffleEntropyTimeout,
+	})
+	if err != nil {
+		log.Fatalf("could not set up the shuffle entropy source: %v", err)
+	}
+	gameService := services.NewGameService(org, source)
+
+	// Gzip finished games when they are archived if configured
+	gameService.SetArchiveCompression(cfg.CompressArchives)
+
+	// Publish game events to the configured message bus
+	publisher, err := eventbus.New(eventbus.Config{
+		Kind:    cfg.EventBus,
+		URL:     cfg.EventBusURL,
+		Topic:   cfg.EventBusTopic,
+		Timeout: cfg.EventBusTimeout,
+	})
+	if err != nil {
+		log.Fatalf("could not set up the event bus: %v", err)
+	}
+	gameService.SetEventPublisher(publisher)
+
+	// Deliver turn alerts on the configured channels
+	notifiers, err := notify.New(notify.Config{
+		SMTPAddr:     cfg.SMTPAddr,
+		SMTPUsername: cfg.SMTPUsername,
+		SMTPPassword: cfg.SMTPPassword,
+		SMTPFrom:     cfg.SMTPFrom,
+		FCMServerKey: cfg.FCMServerKey,
+		FCMURL:       cfg.FCMURL,
+		Timeout:      cfg.NotifyTimeout,
+	})
+	if err != nil {
+		log.Fatalf("could not set up turn alerts: %v", err)
+	}
+	socialService := services.NewSocialService(gameService)
+	socialService.SetNotifiers(notifiers)
+
+	svc := &Services{
+		Org:      org,
+		Game:     gameService,
+		Deck:     services.NewDeckService(),
+		Sessions: services.NewSessionService(org, cfg.SessionTTL),
+		APIKeys:  services.NewAPIKeyService(org, cfg.AdminAPIKey),
+		Health:   services.NewHealthService(),
+		Social:   socialService,
+		Audit:    services.NewAuditService(org),
+		Wallets:  services.NewWalletService(gameService),
+		Queue:    services.NewJobService(gameService, cfg.JobTimeout),
+		Orgs:     orgs,
+		Jobs:     scheduler.New(scheduler.NewMongoLocker(db.GetOrgCollection(org, "job_locks"))),
+	}
+
+	// Register the background jobs, coordinating with other replicas through the job locks
+	if err := registerJobs(svc.Jobs, cfg, svc); err != nil {
+		log.Fatalf("could not register background jobs: %v", err)
+	}
+
+	return svc
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AchievementStatus pairs an achievement with whether a player has unlocked it, and when and in which game they did.
+type AchievementStatus struct {
+	models.Achievement
+	Unlocked   bool                `json:"unlocked"`
+	UnlockedAt *time.Time          `json:"unlocked_at,omitempty"`
+	GameID     *primitive.ObjectID `json:"game_id,omitempty"`
+}
+
+// GetAchievements lists every achievement along with whether the player has unlocked it.
+func (s *GameService) GetAchievements(playerName string) ([]AchievementStatus, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	// Load the player's unlocked achievements
+	unlocked := []models.PlayerAchievement{}
+	err := db.Retry(ctx, func() error {
+		cursor, err := s.achievements.Find(ctx, bson.M{"player_name": playerName})
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, &unlocked)
+	})
+	if err != nil {
+		return nil, err
+	}
+	byID := map[string]models.PlayerAchievement{}
+	for _, achievement := range unlocked {
+		byID[achievement.Achievement] = achievement
+	}
+
+	// Report every achievement, unlocked or not
+	statuses := make([]AchievementStatus, len(models.Achievements))
+	for i, achievement := range models.Achievements {
+		statuses[i] = AchievementStatus{Achievement: achievement}
+		if record, ok := byID[achievement.ID]; ok {
+			unlockedAt, gameID := record.UnlockedAt, record.GameID
+			statuses[i].Unlocked = true
+			statuses[i].UnlockedAt = &unlockedAt
+			statuses[i].GameID = &gameID
+		}
+	}
+	return statuses, nil
+}
+
+// checkAchievements evaluates a newly recorded event against the achievements it can unlock:
+// a game over event unlocks the winner's first win, and a dealt card completing a royal flush unlocks that achievement.
+func (s *GameService) checkAchievements(ctx context.Context, event models.Event) error {
+	if event.Player == "" {
+		return nil
+	}
+
+	switch event.Type {
+	case models.EventGameOver:
+		return s.unlockAchievement(ctx, event.GameID, event.Player, models.AchievementFirstWin)
+
+	case models.EventCardDealt:
+		// Only a ten or a court card or ace can complete a royal flush, so skip reading the hand for the rest
+		card, _ := models.ParseCardCode(stringData(event.Data, "card"))
+		switch card.Value {
+		case models.RankTen, models.RankJack, models.RankQueen, models.RankKing, models.RankAce:
+		default:
+			return nil
+		}
+
+		var game models.Game
+		opts := options.FindOne().SetProjection(bson.M{"player_hands." + event.Player: 1})
+		if err := s.collection.FindOne(ctx, bson.M{"_id": event.GameID}, opts).Decode(&game); err != nil {
+			return err
+		}
+		if models.HasRoyalFlush(game.PlayerHands[event.Player]) {
+			return s.unlockAchievement(ctx, event.GameID, event.Player, models.AchievementRoyalFlush)
+		}
+	}
+	return nil
+}
+
+// unlockAchievement records that the player unlocked the achievement in the given game and emits an
+// achievement_unlocked event in the game's log. Achievements the player already holds are left as they are.
+func (s *GameService) unlockAchievement(ctx context.Context, gameID primitive.ObjectID, playerName, achievement string) error {
+	record := models.PlayerAchievement{
+		ID:          playerName + "/" + achievement,
+		PlayerName:  playerName,
+		Achievement: achievement,
+		GameID:      gameID,
+		UnlockedAt:  time.Now().UTC(),
+	}
+	// The record's ID is fixed by the player and achievement, so a retried insert that already landed is refused
+	// like any other achievement the player holds
+	err := db.Retry(ctx, func() error {
+		_, err := s.achievements.InsertOne(ctx, record)
+		return err
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.recordEvent(ctx, gameID, models.EventAchievement, playerName, map[string]interface{}{"achievement": achievement})
+}
+
+// stringData returns the string stored under key in an event's data, or "" if there is none.
+func stringData(data map[string]interface{}, key string) string {
+	value, _ := data[key].(string)
+	return value
+}
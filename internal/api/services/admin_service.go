@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxAdminPageSize caps how many games a single admin listing returns.
+const maxAdminPageSize = 200
+
+// AdminGameFilter narrows the admin game listing. Empty fields match every game.
+type AdminGameFilter struct {
+	Status string
+	Mode   string
+	Owner  string
+	Limit  int64
+	Offset int64
+	Fields []string // Stored fields to load for each game; empty loads the whole game
+}
+
+// GameStats holds aggregate counts across the whole deployment.
+type GameStats struct {
+	Games         int64            `json:"games"`
+	ByStatus      map[string]int64 `json:"by_status"`
+	ByMode        map[string]int64 `json:"by_mode"`
+	Events        int64            `json:"events"`
+	ArchivedGames int64            `json:"archived_games"`
+	Snapshots     int64            `json:"snapshots"`
+}
+
+// ListAllGames lists every game regardless of owner or privacy, newest first, along with the total number of matches.
+func (s *GameService) ListAllGames(filter AdminGameFilter) ([]models.Game, int64, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	query := bson.M{}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.Mode != "" {
+		query["mode"] = filter.Mode
+	}
+	if filter.Owner != "" {
+		query["owner"] = filter.Owner
+	}
+
+	// Keep pages to a sensible size
+	if filter.Limit <= 0 || filter.Limit > maxAdminPageSize {
+		filter.Limit = maxAdminPageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	total, err := s.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetSkip(filter.Offset).
+		SetLimit(filter.Limit)
+	if len(filter.Fields) > 0 {
+		projection := bson.M{}
+		for _, field := range filter.Fields {
+			projection[field] = 1
+		}
+		opts.SetProjection(projection)
+	}
+	cursor, err := s.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	games := []models.Game{}
+	if err := cursor.All(ctx, &games); err != nil {
+		return nil, 0, err
+	}
+
+	return games, total, nil
+}
+
+// ForceEndGame ends a game immediately, whatever state it is in, and moves it into the archive.
+// A game_force_ended event naming the admin and their reason is recorded before the game is archived.
+func (s *GameService) ForceEndGame(gameID, reason string, viewer models.Viewer) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.Status == models.StatusFinished {
+		return nil, errors.New("game has already finished")
+	}
+	entry := auditGame(models.AuditGameForceEnded, viewer, gameIDObj, "")
+	entry.Reason = reason
+	entry.Before = game.AuditSummary()
+	game.Status = models.StatusFinished
+	entry.After = game.AuditSummary()
+
+	// End the game, record why, and archive it together
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
+			"$set": bson.M{"status": game.Status},
+		})
+		if err != nil {
+			return err
+		}
+
+		data := map[string]interface{}{"by": viewer.PlayerName}
+		if reason != "" {
+			data["reason"] = reason
+		}
+		if err := s.recordEvent(ctx, gameIDObj, models.EventForceEnded, "", data); err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, s.audit, entry); err != nil {
+			return err
+		}
+
+		return s.archiveGame(ctx, gameIDObj)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// ForceDeleteGame removes every trace of a game: the game itself, its events, its snapshots, and any archived copy.
+// Unlike DeleteGame it also succeeds for games that only exist in the archive. Only the audit log entry recording
+// the deletion, and any earlier entries about the game, are kept.
+func (s *GameService) ForceDeleteGame(gameID string, viewer models.Viewer) error {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	// Convert the game ID from a hex string to an ObjectID
+	gameIDObj, err := primitive.ObjectIDFromHex(gameID)
+	if err != nil {
+		return errors.New("invalid game ID")
+	}
+
+	// Summarise the live game for the audit log; archived games are not decoded
+	var game models.Game
+	entry := auditGame(models.AuditGameForceDeleted, viewer, gameIDObj, "")
+	if err := s.collection.FindOne(ctx, bson.M{"_id": gameIDObj}).Decode(&game); err == nil {
+		entry.Before = game.AuditSummary()
+	} else if !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+
+	// Delete from every collection together so nothing is left dangling
+	var deleted int64
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		deleted = 0
+
+		result, err := s.collection.DeleteOne(ctx, bson.M{"_id": gameIDObj})
+		if err != nil {
+			return err
+		}
+		deleted += result.DeletedCount
+
+		result, err = s.archive.DeleteOne(ctx, bson.M{"_id": gameIDObj})
+		if err != nil {
+			return err
+		}
+		deleted += result.DeletedCount
+
+		if _, err := s.events.DeleteMany(ctx, bson.M{"game_id": gameIDObj}); err != nil {
+			return err
+		}
+		if _, err := s.snapshots.DeleteMany(ctx, bson.M{"game_id": gameIDObj}); err != nil {
+			return err
+		}
+
+		// Nothing was deleted, so there is nothing to audit; the caller reports the game as not found
+		if deleted == 0 {
+			return nil
+		}
+		return recordAudit(ctx, s.audit, entry)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Neither the live collection nor the archive held the game
+	if deleted == 0 {
+		return ErrGameNotFound
+	}
+
+	return nil
+}
+
+// GetRawGame returns a game's stored document exactly as it is in the database, without redaction or decoding into the model.
+func (s *GameService) GetRawGame(gameID string) (bson.M, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	// Convert the game ID from a hex string to an ObjectID
+	gameIDObj, err := primitive.ObjectIDFromHex(gameID)
+	if err != nil {
+		return nil, errors.New("invalid game ID")
+	}
+
+	var raw bson.M
+	err = db.Retry(ctx, func() error {
+		return s.collection.FindOne(ctx, bson.M{"_id": gameIDObj}).Decode(&raw)
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrGameNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return raw, nil
+}
+
+// GetGameStats counts the games by status and mode, along with the sizes of the supporting collections.
+func (s *GameService) GetGameStats() (*GameStats, error) {
+	// Create a context with a timeout of 30 seconds since the counts scan whole collections
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stats := &GameStats{ByStatus: map[string]int64{}, ByMode: map[string]int64{}}
+
+	// Count the games grouped by each field in one pass per field
+	for field, counts := range map[string]map[string]int64{"status": stats.ByStatus, "mode": stats.ByMode} {
+		cursor, err := s.collection.Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$" + field}, {Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}}}}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		var groups []struct {
+			Key   string `bson:"_id"`
+			Count int64  `bson:"count"`
+		}
+		if err := cursor.All(ctx, &groups); err != nil {
+			return nil, err
+		}
+		for _, group := range groups {
+			counts[group.Key] += group.Count
+		}
+	}
+	for _, count := range stats.ByStatus {
+		stats.Games += count
+	}
+
+	// Count the supporting collections using their metadata, which is cheap
+	var err error
+	if stats.Events, err = s.events.EstimatedDocumentCount(ctx); err != nil {
+		return nil, err
+	}
+	if stats.ArchivedGames, err = s.archive.EstimatedDocumentCount(ctx); err != nil {
+		return nil, err
+	}
+	if stats.Snapshots, err = s.snapshots.EstimatedDocumentCount(ctx); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// apiKeyPrefix marks generated API keys so they are easy to recognise in logs and config files.
+const apiKeyPrefix = "cgk_"
+
+// APIKeyService provides services related to administrative API keys.
+// Keys are stored hashed in MongoDB; a bootstrap key from the configuration is also accepted
+// so the first managed keys can be created. The keys of every organization are stored together, but each
+// service manages only its own organization's keys.
+type APIKeyService struct {
+	collection    *mongo.Collection
+	audit         *mongo.Collection
+	org           string
+	bootstrapHash string
+}
+
+// NewAPIKeyService creates and returns a new instance of APIKeyService for an organization's keys.
+// An empty bootstrap key disables bootstrap access, leaving only keys stored in the database.
+// The bootstrap key is a platform key, as if it belonged to the default organization.
+func NewAPIKeyService(org, bootstrapKey string) *APIKeyService {
+	service := &APIKeyService{collection: db.GetCollection("api_keys"), audit: db.GetOrgCollection(org, "audit_log"), org: org}
+	if bootstrapKey != "" {
+		service.bootstrapHash = hashToken(bootstrapKey)
+	}
+	return service
+}
+
+// CreateAPIKey generates a new API key of the organization with the given name on behalf of the viewer, recording
+// it in the audit log. The raw key is returned only once; afterwards only its hash is kept.
+func (ks *APIKeyService) CreateAPIKey(name string, viewer models.Viewer) (string, *models.APIKey, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	if name == "" {
+		return "", nil, errors.New("API key name is required")
+	}
+
+	// Generate a random 256-bit key
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, err
+	}
+	key := apiKeyPrefix + hex.EncodeToString(raw)
+
+	// Store the key under its hash
+	apiKey := &models.APIKey{
+		ID:        primitive.NewObjectID(),
+		Name:      name,
+		OrgID:     ks.org,
+		Prefix:    key[:len(apiKeyPrefix)+8],
+		KeyHash:   hashToken(key),
+		CreatedAt: time.Now().UTC(),
+	}
+	err := db.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := ks.collection.InsertOne(ctx, apiKey); err != nil {
+			return err
+		}
+		return recordAudit(ctx, ks.audit, auditAPIKey(models.AuditAPIKeyCreated, viewer, apiKey))
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return key, apiKey, nil
+}
+
+// ListAPIKeys lists the organization's stored API keys, newest first. Key hashes are never returned.
+func (ks *APIKeyService) ListAPIKeys() ([]models.APIKey, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.M{"created_at": -1})
+	cursor, err := ks.collection.Find(ctx, ks.orgFilter(bson.M{}), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []models.APIKey{}
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// DeleteAPIKey revokes the organization's API key with the given ID on behalf of the viewer, recording it in the
+// audit log.
+func (ks *APIKeyService) DeleteAPIKey(id string, viewer models.Viewer) error {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	keyID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid API key ID")
+	}
+
+	return db.WithTransaction(ctx, func(ctx context.Context) error {
+		var apiKey models.APIKey
+		err := ks.collection.FindOneAndDelete(ctx, ks.orgFilter(bson.M{"_id": keyID})).Decode(&apiKey)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return errors.New("API key not found")
+		}
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, ks.audit, auditAPIKey(models.AuditAPIKeyDeleted, viewer, &apiKey))
+	})
+}
+
+// ValidateAPIKey reports whether the given key is the bootstrap key or a stored API key of any organization,
+// and which organization it belongs to, which is empty for platform keys.
+// It implements auth.APIKeyValidator so the service can back the authentication middleware.
+func (ks *APIKeyService) ValidateAPIKey(ctx context.Context, key string) (string, bool) {
+	hash := hashToken(key)
+	if ks.bootstrapHash != "" && subtle.ConstantTimeCompare([]byte(hash), []byte(ks.bootstrapHash)) == 1 {
+		return db.DefaultOrg, true
+	}
+
+	var apiKey models.APIKey
+	opts := options.FindOne().SetProjection(bson.M{"org_id": 1})
+	if err := ks.collection.FindOne(ctx, bson.M{"key_hash": hash}, opts).Decode(&apiKey); err != nil {
+		return "", false
+	}
+	return apiKey.OrgID, true
+}
+
+// orgFilter narrows a filter to the organization's keys. Keys of the default organization are stored without
+// an organization.
+func (ks *APIKeyService) orgFilter(filter bson.M) bson.M {
+	if ks.org == db.DefaultOrg {
+		filter["org_id"] = nil
+	} else {
+		filter["org_id"] = ks.org
+	}
+	return filter
+}
+
+// auditAPIKey builds the audit entry for an action the viewer took on an API key. The key is summarised by
+// its name and prefix, never its hash, as the state after a creation or before a deletion.
+func auditAPIKey(action string, viewer models.Viewer, apiKey *models.APIKey) models.AuditEntry {
+	entry := models.AuditEntry{
+		Action: action,
+		Actor:  models.AuditActor(viewer),
+		Admin:  viewer.Admin,
+		Target: apiKey.ID.Hex(),
+	}
+	summary := map[string]interface{}{"name": apiKey.Name, "prefix": apiKey.Prefix}
+	if action == models.AuditAPIKeyDeleted {
+		entry.Before = summary
+	} else {
+		entry.After = summary
+	}
+	return entry
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// archivePayload is the part of an archived game that is gzipped when archives are compressed.
+type archivePayload struct {
+	Game   *models.Game   `bson:"game"`
+	Events []models.Event `bson:"events"`
+}
+
+// SetArchiveCompression chooses whether finished games are gzipped when they are archived.
+func (s *GameService) SetArchiveCompression(compress bool) {
+	s.compressArchives = compress
+}
+
+// ArchiveFinishedGames archives every finished game still in the games collection and returns how many were moved.
+// Most modes archive a game as soon as it finishes; this catches the rest, along with any archive that failed earlier.
+func (s *GameService) ArchiveFinishedGames() (int, error) {
+	// Create a context with a timeout of 30 seconds since every finished game may need to be moved
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Find the finished games, only pulling their IDs
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := s.collection.Find(ctx, bson.M{"status": models.StatusFinished}, opts)
+	if err != nil {
+		return 0, err
+	}
+	games := []models.Game{}
+	if err := cursor.All(ctx, &games); err != nil {
+		return 0, err
+	}
+
+	archived := 0
+	for _, game := range games {
+		if err := s.archiveGame(ctx, game.ID); err != nil {
+			return archived, err
+		}
+		archived++
+	}
+	return archived, nil
+}
+
+// archiveGame moves a finished game and its event history from the hot collections into the games_archive collection.
+func (s *GameService) archiveGame(ctx context.Context, gameID primitive.ObjectID) error {
+	// Load the game as it was saved when it finished
+	var game models.Game
+	if err := s.collection.FindOne(ctx, bson.M{"_id": gameID}).Decode(&game); err != nil {
+		return ErrGameNotFound
+	}
+
+	// Move the game in a single transaction so it is never lost or left in both places
+	return db.WithTransaction(ctx, func(ctx context.Context) error {
+		// Count the finished game towards its players' career statistics first, so any achievement
+		// it unlocks is part of the archived event history
+		if game.Status == models.StatusFinished {
+			if err := s.recordPlayerStats(ctx, &game, time.Now().UTC()); err != nil {
+				return err
+			}
+		}
+
+		// Load the game's full event history, oldest event first
+		opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+		cursor, err := s.events.Find(ctx, bson.M{"game_id": gameID}, opts)
+		if err != nil {
+			return err
+		}
+		events := []models.Event{}
+		if err := cursor.All(ctx, &events); err != nil {
+			return err
+		}
+
+		archived := models.ArchivedGame{
+			ID:         gameID,
+			ArchivedAt: time.Now().UTC(),
+		}
+		if s.compressArchives {
+			// Store the game and events as gzipped BSON
+			payload, err := compressArchive(archivePayload{Game: &game, Events: events})
+			if err != nil {
+				return err
+			}
+			archived.Compressed = true
+			archived.Payload = payload
+		} else {
+			archived.Game = &game
+			archived.Events = events
+		}
+
+		// Write the archive, replacing any earlier copy
+		_, err = s.archive.ReplaceOne(ctx, bson.M{"_id": gameID}, archived, options.Replace().SetUpsert(true))
+		if err != nil {
+			return err
+		}
+
+		// Remove the game and its events from the hot collections
+		if _, err := s.events.DeleteMany(ctx, bson.M{"game_id": gameID}); err != nil {
+			return err
+		}
+		_, err = s.collection.DeleteOne(ctx, bson.M{"_id": gameID})
+		return err
+	})
+}
+
+// GetArchivedGame retrieves a finished game and its event history from the archive.
+// Compressed archives are expanded before they are returned.
+func (s *GameService) GetArchivedGame(gameID string) (*models.ArchivedGame, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	// Convert the game ID from a hex string to an ObjectID
+	gameIDObj, err := primitive.ObjectIDFromHex(gameID)
+	if err != nil {
+		return nil, errors.New("invalid game ID")
+	}
+
+	var archived models.ArchivedGame
+	if err := s.archive.FindOne(ctx, bson.M{"_id": gameIDObj}).Decode(&archived); err != nil {
+		return nil, errors.New("archived game not found")
+	}
+
+	// Expand the gzipped game and events
+	if archived.Compressed {
+		payload, err := decompressArchive(archived.Payload)
+		if err != nil {
+			return nil, err
+		}
+		archived.Game = payload.Game
+		archived.Events = payload.Events
+		archived.Payload = nil
+	}
+
+	return &archived, nil
+}
+
+// compressArchive encodes an archive payload as gzipped BSON.
+func compressArchive(payload archivePayload) ([]byte, error) {
+	raw, err := bson.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(raw); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompressArchive decodes an archive payload from gzipped BSON.
+func decompressArchive(data []byte) (*archivePayload, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload archivePayload
+	if err := bson.Unmarshal(raw, &payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
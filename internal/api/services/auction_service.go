@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// OpenAuction opens the bidding for the current hand's contract, replacing any closed auction of an earlier hand.
+// The player to the dealer's left calls first. Only the game's owner or an admin can open an auction.
+func (s *GameService) OpenAuction(gameID string, viewer models.Viewer) (*models.Auction, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGameFields(ctx, gameID, "owner", "status", "players", "dealer_index", "auction")
+	if err != nil {
+		return nil, err
+	}
+	if !canManageGame(game, viewer) {
+		return nil, ErrForbidden
+	}
+	if game.Status != models.StatusActive {
+		return nil, errors.New("game is not active")
+	}
+	if game.Auction != nil && !game.Auction.Closed {
+		return nil, errors.New("an auction is already open")
+	}
+	if len(game.Players) < 2 {
+		return nil, ErrNotEnoughPlayers
+	}
+	game.Auction = models.NewAuction(game.Players, (game.DealerIndex+1)%len(game.Players))
+
+	// Save the auction and record its opening together
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{"$set": bson.M{"auction": game.Auction}})
+		if err != nil {
+			return err
+		}
+		return s.recordEvent(ctx, gameIDObj, models.EventAuction, game.Auction.Turn.Player(), nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return game.Auction, nil
+}
+
+// PlaceBid makes a player's call in the game's open auction: a pass, or a bid higher than the last one.
+// The call that ends the auction also settles the contract.
+func (s *GameService) PlaceBid(gameID, playerName string, bid models.Bid) (*models.Auction, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGameFields(ctx, gameID, "status", "players", "teams", "auction", "hand_phase")
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the call is made in turn and outranks the bidding so far
+	bid.Player = playerName
+	bid.PlacedAt = time.Now().UTC()
+	if bid.Pass {
+		bid.Level, bid.Strain = 0, ""
+	}
+	if err := game.CheckAction(models.Action{Type: models.ActionBid, Player: playerName, Bid: bid}); err != nil {
+		return nil, err
+	}
+	game.PlaceBid(bid)
+
+	// Save the call and its events together, only if nobody else has called in the meantime
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		result, err := s.collection.UpdateOne(ctx,
+			bson.M{"_id": gameIDObj, "auction.bids": bson.M{"$size": len(game.Auction.Bids) - 1}},
+			bson.M{"$set": bson.M{"auction": game.Auction}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errors.New("the auction changed while the call was being made; please try again")
+		}
+
+		if err := s.recordEvent(ctx, gameIDObj, models.EventBid, playerName, map[string]interface{}{"bid": bid.String()}); err != nil {
+			return err
+		}
+		if game.Auction.Closed {
+			data := map[string]interface{}{"passed_out": game.Auction.Contract == nil}
+			if contract := game.Auction.Contract; contract != nil {
+				data["level"], data["strain"], data["declarer"] = contract.Level, contract.Strain, contract.Declarer
+			}
+			if err := s.recordEvent(ctx, gameIDObj, models.EventContract, "", data); err != nil {
+				return err
+			}
+		}
+
+		// Bidding counts as activity for the inactivity check
+		return s.touchPlayer(ctx, gameIDObj, playerName)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return game.Auction, nil
+}
+
+// GetAuction returns the state of a game's auction, or ErrNoAuction if none has been opened.
+func (s *GameService) GetAuction(gameID string) (*models.Auction, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, _, err := s.findGameFields(ctx, gameID, "auction")
+	if err != nil {
+		return nil, err
+	}
+	if game.Auction == nil {
+		return nil, ErrNoAuction
+	}
+	return game.Auction, nil
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxAuditPageSize caps how many audit entries a single listing returns.
+const maxAuditPageSize = 200
+
+// AuditService provides read access to the audit log of administrative and destructive actions.
+// The services that take those actions write the entries themselves, in the same transaction as the action.
+type AuditService struct {
+	collection *mongo.Collection
+}
+
+// AuditFilter narrows the audit log listing. Empty fields match every entry.
+type AuditFilter struct {
+	Action string
+	Actor  string
+	GameID string
+	Since  time.Time
+	Until  time.Time
+	Limit  int64
+	Offset int64
+}
+
+// NewAuditService creates and returns a new instance of AuditService for an organization's audit log.
+func NewAuditService(org string) *AuditService {
+	return &AuditService{collection: db.GetOrgCollection(org, "audit_log")}
+}
+
+// ListAuditLog lists the audit entries matching the filter, newest first, along with the total number of matches.
+func (as *AuditService) ListAuditLog(filter AuditFilter) ([]models.AuditEntry, int64, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	query := bson.M{}
+	if filter.Action != "" {
+		query["action"] = filter.Action
+	}
+	if filter.Actor != "" {
+		query["actor"] = filter.Actor
+	}
+	if filter.GameID != "" {
+		gameID, err := primitive.ObjectIDFromHex(filter.GameID)
+		if err != nil {
+			return nil, 0, errors.New("invalid game ID")
+		}
+		query["game_id"] = gameID
+	}
+	createdAt := bson.M{}
+	if !filter.Since.IsZero() {
+		createdAt["$gte"] = filter.Since
+	}
+	if !filter.Until.IsZero() {
+		createdAt["$lt"] = filter.Until
+	}
+	if len(createdAt) > 0 {
+		query["created_at"] = createdAt
+	}
+
+	// Keep pages to a sensible size
+	if filter.Limit <= 0 || filter.Limit > maxAuditPageSize {
+		filter.Limit = maxAuditPageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	total, err := as.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(filter.Offset).
+		SetLimit(filter.Limit)
+	cursor, err := as.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	entries := []models.AuditEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+// recordAudit appends an entry to the audit log, stamping it with a new ID and the current time.
+// Callers pass the context of their transaction so the entry is only kept if the action succeeds.
+func recordAudit(ctx context.Context, collection *mongo.Collection, entry models.AuditEntry) error {
+	entry.ID = primitive.NewObjectID()
+	entry.CreatedAt = time.Now().UTC()
+
+	// The entry has a fixed ID, so retrying an insert that may have landed cannot duplicate it
+	return db.Retry(ctx, func() error {
+		_, err := collection.InsertOne(ctx, entry)
+		if mongo.IsDuplicateKeyError(err) {
+			return nil
+		}
+		return err
+	})
+}
+
+// auditGame builds the audit entry for an action the viewer took on a game.
+func auditGame(action string, viewer models.Viewer, gameID primitive.ObjectID, target string) models.AuditEntry {
+	return models.AuditEntry{
+		Action: action,
+		Actor:  models.AuditActor(viewer),
+		Admin:  viewer.Admin,
+		GameID: &gameID,
+		Target: target,
+	}
+}
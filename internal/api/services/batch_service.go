@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MaxBatchGames is the most games a single batch request may create.
+const MaxBatchGames = 100
+
+// GameSpec describes one game to create in a batch.
+type GameSpec struct {
+	Name string `json:"name" validate:"required,name,max=100"`
+	GameOptions
+}
+
+// BatchResult reports what happened to one game spec in a batch, in the same position as the spec.
+// Exactly one of Game and Error is set.
+type BatchResult struct {
+	Index int          `json:"index"`
+	Game  *models.Game `json:"game,omitempty"`
+	Error string       `json:"error,omitempty"`
+}
+
+// CreateGames creates a game for each spec, all owned by the same player, with a single InsertMany.
+// Invalid specs are reported in their result without stopping the rest of the batch, and the insert is
+// unordered so one failed write does not prevent the others.
+func (s *GameService) CreateGames(specs []GameSpec, owner string) ([]BatchResult, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	if len(specs) == 0 {
+		return nil, errors.New("at least one game is required")
+	}
+	if len(specs) > MaxBatchGames {
+		return nil, fmt.Errorf("at most %d games can be created at once", MaxBatchGames)
+	}
+
+	// Build the games, remembering which result each inserted document belongs to
+	results := make([]BatchResult, len(specs))
+	docs := []interface{}{}
+	positions := []int{}
+	for i, spec := range specs {
+		results[i].Index = i
+		game, err := newGame(spec.Name, owner, spec.GameOptions)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		game.OrgID = s.org
+		results[i].Game = game
+		docs = append(docs, game)
+		positions = append(positions, i)
+	}
+	if len(docs) == 0 {
+		return results, nil
+	}
+
+	// Insert all the valid games at once
+	_, err := s.collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+
+	// Individual write failures are reported against their spec, other failures fail the whole batch
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil {
+		for _, writeErr := range bulkErr.WriteErrors {
+			i := positions[writeErr.Index]
+			results[i].Game = nil
+			results[i].Error = writeErr.Message
+		}
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// PotResult represents the outcome of a single pot at showdown.
+// It includes the pot size, the players eligible to win it, and the chips paid to each winner.
+type PotResult struct {
+	Amount   int            `json:"amount"`
+	Eligible []string       `json:"eligible"`
+	Winners  []string       `json:"winners"`
+	Payouts  map[string]int `json:"payouts"`
+}
+
+// SetPlayerChips sets the chip stack of a player seated in the game.
+// The player must already have joined the game and the amount cannot be negative.
+func (s *GameService) SetPlayerChips(gameID, playerName string, amount int) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	if amount < 0 {
+		return nil, errors.New("chip amount cannot be negative")
+	}
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only seated players can hold chips
+	if !containsPlayer(game.Players, playerName) {
+		return nil, errors.New("player not found in the game")
+	}
+
+	if game.Chips == nil {
+		game.Chips = make(map[string]int)
+	}
+	game.Chips[playerName] = amount
+
+	// Save the change and check the state it leaves the game in, in one transaction. Only the player's own stack is
+	// written, so concurrent changes to other stacks are kept
+	game.Checksum, err = s.saveChecked(ctx, gameIDObj, bson.M{
+		"$set": bson.M{"chips." + playerName: amount},
+	}, actionChipsSet, playerName)
+	if err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// PlaceBet moves chips from a player's stack into the current hand.
+// If the player bets more than they hold, they are put all-in for their remaining stack,
+// which is what later causes side pots to be created at showdown.
+func (s *GameService) PlaceBet(gameID, playerName string, amount int) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate that the player can still bet in this hand
+	if err := game.CheckAction(models.Action{Type: models.ActionBet, Player: playerName, Amount: amount}); err != nil {
+		return nil, err
+	}
+
+	// Cap the bet at the player's stack, which puts them all-in
+	if amount > game.Chips[playerName] {
+		amount = game.Chips[playerName]
+	}
+
+	if game.Bets == nil {
+		game.Bets = make(map[string]int)
+	}
+	game.Chips[playerName] -= amount
+	game.Bets[playerName] += amount
+
+	// Save the change and check the state it leaves the game in, in one transaction
+	game.Checksum, err = s.saveChecked(ctx, gameIDObj, bson.M{
+		"$set": bson.M{"chips": game.Chips, "bets": game.Bets},
+	}, actionBetPlaced, playerName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Acting counts as activity for the inactivity check
+	if err := s.touchPlayer(ctx, gameIDObj, playerName); err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// Fold removes a player from contention for the current hand.
+// Chips the player has already bet stay in the pots, but the player can no longer win them.
+func (s *GameService) Fold(gameID, playerName string) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate that the player is still in this hand
+	if err := game.CheckAction(models.Action{Type: models.ActionFold, Player: playerName}); err != nil {
+		return nil, err
+	}
+	game.Folded = append(game.Folded, playerName)
+
+	// The update is idempotent, so it is safe to retry
+	err = db.Retry(ctx, func() error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
+			"$set": bson.M{"folded": game.Folded},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Acting counts as activity for the inactivity check
+	if err := s.touchPlayer(ctx, gameIDObj, playerName); err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// GetPots returns the main pot and any side pots for the current hand.
+func (s *GameService) GetPots(gameID string) ([]models.Pot, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, _, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewPotManager(game).Pots(), nil
+}
+
+// Showdown resolves the current hand.
+// The committed chips are split into main and side pots, each pot is awarded to the eligible
+// player(s) holding the best hand under the game's scoring strategy, and the winnings are credited to their chip stacks.
+// The betting state is then cleared so the next hand can begin.
+func (s *GameService) Showdown(gameID string) ([]PotResult, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.Mode == models.ModeBlackjack {
+		return nil, errors.New("blackjack bets are settled against the house when the hand is scored")
+	}
+
+	results := settlePots(game)
+	if len(results) == 0 {
+		return nil, errors.New("no bets have been placed")
+	}
+
+	// Save the change and check the state it leaves the game in, in one transaction
+	game.Checksum, err = s.saveChecked(ctx, gameIDObj, bson.M{
+		"$set": bson.M{"chips": game.Chips, "bets": game.Bets, "folded": game.Folded},
+	}, models.EventShowdown, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// settlePots settles the bets of the current hand through the game's payout rules, crediting the winnings to the
+// chip stacks and clearing the betting state, and returns the result of each pot. It returns nothing if no bets
+// were placed.
+func settlePots(game *models.Game) []PotResult {
+	settlement := game.SettleBets()
+	if settlement == nil {
+		return nil
+	}
+
+	results := []PotResult{}
+	for _, line := range settlement.Lines {
+		if line.Kind == models.SettlePot {
+			results = append(results, PotResult{
+				Amount:   line.Stake,
+				Eligible: line.Eligible,
+				Winners:  line.Winners,
+				Payouts:  line.Payouts,
+			})
+		}
+	}
+	return results
+}
+
+// ConfigureBlinds sets the base blind amounts and the optional escalation schedule for a game.
+// Schedule levels must be listed in increasing hand order and every big blind must be at least the small blind.
+func (s *GameService) ConfigureBlinds(gameID string, smallBlind, bigBlind int, schedule []models.BlindLevel) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	// Validate the base blinds and the escalation schedule
+	if smallBlind < 0 || bigBlind < smallBlind {
+		return nil, errors.New("invalid blind amounts")
+	}
+	for i, level := range schedule {
+		if level.SmallBlind < 0 || level.BigBlind < level.SmallBlind {
+			return nil, errors.New("invalid blind amounts in schedule")
+		}
+		if i > 0 && level.FromHand <= schedule[i-1].FromHand {
+			return nil, errors.New("blind schedule must be in increasing hand order")
+		}
+	}
+	if schedule == nil {
+		schedule = []models.BlindLevel{}
+	}
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	game.SmallBlind = smallBlind
+	game.BigBlind = bigBlind
+	game.BlindSchedule = schedule
+
+	// The update is idempotent, so it is safe to retry
+	err = db.Retry(ctx, func() error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
+			"$set": bson.M{"small_blind": smallBlind, "big_blind": bigBlind, "blind_schedule": schedule},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// StartHand begins a new betting hand.
+// The dealer button is rotated to the next player with chips, the blind level is looked up
+// from the escalation schedule, and the small and big blinds are posted automatically.
+func (s *GameService) StartHand(gameID string) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The previous hand must have been resolved before a new one starts
+	for _, amount := range game.Bets {
+		if amount > 0 {
+			return nil, errors.New("current hand has not been resolved")
+		}
+	}
+
+	// At least two players need chips to play a hand
+	playersWithChips := 0
+	for _, player := range game.Players {
+		if game.Chips[player] > 0 {
+			playersWithChips++
+		}
+	}
+	if playersWithChips < 2 {
+		return nil, errors.New("at least two players with chips are required to start a hand")
+	}
+
+	// Move the button and work out who posts the blinds
+	game.HandNumber++
+	game.AdvanceButton()
+	smallSeat, bigSeat := game.BlindSeats()
+	smallBlind, bigBlind := game.CurrentBlinds()
+
+	// Post the blinds, putting short-stacked players all-in
+	game.Bets = map[string]int{}
+	game.Folded = []string{}
+	game.Redraws = nil
+	postBlind(game, game.Players[smallSeat], smallBlind)
+	postBlind(game, game.Players[bigSeat], bigBlind)
+
+	// Save the change and check the state it leaves the game in, in one transaction
+	game.Checksum, err = s.saveChecked(ctx, gameIDObj, bson.M{
+		"$set": bson.M{
+			"hand_number":  game.HandNumber,
+			"dealer_index": game.DealerIndex,
+			"chips":        game.Chips,
+			"bets":         game.Bets,
+			"folded":       game.Folded,
+			"redraws":      game.Redraws,
+		},
+	}, actionHandStarted, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// postBlind moves a forced bet from the player's stack into the current hand.
+// A player who cannot cover the blind is put all-in for whatever they have left.
+func postBlind(game *models.Game, playerName string, amount int) {
+	if amount > game.Chips[playerName] {
+		amount = game.Chips[playerName]
+	}
+	game.Chips[playerName] -= amount
+	game.Bets[playerName] += amount
+}
+
+// containsPlayer reports whether the player name appears in the given list.
+func containsPlayer(players []string, playerName string) bool {
+	for _, player := range players {
+		if player == playerName {
+			return true
+		}
+	}
+	return false
+}
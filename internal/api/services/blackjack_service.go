@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Hit deals a blackjack player another card on their current hand. A hand that goes over 21 busts.
+func (s *GameService) Hit(gameID, playerName string) (*models.Game, error) {
+	return s.playBlackjack(gameID, playerName, models.ActionHit)
+}
+
+// Stand ends play on a blackjack player's current hand.
+func (s *GameService) Stand(gameID, playerName string) (*models.Game, error) {
+	return s.playBlackjack(gameID, playerName, models.ActionStand)
+}
+
+// DoubleDown doubles the bet on a blackjack player's current hand in exchange for exactly one more card.
+func (s *GameService) DoubleDown(gameID, playerName string) (*models.Game, error) {
+	return s.playBlackjack(gameID, playerName, models.ActionDouble)
+}
+
+// Split splits a pair in a blackjack player's current hand into two hands, each played with its own bet.
+func (s *GameService) Split(gameID, playerName string) (*models.Game, error) {
+	return s.playBlackjack(gameID, playerName, models.ActionSplit)
+}
+
+// Surrender gives up a blackjack player's hand as their first decision, for half of its bet back.
+func (s *GameService) Surrender(gameID, playerName string) (*models.Game, error) {
+	return s.playBlackjack(gameID, playerName, models.ActionSurrender)
+}
+
+// playBlackjack makes a blackjack move on the player's current hand through the blackjack rules. Once the player
+// has no hand left to play the turn passes on, and once nobody has, the house plays its hand and the hand reaches
+// its showdown, ready to be scored.
+func (s *GameService) playBlackjack(gameID, playerName, actionType string) (*models.Game, error) {
+	outcome, err := s.MakeMove(gameID, models.Action{Type: actionType, Player: playerName})
+	if err != nil {
+		return nil, err
+	}
+	return outcome.Game, nil
+}
+
+// TakeInsurance stakes a blackjack player's insurance against the house's face-down card making a blackjack.
+// It is offered while the house shows an ace, before the player's first move, for up to half of their bet.
+func (s *GameService) TakeInsurance(gameID, playerName string, amount int) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the stake against the house's up-card and the player's bet
+	if err := game.CheckAction(models.Action{Type: models.ActionInsure, Player: playerName, Amount: amount}); err != nil {
+		return nil, err
+	}
+	game.TakeInsurance(playerName, amount)
+
+	// Save the stake and record it together, only if the player has not insured in the meantime
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		filter := bson.M{"_id": gameIDObj, "insurance." + playerName: bson.M{"$exists": false}}
+		result, err := s.collection.UpdateOne(ctx, filter, bson.M{
+			"$set": bson.M{"chips": game.Chips, "bets": game.Bets, "insurance": game.Insurance},
+		})
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errors.New("player has already taken insurance")
+		}
+
+		data := map[string]interface{}{"amount": amount}
+		if err := s.recordEvent(ctx, gameIDObj, models.EventInsurance, playerName, data); err != nil {
+			return err
+		}
+
+		// Acting counts as activity for the inactivity check
+		return s.touchPlayer(ctx, gameIDObj, playerName)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"my-card-game/internal/entropy"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrNotBot is returned when a bot's configuration is asked for or changed for a player who is not a bot.
+var ErrNotBot = errors.New("player is not a bot")
+
+// AddBot seats a bot under the given name, in the lowest open seat, playing with the given configuration.
+// Only the game's owner or an admin can add bots, and only to games played in one of models.BotModes.
+func (s *GameService) AddBot(gameID, botName string, cfg models.BotConfig, viewer models.Viewer) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGameFields(ctx, gameID, "owner", "mode")
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the game's owner and admins can add bots
+	if !canManageGame(game, viewer) {
+		return nil, ErrForbidden
+	}
+	if err := game.CanSeatBots(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Seat the bot as any player is seated, without the game's password, then mark it as a bot
+	seated, err := s.seatPlayer(gameID, botName, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	// The update is idempotent, so it is safe to retry
+	err = db.Retry(ctx, func() error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
+			"$set": bson.M{"bots." + botName: cfg},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if seated.Bots == nil {
+		seated.Bots = map[string]models.BotConfig{}
+	}
+	seated.Bots[botName] = cfg
+
+	data := map[string]interface{}{"by": viewer.PlayerName, "difficulty": cfg.Difficulty, "aggression": cfg.Aggression, "risk": cfg.Risk}
+	if err := s.recordEvent(ctx, gameIDObj, models.EventBotAdded, botName, data); err != nil {
+		return nil, err
+	}
+
+	return seated, nil
+}
+
+// UpdateBot applies a partial update to how a seated bot plays, taking effect from its next move.
+// Only the game's owner or an admin can change a bot, at any point in the game.
+func (s *GameService) UpdateBot(gameID, botName string, patch models.BotPatch, viewer models.Viewer) (*models.BotConfig, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGameFields(ctx, gameID, "owner", "bots")
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the game's owner and admins can change bots
+	if !canManageGame(game, viewer) {
+		return nil, ErrForbidden
+	}
+	cfg, ok := game.Bots[botName]
+	if !ok {
+		return nil, ErrNotBot
+	}
+	patch.Apply(&cfg)
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Save the configuration and record the change together
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
+			"$set": bson.M{"bots." + botName: cfg},
+		})
+		if err != nil {
+			return err
+		}
+
+		data := map[string]interface{}{"by": viewer.PlayerName, "difficulty": cfg.Difficulty, "aggression": cfg.Aggression, "risk": cfg.Risk}
+		return s.recordEvent(ctx, gameIDObj, models.EventBotUpdated, botName, data)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// PlayBotTurns makes one move for each bot whose turn it is in an active game, and returns the number of moves
+// made. It runs on the scheduler, so bots move at the scheduler's pace and people can follow their moves. Moves
+// are made through the same service methods as people's moves, so they are checked, saved, and recorded alike.
+// A bot with no move to make, such as a Crazy Eights player who can neither play nor draw, waits for the owner
+// to skip its turn.
+func (s *GameService) PlayBotTurns() (int, error) {
+	// Create a context with a timeout of 30 seconds since the job scans every active game with bots
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Find the active games with bots seated, only pulling their IDs
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := s.collection.Find(ctx, bson.M{"status": models.StatusActive, "bots": bson.M{"$exists": true, "$ne": bson.M{}}}, opts)
+	if err != nil {
+		return 0, err
+	}
+	games := []models.Game{}
+	if err := cursor.All(ctx, &games); err != nil {
+		return 0, err
+	}
+
+	moves := 0
+	for _, found := range games {
+		game, _, err := s.findGame(ctx, found.ID.Hex())
+		if err != nil {
+			return moves, err
+		}
+		botName, ok := game.BotToMove()
+		if !ok {
+			continue
+		}
+
+		// Bots draw on a seeded source, since simulation bots play out thousands of games to choose a move and
+		// should not wear out an external source of shuffle randomness
+		seed, err := simulationSeed(nil)
+		if err != nil {
+			return moves, err
+		}
+		action, err := game.ChooseAction(botName, game.Bots[botName], entropy.Seeded(seed))
+		if errors.Is(err, models.ErrNoMove) {
+			continue
+		}
+		if err != nil {
+			return moves, err
+		}
+		if err := s.playBotAction(found.ID.Hex(), action); err != nil {
+			return moves, err
+		}
+		moves++
+	}
+
+	return moves, nil
+}
+
+// playBotAction makes a bot's chosen move through the service method a person's move goes through.
+func (s *GameService) playBotAction(gameID string, action models.Action) error {
+	var err error
+	switch action.Type {
+	case models.ActionPlayCard:
+		_, err = s.PlayCard(gameID, action.Player, action.Cards[0], action.DeclaredSuit)
+	case models.ActionDraw:
+		_, err = s.DrawCard(gameID, action.Player)
+	case models.ActionAsk:
+		_, err = s.AskForValue(gameID, action.Player, action.Target, action.Value)
+	case models.ActionHit:
+		_, err = s.Hit(gameID, action.Player)
+	case models.ActionStand:
+		_, err = s.Stand(gameID, action.Player)
+	case models.ActionDouble:
+		_, err = s.DoubleDown(gameID, action.Player)
+	default:
+		err = errors.New("bots cannot make " + action.Type + " moves")
+	}
+	return err
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// A seated player is connected while they have at least one game stream open. When their last stream closes
+// they are marked as disconnected, but keep their seat and hand, and with them their place in the turn order.
+// If they return within the grace period they resume where they left off; otherwise ForfeitDisconnectedPlayers
+// gives up their seat.
+
+// ConnectPlayer records that a seated player has opened a game stream, resuming their seat if they had
+// disconnected. It returns false if the player has no seat in the game, such as when they are only watching.
+func (s *GameService) ConnectPlayer(gameID, playerName string) (bool, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	_, gameIDObj, err := s.findGameFields(ctx, gameID, "_id")
+	if err != nil {
+		return false, err
+	}
+
+	// Count the new stream and clear any disconnection, keeping the game as it was to tell whether this is a return
+	var before models.Game
+	err = s.collection.FindOneAndUpdate(ctx, bson.M{"_id": gameIDObj, "players": playerName}, bson.M{
+		"$inc":   bson.M{"connections." + playerName: 1},
+		"$set":   bson.M{"last_active." + playerName: time.Now().UTC()},
+		"$unset": bson.M{"disconnected." + playerName: ""},
+		"$pull":  bson.M{"inactive": playerName},
+	}, options.FindOneAndUpdate().SetProjection(bson.M{"disconnected": 1})).Decode(&before)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// Record the return of a player who had dropped out
+	if since, ok := before.Disconnected[playerName]; ok {
+		data := map[string]interface{}{"away_seconds": int(time.Since(since).Seconds())}
+		if err := s.recordEvent(ctx, gameIDObj, models.EventReconnected, playerName, data); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// DisconnectPlayer records that a seated player has closed a game stream.
+// Once their last stream is closed, the player is marked as disconnected and the grace period starts.
+func (s *GameService) DisconnectPlayer(gameID, playerName string) error {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	_, gameIDObj, err := s.findGameFields(ctx, gameID, "_id")
+	if err != nil {
+		return err
+	}
+
+	// Count the closed stream
+	var after models.Game
+	err = s.collection.FindOneAndUpdate(ctx, bson.M{"_id": gameIDObj, "players": playerName}, bson.M{
+		"$inc": bson.M{"connections." + playerName: -1},
+	}, options.FindOneAndUpdate().SetProjection(bson.M{"connections": 1}).SetReturnDocument(options.After)).Decode(&after)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// The player lost their seat while connected, so there is nothing to track
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if after.Connections[playerName] > 0 {
+		return nil
+	}
+
+	// Mark the player as disconnected, unless a new stream was opened in the meantime
+	now := time.Now().UTC()
+	result, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj, "connections." + playerName: bson.M{"$lte": 0}}, bson.M{
+		"$set":   bson.M{"disconnected." + playerName: now},
+		"$unset": bson.M{"connections." + playerName: ""},
+	})
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return nil
+	}
+
+	return s.recordEvent(ctx, gameIDObj, models.EventDisconnected, playerName, nil)
+}
+
+// Heartbeat records that a seated player's client is still there. A heartbeat keeps the player online for
+// the presence timeout and, like acting, clears their inactivity flag and holds off the inactivity check.
+// It returns the game's roster and presence fields.
+func (s *GameService) Heartbeat(gameID, playerName string) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	_, gameIDObj, err := s.findGameFields(ctx, gameID, "_id")
+	if err != nil {
+		return nil, err
+	}
+
+	// The update is idempotent, so it is safe to retry
+	var game models.Game
+	opts := options.FindOneAndUpdate().
+		SetProjection(bson.M{"players": 1, "last_seen": 1, "connections": 1}).
+		SetReturnDocument(options.After)
+	err = db.Retry(ctx, func() error {
+		return s.collection.FindOneAndUpdate(ctx, bson.M{"_id": gameIDObj, "players": playerName}, bson.M{
+			"$set":  bson.M{"last_seen." + playerName: time.Now().UTC()},
+			"$pull": bson.M{"inactive": playerName},
+		}, opts).Decode(&game)
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, errors.New("player not found in the game")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &game, nil
+}
+
+// ResumePlayer returns the game a player already has a seat in, with their hand, so a client that lost its
+// state can pick up where it left off. Callers must have checked that the request comes from the player.
+func (s *GameService) ResumePlayer(gameID, playerName string) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if !containsPlayer(game.Players, playerName) {
+		return nil, errors.New("player not found in the game")
+	}
+
+	// Returning counts as activity for the inactivity check
+	if err := s.touchPlayer(ctx, gameIDObj, playerName); err != nil {
+		return nil, err
+	}
+
+	// A player who comes back through the API rather than a stream has returned too, so stop the grace period
+	if since, ok := game.Disconnected[playerName]; ok {
+		// The update is idempotent, so it is safe to retry
+		err := db.Retry(ctx, func() error {
+			_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{"$unset": bson.M{"disconnected." + playerName: ""}})
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		delete(game.Disconnected, playerName)
+
+		data := map[string]interface{}{"away_seconds": int(time.Since(since).Seconds())}
+		if err := s.recordEvent(ctx, gameIDObj, models.EventReconnected, playerName, data); err != nil {
+			return nil, err
+		}
+	}
+	return game, nil
+}
+
+// ForfeitDisconnectedPlayers gives up the seats of players who have been disconnected for longer than the
+// grace period, returning their hands to the deck as if they had been kicked.
+// It returns the number of players who forfeited.
+func (s *GameService) ForfeitDisconnectedPlayers(grace time.Duration) (int, error) {
+	// Create a context with a timeout of 30 seconds since the check scans every game with a disconnected player
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Load the unfinished games with disconnected players
+	filter := bson.M{
+		"status":       bson.M{"$ne": models.StatusFinished},
+		"disconnected": bson.M{"$exists": true, "$ne": bson.M{}},
+	}
+	cursor, err := s.collection.Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	games := []models.Game{}
+	if err := cursor.All(ctx, &games); err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().UTC().Add(-grace)
+	affected := 0
+	for i := range games {
+		game := &games[i]
+		for player, since := range game.Disconnected {
+			if since.After(cutoff) || !containsPlayer(game.Players, player) {
+				continue
+			}
+
+			// Give up the seat and record why together
+			err := db.WithTransaction(ctx, func(ctx context.Context) error {
+				if err := s.unseatPlayer(ctx, game.ID, game, player); err != nil {
+					return err
+				}
+				data := map[string]interface{}{"disconnected_at": since}
+				return s.recordEvent(ctx, game.ID, models.EventForfeited, player, data)
+			})
+			if err != nil {
+				return affected, err
+			}
+			affected++
+		}
+	}
+
+	return affected, nil
+}
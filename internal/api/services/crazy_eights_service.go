@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// PlayCard plays a card from a player's hand onto the discard pile in a Crazy Eights game.
+// The card must match the suit in play or the value of the top card, or be a wild eight, in which
+// case the player must declare the suit that the next player has to follow. A player who empties
+// their hand wins the game.
+func (s *GameService) PlayCard(gameID, playerName string, card models.Card, declaredSuit string) (*models.Game, error) {
+	play := models.Action{Type: models.ActionPlayCard, Player: playerName, Cards: []models.Card{card}, DeclaredSuit: declaredSuit}
+	outcome, err := s.MakeMove(gameID, play)
+	if err != nil {
+		return nil, err
+	}
+	return outcome.Game, nil
+}
+
+// DrawCard draws the top card of the deck into a player's hand in a Crazy Eights game.
+// Players may only draw when they hold no card that can legally be played on the discard pile.
+func (s *GameService) DrawCard(gameID, playerName string) (*models.Card, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Refill an empty deck from the discard pile if the game allows it, then check the draw: players must play
+	// a card if they are able to, and can only draw while the deck has cards
+	reshuffled := game.ReshuffleDiscards(1)
+	draw := models.Action{Type: models.ActionDraw, Player: playerName}
+	if err := game.CheckAction(draw); err != nil {
+		return nil, err
+	}
+	if err := game.CheckHouseRules(draw); err != nil {
+		return nil, err
+	}
+
+	// Move the top card of the deck into the player's hand
+	drawn := game.DrawFromDeck(playerName, 1)[0]
+
+	// The update is idempotent, so it is safe to retry
+	err = db.Retry(ctx, func() error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
+			"$set": bson.M{"game_deck": game.GameDeck, "player_hands": game.PlayerHands, "discard_pile": game.DiscardPile},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Record any reshuffle, and the draw without revealing the card to other players
+	if reshuffled > 0 {
+		if err := s.recordEvent(ctx, gameIDObj, models.EventReshuffled, "", map[string]interface{}{"cards": reshuffled}); err != nil {
+			return nil, err
+		}
+		if err := s.recordShuffles(ctx, game); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.recordEvent(ctx, gameIDObj, models.EventCardDrawn, playerName, nil); err != nil {
+		return nil, err
+	}
+
+	// Acting counts as activity for the inactivity check
+	if err := s.touchPlayer(ctx, gameIDObj, playerName); err != nil {
+		return nil, err
+	}
+
+	return &drawn, nil
+}
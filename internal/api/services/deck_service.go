@@ -3,11 +3,15 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"my-card-game/internal/api/models"
-	"time"
+	"my-card-game/internal/db"
+	"sort"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // SuitCount represents the count of remaining cards for a specific suit.
@@ -18,10 +22,11 @@ type SuitCount struct {
 }
 
 // CardCount represents the count of remaining cards for a specific suit and value.
-// It includes the suit, value, and the count of cards remaining.
+// It includes the suit, value, two-character card code, and the count of cards remaining.
 type CardCount struct {
 	Suit  string `json:"suit"`
 	Value string `json:"value"`
+	Code  string `json:"code"`
 	Count int    `json:"count"`
 }
 
@@ -40,35 +45,72 @@ func (ds *DeckService) CreateDeck() *models.Deck {
 	return models.NewDeck()
 }
 
-// AddDeckToGame adds a new deck of cards to an existing game's deck.
-// It finds the game by its ID, appends the new deck to the game's deck,
-// and updates the game document in the MongoDB collection.
+// AddDeckToGame adds a new deck of cards to an existing game's deck, along with two jokers if the game plays with them.
+// It finds the game by its ID and appends the new deck to the end of the stored game deck with $push,
+// so the cards already in the deck are not rewritten. A deck that would leave the game holding more copies of a
+// card than it allows is rejected with a models.DeckError.
 func (s *GameService) AddDeckToGame(gameID string, deck *models.Deck) (*models.Game, error) {
-	// Create a context with a timeout of 5 seconds to manage the database operation
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	return s.addCards(gameID, deck.Cards, true)
+}
+
+// AddCustomDeckToGame adds a deck supplied by a client, such as a stripped or pinochle-style deck, to an existing
+// game's deck exactly as given. The deck is checked first and rejected with a models.DeckError listing every
+// problem if it holds cards that are not standard cards or jokers, jokers the game does not play with, or more
+// copies of a card than the game allows.
+func (s *GameService) AddCustomDeckToGame(gameID string, cards []models.Card) (*models.Game, error) {
+	if len(cards) == 0 {
+		return nil, errors.New("a custom deck needs at least one card")
+	}
+	return s.addCards(gameID, cards, false)
+}
+
+// addCards checks cards against the game's deck limits and pushes them onto the end of its deck, adding a pair
+// of jokers first if withJokers is set and the game plays with them.
+func (s *GameService) addCards(gameID string, deck []models.Card, withJokers bool) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
 	defer cancel()
 
-	// Convert the game ID from a hex string to an ObjectID
-	gameIDObj, err := primitive.ObjectIDFromHex(gameID)
+	// Load only the settings, which decide whether jokers are added, and the manifest of cards already added
+	game, gameIDObj, err := s.findGameFields(ctx, gameID, "settings", "card_manifest")
 	if err != nil {
-		// Return an error if the game ID is invalid
-		return nil, errors.New("invalid game ID")
+		return nil, err
 	}
 
-	// Find the game in the MongoDB collection using the provided game ID
-	var game models.Game
-	err = s.collection.FindOne(ctx, bson.M{"_id": gameIDObj}).Decode(&game)
-	if err != nil {
-		// Return an error if the game is not found
-		return nil, errors.New("game not found")
+	// Add two jokers to the new deck if the game plays with them
+	cards := models.CompactCards(deck)
+	if withJokers && game.Settings.Jokers {
+		cards = append(cards, models.Card{Suit: models.SuitJoker, Value: models.RankJoker}, models.Card{Suit: models.SuitJoker, Value: models.RankJoker})
+	}
+	if err := game.CheckCustomDeck(cards); err != nil {
+		return nil, err
 	}
 
-	// Append the new deck to the existing game deck
-	game.GameDeck = append(game.GameDeck, deck.Cards...)
+	// Count the new cards in the manifest, if the game keeps one
+	manifest := bson.M{}
+	for _, code := range cards.Codes() {
+		count, _ := manifest["card_manifest."+code].(int)
+		manifest["card_manifest."+code] = count + 1
+	}
+
+	// Push the new cards onto the end of the stored deck and read back the updated game, together with the manifest
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		filter := bson.M{"_id": gameIDObj, "card_manifest": bson.M{"$type": "object"}}
+		if _, err := s.collection.UpdateOne(ctx, filter, bson.M{"$inc": manifest}); err != nil {
+			return err
+		}
 
-	// Update the game document in the MongoDB collection with the new deck
-	_, err = s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
-		"$set": bson.M{"game_deck": game.GameDeck},
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+		err := s.collection.FindOneAndUpdate(ctx, bson.M{"_id": gameIDObj}, bson.M{
+			"$push": bson.M{"game_deck": bson.M{"$each": cards.Codes()}},
+		}, opts).Decode(game)
+		if err != nil {
+			return err
+		}
+
+		// Check the state the change left the game in and store its checksum
+		game.Checksum, err = s.verifyIntegrity(ctx, gameIDObj, actionCardsAdded, "")
+		return err
 	})
 	if err != nil {
 		// Return an error if the update operation fails
@@ -76,12 +118,14 @@ func (s *GameService) AddDeckToGame(gameID string, deck *models.Deck) (*models.G
 	}
 
 	// Return the updated game object
-	return &game, nil
+	return game, nil
 }
 
 // Shuffle the Deck
-func (s *GameService) ShuffleGameDeck(gameID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// Every card changes position, so the whole deck is written back, as compact card codes.
+// Reshuffling the deck mid-game changes what everyone draws next, so the shuffle is recorded in the audit log.
+func (s *GameService) ShuffleGameDeck(gameID string, viewer models.Viewer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
 	defer cancel()
 
 	gameIDObj, err := primitive.ObjectIDFromHex(gameID)
@@ -92,129 +136,443 @@ func (s *GameService) ShuffleGameDeck(gameID string) error {
 	var game models.Game
 	err = s.collection.FindOne(ctx, bson.M{"_id": gameIDObj}).Decode(&game)
 	if err != nil {
-		return errors.New("game not found")
+		return ErrGameNotFound
 	}
+	game.UseEntropy(s.entropy)
 
 	// Shuffle the game deck
+	entry := auditGame(models.AuditDeckShuffled, viewer, gameIDObj, "")
+	entry.Before = game.AuditSummary()
 	game.ShuffleDeck()
+	entry.After = game.AuditSummary()
 
-	// Update the game state in the database
-	_, err = s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
-		"$set": bson.M{"game_deck": game.GameDeck},
-	})
-	if err != nil {
-		return err
-	}
+	// Update the game state in the database and record the shuffle together
+	return db.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
+			"$set": bson.M{"game_deck": game.GameDeck},
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.recordEvent(ctx, gameIDObj, models.EventDeckShuffled, viewer.PlayerName, nil); err != nil {
+			return err
+		}
+		if err := s.recordShuffles(ctx, &game); err != nil {
+			return err
+		}
 
-	return nil
+		return recordAudit(ctx, s.audit, entry)
+	})
 }
 
-// GetRemainingCardsCountBySuit retrieves the count of remaining cards for each suit in a game.
-// The function returns a list of SuitCount objects, each representing the count of remaining cards for a specific suit.
-func (s *GameService) GetRemainingCardsCountBySuit(gameID string) ([]SuitCount, error) {
-	// Create a context with a timeout of 5 seconds to manage the database operation
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// MaxPeekCount caps how many cards a single peek at the deck can reveal.
+const MaxPeekCount = 52
+
+// PeekDeck returns the next count cards of a game's deck, in the order they will be drawn, without removing them.
+// Only the game's dealer (its owner) and admins can peek, and since a peek reveals what players will draw,
+// every peek is recorded in the audit log.
+func (s *GameService) PeekDeck(gameID string, count int, viewer models.Viewer) ([]models.Card, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
 	defer cancel()
 
-	// Convert the game ID from a hex string to an ObjectID
+	if count < 1 || count > MaxPeekCount {
+		return nil, fmt.Errorf("count must be between 1 and %d", MaxPeekCount)
+	}
 	gameIDObj, err := primitive.ObjectIDFromHex(gameID)
 	if err != nil {
-		// Return an error if the game ID is invalid
 		return nil, errors.New("invalid game ID")
 	}
 
-	// Find the game in the MongoDB collection using the provided game ID
+	// Load only the owner and the top of the deck
 	var game models.Game
-	err = s.collection.FindOne(ctx, bson.M{"_id": gameIDObj}).Decode(&game)
+	opts := options.FindOne().SetProjection(bson.M{"owner": 1, "game_deck": bson.M{"$slice": count}})
+	err = s.collection.FindOne(ctx, bson.M{"_id": gameIDObj}, opts).Decode(&game)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrGameNotFound
+	}
 	if err != nil {
-		// Return an error if the game is not found
-		return nil, errors.New("game not found")
+		return nil, err
+	}
+	if !canManageGame(&game, viewer) {
+		return nil, ErrForbidden
+	}
+
+	// Record who looked, and at how many cards
+	entry := auditGame(models.AuditDeckPeeked, viewer, gameIDObj, "")
+	entry.After = map[string]interface{}{"count": len(game.GameDeck)}
+	if err := recordAudit(ctx, s.audit, entry); err != nil {
+		return nil, err
+	}
+
+	cards := []models.Card(game.GameDeck)
+	if cards == nil {
+		cards = []models.Card{}
 	}
+	return cards, nil
+}
+
+// Orders the remaining-cards listings and hand values can be returned in.
+const (
+	OrderDescending = "desc"
+	OrderAscending  = "asc"
+)
+
+// Groupings of the sorted remaining-cards listing: suit by suit, or one flat listing by value with the suits of
+// each value together.
+const (
+	GroupBySuit = "suit"
+	GroupNone   = "none"
+)
+
+// RemainingCardsQuery filters, orders, and pages the remaining-cards listings.
+// Empty Suits or Values match every card. Aces is "high" or "low" to override where the game's ace mode ranks aces,
+// SuitOrder lists the four suits from highest to lowest to override the game's suit order, Group is GroupBySuit
+// (the default) or GroupNone, and a Limit of zero returns every result after Offset.
+type RemainingCardsQuery struct {
+	Suits     []string
+	Values    []string
+	Order     string
+	Aces      string
+	SuitOrder []string
+	Group     string
+	Limit     int
+	Offset    int
+}
+
+// Validate checks that the query only names real suits and values and uses a supported order and ace ranking.
+func (q RemainingCardsQuery) Validate() error {
+	for _, suit := range q.Suits {
+		if !models.IsValidSuit(suit) && suit != "Joker" {
+			return fmt.Errorf("invalid suit %q", suit)
+		}
+	}
+	for _, value := range q.Values {
+		if !models.IsValidCardValue(value) && value != "Joker" {
+			return fmt.Errorf("invalid card value %q", value)
+		}
+	}
+	if q.Order != "" && q.Order != OrderAscending && q.Order != OrderDescending {
+		return errors.New("order must be asc or desc")
+	}
+	if q.Aces != "" && q.Aces != models.AceHigh && q.Aces != models.AceLow {
+		return errors.New("aces must be high or low")
+	}
+	if err := models.ValidateSuitOrder(q.SuitOrder); err != nil {
+		return err
+	}
+	if q.Group != "" && q.Group != GroupBySuit && q.Group != GroupNone {
+		return errors.New("group must be suit or none")
+	}
+	if q.Limit < 0 || q.Offset < 0 {
+		return errors.New("limit and offset cannot be negative")
+	}
+	return nil
+}
+
+// page returns the start and end indexes of the query's page within n results.
+func (q RemainingCardsQuery) page(n int) (int, int) {
+	start := q.Offset
+	if start > n {
+		start = n
+	}
+	end := n
+	if q.Limit > 0 && start+q.Limit < n {
+		end = start + q.Limit
+	}
+	return start, end
+}
 
-	// Initialize counters for each suit
-	suitCounts := map[string]int{
-		"Hearts":   0,
-		"Diamonds": 0,
-		"Clubs":    0,
-		"Spades":   0,
+// GetRemainingCardsCountBySuit retrieves the count of remaining cards for each suit in a game.
+// The function returns a list of SuitCount objects, each representing the count of remaining cards for a specific suit.
+// Only cards matching the query's filters are counted. The suits are listed in the query's suit order, else from highest
+// to lowest if the game ranks suits, or otherwise in a fixed order (Hearts, Spades, Clubs, Diamonds), then any jokers,
+// unless the query asks for them ordered by count.
+func (s *GameService) GetRemainingCardsCountBySuit(gameID string, query RemainingCardsQuery) ([]SuitCount, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	// Load only the settings, which decide how the suits are listed, not the deck
+	game, gameIDObj, err := s.findGameFields(ctx, gameID, "settings")
+	if err != nil {
+		return nil, err
 	}
 
-	// Count the number of cards left for each suit
-	for _, card := range game.GameDeck {
-		suitCounts[card.Suit]++
+	// Have the database count the matching cards per suit, grouping on the suit letter at the end of each card code
+	suitLetter := bson.M{"$cond": bson.A{
+		bson.M{"$eq": bson.A{"$code", "JK"}},
+		"JK",
+		bson.M{"$substrCP": bson.A{"$code", bson.M{"$subtract": bson.A{bson.M{"$strLenCP": "$code"}, 1}}, 1}},
+	}}
+	pipeline := remainingCardsPipeline(gameIDObj, matchingCodes(allCardCodes(listingSuits(game), models.CardValues), query),
+		bson.D{{Key: "$group", Value: bson.M{"_id": suitLetter, "count": bson.M{"$sum": 1}, "sample": bson.M{"$first": "$code"}}}},
+	)
+	var groups []struct {
+		Count  int    `bson:"count"`
+		Sample string `bson:"sample"` // The code of one card in the group, which names the suit
+	}
+	if err := s.aggregateGames(ctx, pipeline, &groups); err != nil {
+		return nil, err
+	}
+	suitCounts := map[string]int{}
+	for _, group := range groups {
+		card, err := models.ParseCardCode(group.Sample)
+		if err != nil {
+			return nil, err
+		}
+		suitCounts[card.Suit.String()] += group.Count
 	}
 
-	// Convert the map to a slice of SuitCount
+	// Convert the map to a slice of SuitCount, listing every requested suit even when none are left
+	// and jokers only when some remain
+	suitsOrder := listingSuits(game)
+	if len(query.SuitOrder) > 0 {
+		suitsOrder = query.SuitOrder
+	}
 	remainingCounts := []SuitCount{}
-	for suit, count := range suitCounts {
+	for _, suit := range append(append([]string{}, suitsOrder...), "Joker") {
+		requested := len(query.Suits) == 0 || containsString(query.Suits, suit)
+		if !requested || (suit == "Joker" && suitCounts[suit] == 0) {
+			continue
+		}
 		remainingCounts = append(remainingCounts, SuitCount{
 			Suit:  suit,
-			Count: count,
+			Count: suitCounts[suit],
 		})
 	}
 
-	// Return the list of SuitCount objects
-	return remainingCounts, nil
+	// Order the suits by how many cards are left if asked to
+	switch query.Order {
+	case OrderAscending:
+		sort.SliceStable(remainingCounts, func(i, j int) bool { return remainingCounts[i].Count < remainingCounts[j].Count })
+	case OrderDescending:
+		sort.SliceStable(remainingCounts, func(i, j int) bool { return remainingCounts[i].Count > remainingCounts[j].Count })
+	}
+
+	// Return the requested page of SuitCount objects
+	start, end := query.page(len(remainingCounts))
+	return remainingCounts[start:end], nil
 }
 
 // GetRemainingCardsSorted retrieves the count of each card (suit and value) remaining in the game deck,
-// sorted by suit (Hearts, Spades, Clubs, Diamonds) and face value from high value to low value (King, Queen, Jack, etc.).
+// sorted by suit (in the query's suit order, else from highest to lowest if the game ranks suits, or else Hearts,
+// Spades, Clubs, Diamonds) and face value from high value to low value (King, Queen, Jack, etc.).
+// Aces are listed last unless the game's ace scoring mode, or the query, ranks them above kings, and any jokers come after every suit.
+// The query filters the cards, can list them flat by value with the suits of each value together instead of suit by suit,
+// can reverse the order so low cards (and the lowest ranked suit) come first, and pages through the results.
+// Counting, sorting, and paging all happen in the database.
 // The function returns a list of CardCount objects representing the sorted remaining cards.
-func (s *GameService) GetRemainingCardsSorted(gameID string) ([]CardCount, error) {
-	// Create a context with a timeout of 5 seconds to manage the database operation
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (s *GameService) GetRemainingCardsSorted(gameID string, query RemainingCardsQuery) ([]CardCount, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
 	defer cancel()
 
-	// Convert the game ID from a hex string to an ObjectID
-	gameIDObj, err := primitive.ObjectIDFromHex(gameID)
+	// Load only the settings, which decide where aces and suits rank, not the deck
+	game, gameIDObj, err := s.findGameFields(ctx, gameID, "settings")
 	if err != nil {
-		// Return an error if the game ID is invalid
-		return nil, errors.New("invalid game ID")
+		return nil, err
 	}
 
-	// Find the game in the MongoDB collection using the provided game ID
-	var game models.Game
-	err = s.collection.FindOne(ctx, bson.M{"_id": gameIDObj}).Decode(&game)
-	if err != nil {
-		// Return an error if the game is not found
-		return nil, errors.New("game not found")
+	// Define the order of values for sorting
+	valuesOrder := []string{"King", "Queen", "Jack", "10", "9", "8", "7", "6", "5", "4", "3", "2", "Ace"}
+	acesHigh := game.AceRanksHigh()
+	if query.Aces != "" {
+		acesHigh = query.Aces == models.AceHigh
+	}
+	if acesHigh {
+		// Aces rank above kings when the game scores them high
+		valuesOrder = []string{"Ace", "King", "Queen", "Jack", "10", "9", "8", "7", "6", "5", "4", "3", "2"}
+	}
+	suitsOrder, suitsRanked := listingSuits(game), game.RanksSuits()
+	if len(query.SuitOrder) > 0 {
+		// The query's suit order ranks the suits for this listing
+		suitsOrder, suitsRanked = append([]string{}, query.SuitOrder...), true
+	}
+	if query.Order == OrderAscending {
+		// List the values from low to high instead, and ranked suits from the lowest
+		reverseStrings(valuesOrder)
+		if suitsRanked {
+			reverseStrings(suitsOrder)
+		}
 	}
 
-	// Initialize a map to count the cards
-	cardCounts := map[string]map[string]int{
-		"Hearts":   {},
-		"Diamonds": {},
-		"Clubs":    {},
-		"Spades":   {},
+	// Every card code in listing order; the database sorts each card by its position in this list
+	orderedCodes := allCardCodes(suitsOrder, valuesOrder)
+	if query.Group == GroupNone {
+		orderedCodes = cardCodesByValue(suitsOrder, valuesOrder)
 	}
 
-	// Count the remaining cards in the game deck
-	for _, card := range game.GameDeck {
-		cardCounts[card.Suit][card.Value]++
+	// Group the matching cards by code, then sort and page the groups by listing position
+	stages := []bson.D{
+		{{Key: "$group", Value: bson.M{"_id": "$code", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$addFields", Value: bson.M{"rank": bson.M{"$indexOfArray": bson.A{orderedCodes, "$_id"}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "rank", Value: 1}}}},
 	}
+	if query.Offset > 0 {
+		stages = append(stages, bson.D{{Key: "$skip", Value: query.Offset}})
+	}
+	if query.Limit > 0 {
+		stages = append(stages, bson.D{{Key: "$limit", Value: query.Limit}})
+	}
+	pipeline := remainingCardsPipeline(gameIDObj, matchingCodes(orderedCodes, query), stages...)
 
-	// Convert the map to a slice of CardCount and sort it
-	remainingCards := []CardCount{}
-	// Define the order of suits and values for sorting
-	suitsOrder := []string{"Hearts", "Spades", "Clubs", "Diamonds"}
-	valuesOrder := []string{"King", "Queen", "Jack", "10", "9", "8", "7", "6", "5", "4", "3", "2", "Ace"}
+	var groups []struct {
+		Code  string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	if err := s.aggregateGames(ctx, pipeline, &groups); err != nil {
+		return nil, err
+	}
 
-	// Iterate over the suits and values in the specified order
-	for _, suit := range suitsOrder {
-		for _, value := range valuesOrder {
-			// Get the count of the current suit and value
-			count := cardCounts[suit][value]
-			if count > 0 {
-				// Add the suit, value, and count to the remainingCards slice
-				remainingCards = append(remainingCards, CardCount{
-					Suit:  suit,
-					Value: value,
-					Count: count,
-				})
-			}
+	// Convert the sorted groups to a slice of CardCount
+	remainingCards := []CardCount{}
+	for _, group := range groups {
+		card, err := models.ParseCardCode(group.Code)
+		if err != nil {
+			return nil, err
 		}
+		remainingCards = append(remainingCards, CardCount{
+			Suit:  card.Suit.String(),
+			Value: card.Value.String(),
+			Code:  models.CardCode(card),
+			Count: group.Count,
+		})
 	}
 
 	// Return the sorted list of remaining cards
 	return remainingCards, nil
 }
+
+// listingSuits returns the order suits are listed in for a game: its suit order from highest to lowest if it ranks
+// suits, or otherwise Hearts, Spades, Clubs, Diamonds.
+func listingSuits(game *models.Game) []string {
+	if game.RanksSuits() {
+		return append([]string{}, game.Settings.SuitOrder...)
+	}
+	return []string{"Hearts", "Spades", "Clubs", "Diamonds"}
+}
+
+// reverseStrings reverses a slice of strings in place.
+func reverseStrings(s []string) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// allCardCodes lists the code of every card in a standard deck plus the joker, suit by suit in the given order
+// with each suit's values in the given order, and the joker last. Suits and values are named as in the API.
+func allCardCodes(suitsOrder, valuesOrder []string) []string {
+	codes := []string{}
+	for _, suitName := range suitsOrder {
+		suit, _ := models.ParseSuit(suitName)
+		for _, valueName := range valuesOrder {
+			value, _ := models.ParseRank(valueName)
+			codes = append(codes, models.CardCode(models.Card{Suit: suit, Value: value}))
+		}
+	}
+	return append(codes, models.CardCode(models.Card{Suit: models.SuitJoker, Value: models.RankJoker}))
+}
+
+// cardCodesByValue lists the same codes as allCardCodes, but value by value in the given order with each value's
+// suits in the given order, and the joker last.
+func cardCodesByValue(suitsOrder, valuesOrder []string) []string {
+	codes := []string{}
+	for _, valueName := range valuesOrder {
+		value, _ := models.ParseRank(valueName)
+		for _, suitName := range suitsOrder {
+			suit, _ := models.ParseSuit(suitName)
+			codes = append(codes, models.CardCode(models.Card{Suit: suit, Value: value}))
+		}
+	}
+	return append(codes, models.CardCode(models.Card{Suit: models.SuitJoker, Value: models.RankJoker}))
+}
+
+// matchingCodes keeps the codes of the cards that pass the query's suit and value filters.
+func matchingCodes(codes []string, query RemainingCardsQuery) []string {
+	matching := []string{}
+	for _, code := range codes {
+		card, err := models.ParseCardCode(code)
+		if err != nil {
+			continue
+		}
+		if (len(query.Suits) == 0 || containsString(query.Suits, card.Suit.String())) &&
+			(len(query.Values) == 0 || containsString(query.Values, card.Value.String())) {
+			matching = append(matching, code)
+		}
+	}
+	return matching
+}
+
+// remainingCardsPipeline builds an aggregation over one game's deck: it unwinds the deck into one document per card
+// carrying the card's code, keeps only the given codes, and then runs the given stages.
+func remainingCardsPipeline(gameID primitive.ObjectID, codes []string, stages ...bson.D) mongo.Pipeline {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": gameID}}},
+		{{Key: "$project", Value: bson.M{"game_deck": 1}}},
+		{{Key: "$unwind", Value: "$game_deck"}},
+		{{Key: "$project", Value: bson.M{"code": cardCodeExpr("$game_deck")}}},
+		{{Key: "$match", Value: bson.M{"code": bson.M{"$in": codes}}}},
+	}
+	return append(pipeline, stages...)
+}
+
+// cardCodeExpr is an aggregation expression for the code of the card in the given field. Cards are normally stored as
+// codes already, but decks written before codes were used hold {suit, value} documents, which are converted the same
+// way models.CardCode converts standard cards, and older codes may spell the ten "10".
+func cardCodeExpr(field string) bson.M {
+	suit, value := field+".suit", field+".value"
+
+	// The value's code is its first letter for aces and face cards, T for tens, and the number itself otherwise
+	valueCode := bson.M{"$switch": bson.M{
+		"branches": bson.A{
+			bson.M{"case": bson.M{"$eq": bson.A{value, "Ace"}}, "then": "A"},
+			bson.M{"case": bson.M{"$eq": bson.A{value, "10"}}, "then": "T"},
+			bson.M{"case": bson.M{"$eq": bson.A{value, "Jack"}}, "then": "J"},
+			bson.M{"case": bson.M{"$eq": bson.A{value, "Queen"}}, "then": "Q"},
+			bson.M{"case": bson.M{"$eq": bson.A{value, "King"}}, "then": "K"},
+		},
+		"default": value,
+	}}
+
+	// The suit's code is its first letter
+	suitCode := bson.M{"$substrCP": bson.A{suit, 0, 1}}
+
+	// Tens stored before the two-character codes were introduced are written "10" rather than "T"
+	storedCode := bson.M{"$cond": bson.A{
+		bson.M{"$eq": bson.A{bson.M{"$substrCP": bson.A{field, 0, 2}}, "10"}},
+		bson.M{"$concat": bson.A{"T", bson.M{"$substrCP": bson.A{field, 2, 1}}}},
+		field,
+	}}
+
+	return bson.M{"$cond": bson.A{
+		bson.M{"$eq": bson.A{bson.M{"$type": field}, "string"}},
+		storedCode,
+		bson.M{"$cond": bson.A{
+			bson.M{"$eq": bson.A{suit, "Joker"}},
+			"JK",
+			bson.M{"$concat": bson.A{valueCode, suitCode}},
+		}},
+	}}
+}
+
+// aggregateGames runs an aggregation over the games collection and decodes every result, retrying transient failures.
+func (s *GameService) aggregateGames(ctx context.Context, pipeline mongo.Pipeline, results interface{}) error {
+	return db.Retry(ctx, func() error {
+		cursor, err := s.collection.Aggregate(ctx, pipeline)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, results)
+	})
+}
+
+// containsString reports whether the value appears in the given list.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
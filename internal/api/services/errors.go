@@ -0,0 +1,56 @@
+package services
+
+import (
+	"errors"
+	"my-card-game/internal/validate"
+)
+
+// ErrForbidden is returned when the caller is not allowed to see or change the requested resource.
+var ErrForbidden = errors.New("forbidden")
+
+// ErrGameNotFound is returned when the requested game does not exist, or is no longer live.
+var ErrGameNotFound = errors.New("game not found")
+
+// ErrGameFull is returned when a player tries to join a game that already has its maximum number of players.
+var ErrGameFull = errors.New("game is full")
+
+// ErrNotEnoughPlayers is returned when a game is started with fewer players than its minimum.
+var ErrNotEnoughPlayers = errors.New("not enough players to start the game")
+
+// ErrAlreadySeated is returned when a player tries to join a game they already have a seat in.
+var ErrAlreadySeated = errors.New("player already in the game")
+
+// ErrWrongPassword is returned when a player tries to join a password-protected game without its password.
+var ErrWrongPassword = errors.New("wrong game password")
+
+// ErrNameTaken is returned when a player name someone has already claimed is registered or joined with again.
+var ErrNameTaken = errors.New("player name is already taken; sign in to use it")
+
+// ErrBadCredentials is returned when signing in with a player name and password that do not match an account.
+var ErrBadCredentials = errors.New("invalid player name or password")
+
+// ErrNotFriends is returned when a player invites someone who is not their friend to a game.
+var ErrNotFriends = errors.New("players are not friends")
+
+// ErrNoTurnOrder is returned when a game does not keep a turn order, either because it has not started
+// or because its mode is not played in turns.
+var ErrNoTurnOrder = errors.New("game has no turn order")
+
+// ErrUnbalancedTeams is returned when a team game is started with teams that cannot be played,
+// such as teams of different sizes or a seated player left off every team.
+var ErrUnbalancedTeams = errors.New("teams are not balanced")
+
+// ErrInsufficientFunds is returned when a wallet does not hold enough chips for a debit.
+var ErrInsufficientFunds = errors.New("insufficient funds in the wallet")
+
+// ErrNoAuction is returned when a game's auction is asked for before one has been opened.
+var ErrNoAuction = errors.New("no auction has been opened")
+
+// playerNameRules are the rules every player name follows, however it enters the server. Names are used in database
+// field names, such as player_hands.<name>, so they may only hold letters, digits, spaces, - and _.
+const playerNameRules = "required,player,max=32"
+
+// checkPlayerName checks a player name against playerNameRules, returning validate.Errors if it breaks them.
+func checkPlayerName(playerName string) error {
+	return validate.Field("player_name", playerName, playerNameRules)
+}
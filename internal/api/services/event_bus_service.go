@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"my-card-game/internal/eventbus"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// publishBatchSize caps how many events one run of PublishEvents sends to the message bus at once.
+const publishBatchSize = 500
+
+// SetEventPublisher chooses the message bus every recorded event is published to, or turns publishing off if
+// publisher is nil. Events recorded while publishing is on wait in the outbox until PublishEvents sends them.
+func (s *GameService) SetEventPublisher(publisher eventbus.Publisher) {
+	s.publisher = publisher
+}
+
+// publishedEvent is an event as it is published, tagged with the organization it happened in.
+type publishedEvent struct {
+	Org string `json:"org"`
+	models.Event
+}
+
+// PublishEvents sends the events waiting in the outbox to the message bus, oldest first, and returns how many
+// were sent. Events leave the outbox only once the bus has accepted them, so a failed run sends them again on the
+// next one: consumers see every event at least once, and can drop repeats by the event ID.
+func (s *GameService) PublishEvents() (int, error) {
+	if s.publisher == nil {
+		return 0, nil
+	}
+
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	// Load the oldest events waiting to be published
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(publishBatchSize)
+	cursor, err := s.outbox.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return 0, err
+	}
+	events := []models.Event{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	// Publish them keyed by game, so each game's events stay in order on the bus
+	messages := make([]eventbus.Message, len(events))
+	ids := make([]primitive.ObjectID, len(events))
+	for i, event := range events {
+		value, err := json.Marshal(publishedEvent{Org: s.org, Event: event})
+		if err != nil {
+			return 0, err
+		}
+		messages[i] = eventbus.Message{Key: event.GameID.Hex(), Type: event.Type, Value: value}
+		ids[i] = event.ID
+	}
+	if err := s.publisher.Publish(ctx, messages); err != nil {
+		return 0, err
+	}
+
+	// Take the published events out of the outbox
+	// The delete is idempotent, so it is safe to retry
+	if err := db.Retry(ctx, func() error {
+		_, err := s.outbox.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+		return err
+	}); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
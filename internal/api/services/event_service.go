@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// recordEvent appends an event to a game's event log.
+// The event is timestamped and stored in the events collection. Every change to a game records an event in the
+// same transaction, so the game's integrity is verified here too, aborting the change if it breaks an invariant.
+func (s *GameService) recordEvent(ctx context.Context, gameID primitive.ObjectID, eventType, playerName string, data map[string]interface{}) error {
+	event := models.Event{
+		ID:        primitive.NewObjectID(),
+		GameID:    gameID,
+		Type:      eventType,
+		Player:    playerName,
+		Data:      data,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	// The event has a fixed ID, so retrying an insert that may have landed cannot duplicate it
+	err := db.Retry(ctx, func() error {
+		_, err := s.events.InsertOne(ctx, event)
+		if mongo.IsDuplicateKeyError(err) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Queue the event for the message bus in the same transaction, so it is published only if it is recorded
+	if s.publisher != nil {
+		err := db.Retry(ctx, func() error {
+			_, err := s.outbox.InsertOne(ctx, event)
+			if mongo.IsDuplicateKeyError(err) {
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Verify the change the event records left the game in a consistent state
+	if _, err := s.verifyIntegrity(ctx, gameID, eventType, playerName); err != nil {
+		return err
+	}
+
+	// Unlock any achievements the event earns
+	return s.checkAchievements(ctx, event)
+}
+
+// GetEvents retrieves the event log of a game, oldest event first.
+func (s *GameService) GetEvents(gameID string) ([]models.Event, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	// Make sure the game exists before reading its events
+	_, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the game's events in the order they were recorded
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	events := []models.Event{}
+	err = db.Retry(ctx, func() error {
+		cursor, err := s.events.Find(ctx, bson.M{"game_id": gameIDObj}, opts)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, &events)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ExportGame produces a self-contained snapshot of a game with its hands, deck, and full event history.
+func (s *GameService) ExportGame(gameID string) (*models.GameExport, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the game's events in the order they were recorded
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := s.events.Find(ctx, bson.M{"game_id": gameIDObj}, opts)
+	if err != nil {
+		return nil, err
+	}
+	events := []models.Event{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+
+	return &models.GameExport{
+		Version:    models.ExportFormatVersion,
+		ExportedAt: time.Now().UTC(),
+		Game:       *game,
+		Events:     events,
+	}, nil
+}
+
+// ImportGame recreates a game from an export document.
+// The game and its events are given fresh IDs so an import never collides with an existing game. Games whose cards
+// are malformed or held in more copies than a game allows are rejected with a models.DeckError.
+func (s *GameService) ImportGame(export models.GameExport) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	// Check that the document is one this server understands
+	if export.Version != models.ExportFormatVersion {
+		return nil, errors.New("unsupported export version")
+	}
+	game := export.Game
+	if !models.IsValidMode(game.Mode) {
+		return nil, errors.New("invalid game mode")
+	}
+	game.Settings.ApplyDefaults()
+	if err := game.Settings.Validate(); err != nil {
+		return nil, err
+	}
+	if err := game.CheckImportedCards(); err != nil {
+		return nil, err
+	}
+
+	// Refuse a document whose game was changed after it was exported, then checksum the game as imported
+	if err := game.VerifyChecksum(); err != nil {
+		return nil, err
+	}
+	game.UpdateChecksum()
+
+	// Give the game a new identity in the importing organization
+	game.ID = primitive.NewObjectID()
+	game.OrgID = s.org
+	if game.Players == nil {
+		game.Players = []string{}
+	}
+	if game.GameDeck == nil {
+		game.GameDeck = []models.Card{}
+	}
+
+	// Insert the game and its events in a single transaction so a partial failure cannot leave them out of step
+	err := db.WithTransaction(ctx, func(ctx context.Context) error {
+		// Insert the game, then its events re-pointed at the new game
+		if _, err := s.collection.InsertOne(ctx, game); err != nil {
+			return err
+		}
+		if len(export.Events) > 0 {
+			docs := make([]interface{}, 0, len(export.Events))
+			for _, event := range export.Events {
+				event.ID = primitive.NewObjectID()
+				event.GameID = game.ID
+				docs = append(docs, event)
+			}
+			if _, err := s.events.InsertMany(ctx, docs); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &game, nil
+}
@@ -5,46 +5,95 @@ import (
 	"errors"
 	"my-card-game/internal/api/models"
 	"my-card-game/internal/db"
+	"my-card-game/internal/entropy"
+	"my-card-game/internal/eventbus"
 
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // GameService provides services related to game operations.
-// It interacts with the MongoDB collection where game data is stored.
+// It interacts with the MongoDB collections where game data, game events, snapshots, archived games, player statistics, achievements, the audit log, the shuffle log, player notifications, and events waiting to be published are stored.
 type GameService struct {
-	collection *mongo.Collection
+	collection       *mongo.Collection
+	events           *mongo.Collection
+	archive          *mongo.Collection
+	snapshots        *mongo.Collection
+	playerStats      *mongo.Collection
+	achievements     *mongo.Collection
+	audit            *mongo.Collection
+	shuffles         *mongo.Collection
+	notifications    *mongo.Collection
+	outbox           *mongo.Collection
+	org              string
+	compressArchives bool
+	entropy          entropy.Source     // Source the games' decks are shuffled with
+	publisher        eventbus.Publisher // Message bus events are published to; nil when publishing is off
 }
 
-// NewGameService creates and returns a new instance of GameService.
-// It initializes the service with a reference to the MongoDB collection where game data is stored.
-func NewGameService() *GameService {
+// GameOptions holds the optional configuration accepted when a game is created.
+// Empty fields fall back to the defaults of a standard game.
+type GameOptions struct {
+	Mode     string          `json:"mode"`
+	Private  bool            `json:"private"`
+	Password string          `json:"password" validate:"max=72"` // Players must supply it to join; bcrypt uses at most 72 bytes
+	Settings models.Settings `json:"settings"`
+}
+
+// NewGameService creates and returns a new instance of GameService for an organization.
+// It initializes the service with references to the organization's MongoDB collections where game data, events, snapshots, archived games, player statistics, achievements, the audit log, the shuffle log, player notifications, and events waiting to be published are stored.
+// Every game the service loads or creates shuffles its deck with the given entropy source, so each service, and each
+// test, can choose its own.
+func NewGameService(org string, source entropy.Source) *GameService {
 	return &GameService{
-		collection: db.GetCollection("games"),
+		org:           org,
+		entropy:       source,
+		collection:    db.GetOrgCollection(org, "games"),
+		events:        db.GetOrgCollection(org, "events"),
+		archive:       db.GetOrgCollection(org, "games_archive"),
+		snapshots:     db.GetOrgCollection(org, "snapshots"),
+		playerStats:   db.GetOrgCollection(org, "player_stats"),
+		achievements:  db.GetOrgCollection(org, "achievements"),
+		audit:         db.GetOrgCollection(org, "audit_log"),
+		shuffles:      db.GetOrgCollection(org, "shuffle_log"),
+		notifications: db.GetOrgCollection(org, "notifications"),
+		outbox:        db.GetOrgCollection(org, "event_outbox"),
 	}
 }
 
-// CreateGame creates a new game with the given name.
-// It initializes the game with a unique ID, an empty list of players, and an empty game deck.
+// Org returns the organization whose games the service manages.
+func (s *GameService) Org() string {
+	return s.org
+}
+
+// CreateGame creates a new game with the given name, owner, game mode, and settings.
+// The owner is the player creating the game, who acts as its dealer. It initializes the game with a unique ID, an empty list of players, and an empty game deck.
 // The game is then inserted into the MongoDB collection, and the created game is returned.
-func (s *GameService) CreateGame(name string) (*models.Game, error) {
-	// Create a context with a timeout of 5 seconds to manage the database operation
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (s *GameService) CreateGame(name, owner string, opts GameOptions) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
 	defer cancel()
 
-	// Initialize a new game with a unique ID, the provided name, no players, and an empty deck
-	game := &models.Game{
-		ID:       primitive.NewObjectID(),
-		Name:     name,
-		Players:  []string{},
-		GameDeck: []models.Card{}, // Initialize with an empty deck
+	game, err := newGame(name, owner, opts)
+	if err != nil {
+		return nil, err
 	}
+	game.OrgID = s.org
 
-	// Insert the new game into the MongoDB collection
-	_, err := s.collection.InsertOne(ctx, game)
+	// Insert the new game into the MongoDB collection. The game has a fixed ID, so retrying an insert that
+	// may have landed cannot duplicate it
+	err = db.Retry(ctx, func() error {
+		_, err := s.collection.InsertOne(ctx, game)
+		if mongo.IsDuplicateKeyError(err) {
+			return nil
+		}
+		return err
+	})
 	if err != nil {
 		// Return an error if the insertion fails
 		return nil, err
@@ -54,33 +103,242 @@ func (s *GameService) CreateGame(name string) (*models.Game, error) {
 	return game, nil
 }
 
-// DeleteGame deletes an existing game by its ID.
+// newGame validates the options and builds a new game in the lobby, ready to be inserted.
+func newGame(name, owner string, opts GameOptions) (*models.Game, error) {
+	// Validate the game mode, defaulting to a standard game
+	if !models.IsValidMode(opts.Mode) {
+		return nil, errors.New("invalid game mode")
+	}
+	if opts.Mode == "" {
+		opts.Mode = models.ModeStandard
+	}
+
+	// Fill in the default settings and validate the result
+	opts.Settings.ApplyDefaults()
+	if err := opts.Settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Initialize a new game with a unique ID, the provided name, no players, and an empty deck
+	game := &models.Game{
+		ID:           primitive.NewObjectID(),
+		Name:         name,
+		Owner:        owner,
+		Players:      []string{},
+		GameDeck:     []models.Card{},  // Initialize with an empty deck
+		CardManifest: map[string]int{}, // Track every card added from here on
+		Mode:         opts.Mode,
+		Status:       models.StatusLobby,
+		Private:      opts.Private,
+		CreatedAt:    time.Now().UTC(),
+		Settings:     opts.Settings,
+	}
+
+	// Store only a hash of the password, never the password itself
+	if opts.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(opts.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		game.PasswordHash = string(hash)
+		game.PasswordProtected = true
+	}
+	game.UpdateChecksum()
+	return game, nil
+}
+
+// CloneGame creates a new game in the lobby with the same name, mode, settings, privacy, password, and players as an existing game.
+// The new game starts with a freshly shuffled deck built from the settings and no dealt hands, so a group can quickly play again.
+// The caller becomes the owner of the new game, falling back to the original owner.
+func (s *GameService) CloneGame(gameID, owner string) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	original, _, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if owner == "" {
+		owner = original.Owner
+	}
+
+	// Copy the configuration and roster onto a new game with empty hands
+	game := &models.Game{
+		ID:                primitive.NewObjectID(),
+		OrgID:             s.org,
+		Name:              original.Name,
+		Private:           original.Private,
+		PasswordHash:      original.PasswordHash,
+		PasswordProtected: original.PasswordProtected,
+		Settings:          original.Settings,
+		Owner:             owner,
+		Mode:              original.Mode,
+		Status:            models.StatusLobby,
+		CreatedAt:         time.Now().UTC(),
+		Players:           append([]string{}, original.Players...),
+		Reservations:      append([]models.SeatReservation{}, original.Reservations...),
+		PlayerHands:       map[string][]models.Card{},
+		CardManifest:      map[string]int{},
+	}
+
+	// Keep the seats and partnerships for the rematch
+	for player, seat := range original.SeatNumbers {
+		if game.SeatNumbers == nil {
+			game.SeatNumbers = map[string]int{}
+		}
+		game.SeatNumbers[player] = seat
+	}
+	for team, members := range original.Teams {
+		if game.Teams == nil {
+			game.Teams = map[string][]string{}
+		}
+		game.Teams[team] = append([]string{}, members...)
+	}
+
+	// Give the new game a fresh shuffled deck
+	game.UseEntropy(s.entropy)
+	game.GameDeck = []models.Card{}
+	game.AddDeckToGame(game.NewShoe())
+	game.ShuffleDeck()
+	game.UpdateChecksum()
+
+	// Insert the new game into the MongoDB collection, recording and logging its shuffle together
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.collection.InsertOne(ctx, game); err != nil {
+			return err
+		}
+		if err := s.recordEvent(ctx, game.ID, models.EventDeckShuffled, owner, nil); err != nil {
+			return err
+		}
+		return s.recordShuffles(ctx, game)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// GetGame retrieves a game by its ID. If fields are given, only those stored fields are loaded, so callers that
+// need a few fields do not pull the whole deck.
+// If the game is not found or the ID is invalid, an error is returned.
+func (s *GameService) GetGame(id string, fields ...string) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, _, err := s.findGameFields(ctx, id, fields...)
+	return game, err
+}
+
+// DeleteGame deletes an existing game by its ID on behalf of the viewer, recording the deletion in the audit log.
 // The game ID is converted from a hex string to an ObjectID, and the corresponding game is deleted from the collection.
 // If the game is not found or the ID is invalid, an error is returned.
-func (s *GameService) DeleteGame(id string) error {
-	// Create a context with a timeout of 5 seconds to manage the database operation
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (s *GameService) DeleteGame(id string, viewer models.Viewer) error {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
 	defer cancel()
 
+	// Load the game so the audit log can summarise what was deleted
+	game, gameID, err := s.findGame(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// Delete the game and record who deleted it together
+	return db.WithTransaction(ctx, func(ctx context.Context) error {
+		// Attempt to delete the game from the MongoDB collection
+		result, err := s.collection.DeleteOne(ctx, bson.M{"_id": gameID})
+		if err != nil {
+			// Return an error if the deletion fails
+			return err
+		}
+
+		// Check if any document was deleted; if not, return an error indicating the game was not found
+		if result.DeletedCount == 0 {
+			return ErrGameNotFound
+		}
+
+		entry := auditGame(models.AuditGameDeleted, viewer, gameID, "")
+		entry.Before = game.AuditSummary()
+		return recordAudit(ctx, s.audit, entry)
+	})
+}
+
+// findGame loads the game with the given hex ID from the MongoDB collection.
+// It returns the decoded game together with its ObjectID so callers can issue follow-up updates.
+func (s *GameService) findGame(ctx context.Context, gameID string) (*models.Game, primitive.ObjectID, error) {
+	return s.findGameFields(ctx, gameID)
+}
+
+// findGameFields loads only the named top-level fields of a game, leaving the rest of the returned game empty.
+// Read paths use it to avoid pulling large fields such as a multi-deck game_deck they do not need.
+// With no fields it loads the whole game.
+func (s *GameService) findGameFields(ctx context.Context, gameID string, fields ...string) (*models.Game, primitive.ObjectID, error) {
 	// Convert the game ID from a hex string to an ObjectID
-	gameID, err := primitive.ObjectIDFromHex(id)
+	gameIDObj, err := primitive.ObjectIDFromHex(gameID)
 	if err != nil {
 		// Return an error if the game ID is invalid
-		return errors.New("invalid game ID")
+		return nil, primitive.NilObjectID, errors.New("invalid game ID")
 	}
 
-	// Attempt to delete the game from the MongoDB collection
-	result, err := s.collection.DeleteOne(ctx, bson.M{"_id": gameID})
+	// Only ask the server for the requested fields
+	opts := options.FindOne()
+	if len(fields) > 0 {
+		projection := bson.M{}
+		for _, field := range fields {
+			projection[field] = 1
+		}
+		opts.SetProjection(projection)
+	}
+
+	// Find the game in the MongoDB collection using the provided game ID, retrying transient failures
+	var game models.Game
+	err = db.Retry(ctx, func() error {
+		return s.collection.FindOne(ctx, bson.M{"_id": gameIDObj}, opts).Decode(&game)
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// Return an error if the game is not found
+		return nil, primitive.NilObjectID, ErrGameNotFound
+	}
 	if err != nil {
-		// Return an error if the deletion fails
-		return err
+		return nil, primitive.NilObjectID, err
 	}
 
-	// Check if any document was deleted; if not, return an error indicating the game was not found
-	if result.DeletedCount == 0 {
-		return errors.New("game not found")
+	// Shuffle with the service's entropy source
+	game.UseEntropy(s.entropy)
+	return &game, gameIDObj, nil
+}
+
+// GameSummary is a lightweight description of a game, used when listing games.
+type GameSummary struct {
+	ID     primitive.ObjectID `bson:"_id" json:"id"`
+	Name   string             `bson:"name" json:"name"`
+	Mode   string             `bson:"mode" json:"mode"`
+	Status string             `bson:"status" json:"status"`
+}
+
+// GamesForPlayer lists the games in which the given player is currently seated.
+// Only the summary fields are loaded from the database.
+func (s *GameService) GamesForPlayer(playerName string) ([]GameSummary, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	// Find the games listing the player, loading only the summary fields
+	opts := options.Find().SetProjection(bson.M{"name": 1, "mode": 1, "status": 1})
+	games := []GameSummary{}
+	err := db.Retry(ctx, func() error {
+		cursor, err := s.collection.Find(ctx, bson.M{"players": playerName}, opts)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, &games)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Return nil if the deletion was successful
-	return nil
+	return games, nil
 }
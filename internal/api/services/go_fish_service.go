@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// AskResult represents the outcome of a Go Fish ask.
+// It records how many cards were handed over, whether the asker had to go fish (and what they drew),
+// any books completed as a result, and whether the game is now over.
+type AskResult struct {
+	Asker       string       `json:"asker"`
+	Target      string       `json:"target"`
+	Value       string       `json:"value"`
+	Received    int          `json:"received"`
+	WentFishing bool         `json:"went_fishing"`
+	Drew        *models.Card `json:"drew,omitempty"`
+	Lucky       bool         `json:"lucky"`
+	Books       []string     `json:"books"`
+	GameOver    bool         `json:"game_over"`
+	Winner      string       `json:"winner,omitempty"`
+	WinningTeam string       `json:"winning_team,omitempty"`
+}
+
+// PlayerBooks represents the books a player has completed in Go Fish.
+type PlayerBooks struct {
+	PlayerName string   `json:"player_name"`
+	Books      []string `json:"books"`
+	Count      int      `json:"count"`
+}
+
+// AskForValue lets a player ask another player for all of their cards of a given value in Go Fish.
+// If the target holds any, they are transferred to the asker; otherwise the asker goes fishing and
+// draws from the deck. Completed books are collected, and the game ends once the deck and all hands
+// are empty, with the player holding the most books declared the winner.
+func (s *GameService) AskForValue(gameID, asker, target, value string) (*AskResult, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the ask against the rules of Go Fish and any house rules
+	action := models.Action{Type: models.ActionAsk, Player: asker, Target: target, Value: value}
+	if err := game.CheckAction(action); err != nil {
+		return nil, err
+	}
+	if err := game.CheckHouseRules(action); err != nil {
+		return nil, err
+	}
+
+	// Make the ask, collecting books and passing the turn on as the cards fall
+	ask := game.Ask(asker, target, value)
+	result := &AskResult{
+		Asker:       asker,
+		Target:      target,
+		Value:       value,
+		Received:    ask.Received,
+		WentFishing: ask.Received == 0,
+		Drew:        ask.Drew,
+		Lucky:       ask.Lucky,
+		Books:       ask.Books,
+		GameOver:    ask.GameOver,
+	}
+	if ask.GameOver {
+		result.Winner = game.Winner
+		result.WinningTeam = game.WinningTeam
+	}
+
+	// Save the ask, its events, and any archive move together
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
+			"$set": bson.M{
+				"game_deck":    game.GameDeck,
+				"player_hands": game.PlayerHands,
+				"books":        game.Books,
+				"status":       game.Status,
+				"winner":       game.Winner,
+				"winning_team": game.WinningTeam,
+				"turn":         game.Turn,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		// Record the ask and its consequences
+		err = s.recordEvent(ctx, gameIDObj, models.EventAsk, asker, map[string]interface{}{
+			"target":       target,
+			"value":        value,
+			"received":     result.Received,
+			"went_fishing": result.WentFishing,
+		})
+		if err != nil {
+			return err
+		}
+		for _, book := range result.Books {
+			if err := s.recordEvent(ctx, gameIDObj, models.EventBook, asker, map[string]interface{}{"value": book}); err != nil {
+				return err
+			}
+		}
+		if result.GameOver {
+			if err := s.recordEvent(ctx, gameIDObj, models.EventGameOver, game.Winner, nil); err != nil {
+				return err
+			}
+		}
+
+		// Acting counts as activity for the inactivity check
+		if err := s.touchPlayer(ctx, gameIDObj, asker); err != nil {
+			return err
+		}
+
+		// Move the finished game into the archive
+		if game.Status == models.StatusFinished {
+			if err := s.archiveGame(ctx, gameIDObj); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetBooks retrieves the books completed by each player in a Go Fish game, most books first.
+func (s *GameService) GetBooks(gameID string) ([]PlayerBooks, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, _, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.Mode != models.ModeGoFish {
+		return nil, errors.New("books are only kept in go fish games")
+	}
+
+	// List each player's books
+	books := []PlayerBooks{}
+	for _, player := range game.Players {
+		playerBooks := game.Books[player]
+		if playerBooks == nil {
+			playerBooks = []string{}
+		}
+		books = append(books, PlayerBooks{
+			PlayerName: player,
+			Books:      playerBooks,
+			Count:      len(playerBooks),
+		})
+	}
+
+	// Sort the players by number of books in descending order
+	sort.SliceStable(books, func(i, j int) bool {
+		return books[i].Count > books[j].Count
+	})
+
+	return books, nil
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// HandState describes where a game is in its cycle of hands: the hand being played, its phase and dealer,
+// and the summaries of the hands already scored.
+type HandState struct {
+	Number    int                  `json:"number"`
+	Phase     string               `json:"phase,omitempty"`
+	Dealer    string               `json:"dealer,omitempty"`
+	StartedAt *time.Time           `json:"started_at,omitempty"`
+	History   []models.HandSummary `json:"history"`
+}
+
+// DealNextHand clears the table of the previous hand and deals the next one into play.
+// Only the game's owner or an admin can deal, and only once the previous hand has been scored.
+func (s *GameService) DealNextHand(gameID string, viewer models.Viewer) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageGame(game, viewer) {
+		return nil, ErrForbidden
+	}
+	if game.Status != models.StatusActive {
+		return nil, errors.New("game is not active")
+	}
+	if err := game.DealHand(); err != nil {
+		return nil, err
+	}
+
+	// Save the new hand and record the deal together
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
+			"$set": bson.M{
+				"game_deck":       game.GameDeck,
+				"player_hands":    game.PlayerHands,
+				"melds":           game.Melds,
+				"discard_pile":    game.DiscardPile,
+				"declared_suit":   game.DeclaredSuit,
+				"bets":            game.Bets,
+				"folded":          game.Folded,
+				"redraws":         game.Redraws,
+				"hands":           game.Hands,
+				"dealer_hand":     game.DealerHand,
+				"insurance":       game.Insurance,
+				"books":           game.Books,
+				"turn":            game.Turn,
+				"auction":         game.Auction,
+				"dealer_index":    game.DealerIndex,
+				"hand_number":     game.HandNumber,
+				"hand_phase":      game.HandPhase,
+				"hand_started_at": game.HandStartedAt,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		data := map[string]interface{}{"hand": game.HandNumber}
+		if err := s.recordEvent(ctx, gameIDObj, models.EventHandDealt, game.Dealer(), data); err != nil {
+			return err
+		}
+		return s.recordShuffles(ctx, game)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// ShowdownHand ends play in the current hand. No further actions can be taken in the hand until it is scored
+// and the next one dealt. Only the game's owner or an admin can call the showdown.
+func (s *GameService) ShowdownHand(gameID string, viewer models.Viewer) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageGame(game, viewer) {
+		return nil, ErrForbidden
+	}
+	if game.HandPhase != models.HandPhasePlay {
+		return nil, errors.New("no hand is being played")
+	}
+	game.HandPhase = models.HandPhaseShowdown
+
+	// Only move a hand that is still in play, so a showdown is never called twice
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		result, err := s.collection.UpdateOne(ctx,
+			bson.M{"_id": gameIDObj, "hand_phase": models.HandPhasePlay},
+			bson.M{"$set": bson.M{"hand_phase": game.HandPhase}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errors.New("no hand is being played")
+		}
+
+		return s.recordEvent(ctx, gameIDObj, models.EventShowdown, "", map[string]interface{}{"hand": game.HandNumber})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// ScoreHand scores the current hand after its showdown. Every player's hand is valued, the best hands win,
+// and the bets are settled by the payout rules, with a detailed settlement record; the result is kept in the game's hand history. Only the game's owner or an admin
+// can score a hand.
+func (s *GameService) ScoreHand(gameID string, viewer models.Viewer) (*models.HandSummary, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageGame(game, viewer) {
+		return nil, ErrForbidden
+	}
+	if game.HandPhase != models.HandPhaseShowdown {
+		return nil, errors.New("the hand must reach its showdown before it is scored")
+	}
+
+	// Value the hands before the pots are settled, since settling clears the folds
+	values, winners := game.HandValues()
+	summary := models.HandSummary{
+		Number:    game.HandNumber,
+		Dealer:    game.Dealer(),
+		StartedAt: game.HandStartedAt,
+		ScoredAt:  time.Now().UTC(),
+		Values:    values,
+		Winners:   winners,
+	}
+	if game.Auction != nil {
+		summary.Contract = game.Auction.Contract
+	}
+	if settlement := game.SettleBets(); settlement != nil {
+		summary.Payouts = settlement.Paid
+		summary.Settlement = settlement
+	}
+	if game.Mode == models.ModeBlackjack {
+		// Keep the settled hands, now marked with their outcomes, along with the house's hand
+		summary.Hands = game.Hands
+		summary.HouseHand = game.DealerHand
+		summary.Insurance = game.Insurance
+	}
+	game.HandHistory = append(game.HandHistory, summary)
+	game.HandPhase = models.HandPhaseScored
+
+	// Save the score and record it together, only if the hand has not been scored in the meantime
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		result, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj, "hand_phase": models.HandPhaseShowdown}, bson.M{
+			"$set": bson.M{
+				"hand_phase":   game.HandPhase,
+				"hand_history": game.HandHistory,
+				"chips":        game.Chips,
+				"bets":         game.Bets,
+				"folded":       game.Folded,
+				"hands":        game.Hands,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errors.New("the hand has already been scored")
+		}
+
+		data := map[string]interface{}{"hand": summary.Number, "winners": summary.Winners}
+		return s.recordEvent(ctx, gameIDObj, models.EventHandScored, "", data)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
+
+// GetHands returns the state of the current hand and the summaries of the hands already scored.
+func (s *GameService) GetHands(gameID string) (*HandState, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, _, err := s.findGameFields(ctx, gameID, "players", "dealer_index", "hand_number", "hand_phase", "hand_started_at", "hand_history")
+	if err != nil {
+		return nil, err
+	}
+
+	state := &HandState{
+		Number:  game.HandNumber,
+		Phase:   game.HandPhase,
+		History: game.HandHistory,
+	}
+	if game.HandPhase != "" {
+		state.Dealer = game.Dealer()
+		state.StartedAt = &game.HandStartedAt
+	}
+	if state.History == nil {
+		state.History = []models.HandSummary{}
+	}
+	return state, nil
+}
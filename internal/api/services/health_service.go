@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"my-card-game/internal/db"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// HealthCheck is the outcome of one readiness check.
+type HealthCheck struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// HealthService checks whether the server's dependencies are usable.
+type HealthService struct{}
+
+// NewHealthService creates and returns a new instance of HealthService.
+func NewHealthService() *HealthService {
+	return &HealthService{}
+}
+
+// readinessTimeout bounds the readiness checks so a probe never hangs while the database is unreachable.
+const readinessTimeout = 2 * time.Second
+
+// Readiness runs every readiness check: a ping of MongoDB and a read from the games collection.
+// It reports whether all checks passed along with the result of each one.
+func (s *HealthService) Readiness() (bool, []HealthCheck) {
+	ctx, cancel := context.WithTimeout(context.Background(), readinessTimeout)
+	defer cancel()
+
+	checks := []HealthCheck{
+		runCheck("mongo_ping", func() error { return db.Ping(ctx) }),
+		runCheck("games_collection", func() error {
+			// A bounded count proves the collection can be read without scanning it
+			_, err := db.GetCollection("games").CountDocuments(ctx, bson.M{}, options.Count().SetLimit(1))
+			return err
+		}),
+	}
+
+	ready := true
+	for _, check := range checks {
+		ready = ready && check.OK
+	}
+	return ready, checks
+}
+
+// runCheck runs a single readiness check and records its outcome.
+func runCheck(name string, check func() error) HealthCheck {
+	if err := check(); err != nil {
+		return HealthCheck{Name: name, Error: err.Error()}
+	}
+	return HealthCheck{Name: name, OK: true}
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"my-card-game/internal/entropy"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// GetHint suggests the viewer's next move in a game they are seated in, from the game mode's strategy: basic
+// strategy in blackjack, and the heuristic bots follow in Crazy Eights and Go Fish. The hint is recorded in the
+// event log and the player is marked as hinted in the game, so their statistics can tell assisted games apart.
+// It returns ErrForbidden unless the viewer is seated, and models.ErrNoMove if the game gives them no move.
+func (s *GameService) GetHint(gameID string, viewer models.Viewer) (*models.Hint, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hints are for the players themselves, and bots play on their own
+	player := viewer.PlayerName
+	if !containsPlayer(game.Players, player) || game.IsBot(player) {
+		return nil, ErrForbidden
+	}
+
+	// Hints draw on a seeded source, as bots do, rather than on the source of shuffle randomness
+	seed, err := simulationSeed(nil)
+	if err != nil {
+		return nil, err
+	}
+	hint, err := game.SuggestMove(player, entropy.Seeded(seed))
+	if err != nil {
+		return nil, err
+	}
+
+	// Mark the player as hinted and record the hint together
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
+			"$addToSet": bson.M{"hinted": player},
+		})
+		if err != nil {
+			return err
+		}
+
+		data := map[string]interface{}{"action": hint.Action, "strategy": hint.Strategy}
+		return s.recordEvent(ctx, gameIDObj, models.EventHint, player, data)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &hint, nil
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// integrityFields are the parts of a game its integrity check reads.
+var integrityFields = []string{
+	"card_manifest", "game_deck", "player_hands", "dealer_hand", "discard_pile", "melds", "books",
+	"chips", "bets", "insurance",
+}
+
+// Changes that record no event are named by these in integrity alerts.
+const (
+	actionChipsSet       = "chips_set"
+	actionBetPlaced      = "bet_placed"
+	actionHandStarted    = "hand_started"
+	actionCardsAdded     = "cards_added"
+	actionPlayerSeated   = "player_seated"
+	actionPlayerRemoved  = "player_removed"
+	actionMeldDeclared   = "meld_declared"
+	actionLaidOff        = "cards_laid_off"
+	actionSeatChanged    = "seat_changed"
+	actionWaitlistSeated = "waitlist_seated"
+)
+
+// verifyIntegrity reads a game back within the caller's transaction, seeing the change just made to it, and checks
+// its invariants. A game that breaks them raises an alert and returns an IntegrityError, which aborts the
+// transaction so the change is never saved. A game that keeps them has the checksum of its new state stored with
+// it, which is returned. A game deleted by the change has nothing left to check.
+func (s *GameService) verifyIntegrity(ctx context.Context, gameID primitive.ObjectID, eventType, playerName string) (string, error) {
+	projection := bson.M{}
+	for _, field := range integrityFields {
+		projection[field] = 1
+	}
+	for _, field := range models.ChecksumFields {
+		projection[field] = 1
+	}
+
+	var game models.Game
+	err := s.collection.FindOne(ctx, bson.M{"_id": gameID}, options.FindOne().SetProjection(projection)).Decode(&game)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var violation *models.IntegrityError
+	if err := game.CheckIntegrity(); errors.As(err, &violation) {
+		s.raiseIntegrityAlert(gameID, eventType, playerName, violation)
+		return "", err
+	}
+
+	// Store the checksum of the state the change left the game in
+	checksum := game.StateChecksum()
+	// The update is idempotent, so it is safe to retry
+	if err := db.Retry(ctx, func() error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameID}, bson.M{"$set": bson.M{"checksum": checksum}})
+		return err
+	}); err != nil {
+		return "", err
+	}
+	return checksum, nil
+}
+
+// saveChecked applies an update to a game and checks the state it leaves the game in, in one transaction, so a
+// change that breaks the game's invariants is never saved and the checksum stored with the game always matches the
+// state it was computed from. It returns the checksum of the game's new state. Changes that record an event are
+// checked by recordEvent instead.
+func (s *GameService) saveChecked(ctx context.Context, gameID primitive.ObjectID, update bson.M, action, playerName string) (string, error) {
+	var checksum string
+	err := db.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameID}, update); err != nil {
+			return err
+		}
+		var err error
+		checksum, err = s.verifyIntegrity(ctx, gameID, action, playerName)
+		return err
+	})
+	return checksum, err
+}
+
+// raiseIntegrityAlert logs a broken invariant and records it in the audit log for admins to investigate.
+// The entry is written outside the aborted transaction so it is kept.
+func (s *GameService) raiseIntegrityAlert(gameID primitive.ObjectID, eventType, playerName string, violation *models.IntegrityError) {
+	log.Printf("ALERT: game %s failed its integrity check on %s by %q: %v", gameID.Hex(), eventType, playerName, violation)
+
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	entry := models.AuditEntry{
+		Action: models.AuditIntegrity,
+		Actor:  playerName,
+		GameID: &gameID,
+		Reason: eventType,
+		After:  map[string]interface{}{"violations": violation.Violations},
+	}
+	if err := recordAudit(ctx, s.audit, entry); err != nil {
+		log.Printf("could not record the integrity alert for game %s: %v", gameID.Hex(), err)
+	}
+}
+
+// GetChecksum compares the checksum stored with a game against the checksum of its state as it is now, so clients
+// can check their copy of the game and audits can catch changes made outside the server.
+func (s *GameService) GetChecksum(gameID string) (*models.ChecksumReport, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, _, err := s.findGameFields(ctx, gameID, append([]string{"checksum"}, models.ChecksumFields...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ChecksumReport{Checksum: game.Checksum, Computed: game.StateChecksum(), Missing: game.Checksum == ""}
+	report.Match = report.Checksum == report.Computed
+	return report, nil
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Limits of the job queue.
+const (
+	maxJobAttempts    = 3       // Times a job is started before a job that keeps getting interrupted is failed
+	maxJobResultBytes = 8 << 20 // Largest result kept, well inside MongoDB's document size limit
+	jobsPerRun        = 10      // Most jobs one run of RunQueuedJobs works through
+	jobListLimit      = 50      // How many of the most recent jobs are listed
+)
+
+// ErrJobNotFound is returned when a job does not exist, or is not one the caller may read.
+var ErrJobNotFound = errors.New("job not found")
+
+// JobService provides a queue for long-running work, such as exporting archived games, large simulations, and
+// rebuilding player statistics. Requests submit a job and return at once with its ID, and background workers run
+// the jobs in the order they were submitted, keeping each job's status and result for the caller to poll.
+// It interacts with the MongoDB collection where the jobs are stored, and runs the work through the GameService.
+type JobService struct {
+	jobs    *mongo.Collection
+	games   *GameService
+	timeout time.Duration // How long a job may run before another worker may take it over
+}
+
+// NewJobService creates and returns a new instance of JobService.
+// It initializes the service with a reference to the MongoDB collection where jobs are stored, in the organization
+// of the game service. Each job may run for timeout before it is considered interrupted and is run again.
+func NewJobService(games *GameService, timeout time.Duration) *JobService {
+	return &JobService{
+		jobs:    db.GetOrgCollection(games.Org(), "jobs"),
+		games:   games,
+		timeout: timeout,
+	}
+}
+
+// SubmitJob queues a job of the given kind and returns it. Exports and statistics rebuilds are for admins only;
+// simulations can be submitted by any player, and fail when they run if the player may not see every hand.
+func (js *JobService) SubmitJob(kind string, params models.JobParams, viewer models.Viewer) (*models.Job, error) {
+	// Check the job before it is queued, so mistakes are reported at once
+	switch kind {
+	case models.JobExportGame, models.JobRebuildPlayerStats:
+		if !viewer.Admin {
+			return nil, ErrForbidden
+		}
+	case models.JobSimulateGame:
+		if viewer.PlayerName == "" && !viewer.Admin {
+			return nil, ErrForbidden
+		}
+		if params.Runs < 0 || params.Runs > MaxSimulationRuns {
+			return nil, errRunsOutOfRange
+		}
+	default:
+		return nil, fmt.Errorf("unknown job kind %q", kind)
+	}
+	if kind != models.JobRebuildPlayerStats {
+		if _, err := primitive.ObjectIDFromHex(params.GameID); err != nil {
+			return nil, errors.New("invalid game ID")
+		}
+	}
+
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	job := &models.Job{
+		ID:        primitive.NewObjectID(),
+		Kind:      kind,
+		Params:    params,
+		Status:    models.JobQueued,
+		Owner:     viewer.PlayerName,
+		Viewer:    viewer,
+		CreatedAt: time.Now().UTC(),
+	}
+	// The job has a fixed ID, so retrying an insert that may have landed cannot duplicate it
+	err := db.Retry(ctx, func() error {
+		_, err := js.jobs.InsertOne(ctx, job)
+		if mongo.IsDuplicateKeyError(err) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// GetJob retrieves a job's status, and its result once it has succeeded. Players can only read the jobs they
+// submitted; admins can read every job. Anyone else is told the job does not exist.
+func (js *JobService) GetJob(jobID string, viewer models.Viewer) (*models.Job, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	// Convert the job ID from a hex string to an ObjectID
+	jobIDObj, err := primitive.ObjectIDFromHex(jobID)
+	if err != nil {
+		return nil, errors.New("invalid job ID")
+	}
+
+	var job models.Job
+	err = js.jobs.FindOne(ctx, bson.M{"_id": jobIDObj}).Decode(&job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !viewer.Admin && (job.Owner == "" || job.Owner != viewer.PlayerName) {
+		return nil, ErrJobNotFound
+	}
+	return &job, nil
+}
+
+// ListJobs lists the caller's most recent jobs, newest first, leaving their results out; admins see everyone's.
+func (js *JobService) ListJobs(viewer models.Viewer) ([]models.Job, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	filter := bson.M{}
+	if !viewer.Admin {
+		if viewer.PlayerName == "" {
+			return []models.Job{}, nil
+		}
+		filter["owner"] = viewer.PlayerName
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(jobListLimit).
+		SetProjection(bson.M{"result": 0})
+	cursor, err := js.jobs.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	jobs := []models.Job{}
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// RunQueuedJobs works through the queue, oldest job first, and returns how many jobs it ran. Each job is claimed
+// before it runs, so workers on different replicas never run the same job at once. A job whose worker stopped
+// before finishing it is run again once its lease lapses, and failed after maxJobAttempts starts. A job that
+// fails is not retried; its error is kept for the caller.
+func (js *JobService) RunQueuedJobs() (int, error) {
+	if err := js.failAbandonedJobs(); err != nil {
+		return 0, err
+	}
+
+	ran := 0
+	for ran < jobsPerRun {
+		job, err := js.claimJob()
+		if err != nil || job == nil {
+			return ran, err
+		}
+		result, runErr := js.runJob(job)
+		if err := js.finishJob(job, result, runErr); err != nil {
+			return ran, err
+		}
+		ran++
+	}
+	return ran, nil
+}
+
+// failAbandonedJobs fails the jobs whose workers kept stopping before they finished, rather than start them again.
+func (js *JobService) failAbandonedJobs() error {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	now := time.Now().UTC()
+	// The update is idempotent, so it is safe to retry
+	return db.Retry(ctx, func() error {
+		_, err := js.jobs.UpdateMany(ctx, bson.M{
+			"status":      models.JobRunning,
+			"lease_until": bson.M{"$lt": now},
+			"attempts":    bson.M{"$gte": maxJobAttempts},
+		}, bson.M{
+			"$set":   bson.M{"status": models.JobFailed, "error": fmt.Sprintf("the job was interrupted %d times", maxJobAttempts), "finished_at": now},
+			"$unset": bson.M{"lease_until": ""},
+		})
+		return err
+	})
+}
+
+// claimJob takes the oldest job that is queued, or whose worker's lease has lapsed, marking it as running under a
+// new lease. It returns nil if no job is waiting.
+func (js *JobService) claimJob() (*models.Job, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	now := time.Now().UTC()
+	lease := now.Add(js.timeout)
+	filter := bson.M{"$or": bson.A{
+		bson.M{"status": models.JobQueued},
+		bson.M{"status": models.JobRunning, "lease_until": bson.M{"$lt": now}, "attempts": bson.M{"$lt": maxJobAttempts}},
+	}}
+	update := bson.M{
+		"$set": bson.M{"status": models.JobRunning, "started_at": now, "lease_until": lease},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var job models.Job
+	err := js.jobs.FindOneAndUpdate(ctx, filter, update, opts).Decode(&job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// runJob does a claimed job's work and returns its result.
+func (js *JobService) runJob(job *models.Job) (interface{}, error) {
+	switch job.Kind {
+	case models.JobExportGame:
+		return js.exportGame(job.Params.GameID)
+	case models.JobSimulateGame:
+		opts := SimulationOptions{Runs: job.Params.Runs, Seed: job.Params.Seed}
+		return js.games.SimulateGame(job.Params.GameID, opts, job.Viewer)
+	case models.JobRebuildPlayerStats:
+		ctx, cancel := context.WithTimeout(context.Background(), js.timeout)
+		defer cancel()
+		games, err := js.games.rebuildPlayerStats(ctx)
+		return map[string]int{"games": games}, err
+	default:
+		return nil, fmt.Errorf("unknown job kind %q", job.Kind)
+	}
+}
+
+// exportGame exports a game with its event history, from the games collection while it is live or from the
+// archive once it has been archived.
+func (js *JobService) exportGame(gameID string) (*models.GameExport, error) {
+	export, err := js.games.ExportGame(gameID)
+	if !errors.Is(err, ErrGameNotFound) {
+		return export, err
+	}
+
+	archived, err := js.games.GetArchivedGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.GameExport{
+		Version:    models.ExportFormatVersion,
+		ExportedAt: time.Now().UTC(),
+		Game:       *archived.Game,
+		Events:     archived.Events,
+	}, nil
+}
+
+// finishJob stores a job's result, or its error if it failed, and releases its lease.
+func (js *JobService) finishJob(job *models.Job, result interface{}, runErr error) error {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	now := time.Now().UTC()
+	set := bson.M{"status": models.JobSucceeded, "finished_at": now}
+	if runErr == nil {
+		encoded, err := json.Marshal(result)
+		switch {
+		case err != nil:
+			runErr = err
+		case len(encoded) > maxJobResultBytes:
+			runErr = fmt.Errorf("the result is larger than the %d bytes a job can keep", maxJobResultBytes)
+		default:
+			set["result"] = json.RawMessage(encoded)
+		}
+	}
+	if runErr != nil {
+		log.Printf("job %s (%s) failed: %v", job.ID.Hex(), job.Kind, runErr)
+		set["status"], set["error"] = models.JobFailed, runErr.Error()
+	}
+
+	// Only the worker holding the job's current lease may finish it
+	// The update is idempotent, so it is safe to retry
+	return db.Retry(ctx, func() error {
+		_, err := js.jobs.UpdateOne(ctx, bson.M{"_id": job.ID, "lease_until": job.LeaseUntil}, bson.M{
+			"$set":   set,
+			"$unset": bson.M{"lease_until": ""},
+		})
+		return err
+	})
+}
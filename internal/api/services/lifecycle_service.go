@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// StartGame moves a game out of the lobby and sets it up for play according to its game mode.
+// War games get a shuffled deck split between the two players, Crazy Eights games get their opening
+// hands and starter card, Go Fish games get their opening hands, and other modes are dealt opening hands
+// of the size chosen in the settings, if any, before they are marked active.
+// A game_started event is recorded once the game is running.
+func (s *GameService) StartGame(gameID string) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only games still in the lobby can be started (games created before statuses existed count as lobby games)
+	if game.Status != "" && game.Status != models.StatusLobby {
+		return nil, errors.New("game has already been started")
+	}
+
+	// The game needs at least its minimum number of players
+	if len(game.Players) < game.Settings.MinPlayers {
+		return nil, ErrNotEnoughPlayers
+	}
+
+	// A team game needs balanced teams made up of the seated players
+	if err := game.CheckTeams(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnbalancedTeams, err)
+	}
+
+	// Set up the table for the game mode and mark the game active
+	if err := game.SetUpTable(); err != nil {
+		return nil, err
+	}
+
+	// Save the dealt table and the game_started event together
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
+			"$set": bson.M{
+				"status":          game.Status,
+				"game_deck":       game.GameDeck,
+				"card_manifest":   game.CardManifest,
+				"player_hands":    game.PlayerHands,
+				"discard_pile":    game.DiscardPile,
+				"declared_suit":   game.DeclaredSuit,
+				"books":           game.Books,
+				"turn":            game.Turn,
+				"hands":           game.Hands,
+				"dealer_hand":     game.DealerHand,
+				"hand_number":     game.HandNumber,
+				"hand_phase":      game.HandPhase,
+				"hand_started_at": game.HandStartedAt,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		// Record that the game has started, with the seats it started with so it can be replayed
+		data := map[string]interface{}{"mode": game.Mode, "players": game.Players, "dealer_index": game.DealerIndex, "teams": game.Teams}
+		if err := s.recordEvent(ctx, gameIDObj, models.EventGameStarted, "", data); err != nil {
+			return err
+		}
+
+		return s.recordShuffles(ctx, game)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// ResetGame returns all dealt cards to the deck, clears the table, reshuffles, and resets the scores and turn order.
+// Players stay seated, so the game is ready to start a new hand. Only the game's owner or an admin can reset a game.
+func (s *GameService) ResetGame(gameID string, viewer models.Viewer) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageGame(game, viewer) {
+		return nil, ErrForbidden
+	}
+
+	entry := auditGame(models.AuditGameReset, viewer, gameIDObj, "")
+	entry.Before = game.AuditSummary()
+	game.Reset()
+	entry.After = game.AuditSummary()
+
+	// Save the reset table, the game_reset event, and the audit entry together
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
+			"$set": bson.M{
+				"game_deck":     game.GameDeck,
+				"player_hands":  game.PlayerHands,
+				"melds":         game.Melds,
+				"discard_pile":  game.DiscardPile,
+				"declared_suit": game.DeclaredSuit,
+				"bets":          game.Bets,
+				"folded":        game.Folded,
+				"redraws":       game.Redraws,
+				"hands":         game.Hands,
+				"dealer_hand":   game.DealerHand,
+				"insurance":     game.Insurance,
+				"books":         game.Books,
+				"dealer_index":  game.DealerIndex,
+				"hand_number":   game.HandNumber,
+				"winner":        game.Winner,
+				"winning_team":  game.WinningTeam,
+				"status":        game.Status,
+				"turn":          game.Turn,
+				"auction":       game.Auction,
+				"hand_phase":    game.HandPhase,
+				"hand_history":  game.HandHistory,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		// Record that the game was reset, along with its reshuffle
+		if err := s.recordEvent(ctx, gameIDObj, models.EventGameReset, viewer.PlayerName, nil); err != nil {
+			return err
+		}
+		if err := s.recordShuffles(ctx, game); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, s.audit, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
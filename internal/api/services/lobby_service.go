@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MaxOpenGames is the most open games listed at once.
+const MaxOpenGames = 100
+
+// OpenGame describes a public game that still has free seats, as listed by the game browser.
+type OpenGame struct {
+	ID                primitive.ObjectID `bson:"_id" json:"id"`
+	Name              string             `bson:"name" json:"name"`
+	Mode              string             `bson:"mode" json:"mode"`
+	Host              string             `bson:"owner" json:"host"`
+	Status            string             `bson:"status" json:"status"`
+	Players           int                `bson:"players" json:"players"`
+	MaxPlayers        int                `bson:"max_players" json:"max_players"`               // 0 means there is no limit
+	PasswordProtected bool               `bson:"password_protected" json:"password_protected"` // Joining requires the game's password
+	CreatedAt         time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// ListOpenGames lists the public games players can still join, newest first: games that are not private,
+// have not finished, and have seats left. The list can be narrowed to one game mode, and is capped at limit games
+// (MaxOpenGames when limit is 0).
+func (s *GameService) ListOpenGames(mode string, limit int) ([]OpenGame, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	if limit < 0 || limit > MaxOpenGames {
+		return nil, errors.New("limit must be between 0 and 100")
+	}
+	if limit == 0 {
+		limit = MaxOpenGames
+	}
+	if mode != "" && !models.IsValidMode(mode) {
+		return nil, errors.New("unknown game mode")
+	}
+
+	match := bson.M{
+		"private": bson.M{"$ne": true},
+		"status":  bson.M{"$ne": models.StatusFinished},
+	}
+	if mode == models.ModeStandard {
+		// Games created before modes existed have no mode and play the standard game
+		match["mode"] = bson.M{"$in": bson.A{models.ModeStandard, ""}}
+	} else if mode != "" {
+		match["mode"] = mode
+	}
+
+	// Count the seats taken and keep only the games with room left
+	playerCount := bson.M{"$size": bson.M{"$ifNull": bson.A{"$players", bson.A{}}}}
+	maxPlayers := bson.M{"$ifNull": bson.A{"$settings.max_players", 0}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$project", Value: bson.M{
+			"name":               1,
+			"mode":               1,
+			"owner":              1,
+			"status":             1,
+			"created_at":         1,
+			"password_protected": 1,
+			"players":            playerCount,
+			"max_players":        maxPlayers,
+		}}},
+		{{Key: "$match", Value: bson.M{"$expr": bson.M{"$or": bson.A{
+			bson.M{"$lte": bson.A{"$max_players", 0}},
+			bson.M{"$lt": bson.A{"$players", "$max_players"}},
+		}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: -1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	games := []OpenGame{}
+	if err := s.aggregateGames(ctx, pipeline, &games); err != nil {
+		return nil, err
+	}
+	for i := range games {
+		if games[i].Mode == "" {
+			games[i].Mode = models.ModeStandard
+		}
+	}
+
+	return games, nil
+}
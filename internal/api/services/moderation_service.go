@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Actions the inactivity check can take against a player who has stopped acting.
+const (
+	InactivityFlag   = "flag"
+	InactivityRemove = "remove"
+)
+
+// KickPlayer removes a player from a game on behalf of the game's owner.
+// The player's hand is returned to the bottom of the deck. If ban is true the player is also
+// prevented from rejoining. Only the owner and admins may kick or ban players.
+func (s *GameService) KickPlayer(gameID, playerName string, ban bool, viewer models.Viewer) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the game's owner and admins can moderate the game
+	if !canManageGame(game, viewer) {
+		return nil, ErrForbidden
+	}
+	if playerName == game.Owner {
+		return nil, errors.New("the game owner cannot be kicked")
+	}
+
+	// Banning also works for players who are not currently seated
+	if !containsPlayer(game.Players, playerName) && !ban {
+		return nil, errors.New("player not found in the game")
+	}
+	if ban && !containsPlayer(game.Banned, playerName) {
+		game.Banned = append(game.Banned, playerName)
+	}
+
+	// Unseat the player and record the action together
+	eventType, action := models.EventKicked, models.AuditPlayerKicked
+	if ban {
+		eventType, action = models.EventBanned, models.AuditPlayerBanned
+	}
+	entry := auditGame(action, viewer, gameIDObj, playerName)
+	entry.Before = game.AuditSummary()
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.unseatPlayer(ctx, gameIDObj, game, playerName); err != nil {
+			return err
+		}
+
+		// Record the moderation action in the event log and the audit log
+		if err := s.recordEvent(ctx, gameIDObj, eventType, playerName, map[string]interface{}{"by": viewer.PlayerName}); err != nil {
+			return err
+		}
+		entry.After = game.AuditSummary()
+		return recordAudit(ctx, s.audit, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// PenaltyDraw makes a player draw cards from the top of the deck outside the normal turn flow, as for a draw-two
+// or draw-four effect or a broken table rule. The penalty is recorded in the event log with its reason, but not
+// the cards drawn, which only the player sees. Only the owner and admins may hand out penalties.
+func (s *GameService) PenaltyDraw(gameID, playerName string, count int, reason string, viewer models.Viewer) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageGame(game, viewer) {
+		return nil, ErrForbidden
+	}
+
+	// Refill a short deck from the discard pile if the game allows it, then check the player is seated
+	// in a running game and the deck can cover the penalty
+	reshuffled := game.ReshuffleDiscards(count)
+	if err := game.CheckAction(models.Action{Type: models.ActionPenalty, Player: playerName, Amount: count}); err != nil {
+		return nil, err
+	}
+
+	// Move the cards from the top of the deck into the player's hand
+	entry := auditGame(models.AuditPenaltyDraw, viewer, gameIDObj, playerName)
+	entry.Reason = reason
+	entry.Before = game.AuditSummary()
+	game.DrawFromDeck(playerName, count)
+	entry.After = game.AuditSummary()
+
+	// Save the draw and record the penalty together
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
+			"$set": bson.M{"game_deck": game.GameDeck, "player_hands": game.PlayerHands, "discard_pile": game.DiscardPile},
+		})
+		if err != nil {
+			return err
+		}
+
+		if reshuffled > 0 {
+			if err := s.recordEvent(ctx, gameIDObj, models.EventReshuffled, "", map[string]interface{}{"cards": reshuffled}); err != nil {
+				return err
+			}
+			if err := s.recordShuffles(ctx, game); err != nil {
+				return err
+			}
+		}
+		data := map[string]interface{}{"count": count, "by": viewer.PlayerName}
+		if reason != "" {
+			data["reason"] = reason
+		}
+		if err := s.recordEvent(ctx, gameIDObj, models.EventPenaltyDraw, playerName, data); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// CheckInactivity looks for players in active games who have neither acted nor sent a heartbeat within the given window.
+// Depending on the action, idle players are either flagged as inactive or removed from the game.
+// It returns the number of players affected.
+func (s *GameService) CheckInactivity(window time.Duration, action string) (int, error) {
+	// Create a context with a timeout of 30 seconds since the check scans every active game
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Load the active games, only pulling the fields the check needs
+	opts := options.Find().SetProjection(bson.M{"name": 1, "status": 1, "settings": 1, "seat_numbers": 1, "reservations": 1, "waitlist": 1, "players": 1, "owner": 1, "last_active": 1, "last_seen": 1, "inactive": 1, "player_hands": 1, "game_deck": 1, "banned": 1, "turn": 1, "teams": 1, "dealer_index": 1})
+	cursor, err := s.collection.Find(ctx, bson.M{"status": models.StatusActive}, opts)
+	if err != nil {
+		return 0, err
+	}
+	games := []models.Game{}
+	if err := cursor.All(ctx, &games); err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().UTC().Add(-window)
+	affected := 0
+	for i := range games {
+		game := &games[i]
+		for _, player := range append([]string{}, game.Players...) {
+			// Skip players who have acted or checked in recently, or were already flagged
+			lastActive, seen := game.LastActive[player]
+			if lastSeen, ok := game.LastSeen[player]; ok && (!seen || lastSeen.After(lastActive)) {
+				lastActive, seen = lastSeen, true
+			}
+			if !seen || lastActive.After(cutoff) || (action != InactivityRemove && containsPlayer(game.Inactive, player)) {
+				continue
+			}
+
+			if action == InactivityRemove {
+				err = s.unseatPlayer(ctx, game.ID, game, player)
+			} else {
+				game.Inactive = append(game.Inactive, player)
+
+				// The update is idempotent, so it is safe to retry
+				err = db.Retry(ctx, func() error {
+					_, err := s.collection.UpdateOne(ctx, bson.M{"_id": game.ID}, bson.M{
+						"$set": bson.M{"inactive": game.Inactive},
+					})
+					return err
+				})
+			}
+			if err != nil {
+				return affected, err
+			}
+
+			data := map[string]interface{}{"last_active": lastActive, "action": action}
+			if err := s.recordEvent(ctx, game.ID, models.EventInactive, player, data); err != nil {
+				return affected, err
+			}
+			affected++
+		}
+	}
+
+	return affected, nil
+}
+
+// touchPlayer records that the player has just acted in the game and clears any inactivity flag.
+func (s *GameService) touchPlayer(ctx context.Context, gameID primitive.ObjectID, playerName string) error {
+	// The update is idempotent, so it is safe to retry
+	return db.Retry(ctx, func() error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameID}, bson.M{
+			"$set":  bson.M{"last_active." + playerName: time.Now().UTC()},
+			"$pull": bson.M{"inactive": playerName},
+		})
+		return err
+	})
+}
+
+// unseatPlayer removes a player from the game's seats, returning their hand to the bottom of the deck,
+// and saves the result. The open seat goes to the first player on the waitlist.
+func (s *GameService) unseatPlayer(ctx context.Context, gameID primitive.ObjectID, game *models.Game, playerName string) error {
+	// Take the player off their seat, out of the turn order, and off their team, returning their cards to the deck
+	game.LeaveTable(playerName)
+	delete(game.LastActive, playerName)
+	delete(game.LastSeen, playerName)
+	delete(game.Connections, playerName)
+	delete(game.Disconnected, playerName)
+
+	// Clear the inactivity flag
+	inactive := []string{}
+	for _, player := range game.Inactive {
+		if player != playerName {
+			inactive = append(inactive, player)
+		}
+	}
+	game.Inactive = inactive
+
+	// The update is idempotent, so it is safe to retry
+	err := db.Retry(ctx, func() error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameID}, bson.M{
+			"$set": bson.M{
+				"players":      game.Players,
+				"dealer_index": game.DealerIndex,
+				"game_deck":    game.GameDeck,
+				"player_hands": game.PlayerHands,
+				"banned":       game.Banned,
+				"inactive":     game.Inactive,
+				"turn":         game.Turn,
+				"teams":        game.Teams,
+			},
+			"$unset": bson.M{
+				"last_active." + playerName:  "",
+				"last_seen." + playerName:    "",
+				"connections." + playerName:  "",
+				"disconnected." + playerName: "",
+				"hands." + playerName:        "",
+				"seat_numbers." + playerName: "",
+				"insurance." + playerName:    "",
+				"bots." + playerName:         "",
+			},
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Give the open seat to the first player waiting for one
+	return s.seatWaitlisted(ctx, gameID, game)
+}
+
+// canManageGame reports whether the viewer is the game's owner (its dealer) or an admin.
+func canManageGame(game *models.Game, viewer models.Viewer) bool {
+	return viewer.Admin || (viewer.PlayerName != "" && viewer.PlayerName == game.Owner)
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// MoveOutcome is the game after a move, along with the round's scores if the move ended the round.
+type MoveOutcome struct {
+	Game      *models.Game   `json:"game"`
+	RoundOver bool           `json:"round_over"`
+	Values    map[string]int `json:"values,omitempty"`  // Each player's value at the end of the round
+	Winners   []string       `json:"winners,omitempty"` // Players who won the round
+}
+
+// MakeMove makes a move in any game whose mode has rules registered with models.RegisterRules: the mode's rules
+// validate the move and apply it, and then decide whether the round is over and score it. The game's play state
+// is saved and the events the move made happen are recorded together; a move that ends the game also records its
+// end and moves it into the archive.
+func (s *GameService) MakeMove(gameID string, move models.Action) (*MoveOutcome, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the move against the mode's rules, then make it
+	rules, err := game.Rules()
+	if err != nil {
+		return nil, err
+	}
+	if err := rules.ValidateMove(game, move); err != nil {
+		return nil, err
+	}
+	events, err := rules.ApplyMove(game, move)
+	if err != nil {
+		return nil, err
+	}
+
+	outcome := &MoveOutcome{Game: game, RoundOver: rules.IsRoundOver(game)}
+	if outcome.RoundOver {
+		outcome.Values, outcome.Winners = rules.Score(game)
+	}
+
+	// Save the play state, the move's events, and any archive move together
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
+			"$set": bson.M{
+				"game_deck":     game.GameDeck,
+				"player_hands":  game.PlayerHands,
+				"hands":         game.Hands,
+				"dealer_hand":   game.DealerHand,
+				"discard_pile":  game.DiscardPile,
+				"declared_suit": game.DeclaredSuit,
+				"books":         game.Books,
+				"chips":         game.Chips,
+				"bets":          game.Bets,
+				"turn":          game.Turn,
+				"hand_phase":    game.HandPhase,
+				"status":        game.Status,
+				"winner":        game.Winner,
+				"winning_team":  game.WinningTeam,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		// Record the move's events, any shuffle it made, and the end of the game if it was the winning move
+		for _, event := range events {
+			if err := s.recordEvent(ctx, gameIDObj, event.Type, event.Player, event.Data); err != nil {
+				return err
+			}
+		}
+		if err := s.recordShuffles(ctx, game); err != nil {
+			return err
+		}
+		if game.Status == models.StatusFinished {
+			if err := s.recordEvent(ctx, gameIDObj, models.EventGameOver, game.Winner, nil); err != nil {
+				return err
+			}
+		}
+
+		// Acting counts as activity for the inactivity check, for moves a seated player makes
+		if containsPlayer(game.Players, move.Player) {
+			if err := s.touchPlayer(ctx, gameIDObj, move.Player); err != nil {
+				return err
+			}
+		}
+
+		// Move the finished game into the archive
+		if game.Status == models.StatusFinished {
+			return s.archiveGame(ctx, gameIDObj)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return outcome, nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"log"
+	"my-card-game/internal/api/models"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// NotificationHub fans player notifications out to the real-time streams each player has open.
+// Subscribers are grouped by player name, and each receives notifications on its own buffered channel.
+type NotificationHub struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan models.Notification]struct{}
+}
+
+// NewNotificationHub creates and returns a new, empty NotificationHub.
+func NewNotificationHub() *NotificationHub {
+	return &NotificationHub{subscribers: map[string]map[chan models.Notification]struct{}{}}
+}
+
+// Subscribe registers interest in a player's notifications.
+// It returns the channel the notifications arrive on and a function that must be called to unsubscribe.
+func (h *NotificationHub) Subscribe(player string) (<-chan models.Notification, func()) {
+	ch := make(chan models.Notification, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subscribers[player] == nil {
+		h.subscribers[player] = map[chan models.Notification]struct{}{}
+	}
+	h.subscribers[player][ch] = struct{}{}
+	h.mu.Unlock()
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subscribers[player][ch]; !ok {
+			return
+		}
+		delete(h.subscribers[player], ch)
+		if len(h.subscribers[player]) == 0 {
+			delete(h.subscribers, player)
+		}
+		close(ch)
+	}
+	return ch, unsubscribe
+}
+
+// Publish sends a notification to every stream its player has open.
+// Streams whose buffers are full miss the notification, which can still be listed later.
+func (h *NotificationHub) Publish(notification models.Notification) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.subscribers[notification.Player] {
+		select {
+		case ch <- notification:
+		default:
+		}
+	}
+}
+
+// WatchNotifications follows the change stream of the notifications collection and publishes every new
+// notification to the hub, so notifications created on any replica reach the player wherever they are connected.
+// If the stream fails it is reopened after a short pause, until the context is cancelled.
+func (ss *SocialService) WatchNotifications(ctx context.Context, hub *NotificationHub) {
+	go func() {
+		for {
+			if err := ss.watchNotifications(ctx, hub); err != nil && ctx.Err() == nil {
+				log.Printf("notifications change stream failed: %v", err)
+			}
+
+			// Wait before reopening the stream, unless the watcher is shutting down
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+		}
+	}()
+}
+
+// watchNotifications opens a change stream of inserted notifications and publishes them until the stream ends.
+func (ss *SocialService) watchNotifications(ctx context.Context, hub *NotificationHub) error {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{"operationType": "insert"}}}}
+	stream, err := ss.notifications.Watch(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer stream.Close(context.Background())
+
+	for stream.Next(ctx) {
+		var event struct {
+			FullDocument models.Notification `bson:"fullDocument"`
+		}
+		if err := stream.Decode(&event); err != nil {
+			log.Printf("could not decode notification: %v", err)
+			continue
+		}
+		hub.Publish(event.FullDocument)
+	}
+	return stream.Err()
+}
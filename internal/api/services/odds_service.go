@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DrawOddsQuery describes the cards whose chance of coming off the top of the deck is wanted.
+// A card matches when it passes every filter given: its suit is one of Suits, its value is one of Values, and its
+// value ranks between MinValue and MaxValue inclusive. Aces is "high" or "low" to override where the game's ace mode
+// ranks aces within the range. Jokers never fall within a range.
+type DrawOddsQuery struct {
+	Suits    []string
+	Values   []string
+	MinValue string
+	MaxValue string
+	Aces     string
+}
+
+// Validate checks that the query only names real suits and values and asks for a supported ace ranking.
+// A range whose bounds are the wrong way round is allowed and simply matches nothing.
+func (q DrawOddsQuery) Validate() error {
+	if err := (RemainingCardsQuery{Suits: q.Suits, Values: q.Values, Aces: q.Aces}).Validate(); err != nil {
+		return err
+	}
+	for _, value := range []string{q.MinValue, q.MaxValue} {
+		if value != "" && !models.IsValidCardValue(value) {
+			return fmt.Errorf("invalid card value %q", value)
+		}
+	}
+	return nil
+}
+
+// DrawOdds is the chance that the next card drawn from a game's deck matches a query.
+type DrawOdds struct {
+	Remaining   int     `json:"remaining"`   // Cards left in the deck
+	Matching    int     `json:"matching"`    // Cards left in the deck that match the query
+	Probability float64 `json:"probability"` // Matching divided by remaining, or 0 when the deck is empty
+}
+
+// GetDrawOdds works out the probability that the next card drawn from the game's deck matches the query,
+// from the cards still in the deck. Nothing about the order of the deck is revealed.
+func (s *GameService) GetDrawOdds(gameID string, query DrawOddsQuery) (*DrawOdds, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	// Load only the settings, which decide where aces rank, not the deck
+	game, gameIDObj, err := s.findGameFields(ctx, gameID, "settings")
+	if err != nil {
+		return nil, err
+	}
+	acesHigh := game.AceRanksHigh()
+	if query.Aces != "" {
+		acesHigh = query.Aces == models.AceHigh
+	}
+
+	remaining, err := s.remainingCardCounts(ctx, gameIDObj)
+	if err != nil {
+		return nil, err
+	}
+
+	odds := &DrawOdds{}
+	for card, count := range remaining {
+		odds.Remaining += count
+		if query.matches(card, acesHigh) {
+			odds.Matching += count
+		}
+	}
+	if odds.Remaining > 0 {
+		odds.Probability = float64(odds.Matching) / float64(odds.Remaining)
+	}
+	return odds, nil
+}
+
+// matches reports whether the card passes every filter in the query.
+func (q DrawOddsQuery) matches(card models.Card, acesHigh bool) bool {
+	if len(q.Suits) > 0 && !containsString(q.Suits, card.Suit.String()) {
+		return false
+	}
+	if len(q.Values) > 0 && !containsString(q.Values, card.Value.String()) {
+		return false
+	}
+	if q.MinValue == "" && q.MaxValue == "" {
+		return true
+	}
+
+	// Only ranked values fall within a range
+	rank := models.ValueRank(card.Value, acesHigh)
+	if rank == 0 {
+		return false
+	}
+	if low, err := models.ParseRank(q.MinValue); err == nil && rank < models.ValueRank(low, acesHigh) {
+		return false
+	}
+	if high, err := models.ParseRank(q.MaxValue); err == nil && rank > models.ValueRank(high, acesHigh) {
+		return false
+	}
+	return true
+}
+
+// CountStats are the card-counting statistics of a game's deck, as a blackjack player counting with Hi-Lo would
+// keep them. Every card no longer in the deck counts as seen.
+type CountStats struct {
+	RunningCount   int     `json:"running_count"`   // Sum of the Hi-Lo tags of the cards seen
+	TrueCount      float64 `json:"true_count"`      // Running count per deck remaining, or 0 when the deck is empty
+	DecksRemaining float64 `json:"decks_remaining"` // Cards left in the deck divided by 52
+	Remaining      int     `json:"remaining"`       // Cards left in the deck
+	Low            int     `json:"low"`             // Cards 2 to 6 left in the deck
+	Neutral        int     `json:"neutral"`         // Cards 7 to 9 and jokers left in the deck
+	High           int     `json:"high"`            // Tens, face cards, and aces left in the deck
+}
+
+// GetCountStats works out the Hi-Lo running count, the true count, and the mix of high and low cards left in the
+// game's deck. The shoe starts from complete decks, whose tags sum to zero, so the running count of the cards seen
+// is the negated sum of the tags of the cards still in the deck.
+func (s *GameService) GetCountStats(gameID string) (*CountStats, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	// Check the game exists without loading its deck
+	_, gameIDObj, err := s.findGameFields(ctx, gameID, "_id")
+	if err != nil {
+		return nil, err
+	}
+	remaining, err := s.remainingCardCounts(ctx, gameIDObj)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &CountStats{}
+	for card, count := range remaining {
+		stats.Remaining += count
+		switch models.HiLoValue(card) {
+		case 1:
+			stats.Low += count
+		case -1:
+			stats.High += count
+		default:
+			stats.Neutral += count
+		}
+	}
+	stats.RunningCount = stats.High - stats.Low
+	if stats.Remaining > 0 {
+		stats.DecksRemaining = float64(stats.Remaining) / 52
+		stats.TrueCount = float64(stats.RunningCount) / stats.DecksRemaining
+	}
+	return stats, nil
+}
+
+// remainingCardCounts has the database count the copies of each card left in the game's deck.
+func (s *GameService) remainingCardCounts(ctx context.Context, gameID primitive.ObjectID) (map[models.Card]int, error) {
+	pipeline := remainingCardsPipeline(gameID, allCardCodes(models.Suits, models.CardValues),
+		bson.D{{Key: "$group", Value: bson.M{"_id": "$code", "count": bson.M{"$sum": 1}}}},
+	)
+	var groups []struct {
+		Code  string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	if err := s.aggregateGames(ctx, pipeline, &groups); err != nil {
+		return nil, err
+	}
+
+	counts := map[models.Card]int{}
+	for _, group := range groups {
+		card, err := models.ParseCardCode(group.Code)
+		if err != nil {
+			return nil, err
+		}
+		counts[card] += group.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrOrgNotFound is returned when a request names an organization that has not been created.
+var ErrOrgNotFound = errors.New("organization not found")
+
+// OrgService provides services related to the organizations hosted on the deployment.
+// The organizations are listed in the default organization's database; each one's data is kept in its own.
+type OrgService struct {
+	collection *mongo.Collection
+	audit      *mongo.Collection
+}
+
+// NewOrgService creates and returns a new instance of OrgService.
+func NewOrgService() *OrgService {
+	return &OrgService{collection: db.GetCollection("organizations"), audit: db.GetCollection("audit_log")}
+}
+
+// CreateOrg creates an organization with the given ID and name on behalf of the viewer, recording it in the
+// platform's audit log, and creates the indexes of its database.
+func (o *OrgService) CreateOrg(id, name string, viewer models.Viewer) (*models.Organization, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	if !db.ValidOrgID(id) {
+		return nil, errors.New("organization IDs are 1 to 32 lowercase letters, digits, and dashes, starting with a letter or digit")
+	}
+
+	org := &models.Organization{ID: id, Name: name, CreatedAt: time.Now().UTC()}
+	err := db.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := o.collection.InsertOne(ctx, org); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return errors.New("an organization with this ID already exists")
+			}
+			return err
+		}
+		entry := models.AuditEntry{
+			Action: models.AuditOrgCreated,
+			Actor:  models.AuditActor(viewer),
+			Admin:  viewer.Admin,
+			Target: id,
+			After:  map[string]interface{}{"name": name},
+		}
+		return recordAudit(ctx, o.audit, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Give the new organization's database the same indexes as every other
+	if err := db.EnsureOrgIndexes(id); err != nil {
+		return nil, err
+	}
+
+	return org, nil
+}
+
+// ListOrgs lists the organizations, oldest first. The default organization is not listed.
+func (o *OrgService) ListOrgs() ([]models.Organization, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := o.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	orgs := []models.Organization{}
+	if err := cursor.All(ctx, &orgs); err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+// OrgExists reports whether the organization has been created. The default organization always exists.
+func (o *OrgService) OrgExists(ctx context.Context, id string) (bool, error) {
+	if id == db.DefaultOrg {
+		return true, nil
+	}
+	count, err := o.collection.CountDocuments(ctx, bson.M{"_id": id}, options.Count().SetLimit(1))
+	return count > 0, err
+}
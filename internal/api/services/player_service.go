@@ -4,25 +4,60 @@ import (
 	"context"
 	"errors"
 	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
 	"sort"
-	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // PlayerHandValue represents the total value of a player's hand.
-// It includes the player's name and the total hand value.
+// It includes the player's name and the total hand value, and, when asked for, the value of each card.
 type PlayerHandValue struct {
-	PlayerName string `json:"player_name"`
-	HandValue  int    `json:"hand_value"`
+	PlayerName string          `json:"player_name"`
+	HandValue  int             `json:"hand_value"`
+	Cards      []CardBreakdown `json:"cards,omitempty"`
 }
 
-// AddPlayer adds a player to a game
-func (s *GameService) AddPlayer(gameID, playerName string) (*models.Game, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// CardBreakdown is the value of one card of a hand, scored on its own. Strategies that score combinations of
+// cards, such as cribbage, or promote aces by the rest of the hand, such as blackjack, value a hand differently
+// from the sum of its cards.
+type CardBreakdown struct {
+	Card  models.Card `json:"card"`
+	Value int         `json:"value"`
+}
+
+// HandValueOptions shapes the list of hand values: its order, how many players it includes, the lowest hand
+// value it includes, and whether it breaks each hand down card by card. The zero value lists every player,
+// best hand first, without breakdowns.
+type HandValueOptions struct {
+	Order     string        // OrderAscending or OrderDescending by value; empty lists the best hand first
+	Limit     int           // Most players to list; 0 lists them all
+	MinValue  *int          // Leave out hands worth less than this
+	Breakdown bool          // Include each card's value, for the hands the viewer may see
+	Viewer    models.Viewer // Who is asking, which decides whose cards the breakdowns may show
+}
+
+// AddPlayer adds a player to a game, sitting them in the given seat, or in the seat reserved for them or the
+// lowest open seat when seat is 0.
+// Password-protected games return ErrWrongPassword unless the password matches the one set when the game was created.
+func (s *GameService) AddPlayer(gameID, playerName, password string, seat int) (*models.Game, error) {
+	return s.seatPlayer(gameID, playerName, &password, seat)
+}
+
+// seatPlayer adds a player to a game, checking the game's password when password is not nil.
+// Invitations seat players with a nil password, since the invitation already grants entry.
+func (s *GameService) seatPlayer(gameID, playerName string, password *string, seat int) (*models.Game, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
 	defer cancel()
 
+	if err := checkPlayerName(playerName); err != nil {
+		return nil, err
+	}
+
 	gameIDObj, err := primitive.ObjectIDFromHex(gameID)
 	if err != nil {
 		return nil, errors.New("invalid game ID")
@@ -31,30 +66,61 @@ func (s *GameService) AddPlayer(gameID, playerName string) (*models.Game, error)
 	var game models.Game
 	err = s.collection.FindOne(ctx, bson.M{"_id": gameIDObj}).Decode(&game)
 	if err != nil {
-		return nil, errors.New("game not found")
+		return nil, ErrGameNotFound
+	}
+
+	// Banned players cannot rejoin the game
+	if containsPlayer(game.Banned, playerName) {
+		return nil, errors.New("player is banned from this game")
 	}
 
 	// Add the player to the game if they are not already in it
-	for _, player := range game.Players {
-		if player == playerName {
-			return nil, errors.New("player already in the game")
+	if containsPlayer(game.Players, playerName) {
+		return nil, ErrAlreadySeated
+	}
+
+	// Players joining a password-protected game must know its password
+	if password != nil && game.PasswordHash != "" {
+		if bcrypt.CompareHashAndPassword([]byte(game.PasswordHash), []byte(*password)) != nil {
+			return nil, ErrWrongPassword
 		}
 	}
-	game.Players = append(game.Players, playerName)
 
-	_, err = s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
-		"$set": bson.M{"players": game.Players},
-	})
+	// Reject the join if the game already has its maximum number of players or every seat is taken or held
+	if game.IsFull(playerName) {
+		return nil, ErrGameFull
+	}
+
+	// Sit the player down; a player joining a game in progress takes their place in the turn order by their seat
+	if err := game.SitDown(playerName, seat); err != nil {
+		return nil, err
+	}
+
+	// Save the change and check the state it leaves the game in, in one transaction
+	game.Checksum, err = s.saveChecked(ctx, gameIDObj, bson.M{
+		"$set": bson.M{
+			"players":      game.Players,
+			"seat_numbers": game.SeatNumbers,
+			"reservations": game.Reservations,
+			"dealer_index": game.DealerIndex,
+			"turn":         game.Turn,
+		},
+	}, actionPlayerSeated, playerName)
 	if err != nil {
 		return nil, err
 	}
 
+	// Joining counts as activity for the inactivity check
+	if err := s.touchPlayer(ctx, gameIDObj, playerName); err != nil {
+		return nil, err
+	}
+
 	return &game, nil
 }
 
 // RemovePlayer removes a player from a game
 func (s *GameService) RemovePlayer(gameID, playerName string) (*models.Game, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
 	defer cancel()
 
 	gameIDObj, err := primitive.ObjectIDFromHex(gameID)
@@ -65,109 +131,243 @@ func (s *GameService) RemovePlayer(gameID, playerName string) (*models.Game, err
 	var game models.Game
 	err = s.collection.FindOne(ctx, bson.M{"_id": gameIDObj}).Decode(&game)
 	if err != nil {
-		return nil, errors.New("game not found")
+		return nil, ErrGameNotFound
 	}
 
-	// Remove the player from the game
-	newPlayers := []string{}
-	for _, player := range game.Players {
-		if player != playerName {
-			newPlayers = append(newPlayers, player)
-		}
+	// Remove the player from the game, keeping the dealer button in place, or return an error if they are not seated
+	if !game.Unseat(playerName) {
+		return nil, errors.New("player not found in the game")
+	}
+	if game.Turn != nil {
+		game.Turn.Remove(playerName)
+	}
+	game.RemoveFromTeams(playerName)
+	delete(game.Bots, playerName)
+
+	// Save the change and check the state it leaves the game in, in one transaction
+	game.Checksum, err = s.saveChecked(ctx, gameIDObj, bson.M{
+		"$set":   bson.M{"players": game.Players, "seat_numbers": game.SeatNumbers, "dealer_index": game.DealerIndex, "turn": game.Turn, "teams": game.Teams},
+		"$unset": bson.M{"bots." + playerName: ""},
+	}, actionPlayerRemoved, playerName)
+	if err != nil {
+		return nil, err
 	}
 
-	// If the player was not found, return an error
-	if len(newPlayers) == len(game.Players) {
-		return nil, errors.New("player not found in the game")
+	// Give the open seat to the first player waiting for one
+	if err := s.seatWaitlisted(ctx, gameIDObj, &game); err != nil {
+		return nil, err
 	}
 
-	game.Players = newPlayers
+	return &game, nil
+}
+
+// DealCardToPlayer deals a card from the game's deck to the specified player. Only the game's owner, who deals,
+// and admins may deal; anyone else gets ErrForbidden, since the dealt card is returned to the dealer.
+// The top card is popped off the stored deck and pushed onto the player's stored hand,
+// so neither the rest of the deck nor the other hands are rewritten.
+func (s *GameService) DealCardToPlayer(gameID, playerName string, viewer models.Viewer) (*models.Card, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	// Check the caller deals, the game is still running, and the player has a seat, without loading the deck
+	game, gameIDObj, err := s.findGameFields(ctx, gameID, "owner", "status", "players", "mode", "hand_phase", "settings")
+	if err != nil {
+		return nil, err
+	}
+	if !canManageGame(game, viewer) {
+		return nil, ErrForbidden
+	}
+	if err := game.CheckAction(models.Action{Type: models.ActionDeal, Player: playerName}); err != nil {
+		return nil, err
+	}
 
-	_, err = s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
-		"$set": bson.M{"players": game.Players},
+	// Move the top card from the deck into the player's hand together
+	var dealtCard models.Card
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		// Pop the top card off the deck, reading back only that card as it was before the pop
+		var before struct {
+			GameDeck models.CompactCards `bson:"game_deck"`
+		}
+		opts := options.FindOneAndUpdate().
+			SetReturnDocument(options.Before).
+			SetProjection(bson.M{"game_deck": bson.M{"$slice": 1}})
+		pop := func() error {
+			return s.collection.FindOneAndUpdate(ctx,
+				bson.M{"_id": gameIDObj, "game_deck.0": bson.M{"$exists": true}},
+				bson.M{"$pop": bson.M{"game_deck": -1}},
+				opts,
+			).Decode(&before)
+		}
+		err := pop()
+
+		// Refill an empty deck from the discard pile if the game allows it, and try again
+		if errors.Is(err, mongo.ErrNoDocuments) && game.Settings.ReshuffleDiscards {
+			reshuffled, reshuffleErr := s.reshuffleDiscards(ctx, gameIDObj)
+			if reshuffleErr != nil {
+				return reshuffleErr
+			}
+			if reshuffled > 0 {
+				err = pop()
+			}
+		}
+		if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && len(before.GameDeck) == 0) {
+			// Report the empty deck as a broken rule, like the checks above
+			return models.Violations{{Rule: models.RuleDeck, Reason: "no cards left to deal"}}
+		}
+		if err != nil {
+			return err
+		}
+		dealtCard = before.GameDeck[0]
+
+		// Games that have never dealt a hand may store no hands map at all, which $push cannot add a field to
+		_, err = s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj, "player_hands": nil}, bson.M{
+			"$set": bson.M{"player_hands": bson.M{}},
+		})
+		if err != nil {
+			return err
+		}
+
+		// Push the card onto the end of the player's hand
+		_, err = s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
+			"$push": bson.M{"player_hands." + playerName: dealtCard},
+		})
+		if err != nil {
+			return err
+		}
+
+		// Record the deal, which is also what achievements for dealt hands are evaluated from
+		return s.recordEvent(ctx, gameIDObj, models.EventCardDealt, playerName, map[string]interface{}{"card": models.CardCode(dealtCard)})
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &game, nil
+	// Return the dealt card
+	return &dealtCard, nil
 }
 
-// DealCardToPlayer deals a card from the game's deck to the specified player.
-// The top card from the game deck is removed and added to the player's hand.
-// The updated game state is then saved to the database.
-func (s *GameService) DealCardToPlayer(gameID, playerName string) (*models.Card, error) {
-	// Create a context with a timeout of 5 seconds to manage the database operation
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// RedrawCards returns cards from a player's hand and draws the same number of replacements from the top of the
+// deck in one step, as in draw poker. The returned cards go to the bottom of the deck after the replacements are
+// drawn, so they cannot come straight back. The game's settings limit how many redraws each player gets per hand
+// and how many cards each may replace. It returns the player's new hand.
+func (s *GameService) RedrawCards(gameID, playerName string, cards []models.Card) ([]models.Card, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
 	defer cancel()
 
-	// Convert the game ID from a hex string to an ObjectID
-	gameIDObj, err := primitive.ObjectIDFromHex(gameID)
+	game, gameIDObj, err := s.findGame(ctx, gameID)
 	if err != nil {
-		// Return an error if the game ID is invalid
-		return nil, errors.New("invalid game ID")
+		return nil, err
 	}
 
-	// Find the game in the MongoDB collection using the provided game ID
-	var game models.Game
-	err = s.collection.FindOne(ctx, bson.M{"_id": gameIDObj}).Decode(&game)
-	if err != nil {
-		// Return an error if the game is not found
-		return nil, errors.New("game not found")
+	// Refill a short deck from the discard pile if the game allows it, then check the redraw
+	reshuffled := game.ReshuffleDiscards(len(cards))
+	if err := game.CheckAction(models.Action{Type: models.ActionRedraw, Player: playerName, Cards: cards}); err != nil {
+		return nil, err
 	}
 
-	// Check if there are any cards left to deal
-	if len(game.GameDeck) == 0 {
-		// Return an error if there are no cards left in the deck
-		return nil, errors.New("no cards left to deal")
+	// Swap the cards for the top of the deck; the rules have checked the player holds them
+	hand, _ := models.RemoveCards(game.PlayerHands[playerName], cards)
+	hand = append(hand, game.GameDeck[:len(cards)]...)
+	game.GameDeck = append(game.GameDeck[len(cards):], cards...)
+	game.PlayerHands[playerName] = hand
+	if game.Redraws == nil {
+		game.Redraws = map[string]int{}
 	}
+	game.Redraws[playerName]++
 
-	// Deal the top card from the deck
-	dealtCard := game.GameDeck[0]
-	// Remove the dealt card from the game deck
-	game.GameDeck = game.GameDeck[1:]
+	// Save the swap and record it together, only if the player has not redrawn in the meantime
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		filter := bson.M{"_id": gameIDObj, "redraws." + playerName: bson.M{"$lt": game.Redraws[playerName]}}
+		if game.Redraws[playerName] == 1 {
+			filter = bson.M{"_id": gameIDObj, "redraws." + playerName: bson.M{"$exists": false}}
+		}
+		result, err := s.collection.UpdateOne(ctx, filter, bson.M{
+			"$set": bson.M{
+				"game_deck":                  game.GameDeck,
+				"discard_pile":               game.DiscardPile,
+				"player_hands." + playerName: hand,
+				"redraws":                    game.Redraws,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errors.New("player has already redrawn; please try again")
+		}
 
-	// Initialize the player hands map if it hasn't been already
-	if game.PlayerHands == nil {
-		game.PlayerHands = make(map[string][]models.Card)
-	}
-	// Add the dealt card to the player's hand
-	game.PlayerHands[playerName] = append(game.PlayerHands[playerName], dealtCard)
+		if reshuffled > 0 {
+			if err := s.recordEvent(ctx, gameIDObj, models.EventReshuffled, "", map[string]interface{}{"cards": reshuffled}); err != nil {
+				return err
+			}
+			if err := s.recordShuffles(ctx, game); err != nil {
+				return err
+			}
+		}
+		if err := s.recordEvent(ctx, gameIDObj, models.EventRedraw, playerName, map[string]interface{}{"count": len(cards)}); err != nil {
+			return err
+		}
 
-	// Update the game state in the database
-	_, err = s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
-		"$set": bson.M{"game_deck": game.GameDeck, "player_hands": game.PlayerHands},
+		// Acting counts as activity for the inactivity check
+		return s.touchPlayer(ctx, gameIDObj, playerName)
 	})
 	if err != nil {
-		// Return an error if the update operation fails
 		return nil, err
 	}
 
-	// Return the dealt card
-	return &dealtCard, nil
+	return hand, nil
+}
+
+// reshuffleDiscards refills a game's empty deck from its discard pile, loading only the cards involved, and
+// records the reshuffle. It returns the number of cards moved, which is 0 if there was nothing to reshuffle.
+func (s *GameService) reshuffleDiscards(ctx context.Context, gameID primitive.ObjectID) (int, error) {
+	var game models.Game
+	opts := options.FindOne().SetProjection(bson.M{"settings": 1, "game_deck": 1, "discard_pile": 1})
+	if err := s.collection.FindOne(ctx, bson.M{"_id": gameID}, opts).Decode(&game); err != nil {
+		return 0, err
+	}
+	game.UseEntropy(s.entropy)
+
+	reshuffled := game.ReshuffleDiscards(1)
+	if reshuffled == 0 {
+		return 0, nil
+	}
+	// The update is idempotent, so it is safe to retry
+	err := db.Retry(ctx, func() error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameID}, bson.M{
+			"$set": bson.M{"game_deck": game.GameDeck, "discard_pile": game.DiscardPile},
+		})
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.recordEvent(ctx, gameID, models.EventReshuffled, "", map[string]interface{}{"cards": reshuffled}); err != nil {
+		return 0, err
+	}
+	return reshuffled, s.recordShuffles(ctx, &game)
 }
 
 // GetPlayerHand retrieves the list of cards held by a specific player in a game.
-// It finds the game by its ID, checks if the player has any cards dealt,
-// and returns the player's hand or an error if the game or player is not found.
-func (s *GameService) GetPlayerHand(gameID, playerName string) ([]models.Card, error) {
-	// Create a context with a timeout of 5 seconds to manage the database operation
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// It finds the game by its ID, checks that the viewer is allowed to see the hand and that the player
+// has any cards dealt, and returns the player's hand or an error if the game or player is not found.
+func (s *GameService) GetPlayerHand(gameID, playerName string, viewer models.Viewer) ([]models.Card, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
 	defer cancel()
 
-	// Convert the game ID from a hex string to an ObjectID
-	gameIDObj, err := primitive.ObjectIDFromHex(gameID)
+	// Load only the hand and what the visibility check needs, not the deck
+	game, _, err := s.findGameFields(ctx, gameID, append([]string{"player_hands"}, models.HandViewFields...)...)
 	if err != nil {
-		// Return an error if the game ID is invalid
-		return nil, errors.New("invalid game ID")
+		return nil, err
 	}
 
-	// Find the game in the MongoDB collection using the provided game ID
-	var game models.Game
-	err = s.collection.FindOne(ctx, bson.M{"_id": gameIDObj}).Decode(&game)
-	if err != nil {
-		// Return an error if the game is not found
-		return nil, errors.New("game not found")
+	// Only the player, a dealing owner who is not seated, and admins may see the hand
+	if !game.CanViewHand(viewer, playerName) {
+		return nil, ErrForbidden
 	}
 
 	// Retrieve the player's hand from the game's PlayerHands map
@@ -177,86 +377,65 @@ func (s *GameService) GetPlayerHand(gameID, playerName string) ([]models.Card, e
 		return nil, errors.New("player not found or no cards dealt to this player")
 	}
 
-	// Return the player's hand
-	return hand, nil
+	// Return the player's hand, with any wild cards flagged
+	return game.MarkWild(hand), nil
 }
 
 // GetPlayersWithHandValues retrieves the list of players in a game along with the total value of their hands.
-// The players are sorted in descending order based on the value of their hands, and the sorted list is returned.
-func (s *GameService) GetPlayersWithHandValues(gameID string) ([]PlayerHandValue, error) {
-	// Create a context with a timeout of 5 seconds to manage the database operation
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// Hands are valued by the game's scoring strategy. By default the players are sorted best hand first, which is
+// descending order except for penalty-point games such as Hearts; the options can fix the order, keep only the
+// top players or the hands worth at least a minimum, and break down the hands the viewer may see card by card.
+func (s *GameService) GetPlayersWithHandValues(gameID string, opts HandValueOptions) ([]PlayerHandValue, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
 	defer cancel()
 
-	// Convert the game ID from a hex string to an ObjectID
-	gameIDObj, err := primitive.ObjectIDFromHex(gameID)
+	// Load only the hands and what the scoring and the visibility check need, not the deck
+	game, _, err := s.findGameFields(ctx, gameID, append([]string{"player_hands"}, models.HandViewFields...)...)
 	if err != nil {
-		// Return an error if the game ID is invalid
-		return nil, errors.New("invalid game ID")
-	}
-
-	// Find the game in the MongoDB collection using the provided game ID
-	var game models.Game
-	err = s.collection.FindOne(ctx, bson.M{"_id": gameIDObj}).Decode(&game)
-	if err != nil {
-		// Return an error if the game is not found
-		return nil, errors.New("game not found")
+		return nil, err
 	}
 
-	// Calculate the hand value for each player
+	// Calculate the hand value for each player using the game's scoring strategy
+	strategy := game.ScoringStrategy()
 	playerHandValues := []PlayerHandValue{}
 	for player, hand := range game.PlayerHands {
-		totalValue := 0
-		for _, card := range hand {
-			// Add the value of each card to the player's total hand value
-			totalValue += s.getCardValue(card)
+		totalValue := strategy.HandValue(hand)
+		if opts.MinValue != nil && totalValue < *opts.MinValue {
+			continue
 		}
 		// Append the player's name and hand value to the playerHandValues slice
-		playerHandValues = append(playerHandValues, PlayerHandValue{
+		value := PlayerHandValue{
 			PlayerName: player,
 			HandValue:  totalValue,
-		})
+		}
+
+		// Only break down the hands the viewer is allowed to see
+		if opts.Breakdown && game.CanViewHand(opts.Viewer, player) {
+			value.Cards = []CardBreakdown{}
+			for _, card := range hand {
+				value.Cards = append(value.Cards, CardBreakdown{Card: game.MarkWild([]models.Card{card})[0], Value: strategy.HandValue([]models.Card{card})})
+			}
+		}
+		playerHandValues = append(playerHandValues, value)
 	}
 
-	// Sort the players by hand value in descending order
+	// Sort the players in the order asked for, or best hand first: descending values, or ascending for penalty
+	// scoring. Players with equal hands are listed by name.
+	ascending := opts.Order == OrderAscending || (opts.Order == "" && strategy.LowestWins())
 	sort.Slice(playerHandValues, func(i, j int) bool {
-		return playerHandValues[i].HandValue > playerHandValues[j].HandValue
+		a, b := playerHandValues[i], playerHandValues[j]
+		if a.HandValue != b.HandValue {
+			return (a.HandValue < b.HandValue) == ascending
+		}
+		return a.PlayerName < b.PlayerName
 	})
 
+	// Keep only the top of the list if a limit was given
+	if opts.Limit > 0 && len(playerHandValues) > opts.Limit {
+		playerHandValues = playerHandValues[:opts.Limit]
+	}
+
 	// Return the sorted list of players with their hand values
 	return playerHandValues, nil
 }
-
-// Helper function to get the value of a card
-func (s *GameService) getCardValue(card models.Card) int {
-	switch card.Value {
-	case "Ace":
-		return 1
-	case "2":
-		return 2
-	case "3":
-		return 3
-	case "4":
-		return 4
-	case "5":
-		return 5
-	case "6":
-		return 6
-	case "7":
-		return 7
-	case "8":
-		return 8
-	case "9":
-		return 9
-	case "10":
-		return 10
-	case "Jack":
-		return 11
-	case "Queen":
-		return 12
-	case "King":
-		return 13
-	default:
-		return 0
-	}
-}
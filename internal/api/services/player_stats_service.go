@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetPlayerStats retrieves a player's career statistics. If fields are given, only those stored fields are loaded.
+// Players who have not finished a game yet get empty statistics rather than an error.
+func (s *GameService) GetPlayerStats(playerName string, fields ...string) (*models.PlayerStats, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	// Only ask the server for the requested fields
+	opts := options.FindOne()
+	if len(fields) > 0 {
+		projection := bson.M{}
+		for _, field := range fields {
+			projection[field] = 1
+		}
+		opts.SetProjection(projection)
+	}
+
+	stats := &models.PlayerStats{PlayerName: playerName, ModeCounts: map[string]int{}}
+	err := db.Retry(ctx, func() error {
+		return s.playerStats.FindOne(ctx, bson.M{"_id": playerName}, opts).Decode(stats)
+	})
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// recordPlayerStats folds a finished game into the career statistics of every player seated in it.
+// Each player's document is updated by an aggregation pipeline, so the streaks and favorite mode are derived
+// from the stored counters inside MongoDB. Games that ended without a winner, such as ones an admin ended,
+// count as played but leave the streaks alone. In a team game every player on the winning team is credited with the win.
+// The players' last_played is set to playedAt.
+func (s *GameService) recordPlayerStats(ctx context.Context, game *models.Game, playedAt time.Time) error {
+	mode := game.Mode
+	if mode == "" {
+		mode = models.ModeStandard
+	}
+	points := game.FinalPoints()
+
+	for _, player := range game.Players {
+		won := game.Won(player)
+		wins := 0
+		if won {
+			wins = 1
+		}
+
+		// Games the player took hints in are counted on their own too, so assisted results can be told apart
+		hinted, hintedWins := 0, 0
+		if containsPlayer(game.Hinted, player) {
+			hinted, hintedWins = 1, wins
+		}
+
+		// Extend the streak on a win, break it on a loss, and keep it when nobody won
+		streak := interface{}("$current_streak")
+		if won {
+			streak = bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$current_streak", 0}}, 1}}
+		} else if game.Winner != "" {
+			streak = 0
+		}
+
+		counters := bson.M{
+			"games_played": bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$games_played", 0}}, 1}},
+			"games_won":    bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$games_won", 0}}, wins}},
+			"total_points": bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$total_points", 0}}, points[player]}},
+			"hinted_games": bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$hinted_games", 0}}, hinted}},
+			"hinted_wins":  bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$hinted_wins", 0}}, hintedWins}},
+			"mode_counts": bson.M{"$mergeObjects": bson.A{
+				bson.M{"$ifNull": bson.A{"$mode_counts", bson.M{}}},
+				bson.M{mode: bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$mode_counts." + mode, 0}}, 1}}},
+			}},
+			"current_streak": bson.M{"$ifNull": bson.A{streak, 0}},
+			"last_played":    playedAt,
+		}
+		if s.org != db.DefaultOrg {
+			counters["org_id"] = s.org
+		}
+
+		pipeline := mongo.Pipeline{
+			// Update the counters
+			{{Key: "$set", Value: counters}},
+			// Derive the best streak and the most played mode from the updated counters
+			{{Key: "$set", Value: bson.M{
+				"best_streak": bson.M{"$max": bson.A{bson.M{"$ifNull": bson.A{"$best_streak", 0}}, "$current_streak"}},
+				"favorite_mode": bson.M{"$let": bson.M{
+					"in": "$$top.k",
+					"vars": bson.M{"top": bson.M{"$reduce": bson.M{
+						"input":        bson.M{"$objectToArray": "$mode_counts"},
+						"initialValue": bson.M{"k": "", "v": 0},
+						"in":           bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$$this.v", "$$value.v"}}, "$$this", "$$value"}},
+					}}},
+				}},
+			}}},
+		}
+
+		var updated models.PlayerStats
+		opts := options.FindOneAndUpdate().
+			SetUpsert(true).
+			SetReturnDocument(options.After).
+			SetProjection(bson.M{"games_played": 1})
+		if err := s.playerStats.FindOneAndUpdate(ctx, bson.M{"_id": player}, pipeline, opts).Decode(&updated); err != nil {
+			return err
+		}
+
+		// Finishing enough games unlocks the centurion achievement
+		if updated.GamesPlayed >= models.CenturionGames {
+			if err := s.unlockAchievement(ctx, game.ID, player, models.AchievementCenturion); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rebuildPlayerStats recounts every player's career statistics from the finished games in the archive, in the
+// order they were archived, replacing the statistics kept so far, and returns how many games it counted. It reads
+// the whole archive, so it runs as a background job; statistics read while it runs are only partly rebuilt.
+func (s *GameService) rebuildPlayerStats(ctx context.Context) (int, error) {
+	// The delete is idempotent, so it is safe to retry
+	if err := db.Retry(ctx, func() error {
+		_, err := s.playerStats.DeleteMany(ctx, bson.M{})
+		return err
+	}); err != nil {
+		return 0, err
+	}
+
+	cursor, err := s.archive.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "archived_at", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	counted := 0
+	for cursor.Next(ctx) {
+		var archived models.ArchivedGame
+		if err := cursor.Decode(&archived); err != nil {
+			return counted, err
+		}
+
+		// Expand gzipped archives to reach the game
+		game := archived.Game
+		if archived.Compressed {
+			payload, err := decompressArchive(archived.Payload)
+			if err != nil {
+				return counted, err
+			}
+			game = payload.Game
+		}
+		if game == nil || game.Status != models.StatusFinished {
+			continue
+		}
+
+		if err := s.recordPlayerStats(ctx, game, archived.ArchivedAt); err != nil {
+			return counted, err
+		}
+		counted++
+	}
+	return counted, cursor.Err()
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"my-card-game/internal/notify"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetProfile retrieves the player's profile. A player who has not saved any preferences gets the defaults,
+// with turn alerts off.
+func (ss *SocialService) GetProfile(player string) (*models.PlayerProfile, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	return ss.findProfile(ctx, player)
+}
+
+// SetNotificationPrefs saves how the player wants to be alerted when it is their turn and returns their profile.
+// Every channel chosen must be offered by the server and have an address to deliver to.
+func (ss *SocialService) SetNotificationPrefs(player string, prefs models.NotificationPrefs) (*models.PlayerProfile, error) {
+	// Check the preferences before they are saved
+	if prefs.Channels == nil {
+		prefs.Channels = []string{}
+	}
+	if err := prefs.Check(); err != nil {
+		return nil, err
+	}
+	for _, channel := range prefs.Channels {
+		if _, ok := ss.notifiers[channel]; !ok {
+			return nil, fmt.Errorf("%s alerts are not offered on this server", channel)
+		}
+	}
+	if prefs.WebhookURL != "" {
+		if err := notify.CheckWebhookURL(prefs.WebhookURL); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	profile := &models.PlayerProfile{Player: player, Notifications: prefs, UpdatedAt: time.Now().UTC()}
+	// The update is idempotent, so it is safe to retry
+	err := db.Retry(ctx, func() error {
+		_, err := ss.profiles.UpdateOne(ctx, bson.M{"_id": player}, bson.M{
+			"$set": bson.M{"notifications": profile.Notifications, "updated_at": profile.UpdatedAt},
+		}, options.Update().SetUpsert(true))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// findProfile loads the player's profile, or the default profile if they have not saved one.
+func (ss *SocialService) findProfile(ctx context.Context, player string) (*models.PlayerProfile, error) {
+	profile := models.PlayerProfile{Player: player, Notifications: models.NotificationPrefs{Channels: []string{}}}
+	err := db.Retry(ctx, func() error {
+		return ss.profiles.FindOne(ctx, bson.M{"_id": player}).Decode(&profile)
+	})
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+	return &profile, nil
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// PlayerDeadwood represents the deadwood left in a player's hand at the end of a rummy round.
+// It includes the player's name, the unmelded cards, and their total deadwood points.
+type PlayerDeadwood struct {
+	PlayerName string        `json:"player_name"`
+	Cards      []models.Card `json:"cards,omitempty"`
+	Deadwood   int           `json:"deadwood"`
+}
+
+// DeclareMeld lays down a set or run from a player's hand in a Gin Rummy game.
+// The cards are validated server-side, removed from the player's hand, and placed on the table as a new meld.
+func (s *GameService) DeclareMeld(gameID, playerName string, cards []models.Card) (*models.Meld, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate that the player holds the cards and that they form a set or run
+	if err := game.CheckAction(models.Action{Type: models.ActionMeld, Player: playerName, Cards: cards}); err != nil {
+		return nil, err
+	}
+	meldType, ordered, err := game.ClassifyMeld(cards)
+	if err != nil {
+		return nil, err
+	}
+
+	// Take the cards out of the player's hand
+	hand, _ := models.RemoveCards(game.PlayerHands[playerName], cards)
+	game.PlayerHands[playerName] = hand
+
+	// Place the new meld on the table
+	meld := models.Meld{
+		ID:    len(game.Melds) + 1,
+		Owner: playerName,
+		Type:  meldType,
+		Cards: ordered,
+	}
+	game.Melds = append(game.Melds, meld)
+
+	// Save the change and check the state it leaves the game in, in one transaction
+	game.Checksum, err = s.saveChecked(ctx, gameIDObj, bson.M{
+		"$set": bson.M{"player_hands": game.PlayerHands, "melds": game.Melds},
+	}, actionMeldDeclared, playerName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Acting counts as activity for the inactivity check
+	if err := s.touchPlayer(ctx, gameIDObj, playerName); err != nil {
+		return nil, err
+	}
+
+	game.MarkWild(meld.Cards)
+	return &meld, nil
+}
+
+// LayOff adds cards from a player's hand onto an existing meld on the table.
+// The extended meld must still be a valid set or run of the same type.
+func (s *GameService) LayOff(gameID, playerName string, meldID int, cards []models.Card) (*models.Meld, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check that the player holds the cards and that the extended meld is still valid and of the same type
+	if err := game.CheckAction(models.Action{Type: models.ActionLayOff, Player: playerName, Cards: cards, MeldID: meldID}); err != nil {
+		return nil, err
+	}
+
+	// Find the meld being extended, which the rules have checked exists
+	index := 0
+	for i, meld := range game.Melds {
+		if meld.ID == meldID {
+			index = i
+			break
+		}
+	}
+	meld := game.Melds[index]
+	_, ordered, err := game.ClassifyMeld(append(append([]models.Card{}, meld.Cards...), cards...))
+	if err != nil {
+		return nil, err
+	}
+
+	// Take the cards out of the player's hand
+	hand, _ := models.RemoveCards(game.PlayerHands[playerName], cards)
+	game.PlayerHands[playerName] = hand
+	meld.Cards = ordered
+	game.Melds[index] = meld
+
+	// Save the change and check the state it leaves the game in, in one transaction
+	game.Checksum, err = s.saveChecked(ctx, gameIDObj, bson.M{
+		"$set": bson.M{"player_hands": game.PlayerHands, "melds": game.Melds},
+	}, actionLaidOff, playerName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Acting counts as activity for the inactivity check
+	if err := s.touchPlayer(ctx, gameIDObj, playerName); err != nil {
+		return nil, err
+	}
+
+	game.MarkWild(meld.Cards)
+	return &meld, nil
+}
+
+// GetMelds returns the melds currently laid down on the table, with any wild cards in them flagged.
+func (s *GameService) GetMelds(gameID string) ([]models.Meld, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, _, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	if game.Melds == nil {
+		return []models.Meld{}, nil
+	}
+	game.MarkWildCards()
+	return game.Melds, nil
+}
+
+// GetDeadwood computes the deadwood points left in every player's hand at the end of a rummy round.
+// The unmelded cards are only listed for hands the viewer is allowed to see.
+// The list is sorted from the lowest deadwood (the best position) to the highest.
+func (s *GameService) GetDeadwood(gameID string, viewer models.Viewer) ([]PlayerDeadwood, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, _, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.Mode != models.ModeGinRummy {
+		return nil, errors.New("deadwood is only counted in gin rummy games")
+	}
+
+	// Count the unmelded cards left in each player's hand
+	deadwood := []PlayerDeadwood{}
+	for _, player := range game.Players {
+		hand := game.PlayerHands[player]
+		if hand == nil {
+			hand = []models.Card{}
+		}
+		entry := PlayerDeadwood{
+			PlayerName: player,
+			Deadwood:   models.Deadwood(hand),
+		}
+		if game.CanViewHand(viewer, player) {
+			entry.Cards = hand
+		}
+		deadwood = append(deadwood, entry)
+	}
+
+	// Sort so the player with the least deadwood comes first
+	sort.SliceStable(deadwood, func(i, j int) bool {
+		return deadwood[i].Deadwood < deadwood[j].Deadwood
+	})
+
+	return deadwood, nil
+}
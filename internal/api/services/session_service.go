@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/auth"
+	"my-card-game/internal/db"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// minPlayerPassword is the shortest password a player account accepts.
+const minPlayerPassword = 8
+
+// SessionService provides services related to player sessions and the accounts that claim player names.
+// It issues session tokens to the players who hold a name, and resolves the tokens back to player identities.
+type SessionService struct {
+	collection *mongo.Collection
+	accounts   *mongo.Collection
+	org        string
+	ttl        time.Duration
+}
+
+// NewSessionService creates and returns a new instance of SessionService for an organization's players.
+// Sessions issued by the service remain valid for the given time to live, and only in the organization.
+func NewSessionService(org string, ttl time.Duration) *SessionService {
+	return &SessionService{
+		collection: db.GetOrgCollection(org, "sessions"),
+		accounts:   db.GetOrgCollection(org, "player_accounts"),
+		org:        org,
+		ttl:        ttl,
+	}
+}
+
+// CreateSession issues a new session for the given player.
+// It returns the raw session token, which is only ever handed to the client, along with the stored session.
+// It does not check that the caller holds the name; callers must first sign the player in with SignIn, or claim the
+// name with Register or ClaimPlayer.
+func (ss *SessionService) CreateSession(playerName string) (string, *models.Session, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	if playerName == "" {
+		return "", nil, errors.New("player name is required")
+	}
+
+	// Generate a random 256-bit token
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, err
+	}
+	token := hex.EncodeToString(raw)
+
+	// Store the session under the hash of the token
+	now := time.Now().UTC()
+	session := &models.Session{
+		TokenHash:  hashToken(token),
+		PlayerName: playerName,
+		OrgID:      ss.org,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(ss.ttl),
+	}
+
+	// The session is keyed by its token, so retrying an insert that may have landed cannot duplicate it
+	err := db.Retry(ctx, func() error {
+		_, err := ss.collection.InsertOne(ctx, session)
+		if mongo.IsDuplicateKeyError(err) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, session, nil
+}
+
+// Register claims an unclaimed player name with a password, and issues a session for it.
+func (ss *SessionService) Register(playerName, password string) (string, *models.Session, error) {
+	hash, err := hashPlayerPassword(password)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := ss.claim(playerName, hash); err != nil {
+		return "", nil, err
+	}
+	return ss.CreateSession(playerName)
+}
+
+// ClaimPlayer claims an unclaimed player name without a password, for an anonymous caller joining a game with it.
+// The caller is then issued a session for the name, and can set a password with SetPassword to sign in again once
+// it expires.
+func (ss *SessionService) ClaimPlayer(playerName string) error {
+	return ss.claim(playerName, "")
+}
+
+// ReleasePlayer gives up a name claimed with ClaimPlayer, when the join it was claimed for fails. Names that
+// have a password are kept.
+func (ss *SessionService) ReleasePlayer(playerName string) error {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	// The delete is idempotent, so it is safe to retry
+	return db.Retry(ctx, func() error {
+		_, err := ss.accounts.DeleteOne(ctx, bson.M{"_id": playerName, "password_hash": bson.M{"$exists": false}})
+		return err
+	})
+}
+
+// IsClaimed reports whether someone has claimed the player name, with or without a password.
+func (ss *SessionService) IsClaimed(playerName string) (bool, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	count, err := ss.accounts.CountDocuments(ctx, bson.M{"_id": playerName}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// SignIn checks the player's password and issues a session for them. Names claimed without a password cannot
+// sign in until their player sets one.
+func (ss *SessionService) SignIn(playerName, password string) (string, *models.Session, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	var account models.PlayerAccount
+	err := ss.accounts.FindOne(ctx, bson.M{"_id": playerName}).Decode(&account)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", nil, ErrBadCredentials
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if account.PasswordHash == "" || bcrypt.CompareHashAndPassword([]byte(account.PasswordHash), []byte(password)) != nil {
+		return "", nil, ErrBadCredentials
+	}
+	return ss.CreateSession(playerName)
+}
+
+// SetPassword sets or changes the password of the player's account, claiming the name if it has not been claimed
+// yet. Only the player holding a session for the name may call it.
+func (ss *SessionService) SetPassword(playerName, password string) error {
+	hash, err := hashPlayerPassword(password)
+	if err != nil {
+		return err
+	}
+
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	// The update is idempotent, so it is safe to retry
+	return db.Retry(ctx, func() error {
+		_, err := ss.accounts.UpdateOne(ctx, bson.M{"_id": playerName}, bson.M{
+			"$set":         bson.M{"password_hash": hash},
+			"$setOnInsert": bson.M{"created_at": time.Now().UTC()},
+		}, options.Update().SetUpsert(true))
+		return err
+	})
+}
+
+// claim stores the account claiming the name, failing with ErrNameTaken if someone already holds it.
+func (ss *SessionService) claim(playerName, passwordHash string) error {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	if err := checkPlayerName(playerName); err != nil {
+		return err
+	}
+	_, err := ss.accounts.InsertOne(ctx, models.PlayerAccount{
+		PlayerName:   playerName,
+		PasswordHash: passwordHash,
+		CreatedAt:    time.Now().UTC(),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrNameTaken
+	}
+	return err
+}
+
+// GetSession retrieves the unexpired session for a token.
+func (ss *SessionService) GetSession(ctx context.Context, token string) (*models.Session, error) {
+	var session models.Session
+	err := ss.collection.FindOne(ctx, bson.M{
+		"_id":        hashToken(token),
+		"expires_at": bson.M{"$gt": time.Now().UTC()},
+	}).Decode(&session)
+	if err != nil {
+		return nil, errors.New("session not found or expired")
+	}
+	return &session, nil
+}
+
+// ResolveSession returns the identity of the player owning an unexpired session token.
+// It implements auth.Resolver so the service can back the authentication middleware.
+func (ss *SessionService) ResolveSession(ctx context.Context, token string) (auth.Identity, bool) {
+	session, err := ss.GetSession(ctx, token)
+	if err != nil {
+		return auth.Identity{}, false
+	}
+	return auth.Identity{PlayerName: session.PlayerName}, true
+}
+
+// PurgeExpiredSessions deletes every session that has expired and returns how many were removed.
+// MongoDB's TTL index removes them too, but only on its own schedule, which can lag behind under load.
+func (ss *SessionService) PurgeExpiredSessions() (int, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	result, err := ss.collection.DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lte": time.Now().UTC()}})
+	if err != nil {
+		return 0, err
+	}
+	return int(result.DeletedCount), nil
+}
+
+// hashPlayerPassword checks that a player's password is long enough and returns its bcrypt hash.
+func hashPlayerPassword(password string) (string, error) {
+	if len(password) < minPlayerPassword {
+		return "", fmt.Errorf("password must be at least %d characters", minPlayerPassword)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(hash), err
+}
+
+// hashToken returns the hex-encoded SHA-256 hash of a token.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// maxGameNameLength caps the length of a game's name.
+const maxGameNameLength = 100
+
+// GamePatch describes a partial update to a game's metadata.
+// Only the fields that are present in the request are changed.
+type GamePatch struct {
+	Name     *string               `json:"name" validate:"name,max=100"`
+	Private  *bool                 `json:"private"`
+	Settings *models.SettingsPatch `json:"settings"`
+}
+
+// UpdateGame applies a partial update to a game's name, privacy, and settings.
+// Only the game's owner or an admin can update a game, and settings can only be changed while the game is in the lobby.
+// Only the changed fields are written back, rather than replacing the whole document.
+func (s *GameService) UpdateGame(gameID string, patch GamePatch, viewer models.Viewer) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the game's owner and admins can update the game
+	if !canManageGame(game, viewer) {
+		return nil, ErrForbidden
+	}
+
+	// Collect only the fields that change
+	update := bson.M{}
+
+	if patch.Name != nil {
+		name := strings.TrimSpace(*patch.Name)
+		if name == "" {
+			return nil, errors.New("name cannot be empty")
+		}
+		if len(name) > maxGameNameLength {
+			return nil, errors.New("name is too long")
+		}
+		game.Name = name
+		update["name"] = game.Name
+	}
+
+	if patch.Private != nil {
+		game.Private = *patch.Private
+		update["private"] = game.Private
+	}
+
+	if patch.Settings != nil {
+		// Settings are fixed once the game has started
+		if game.Status != "" && game.Status != models.StatusLobby {
+			return nil, errors.New("settings can only be changed in the lobby")
+		}
+
+		// Apply the changes and validate the resulting settings
+		patch.Settings.Apply(&game.Settings)
+		game.Settings.ApplyDefaults()
+		if err := game.Settings.Validate(); err != nil {
+			return nil, err
+		}
+		if game.Settings.MaxPlayers > 0 && len(game.Players) > game.Settings.MaxPlayers {
+			return nil, errors.New("max_players cannot be less than the number of seated players")
+		}
+		for _, seat := range game.PlayerSeats() {
+			if size := game.TableSize(); size > 0 && seat > size {
+				return nil, errors.New("the table cannot lose seats that players are sitting in")
+			}
+		}
+		if game.Settings.HandSize*len(game.Players) > game.Settings.ShoeSize() {
+			return nil, errors.New("hand_size is too large to deal a hand to every seated player")
+		}
+		update["settings"] = game.Settings
+	}
+
+	// Nothing to write if the patch was empty
+	if len(update) == 0 {
+		return game, nil
+	}
+
+	// The update is idempotent, so it is safe to retry
+	err = db.Retry(ctx, func() error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{"$set": update})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// A larger table seats the players waiting for one
+	if patch.Settings != nil {
+		if err := s.seatWaitlisted(ctx, gameIDObj, game); err != nil {
+			return nil, err
+		}
+	}
+
+	return game, nil
+}
+
+// UpdateSettings applies a partial update to a game's settings.
+// Settings can only be changed by the game's owner or an admin while the game is still in the lobby.
+func (s *GameService) UpdateSettings(gameID string, patch models.SettingsPatch, viewer models.Viewer) (*models.Game, error) {
+	return s.UpdateGame(gameID, GamePatch{Settings: &patch}, viewer)
+}
+
+// SetHouseRules attaches a house rules script to a game, replacing any it had, or removes its house rules if the
+// script is empty. Only the game's owner or an admin can change the house rules, and only while the game is in the
+// lobby, so a game is played under the same rules from its first move to its last.
+func (s *GameService) SetHouseRules(gameID, script string, viewer models.Viewer) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the game's owner and admins can change the rules
+	if !canManageGame(game, viewer) {
+		return nil, ErrForbidden
+	}
+	if game.Status != "" && game.Status != models.StatusLobby {
+		return nil, errors.New("house rules can only be changed in the lobby")
+	}
+
+	// Compile the script, refusing it if it has errors or is over the limits
+	if err := game.SetHouseRules(script); err != nil {
+		return nil, err
+	}
+
+	update := bson.M{"$set": bson.M{"house_rules": game.HouseRules}}
+	if game.HouseRules == "" {
+		update = bson.M{"$unset": bson.M{"house_rules": ""}}
+	}
+	// The update is idempotent, so it is safe to retry
+	if err := db.Retry(ctx, func() error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, update)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"log"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ListShuffles returns the shuffle log of a game, oldest shuffle first: which entropy source each shuffle drew
+// from, and why the configured source was passed over if it failed. The log is kept after the game is deleted.
+func (s *GameService) ListShuffles(gameID string) ([]models.ShuffleRecord, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	gameIDObj, err := primitive.ObjectIDFromHex(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "shuffled_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := s.shuffles.Find(ctx, bson.M{"game_id": gameIDObj}, opts)
+	if err != nil {
+		return nil, err
+	}
+	records := []models.ShuffleRecord{}
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// VerifyReplay re-simulates a game from its recorded shuffle seeds and event log and reports whether the replay
+// ends in the game's recorded state. Finished games are replayed from the archive.
+func (s *GameService) VerifyReplay(gameID string) (*models.ReplayReport, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	// Load the game and its events, from the archive once the game has finished
+	var events []models.Event
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err == nil {
+		events, err = s.GetEvents(gameID)
+	} else if archived, archiveErr := s.GetArchivedGame(gameID); archiveErr == nil && archived.Game != nil {
+		game, gameIDObj, events, err = archived.Game, archived.ID, archived.Events, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the shuffles in the order they were made
+	opts := options.Find().SetSort(bson.D{{Key: "shuffled_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := s.shuffles.Find(ctx, bson.M{"game_id": gameIDObj}, opts)
+	if err != nil {
+		return nil, err
+	}
+	records := []models.ShuffleRecord{}
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	return models.Replay(game, events, records), nil
+}
+
+// recordShuffles logs the shuffles made to a game since it was loaded. Callers pass the context of the transaction
+// that saves the shuffled deck, so the records are only kept if the deck is. A shuffle that fell back to
+// crypto/rand is also logged to the server log, as the configured source needs attention.
+func (s *GameService) recordShuffles(ctx context.Context, game *models.Game) error {
+	if len(game.Shuffles) == 0 {
+		return nil
+	}
+
+	records := make([]interface{}, 0, len(game.Shuffles))
+	for _, record := range game.Shuffles {
+		record.ID = primitive.NewObjectID()
+		record.GameID = game.ID
+		if record.Fallback != "" {
+			log.Printf("shuffle of game %s fell back to %s: %s", game.ID.Hex(), record.Source, record.Fallback)
+		}
+		records = append(records, record)
+	}
+	_, err := s.shuffles.InsertMany(ctx, records)
+	return err
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"my-card-game/internal/entropy"
+	"runtime"
+)
+
+// Limits on the continuations one simulation request may run.
+const (
+	DefaultSimulationRuns = 1000
+	MaxSimulationRuns     = 20000
+)
+
+// errRunsOutOfRange is returned for a simulation asked to run a negative number of continuations, or too many.
+var errRunsOutOfRange = fmt.Errorf("runs must be between 1 and %d, or 0 for the default of %d", MaxSimulationRuns, DefaultSimulationRuns)
+
+// SimulationOptions chooses how many continuations a simulation runs and what they are drawn from.
+// Zero Runs runs DefaultSimulationRuns, and a nil Seed draws a fresh one.
+type SimulationOptions struct {
+	Runs int    `json:"runs"`
+	Seed *int64 `json:"seed"`
+}
+
+// SimulateGame plays many random continuations of an active game in parallel and returns how often each player
+// won them. Continuations see every hand, so only viewers allowed to see every hand may run them; ErrForbidden is
+// returned for anyone else. Each worker draws from its own seeded source, so a simulation repeats exactly when
+// run again with the seed it reports.
+func (s *GameService) SimulateGame(gameID string, opts SimulationOptions, viewer models.Viewer) (*models.Simulation, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, _, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if err := game.CanSimulate(); err != nil {
+		return nil, err
+	}
+	for _, player := range game.Players {
+		if !game.CanViewHand(viewer, player) {
+			return nil, ErrForbidden
+		}
+	}
+
+	runs := opts.Runs
+	if runs == 0 {
+		runs = DefaultSimulationRuns
+	}
+	if runs < 0 || runs > MaxSimulationRuns {
+		return nil, errRunsOutOfRange
+	}
+	seed, err := simulationSeed(opts.Seed)
+	if err != nil {
+		return nil, err
+	}
+
+	// Split the runs between the workers, each playing its share with its own source
+	workers := runtime.NumCPU()
+	if workers > runs {
+		workers = runs
+	}
+	results := make(chan []string, runs)
+	for w := 0; w < workers; w++ {
+		share := runs / workers
+		if w < runs%workers {
+			share++
+		}
+		go func(source entropy.Source, share int) {
+			for i := 0; i < share; i++ {
+				results <- game.SimulateContinuation(source)
+			}
+		}(entropy.Seeded(seed+int64(w)), share)
+	}
+
+	// Tally the winners, sharing a win between everyone who won the same continuation
+	wins := map[string]float64{}
+	simulation := &models.Simulation{Runs: runs, Seed: seed, Players: []models.SimulatedPlayer{}}
+	for i := 0; i < runs; i++ {
+		winners := <-results
+		if len(winners) == 0 {
+			simulation.Undecided++
+			continue
+		}
+		share := 1 / float64(len(winners))
+		if game.Mode == models.ModeBlackjack {
+			// Blackjack players each play the house, so every player who beats it wins outright
+			share = 1
+		}
+		for _, winner := range winners {
+			wins[winner] += share
+		}
+	}
+	for _, player := range game.Players {
+		simulation.Players = append(simulation.Players, models.SimulatedPlayer{
+			Player:         player,
+			Wins:           wins[player],
+			WinProbability: wins[player] / float64(runs),
+		})
+	}
+
+	return simulation, nil
+}
+
+// simulationSeed returns the seed asked for, or a fresh one from crypto/rand.
+func simulationSeed(seed *int64) (int64, error) {
+	if seed != nil {
+		return *seed, nil
+	}
+	var raw [8]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return 0, err
+	}
+	return int64(binary.BigEndian.Uint64(raw[:]) >> 1), nil
+}
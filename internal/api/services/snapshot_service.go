@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreateSnapshot saves a named copy of a game's current state.
+// Only the game's owner (its dealer) or an admin can take snapshots, and names must be unique within a game.
+func (s *GameService) CreateSnapshot(gameID, name string, viewer models.Viewer) (*models.Snapshot, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("snapshot name is required")
+	}
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageGame(game, viewer) {
+		return nil, ErrForbidden
+	}
+
+	// Snapshot names identify the snapshot when rolling back, so they cannot repeat
+	count, err := s.snapshots.CountDocuments(ctx, bson.M{"game_id": gameIDObj, "name": name})
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, errors.New("a snapshot with that name already exists")
+	}
+
+	snapshot := models.Snapshot{
+		ID:        primitive.NewObjectID(),
+		GameID:    gameIDObj,
+		Name:      name,
+		Game:      *game,
+		CreatedBy: viewer.PlayerName,
+		CreatedAt: time.Now().UTC(),
+	}
+	if _, err := s.snapshots.InsertOne(ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// ListSnapshots lists the snapshots taken of a game, oldest first.
+func (s *GameService) ListSnapshots(gameID string) ([]models.Snapshot, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	_, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip loading the saved game states, which are not returned
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetProjection(bson.M{"game": 0})
+	cursor, err := s.snapshots.Find(ctx, bson.M{"game_id": gameIDObj}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := []models.Snapshot{}
+	if err := cursor.All(ctx, &snapshots); err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// RollbackToSnapshot restores a game to the state saved in one of its snapshots.
+// Only the game's owner (its dealer) or an admin can roll a game back. A rolled_back event is recorded.
+func (s *GameService) RollbackToSnapshot(gameID, name string, viewer models.Viewer) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageGame(game, viewer) {
+		return nil, ErrForbidden
+	}
+
+	var snapshot models.Snapshot
+	err = s.snapshots.FindOne(ctx, bson.M{"game_id": gameIDObj, "name": name}).Decode(&snapshot)
+	if err != nil {
+		return nil, errors.New("snapshot not found")
+	}
+
+	restored := snapshot.Game
+	restored.ID = gameIDObj
+
+	// Refuse to restore a snapshot that was changed after it was taken
+	if err := restored.VerifyChecksum(); err != nil {
+		return nil, err
+	}
+
+	entry := auditGame(models.AuditRolledBack, viewer, gameIDObj, name)
+	entry.Before = game.AuditSummary()
+	entry.After = restored.AuditSummary()
+
+	// Restore the game and record the rollback together
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		// Replace the whole game document with the saved state
+		if _, err := s.collection.ReplaceOne(ctx, bson.M{"_id": gameIDObj}, restored); err != nil {
+			return err
+		}
+
+		// Record the rollback in the event log
+		if err := s.recordEvent(ctx, gameIDObj, models.EventRolledBack, viewer.PlayerName, map[string]interface{}{"snapshot": name}); err != nil {
+			return err
+		}
+
+		// Record who rolled the game back in the audit log
+		return recordAudit(ctx, s.audit, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &restored, nil
+}
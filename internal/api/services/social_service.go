@@ -0,0 +1,367 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"my-card-game/internal/notify"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SocialService provides services related to friends, game invitations, player notifications, and turn alerts.
+// It interacts with the MongoDB collections where friendships, invitations, notifications, and player profiles are stored,
+// and seats invited players through the GameService.
+type SocialService struct {
+	friendships   *mongo.Collection
+	invitations   *mongo.Collection
+	notifications *mongo.Collection
+	profiles      *mongo.Collection
+	games         *GameService
+	notifiers     map[string]notify.Notifier // Channels turn alerts can be delivered on, by channel
+}
+
+// FriendList holds a player's friends along with the friend requests waiting on either side.
+type FriendList struct {
+	Friends  []string            `json:"friends"`
+	Incoming []models.Friendship `json:"incoming"` // Requests other players sent to this player
+	Outgoing []models.Friendship `json:"outgoing"` // Requests this player sent that are still pending
+}
+
+// notificationLimit is how many of a player's most recent notifications are listed.
+const notificationLimit = 50
+
+// NewSocialService creates and returns a new instance of SocialService.
+// It initializes the service with references to the MongoDB collections where friendships, invitations, notifications, and player profiles are stored,
+// in the organization of the game service.
+func NewSocialService(games *GameService) *SocialService {
+	return &SocialService{
+		friendships:   db.GetOrgCollection(games.Org(), "friendships"),
+		invitations:   db.GetOrgCollection(games.Org(), "invitations"),
+		notifications: db.GetOrgCollection(games.Org(), "notifications"),
+		profiles:      db.GetOrgCollection(games.Org(), "player_profiles"),
+		games:         games,
+	}
+}
+
+// SendFriendRequest sends a friend request from one player to another and notifies the recipient.
+// If the recipient had already asked to be friends, their request is accepted instead.
+func (ss *SocialService) SendFriendRequest(from, to string) (*models.Friendship, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	if from == to {
+		return nil, errors.New("players cannot befriend themselves")
+	}
+
+	// Look for an existing request or friendship in either direction
+	var existing models.Friendship
+	err := ss.friendships.FindOne(ctx, bson.M{"$or": []bson.M{
+		{"from": from, "to": to},
+		{"from": to, "to": from},
+	}}).Decode(&existing)
+	switch {
+	case err == nil && existing.Status == models.RequestAccepted:
+		return nil, errors.New("players are already friends")
+	case err == nil && existing.From == from:
+		return nil, errors.New("friend request already sent")
+	case err == nil:
+		// The other player asked first, so accept their request
+		return ss.RespondToFriendRequest(existing.ID.Hex(), from, true)
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		return nil, err
+	}
+
+	// Store the request and tell the recipient about it together
+	request := &models.Friendship{
+		ID:        primitive.NewObjectID(),
+		From:      from,
+		To:        to,
+		Status:    models.RequestPending,
+		CreatedAt: time.Now().UTC(),
+	}
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := ss.friendships.InsertOne(ctx, request); err != nil {
+			return err
+		}
+		return ss.notify(ctx, to, models.NotifyFriendRequest, map[string]interface{}{"request_id": request.ID.Hex(), "from": from})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// RespondToFriendRequest accepts or declines a pending friend request sent to the player.
+// Declined requests are removed so they can be sent again later; accepting one notifies the sender.
+func (ss *SocialService) RespondToFriendRequest(requestID, player string, accept bool) (*models.Friendship, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	requestIDObj, err := primitive.ObjectIDFromHex(requestID)
+	if err != nil {
+		return nil, errors.New("invalid friend request ID")
+	}
+
+	var request models.Friendship
+	filter := bson.M{"_id": requestIDObj, "to": player, "status": models.RequestPending}
+	if err := ss.friendships.FindOne(ctx, filter).Decode(&request); err != nil {
+		return nil, errors.New("friend request not found")
+	}
+
+	if !accept {
+		request.Status = models.RequestDeclined
+
+		// The delete is idempotent, so it is safe to retry
+		err := db.Retry(ctx, func() error {
+			_, err := ss.friendships.DeleteOne(ctx, filter)
+			return err
+		})
+		return &request, err
+	}
+
+	now := time.Now().UTC()
+	request.Status = models.RequestAccepted
+	request.AcceptedAt = &now
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := ss.friendships.UpdateOne(ctx, filter, bson.M{
+			"$set": bson.M{"status": request.Status, "accepted_at": now},
+		})
+		if err != nil {
+			return err
+		}
+		return ss.notify(ctx, request.From, models.NotifyFriendAccepted, map[string]interface{}{"player": player})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+// ListFriends lists the player's friends, sorted by name, and the friend requests still pending in either direction.
+func (ss *SocialService) ListFriends(player string) (*FriendList, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	friendships := []models.Friendship{}
+	err := db.Retry(ctx, func() error {
+		cursor, err := ss.friendships.Find(ctx, bson.M{"$or": []bson.M{{"from": player}, {"to": player}}})
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, &friendships)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := &FriendList{Friends: []string{}, Incoming: []models.Friendship{}, Outgoing: []models.Friendship{}}
+	for _, friendship := range friendships {
+		switch {
+		case friendship.Status == models.RequestAccepted && friendship.From == player:
+			list.Friends = append(list.Friends, friendship.To)
+		case friendship.Status == models.RequestAccepted:
+			list.Friends = append(list.Friends, friendship.From)
+		case friendship.To == player:
+			list.Incoming = append(list.Incoming, friendship)
+		default:
+			list.Outgoing = append(list.Outgoing, friendship)
+		}
+	}
+	sort.Strings(list.Friends)
+
+	return list, nil
+}
+
+// InviteToGame invites a friend to a game the inviting player is part of and notifies them.
+// Inviting a player who already has a pending invitation to the game returns that invitation.
+func (ss *SocialService) InviteToGame(gameID, from, to string) (*models.Invitation, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := ss.games.findGameFields(ctx, gameID, "name", "owner", "players", "status")
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the game's players and its owner can hand out invitations
+	if from != game.Owner && !containsPlayer(game.Players, from) {
+		return nil, ErrForbidden
+	}
+	if game.Status == models.StatusFinished {
+		return nil, errors.New("game has already finished")
+	}
+	if containsPlayer(game.Players, to) {
+		return nil, ErrAlreadySeated
+	}
+
+	// Invitations can only go to friends
+	friends, err := ss.friendships.CountDocuments(ctx, bson.M{
+		"status": models.RequestAccepted,
+		"$or": []bson.M{
+			{"from": from, "to": to},
+			{"from": to, "to": from},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if friends == 0 {
+		return nil, ErrNotFriends
+	}
+
+	// Reuse a pending invitation rather than sending a second one
+	var existing models.Invitation
+	err = ss.invitations.FindOne(ctx, bson.M{"game_id": gameIDObj, "to": to, "status": models.RequestPending}).Decode(&existing)
+	if err == nil {
+		return &existing, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	// Store the invitation and tell the recipient about it together
+	invitation := &models.Invitation{
+		ID:        primitive.NewObjectID(),
+		GameID:    gameIDObj,
+		GameName:  game.Name,
+		From:      from,
+		To:        to,
+		Status:    models.RequestPending,
+		CreatedAt: time.Now().UTC(),
+	}
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := ss.invitations.InsertOne(ctx, invitation); err != nil {
+			return err
+		}
+		return ss.notify(ctx, to, models.NotifyGameInvite, map[string]interface{}{
+			"invitation_id": invitation.ID.Hex(),
+			"game_id":       gameIDObj.Hex(),
+			"game_name":     game.Name,
+			"from":          from,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// ListInvitations lists the player's pending game invitations, newest first.
+func (ss *SocialService) ListInvitations(player string) ([]models.Invitation, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	invitations := []models.Invitation{}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	err := db.Retry(ctx, func() error {
+		cursor, err := ss.invitations.Find(ctx, bson.M{"to": player, "status": models.RequestPending}, opts)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, &invitations)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// RespondToInvitation accepts or declines a pending invitation sent to the player.
+// Accepting seats the player in the game and returns the game; declining returns nil.
+func (ss *SocialService) RespondToInvitation(invitationID, player string, accept bool) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	invitationIDObj, err := primitive.ObjectIDFromHex(invitationID)
+	if err != nil {
+		return nil, errors.New("invalid invitation ID")
+	}
+
+	var invitation models.Invitation
+	filter := bson.M{"_id": invitationIDObj, "to": player, "status": models.RequestPending}
+	if err := ss.invitations.FindOne(ctx, filter).Decode(&invitation); err != nil {
+		return nil, errors.New("invitation not found")
+	}
+
+	// Join the game first, so an invitation to a full game or one the player is banned from stays pending.
+	// The invitation stands in for the game's password, if it has one.
+	var game *models.Game
+	status := models.RequestDeclined
+	if accept {
+		game, err = ss.games.seatPlayer(invitation.GameID.Hex(), player, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		status = models.RequestAccepted
+	}
+
+	// The update is idempotent, so it is safe to retry
+	if err := db.Retry(ctx, func() error {
+		_, err := ss.invitations.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"status": status}})
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// ListNotifications lists the player's most recent notifications, newest first.
+func (ss *SocialService) ListNotifications(player string) ([]models.Notification, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	notifications := []models.Notification{}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(notificationLimit)
+	err := db.Retry(ctx, func() error {
+		cursor, err := ss.notifications.Find(ctx, bson.M{"player": player}, opts)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, &notifications)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// notify stores a notification for the player. The notifications change stream then delivers it
+// to the player's real-time stream on whichever replica they are connected to.
+func (ss *SocialService) notify(ctx context.Context, player, notificationType string, data map[string]interface{}) error {
+	notification := models.Notification{
+		ID:        primitive.NewObjectID(),
+		Player:    player,
+		Type:      notificationType,
+		Data:      data,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	// The notification has a fixed ID, so retrying an insert that may have landed cannot duplicate it
+	return db.Retry(ctx, func() error {
+		_, err := ss.notifications.InsertOne(ctx, notification)
+		if mongo.IsDuplicateKeyError(err) {
+			return nil
+		}
+		return err
+	})
+}
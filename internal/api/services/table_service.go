@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// GetSeating returns the seating arrangement of a game's table: every seat with the player sitting in it or the
+// player it is held for, along with the dealer button and blinds of a running game.
+func (s *GameService) GetSeating(gameID string) ([]models.Seat, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	// Load only what the chart needs, not the deck or hands
+	game, _, err := s.findGameFields(ctx, gameID, "status", "settings", "players", "seat_numbers", "reservations",
+		"dealer_index", "chips", "hand_number", "small_blind", "big_blind", "blind_schedule")
+	if err != nil {
+		return nil, err
+	}
+	return game.SeatingChart(), nil
+}
+
+// ChangeSeat moves a seated player to another seat at the table, which must be open or held for them.
+// Seats can only be changed in the lobby, since a move would change the order of play.
+func (s *GameService) ChangeSeat(gameID, playerName string, seat int) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.Status != "" && game.Status != models.StatusLobby {
+		return nil, errors.New("seats can only be changed in the lobby")
+	}
+	if err := game.MoveSeat(playerName, seat); err != nil {
+		return nil, err
+	}
+
+	// Save the change and check the state it leaves the game in, in one transaction
+	game.Checksum, err = s.saveChecked(ctx, gameIDObj, bson.M{
+		"$set": bson.M{
+			"players":      game.Players,
+			"seat_numbers": game.SeatNumbers,
+			"reservations": game.Reservations,
+			"dealer_index": game.DealerIndex,
+		},
+	}, actionSeatChanged, playerName)
+	if err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// ReserveSeat holds a seat at the table for a player who has not joined yet, replacing any seat already held for
+// them. Nobody else can sit there until the reservation is released, and the player is given the seat when they
+// join. Only the game's owner or an admin can reserve seats.
+func (s *GameService) ReserveSeat(gameID string, seat int, playerName string, viewer models.Viewer) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageGame(game, viewer) {
+		return nil, ErrForbidden
+	}
+	if game.Status == models.StatusFinished {
+		return nil, errors.New("game is finished")
+	}
+	if containsPlayer(game.Banned, playerName) {
+		return nil, errors.New("player is banned from this game")
+	}
+	if err := game.ReserveSeat(seat, playerName); err != nil {
+		return nil, err
+	}
+
+	// The update is idempotent, so it is safe to retry
+	err = db.Retry(ctx, func() error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
+			"$set": bson.M{"reservations": game.Reservations},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// ReleaseSeat drops the reservation on a seat, opening it to anyone; the first player on the waitlist takes it.
+// The game's owner, an admin, or the player the seat is held for can release it.
+func (s *GameService) ReleaseSeat(gameID string, seat int, viewer models.Viewer) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the caller may drop the reservation before dropping it
+	reservedFor := ""
+	for _, reservation := range game.Reservations {
+		if reservation.Seat == seat {
+			reservedFor = reservation.Player
+		}
+	}
+	if reservedFor == "" {
+		return nil, errors.New("the seat is not reserved")
+	}
+	if !canManageGame(game, viewer) && viewer.PlayerName != reservedFor {
+		return nil, ErrForbidden
+	}
+	game.ReleaseSeat(seat)
+
+	// The update is idempotent, so it is safe to retry
+	err = db.Retry(ctx, func() error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
+			"$set": bson.M{"reservations": game.Reservations},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Give the released seat to the first player waiting for one
+	if err := s.seatWaitlisted(ctx, gameIDObj, game); err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// maxTeamNameLength caps the length of a team's name.
+const maxTeamNameLength = 32
+
+// TeamStandings describes a game's teams and how they are doing: each team's players, its score
+// (the sum of its players' points), and the winning team once the game is finished.
+type TeamStandings struct {
+	Teams       map[string][]string `json:"teams"`
+	Scores      map[string]int      `json:"scores"`
+	WinningTeam string              `json:"winning_team,omitempty"`
+}
+
+// SetTeams groups a game's players into teams, replacing any teams set before; an empty map makes the game
+// a game without teams again. Teams can only be set by the game's owner or an admin while the game is in the
+// lobby. Players may be named before they join, so the teams are only checked for balance when the game starts.
+func (s *GameService) SetTeams(gameID string, teams map[string][]string, viewer models.Viewer) (*models.Game, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageGame(game, viewer) {
+		return nil, ErrForbidden
+	}
+
+	// Teams are fixed once the game has started
+	if game.Status != "" && game.Status != models.StatusLobby {
+		return nil, errors.New("teams can only be changed in the lobby")
+	}
+
+	// Check the names, and that nobody plays for two teams
+	placed := map[string]bool{}
+	for team, members := range teams {
+		if strings.TrimSpace(team) == "" || len(team) > maxTeamNameLength {
+			return nil, errors.New("team names must be between 1 and 32 characters")
+		}
+		for _, member := range members {
+			if member == "" {
+				return nil, errors.New("team " + team + " has a player without a name")
+			}
+			if placed[member] {
+				return nil, errors.New("player " + member + " is on more than one team")
+			}
+			placed[member] = true
+		}
+	}
+
+	game.Teams = teams
+	if len(teams) == 0 {
+		game.Teams = nil
+	}
+	// The update is idempotent, so it is safe to retry
+	err = db.Retry(ctx, func() error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{"$set": bson.M{"teams": game.Teams}})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// GetTeamStandings returns the teams of a game with their scores and, once the game is finished, the winning team.
+func (s *GameService) GetTeamStandings(gameID string) (*TeamStandings, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, _, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	standings := &TeamStandings{
+		Teams:       game.Teams,
+		Scores:      game.TeamScores(),
+		WinningTeam: game.WinningTeam,
+	}
+	if standings.Teams == nil {
+		standings.Teams = map[string][]string{}
+	}
+	return standings, nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"my-card-game/internal/notify"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SetNotifiers sets the channels turn alerts are delivered on, by channel. Without any, players are only alerted
+// in the app.
+func (ss *SocialService) SetNotifiers(notifiers map[string]notify.Notifier) {
+	ss.notifiers = notifiers
+}
+
+// CheckTurnAlerts alerts each person an active game has been waiting on for at least delay, and returns how many
+// were alerted. Each turn is alerted once: with an in-app notification, and on every channel the player chose
+// in their profile. Players who have not turned turn alerts on are skipped.
+//
+// Turns are noticed when the check runs, so a turn counts as starting when the check first sees it, and a turn
+// that passes away and back between two checks counts as the same turn.
+func (ss *SocialService) CheckTurnAlerts(delay time.Duration) (int, error) {
+	// Create a context with a timeout of 30 seconds since the check scans every active game
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Load the active turn-based games, only pulling the fields the check needs
+	opts := options.Find().SetProjection(bson.M{"name": 1, "mode": 1, "status": 1, "turn": 1, "hand_phase": 1, "bots": 1, "turn_alert": 1})
+	cursor, err := ss.games.collection.Find(ctx, bson.M{"status": models.StatusActive, "turn": bson.M{"$ne": nil}}, opts)
+	if err != nil {
+		return 0, err
+	}
+	games := []models.Game{}
+	if err := cursor.All(ctx, &games); err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UTC()
+	alerted := 0
+	for i := range games {
+		sent, err := ss.checkTurnAlert(&games[i], delay, now)
+		if err != nil {
+			return alerted, err
+		}
+		if sent {
+			alerted++
+		}
+	}
+
+	return alerted, nil
+}
+
+// checkTurnAlert tracks the turn the game is waiting on, and alerts the player to move once it has gone on for
+// delay. It reports whether the player was alerted.
+func (ss *SocialService) checkTurnAlert(game *models.Game, delay time.Duration, now time.Time) (bool, error) {
+	// Create a context with the configured operation timeout to manage the database operations
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	// Start tracking a turn the check has not seen yet, and stop tracking one that is waiting on nobody
+	player, ok := game.PlayerToMove()
+	alert := game.TurnAlert
+	if !ok {
+		if alert == nil {
+			return false, nil
+		}
+		// The update is idempotent, so it is safe to retry
+		err := db.Retry(ctx, func() error {
+			_, err := ss.games.collection.UpdateOne(ctx, bson.M{"_id": game.ID}, bson.M{"$unset": bson.M{"turn_alert": ""}})
+			return err
+		})
+		return false, err
+	}
+	if alert == nil || alert.Player != player {
+		// The update is idempotent, so it is safe to retry
+		err := db.Retry(ctx, func() error {
+			_, err := ss.games.collection.UpdateOne(ctx, bson.M{"_id": game.ID}, bson.M{
+				"$set": bson.M{"turn_alert": models.TurnAlert{Player: player, Since: now}},
+			})
+			return err
+		})
+		return false, err
+	}
+	if alert.Sent || now.Sub(alert.Since) < delay {
+		return false, nil
+	}
+
+	// Mark the turn as alerted before delivering, so a failed delivery is not repeated every run
+	result, err := ss.games.collection.UpdateOne(ctx, bson.M{"_id": game.ID, "turn_alert.player": player, "turn_alert.sent": false}, bson.M{
+		"$set": bson.M{"turn_alert.sent": true},
+	})
+	if err != nil || result.ModifiedCount == 0 {
+		return false, err
+	}
+	return ss.alertTurn(ctx, player, game, alert.Since)
+}
+
+// alertTurn tells the player the game is waiting on their move, if they have turned turn alerts on. The in-app
+// notification is stored first; delivery on the player's other channels is best effort, and failures are logged
+// rather than stopping the check. Each channel bounds its own delivery time.
+func (ss *SocialService) alertTurn(ctx context.Context, player string, game *models.Game, since time.Time) (bool, error) {
+	profile, err := ss.findProfile(ctx, player)
+	if err != nil {
+		return false, err
+	}
+	prefs := profile.Notifications
+	if !prefs.TurnAlerts {
+		return false, nil
+	}
+
+	data := map[string]interface{}{"game_id": game.ID.Hex(), "game_name": game.Name, "since": since}
+	if err := ss.notify(ctx, player, models.NotifyYourTurn, data); err != nil {
+		return false, err
+	}
+
+	message := notify.Message{
+		Type:    models.NotifyYourTurn,
+		Subject: fmt.Sprintf("Your turn in %s", game.Name),
+		Body:    fmt.Sprintf("%s is waiting on your move.", game.Name),
+		Data:    map[string]string{"game_id": game.ID.Hex(), "game_name": game.Name, "player": player},
+	}
+	for _, channel := range prefs.Channels {
+		notifier, ok := ss.notifiers[channel]
+		if !ok {
+			continue
+		}
+		if err := notifier.Send(context.Background(), prefs.Address(channel), message); err != nil {
+			log.Printf("could not alert %s of their turn in game %s by %s: %v", player, game.ID.Hex(), channel, err)
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// GetTurn returns a game's turn order, or ErrNoTurnOrder if the game does not keep one.
+func (s *GameService) GetTurn(gameID string) (*models.TurnOrder, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, _, err := s.findGameFields(ctx, gameID, "turn")
+	if err != nil {
+		return nil, err
+	}
+	if game.Turn == nil {
+		return nil, ErrNoTurnOrder
+	}
+	return game.Turn, nil
+}
+
+// SkipTurn makes the next turn change pass over one more player, as a skip card does.
+// Only the game's owner or an admin can skip turns.
+func (s *GameService) SkipTurn(gameID string, viewer models.Viewer) (*models.TurnOrder, error) {
+	return s.changeTurn(gameID, viewer, models.EventTurnSkipped, (*models.TurnOrder).Skip)
+}
+
+// ReverseTurn flips the direction of play, as a reverse card does. The current player keeps their turn.
+// Only the game's owner or an admin can reverse the direction.
+func (s *GameService) ReverseTurn(gameID string, viewer models.Viewer) (*models.TurnOrder, error) {
+	return s.changeTurn(gameID, viewer, models.EventReversed, (*models.TurnOrder).Reverse)
+}
+
+// changeTurn applies a change to a running game's turn order on behalf of its owner, and records it as an event.
+func (s *GameService) changeTurn(gameID string, viewer models.Viewer, eventType string, change func(*models.TurnOrder)) (*models.TurnOrder, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGameFields(ctx, gameID, "owner", "status", "turn")
+	if err != nil {
+		return nil, err
+	}
+	if !canManageGame(game, viewer) {
+		return nil, ErrForbidden
+	}
+	if game.Status != models.StatusActive {
+		return nil, errors.New("game is not active")
+	}
+	if game.Turn == nil {
+		return nil, ErrNoTurnOrder
+	}
+	change(game.Turn)
+
+	// Save the turn order and record the change together
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
+			"$set": bson.M{"turn": game.Turn},
+		})
+		if err != nil {
+			return err
+		}
+
+		data := map[string]interface{}{"by": viewer.PlayerName, "direction": game.Turn.Direction}
+		return s.recordEvent(ctx, gameIDObj, eventType, game.Turn.Player(), data)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return game.Turn, nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"log"
+	"my-card-game/internal/api/models"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GameUpdate describes a change to a game document, as seen by the games collection change stream.
+// Game is nil when the game was deleted or archived.
+type GameUpdate struct {
+	GameID    string       `json:"game_id"`
+	Operation string       `json:"operation"` // The change stream operation: insert, update, replace, or delete
+	Game      *models.Game `json:"game,omitempty"`
+}
+
+// UpdateHub fans game updates out to the subscribers watching each game.
+// Subscribers are grouped by game ID, and each receives updates on its own buffered channel.
+type UpdateHub struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan GameUpdate]struct{}
+}
+
+// AllGames is the game ID to subscribe to for the updates of every game, such as for the open game browser.
+const AllGames = "*"
+
+// subscriberBuffer is how many updates a slow subscriber may fall behind before updates to it are dropped.
+const subscriberBuffer = 16
+
+// NewUpdateHub creates and returns a new, empty UpdateHub.
+func NewUpdateHub() *UpdateHub {
+	return &UpdateHub{subscribers: map[string]map[chan GameUpdate]struct{}{}}
+}
+
+// Subscribe registers interest in the updates of a game.
+// It returns the channel the updates arrive on and a function that must be called to unsubscribe.
+func (h *UpdateHub) Subscribe(gameID string) (<-chan GameUpdate, func()) {
+	ch := make(chan GameUpdate, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subscribers[gameID] == nil {
+		h.subscribers[gameID] = map[chan GameUpdate]struct{}{}
+	}
+	h.subscribers[gameID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subscribers[gameID][ch]; !ok {
+			return
+		}
+		delete(h.subscribers[gameID], ch)
+		if len(h.subscribers[gameID]) == 0 {
+			delete(h.subscribers, gameID)
+		}
+		close(ch)
+	}
+	return ch, unsubscribe
+}
+
+// Publish sends an update to every subscriber of its game and to the subscribers of AllGames.
+// Subscribers whose buffers are full miss the update rather than blocking the other subscribers.
+func (h *UpdateHub) Publish(update GameUpdate) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, gameID := range []string{update.GameID, AllGames} {
+		for ch := range h.subscribers[gameID] {
+			select {
+			case ch <- update:
+			default:
+			}
+		}
+	}
+}
+
+// changeEvent is the part of a change stream event the update watcher reads.
+type changeEvent struct {
+	OperationType string `bson:"operationType"`
+	DocumentKey   struct {
+		ID primitive.ObjectID `bson:"_id"`
+	} `bson:"documentKey"`
+	FullDocument *models.Game `bson:"fullDocument"`
+}
+
+// WatchGames follows the change stream of the games collection and publishes every change to the hub.
+// Because the changes come from MongoDB, updates made by other server replicas reach this replica's subscribers too.
+// If the stream fails it is reopened after a short pause, until the context is cancelled.
+// Change streams require MongoDB to run as a replica set.
+func (s *GameService) WatchGames(ctx context.Context, hub *UpdateHub) {
+	go func() {
+		for {
+			if err := s.watchGames(ctx, hub); err != nil && ctx.Err() == nil {
+				log.Printf("games change stream failed: %v", err)
+			}
+
+			// Wait before reopening the stream, unless the watcher is shutting down
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+		}
+	}()
+}
+
+// watchGames opens a change stream on the games collection and publishes changes until the stream ends.
+func (s *GameService) watchGames(ctx context.Context, hub *UpdateHub) error {
+	// Ask for the full document after each update so subscribers get the whole game
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	stream, err := s.collection.Watch(ctx, mongo.Pipeline{}, opts)
+	if err != nil {
+		return err
+	}
+	defer stream.Close(context.Background())
+
+	for stream.Next(ctx) {
+		var event changeEvent
+		if err := stream.Decode(&event); err != nil {
+			log.Printf("could not decode game change: %v", err)
+			continue
+		}
+
+		hub.Publish(GameUpdate{
+			GameID:    event.DocumentKey.ID.Hex(),
+			Operation: event.OperationType,
+			Game:      event.FullDocument,
+		})
+	}
+	return stream.Err()
+}
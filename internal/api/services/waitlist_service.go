@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrSeatsOpen is returned when a player tries to join the waitlist of a game they could sit down in straight away.
+var ErrSeatsOpen = errors.New("the game has an open seat; join the game instead")
+
+// ErrNotWaiting is returned when a player who is not on a game's waitlist asks for their place in it or leaves it.
+var ErrNotWaiting = errors.New("player is not on the waitlist")
+
+// WaitlistStatus describes a player's place in a game's waitlist.
+type WaitlistStatus struct {
+	Player   string `json:"player"`
+	Position int    `json:"position"` // Place in the queue, counting from 1
+	Waiting  int    `json:"waiting"`  // Players in the queue
+}
+
+// JoinWaitlist puts a player in line for a seat at a full game. They are seated automatically, and notified, as
+// soon as a seat opens for them. Password-protected games return ErrWrongPassword unless the password matches, and
+// games with an open seat return ErrSeatsOpen. Joining again keeps the player's place.
+func (s *GameService) JoinWaitlist(gameID, playerName, password string) (*WaitlistStatus, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Players queue for the game they would otherwise join, so the same checks apply
+	if containsPlayer(game.Banned, playerName) {
+		return nil, errors.New("player is banned from this game")
+	}
+	if containsPlayer(game.Players, playerName) {
+		return nil, ErrAlreadySeated
+	}
+	if game.Status == models.StatusFinished {
+		return nil, errors.New("game is finished")
+	}
+	if game.PasswordHash != "" && bcrypt.CompareHashAndPassword([]byte(game.PasswordHash), []byte(password)) != nil {
+		return nil, ErrWrongPassword
+	}
+	if position := game.WaitlistPosition(playerName); position > 0 {
+		return &WaitlistStatus{Player: playerName, Position: position, Waiting: len(game.Waitlist)}, nil
+	}
+	if !game.IsFull(playerName) {
+		return nil, ErrSeatsOpen
+	}
+
+	// Join the end of the line, only if the player has not joined it in the meantime
+	entry := models.WaitlistEntry{Player: playerName, JoinedAt: time.Now().UTC()}
+	result, err := s.collection.UpdateOne(ctx,
+		bson.M{"_id": gameIDObj, "waitlist.player": bson.M{"$ne": playerName}},
+		bson.M{"$push": bson.M{"waitlist": entry}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if result.ModifiedCount == 0 {
+		return s.GetWaitlistPosition(gameID, playerName)
+	}
+
+	game.Waitlist = append(game.Waitlist, entry)
+	return &WaitlistStatus{Player: playerName, Position: len(game.Waitlist), Waiting: len(game.Waitlist)}, nil
+}
+
+// GetWaitlistPosition returns a player's place in a game's waitlist, or ErrNotWaiting if they are not in it.
+func (s *GameService) GetWaitlistPosition(gameID, playerName string) (*WaitlistStatus, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, _, err := s.findGameFields(ctx, gameID, "waitlist")
+	if err != nil {
+		return nil, err
+	}
+
+	position := game.WaitlistPosition(playerName)
+	if position == 0 {
+		return nil, ErrNotWaiting
+	}
+	return &WaitlistStatus{Player: playerName, Position: position, Waiting: len(game.Waitlist)}, nil
+}
+
+// LeaveWaitlist takes a player out of a game's waitlist, or returns ErrNotWaiting if they are not in it.
+func (s *GameService) LeaveWaitlist(gameID, playerName string) error {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	gameIDObj, err := primitive.ObjectIDFromHex(gameID)
+	if err != nil {
+		return errors.New("invalid game ID")
+	}
+
+	result, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
+		"$pull": bson.M{"waitlist": bson.M{"player": playerName}},
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrGameNotFound
+	}
+	if result.ModifiedCount == 0 {
+		return ErrNotWaiting
+	}
+	return nil
+}
+
+// seatWaitlisted sits waiting players down in a game that may have room after a seat opened, saves their seats,
+// and notifies each of them. It does nothing if nobody is waiting or there is still no room.
+func (s *GameService) seatWaitlisted(ctx context.Context, gameID primitive.ObjectID, game *models.Game) error {
+	seated := game.SeatWaitlist()
+	if len(seated) == 0 {
+		return nil
+	}
+
+	var err error
+	// Save the change and check the state it leaves the game in, in one transaction
+	game.Checksum, err = s.saveChecked(ctx, gameID, bson.M{
+		"$set": bson.M{
+			"players":      game.Players,
+			"seat_numbers": game.SeatNumbers,
+			"reservations": game.Reservations,
+			"dealer_index": game.DealerIndex,
+			"turn":         game.Turn,
+			"waitlist":     game.Waitlist,
+		},
+	}, actionWaitlistSeated, "")
+	if err != nil {
+		return err
+	}
+
+	seats := game.PlayerSeats()
+	for _, player := range seated {
+		// Being seated counts as activity, so the inactivity check gives the player time to arrive
+		if err := s.touchPlayer(ctx, gameID, player); err != nil {
+			return err
+		}
+		notification := models.Notification{
+			ID:     primitive.NewObjectID(),
+			Player: player,
+			Type:   models.NotifyWaitlistSeated,
+			Data: map[string]interface{}{
+				"game_id":   gameID.Hex(),
+				"game_name": game.Name,
+				"seat":      seats[player],
+			},
+			CreatedAt: time.Now().UTC(),
+		}
+		// The notification has a fixed ID, so retrying an insert that may have landed cannot duplicate it
+		err := db.Retry(ctx, func() error {
+			_, err := s.notifications.InsertOne(ctx, notification)
+			if mongo.IsDuplicateKeyError(err) {
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WalletService provides services related to players' wallets of virtual chips.
+// It interacts with the MongoDB collections where wallet balances and their transactions are stored,
+// and moves chips on and off game tables through the GameService.
+type WalletService struct {
+	wallets      *mongo.Collection
+	transactions *mongo.Collection
+	games        *GameService
+}
+
+// WalletStatement holds a player's wallet along with its most recent transactions, newest first.
+type WalletStatement struct {
+	Wallet       models.Wallet              `json:"wallet"`
+	Transactions []models.WalletTransaction `json:"transactions"`
+}
+
+// walletTransactionLimit is how many of a wallet's most recent transactions are listed.
+const walletTransactionLimit = 50
+
+// NewWalletService creates and returns a new instance of WalletService.
+// It initializes the service with references to the MongoDB collections where wallets and their transactions are stored,
+// in the organization of the game service.
+func NewWalletService(games *GameService) *WalletService {
+	return &WalletService{
+		wallets:      db.GetOrgCollection(games.Org(), "wallets"),
+		transactions: db.GetOrgCollection(games.Org(), "wallet_transactions"),
+		games:        games,
+	}
+}
+
+// GetWallet returns a player's wallet and its most recent transactions.
+// Players who have never had a wallet get an empty one rather than an error.
+func (ws *WalletService) GetWallet(playerName string) (*WalletStatement, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	statement := &WalletStatement{Wallet: models.Wallet{PlayerName: playerName}, Transactions: []models.WalletTransaction{}}
+	err := ws.wallets.FindOne(ctx, bson.M{"_id": playerName}).Decode(&statement.Wallet)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(walletTransactionLimit)
+	cursor, err := ws.transactions.Find(ctx, bson.M{"player_name": playerName}, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(ctx, &statement.Transactions); err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+// Credit grants chips to a player's wallet, creating the wallet if needed. Only admins credit wallets.
+func (ws *WalletService) Credit(playerName string, amount int, note string) (*models.Wallet, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	if amount <= 0 {
+		return nil, errors.New("credit amount must be positive")
+	}
+
+	var wallet *models.Wallet
+	err := db.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		wallet, err = ws.credit(ctx, playerName, amount, models.WalletCredit, nil, note)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
+
+// BuyIn moves chips from a player's wallet onto their stack in a game they are seated in.
+// The buy-in fails with ErrInsufficientFunds, leaving the wallet and the game untouched, if the wallet cannot cover it.
+func (ws *WalletService) BuyIn(gameID, playerName string, amount int) (*models.Wallet, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	if amount <= 0 {
+		return nil, errors.New("buy-in amount must be positive")
+	}
+
+	game, gameIDObj, err := ws.games.findGameFields(ctx, gameID, "players", "status", "chips")
+	if err != nil {
+		return nil, err
+	}
+	if !containsPlayer(game.Players, playerName) {
+		return nil, errors.New("player not found in the game")
+	}
+	if game.Status == models.StatusFinished {
+		return nil, errors.New("game has already finished")
+	}
+
+	// Take the chips out of the wallet and put them on the table together
+	var wallet *models.Wallet
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		wallet, err = ws.debit(ctx, playerName, amount, models.WalletBuyIn, &gameIDObj)
+		if err != nil {
+			return err
+		}
+
+		stack := game.Chips[playerName]
+		if err := ws.games.setStack(ctx, gameIDObj, game, playerName, stack, stack+amount); err != nil {
+			return err
+		}
+
+		data := map[string]interface{}{"amount": amount}
+		return ws.games.recordEvent(ctx, gameIDObj, models.EventBuyIn, playerName, data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
+
+// CashOut moves a player's whole stack in a game back into their wallet. Chips committed to a hand still being
+// played cannot be cashed out until the hand is settled.
+func (ws *WalletService) CashOut(gameID, playerName string) (*models.Wallet, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := ws.games.findGameFields(ctx, gameID, "players", "chips", "bets")
+	if err != nil {
+		return nil, err
+	}
+	if !containsPlayer(game.Players, playerName) {
+		return nil, errors.New("player not found in the game")
+	}
+	if game.Bets[playerName] > 0 {
+		return nil, errors.New("player has chips committed to the current hand; cash out once it is settled")
+	}
+	amount := game.Chips[playerName]
+	if amount == 0 {
+		return nil, errors.New("player has no chips to cash out")
+	}
+
+	// Take the stack off the table and pay it into the wallet together
+	var wallet *models.Wallet
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := ws.games.setStack(ctx, gameIDObj, game, playerName, amount, 0); err != nil {
+			return err
+		}
+
+		var err error
+		wallet, err = ws.credit(ctx, playerName, amount, models.WalletCashOut, &gameIDObj, "")
+		if err != nil {
+			return err
+		}
+
+		data := map[string]interface{}{"amount": amount}
+		return ws.games.recordEvent(ctx, gameIDObj, models.EventCashOut, playerName, data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
+
+// credit adds chips to a wallet, creating it if needed, and records the transaction.
+func (ws *WalletService) credit(ctx context.Context, playerName string, amount int, kind string, gameID *primitive.ObjectID, note string) (*models.Wallet, error) {
+	var wallet models.Wallet
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := ws.wallets.FindOneAndUpdate(ctx, bson.M{"_id": playerName}, bson.M{
+		"$inc": bson.M{"balance": amount},
+		"$set": bson.M{"updated_at": time.Now().UTC()},
+	}, opts).Decode(&wallet)
+	if err != nil {
+		return nil, err
+	}
+	return &wallet, ws.recordTransaction(ctx, &wallet, kind, amount, gameID, note)
+}
+
+// debit takes chips out of a wallet and records the transaction. The balance is only changed if it covers the
+// amount, so it can never go below zero; otherwise ErrInsufficientFunds is returned.
+func (ws *WalletService) debit(ctx context.Context, playerName string, amount int, kind string, gameID *primitive.ObjectID) (*models.Wallet, error) {
+	var wallet models.Wallet
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := ws.wallets.FindOneAndUpdate(ctx, bson.M{"_id": playerName, "balance": bson.M{"$gte": amount}}, bson.M{
+		"$inc": bson.M{"balance": -amount},
+		"$set": bson.M{"updated_at": time.Now().UTC()},
+	}, opts).Decode(&wallet)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrInsufficientFunds
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &wallet, ws.recordTransaction(ctx, &wallet, kind, -amount, gameID, "")
+}
+
+// recordTransaction stores a change to the wallet's balance, which the wallet already reflects.
+func (ws *WalletService) recordTransaction(ctx context.Context, wallet *models.Wallet, kind string, amount int, gameID *primitive.ObjectID, note string) error {
+	_, err := ws.transactions.InsertOne(ctx, models.WalletTransaction{
+		PlayerName:   wallet.PlayerName,
+		Kind:         kind,
+		Amount:       amount,
+		BalanceAfter: wallet.Balance,
+		GameID:       gameID,
+		Note:         note,
+		CreatedAt:    time.Now().UTC(),
+	})
+	return err
+}
+
+// setStack changes a seated player's chip stack from one amount to another, only if the stack has not changed
+// since the game was loaded.
+func (s *GameService) setStack(ctx context.Context, gameID primitive.ObjectID, game *models.Game, playerName string, from, to int) error {
+	if game.Chips == nil {
+		game.Chips = map[string]int{}
+	}
+	game.Chips[playerName] = to
+
+	// A player who has never held chips has no stack recorded yet
+	stack := interface{}(from)
+	if from == 0 {
+		stack = bson.M{"$in": bson.A{0, nil}}
+	}
+	result, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameID, "players": playerName, "chips." + playerName: stack}, bson.M{
+		"$set": bson.M{"chips." + playerName: to},
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("player's chip stack changed; please try again")
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// PlayWarBattle resolves the next battle of a War game automatically from the players' face-down piles.
+// A battle event is recorded for every battle, and a game_over event once one player holds all the cards.
+func (s *GameService) PlayWarBattle(gameID string) (*models.BattleResult, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(context.Background(), db.OperationTimeout)
+	defer cancel()
+
+	game, gameIDObj, err := s.findGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the game is a running war between two players, and that its house rules allow the battle
+	if err := game.CheckAction(models.Action{Type: models.ActionBattle}); err != nil {
+		return nil, err
+	}
+	if err := game.CheckHouseRules(models.Action{Type: models.ActionBattle}); err != nil {
+		return nil, err
+	}
+
+	// Resolve the battle, including any wars needed to break ties
+	result, err := game.ResolveBattle()
+	if err != nil {
+		return nil, err
+	}
+
+	// Save the battle and its events together
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		// Save the updated piles and game status
+		_, err := s.collection.UpdateOne(ctx, bson.M{"_id": gameIDObj}, bson.M{
+			"$set": bson.M{"player_hands": game.PlayerHands, "status": game.Status, "winner": game.Winner, "winning_team": game.WinningTeam},
+		})
+		if err != nil {
+			return err
+		}
+
+		// Emit an event describing the battle
+		err = s.recordEvent(ctx, gameIDObj, models.EventBattle, result.Winner, map[string]interface{}{
+			"flips":     result.Flips,
+			"wars":      result.Wars,
+			"cards_won": result.CardsWon,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Emit a game over event if this battle decided the game, then move the game into the archive
+		if result.GameOver {
+			if err := s.recordEvent(ctx, gameIDObj, models.EventGameOver, result.GameWinner, nil); err != nil {
+				return err
+			}
+			if err := s.archiveGame(ctx, gameIDObj); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// SessionCookie is the name of the cookie carrying a player's session token.
+const SessionCookie = "session_token"
+
+// APIKeyHeader is the request header carrying an administrative API key.
+const APIKeyHeader = "X-API-Key"
+
+// OrgHeader is the request header naming the organization a request acts in. Without it, a request made with an
+// organization's API key acts in that organization, and any other request acts in the default organization.
+const OrgHeader = "X-Org-ID"
+
+// contextKey is the type used for values stored in a request context by this package.
+type contextKey int
+
+// identityKey is the context key under which the caller's identity is stored.
+const identityKey contextKey = 0
+
+// Identity describes who is making a request, and in which organization.
+// An empty PlayerName means the caller is anonymous. Admin callers may see and manage every game of the
+// organization; platform admins, who hold a key of the default organization, may also manage the organizations.
+type Identity struct {
+	PlayerName string
+	Admin      bool
+	Platform   bool
+	OrgID      string
+}
+
+// Resolver looks up the identity behind a session token.
+// It reports false if the token is unknown or has expired.
+type Resolver interface {
+	ResolveSession(ctx context.Context, token string) (Identity, bool)
+}
+
+// APIKeyValidator checks administrative API keys.
+// It reports the organization the key belongs to, which is empty for a platform key, and false if the key is unknown.
+type APIKeyValidator interface {
+	ValidateAPIKey(ctx context.Context, key string) (string, bool)
+}
+
+// WithIdentity returns a copy of the context carrying the given identity.
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey, identity)
+}
+
+// FromRequest returns the identity attached to the request, or an anonymous identity if there is none.
+func FromRequest(r *http.Request) Identity {
+	return FromContext(r.Context())
+}
+
+// FromContext returns the identity stored in the context, or an anonymous identity if there is none.
+func FromContext(ctx context.Context) Identity {
+	identity, _ := ctx.Value(identityKey).(Identity)
+	return identity
+}
+
+// SessionToken extracts the session token from the request.
+// A bearer token in the Authorization header takes precedence over the session cookie.
+func SessionToken(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	}
+	if cookie, err := r.Cookie(SessionCookie); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// Middleware resolves the caller's identity in the given organization from their session token for every request
+// and stores it in the request context. The resolver only knows the organization's own sessions.
+// A valid API key of the organization marks the caller as an admin, and a platform key as a platform admin too;
+// another organization's key grants nothing. Requests without a valid session or API key are anonymous.
+func Middleware(org string, resolver Resolver, keys APIKeyValidator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity := Identity{}
+			if token := SessionToken(r); token != "" {
+				if resolved, ok := resolver.ResolveSession(r.Context(), token); ok {
+					identity = resolved
+				}
+			}
+			if key := r.Header.Get(APIKeyHeader); key != "" {
+				if keyOrg, ok := keys.ValidateAPIKey(r.Context(), key); ok && (keyOrg == "" || keyOrg == org) {
+					identity.Admin = true
+					identity.Platform = keyOrg == ""
+				}
+			}
+			identity.OrgID = org
+			next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), identity)))
+		})
+	}
+}
+
+// RequestedOrg returns the organization a request acts in: the one named by the OrgHeader, or else the one the
+// request's API key belongs to, or else the default organization, which is empty.
+func RequestedOrg(r *http.Request, keys APIKeyValidator) string {
+	if org := r.Header.Get(OrgHeader); org != "" {
+		return org
+	}
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		if keyOrg, ok := keys.ValidateAPIKey(r.Context(), key); ok {
+			return keyOrg
+		}
+	}
+	return ""
+}
+
+// RequireAdmin wraps a handler so it can only be called with a valid API key.
+// Other callers receive a 401 Unauthorized response.
+func RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !FromRequest(r).Admin {
+			http.Error(w, "a valid API key is required", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// RequirePlatformAdmin wraps a handler so it can only be called with a platform API key.
+// Other callers receive a 401 Unauthorized response, or a 403 Forbidden response if their key only belongs to
+// an organization.
+func RequirePlatformAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		identity := FromRequest(r)
+		if !identity.Admin {
+			http.Error(w, "a valid API key is required", http.StatusUnauthorized)
+			return
+		}
+		if !identity.Platform {
+			http.Error(w, "a platform API key is required", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// RequirePlayer wraps a handler so it can only be called with a valid player session.
+// Other callers receive a 401 Unauthorized response.
+func RequirePlayer(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if FromRequest(r).PlayerName == "" {
+			http.Error(w, "a valid player session is required", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
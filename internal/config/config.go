@@ -1,18 +1,124 @@
 package config
 
+import "time"
+
 // Config holds the configuration settings for the application.
-// It includes the MongoDB connection URI and the name of the MongoDB database to use.
+// It includes the HTTP server's address, timeouts, request size limits, and TLS settings, the gRPC server's address, the MongoDB connection URI and database name,
+// the connection pool and timeouts, how long player sessions stay valid, the bootstrap administrative API key,
+// how idle players are detected and handled, how often the background jobs run and how long queued jobs may take, where card images are served from, how finished games are archived, where shuffles get their randomness, which message bus game events are published to, how players are alerted of their turn, which requests are logged,
+// and when the unversioned legacy API paths are retired.
+// Each field can be set in the config file under its yaml key and overridden by the environment variable named in its env tag.
 type Config struct {
-	MongoDBURI      string // The URI for connecting to the MongoDB instance
-	MongoDBDatabase string // The name of the MongoDB database to use
+	ServerAddr                  string        `yaml:"server_addr" env:"SERVER_ADDR"`                                       // Address the HTTP server listens on, such as ":8080"
+	ReadHeaderTimeout           time.Duration `yaml:"read_header_timeout" env:"READ_HEADER_TIMEOUT"`                       // How long a client may take to send the request headers
+	ReadTimeout                 time.Duration `yaml:"read_timeout" env:"READ_TIMEOUT"`                                     // How long a client may take to send the whole request
+	WriteTimeout                time.Duration `yaml:"write_timeout" env:"WRITE_TIMEOUT"`                                   // How long the server may take to write a response; streams clear it themselves
+	IdleTimeout                 time.Duration `yaml:"idle_timeout" env:"IDLE_TIMEOUT"`                                     // How long an idle keep-alive connection stays open
+	MaxRequestBytes             int64         `yaml:"max_request_bytes" env:"MAX_REQUEST_BYTES"`                           // Largest request body accepted
+	MaxImportBytes              int64         `yaml:"max_import_bytes" env:"MAX_IMPORT_BYTES"`                             // Largest request body accepted by the game import endpoint
+	GRPCAddr                    string        `yaml:"grpc_addr" env:"GRPC_ADDR"`                                           // Address the gRPC API listens on; empty disables it
+	TLSCertFile                 string        `yaml:"tls_cert_file" env:"TLS_CERT_FILE"`                                   // PEM certificate served for HTTPS; set together with TLSKeyFile
+	TLSKeyFile                  string        `yaml:"tls_key_file" env:"TLS_KEY_FILE"`                                     // PEM private key of TLSCertFile
+	AutocertDomains             []string      `yaml:"autocert_domains" env:"AUTOCERT_DOMAINS"`                             // Domains to obtain Let's Encrypt certificates for, instead of a certificate file
+	AutocertCacheDir            string        `yaml:"autocert_cache_dir" env:"AUTOCERT_CACHE_DIR"`                         // Directory where Let's Encrypt certificates are cached between restarts
+	MongoDBURI                  string        `yaml:"mongodb_uri" env:"MONGODB_URI"`                                       // The URI for connecting to the MongoDB instance
+	MongoDBDatabase             string        `yaml:"mongodb_database" env:"MONGODB_DATABASE"`                             // The name of the MongoDB database to use
+	MongoMaxPoolSize            uint64        `yaml:"mongo_max_pool_size" env:"MONGO_MAX_POOL_SIZE"`                       // Most connections the MongoDB client keeps open at once
+	MongoMinPoolSize            uint64        `yaml:"mongo_min_pool_size" env:"MONGO_MIN_POOL_SIZE"`                       // Connections the MongoDB client keeps open even when idle
+	MongoMaxConnIdleTime        time.Duration `yaml:"mongo_max_conn_idle_time" env:"MONGO_MAX_CONN_IDLE_TIME"`             // How long an idle connection stays in the pool before it is closed
+	MongoConnectTimeout         time.Duration `yaml:"mongo_connect_timeout" env:"MONGO_CONNECT_TIMEOUT"`                   // How long to wait when opening a connection to MongoDB
+	MongoSocketTimeout          time.Duration `yaml:"mongo_socket_timeout" env:"MONGO_SOCKET_TIMEOUT"`                     // How long a read or write on a MongoDB connection may take
+	MongoServerSelectionTimeout time.Duration `yaml:"mongo_server_selection_timeout" env:"MONGO_SERVER_SELECTION_TIMEOUT"` // How long to wait for a suitable MongoDB server, e.g. during a primary election
+	MongoOperationTimeout       time.Duration `yaml:"mongo_operation_timeout" env:"MONGO_OPERATION_TIMEOUT"`               // How long each database operation made for a request may take
+	MongoRetryMaxAttempts       int           `yaml:"mongo_retry_max_attempts" env:"MONGO_RETRY_MAX_ATTEMPTS"`             // Attempts made at an operation that fails with a transient error
+	MongoRetryBaseDelay         time.Duration `yaml:"mongo_retry_base_delay" env:"MONGO_RETRY_BASE_DELAY"`                 // Delay before the first retry of a transient failure; it doubles each attempt
+	SessionTTL                  time.Duration `yaml:"session_ttl" env:"SESSION_TTL"`                                       // How long a player session remains valid after it is issued
+	AdminAPIKey                 string        `yaml:"admin_api_key" env:"ADMIN_API_KEY"`                                   // Bootstrap API key accepted for admin operations; empty disables it
+	InactivityWindow            time.Duration `yaml:"inactivity_window" env:"INACTIVITY_WINDOW"`                           // How long a player may go without acting before being considered inactive
+	InactivityAction            string        `yaml:"inactivity_action" env:"INACTIVITY_ACTION"`                           // What to do with inactive players: "flag" or "remove"
+	InactivityCheckInterval     time.Duration `yaml:"inactivity_check_interval" env:"INACTIVITY_CHECK_INTERVAL"`           // How often active games are checked for inactive players
+	PresenceTimeout             time.Duration `yaml:"presence_timeout" env:"PRESENCE_TIMEOUT"`                             // How long after their last heartbeat a player is still shown as online
+	ReconnectGracePeriod        time.Duration `yaml:"reconnect_grace_period" env:"RECONNECT_GRACE_PERIOD"`                 // How long a disconnected player keeps their seat and hand before forfeiting them
+	SessionPurgeInterval        time.Duration `yaml:"session_purge_interval" env:"SESSION_PURGE_INTERVAL"`                 // How often expired sessions are deleted
+	ArchiveInterval             time.Duration `yaml:"archive_interval" env:"ARCHIVE_INTERVAL"`                             // How often finished games left in the games collection are archived
+	BotTurnInterval             time.Duration `yaml:"bot_turn_interval" env:"BOT_TURN_INTERVAL"`                           // How often each bot whose turn it is makes a move
+	JobPollInterval             time.Duration `yaml:"job_poll_interval" env:"JOB_POLL_INTERVAL"`                           // How often the job queue is checked for work submitted through the API
+	JobTimeout                  time.Duration `yaml:"job_timeout" env:"JOB_TIMEOUT"`                                       // How long a queued job may run before it is considered interrupted and run again
+	CardImageBaseURL            string        `yaml:"card_image_base_url" env:"CARD_IMAGE_BASE_URL"`                       // Base URL card images are served from, such as a CDN; empty leaves image URLs out
+	CompressArchives            bool          `yaml:"compress_archives" env:"COMPRESS_ARCHIVES"`                           // Whether finished games are gzipped when moved to the archive
+	ShuffleEntropy              string        `yaml:"shuffle_entropy" env:"SHUFFLE_ENTROPY"`                               // Where shuffles get their randomness: "crypto", "device", or "http"
+	ShuffleEntropyDevice        string        `yaml:"shuffle_entropy_device" env:"SHUFFLE_ENTROPY_DEVICE"`                 // Random device read when shuffle_entropy is "device", such as an HSM's /dev/hwrng
+	ShuffleEntropyURL           string        `yaml:"shuffle_entropy_url" env:"SHUFFLE_ENTROPY_URL"`                       // Random-beacon API asked for bytes when shuffle_entropy is "http"
+	ShuffleEntropyTimeout       time.Duration `yaml:"shuffle_entropy_timeout" env:"SHUFFLE_ENTROPY_TIMEOUT"`               // How long a request to the random-beacon API may take before falling back to crypto/rand
+	EventBus                    string        `yaml:"event_bus" env:"EVENT_BUS"`                                           // Message bus game events are published to: "off", "nats", or "kafka"
+	EventBusURL                 string        `yaml:"event_bus_url" env:"EVENT_BUS_URL"`                                   // URL of the NATS server, or of the Kafka REST Proxy
+	EventBusTopic               string        `yaml:"event_bus_topic" env:"EVENT_BUS_TOPIC"`                               // Kafka topic, or the NATS subject prefix each event type is appended to
+	EventBusTimeout             time.Duration `yaml:"event_bus_timeout" env:"EVENT_BUS_TIMEOUT"`                           // How long publishing a batch of events may take
+	EventPublishInterval        time.Duration `yaml:"event_publish_interval" env:"EVENT_PUBLISH_INTERVAL"`                 // How often recorded events are sent to the message bus
+	TurnAlertDelay              time.Duration `yaml:"turn_alert_delay" env:"TURN_ALERT_DELAY"`                             // How long a game waits on a player's move before they are alerted
+	TurnAlertCheckInterval      time.Duration `yaml:"turn_alert_check_interval" env:"TURN_ALERT_CHECK_INTERVAL"`           // How often active games are checked for turns to alert
+	NotifyTimeout               time.Duration `yaml:"notify_timeout" env:"NOTIFY_TIMEOUT"`                                 // How long delivering one email, push notification, or webhook may take
+	SMTPAddr                    string        `yaml:"smtp_addr" env:"SMTP_ADDR"`                                           // host:port of the SMTP relay turn alerts are emailed through; empty turns email alerts off
+	SMTPUsername                string        `yaml:"smtp_username" env:"SMTP_USERNAME"`                                   // User to authenticate to the SMTP relay as; empty sends without authenticating
+	SMTPPassword                string        `yaml:"smtp_password" env:"SMTP_PASSWORD"`                                   // Password of smtp_username
+	SMTPFrom                    string        `yaml:"smtp_from" env:"SMTP_FROM"`                                           // Address turn alert emails are sent from
+	FCMServerKey                string        `yaml:"fcm_server_key" env:"FCM_SERVER_KEY"`                                 // Firebase Cloud Messaging server key; empty turns push alerts off
+	FCMURL                      string        `yaml:"fcm_url" env:"FCM_URL"`                                               // Endpoint push alerts are sent to
+	AccessLogLevel              string        `yaml:"access_log_level" env:"ACCESS_LOG_LEVEL"`                             // Which requests are logged: "off", "errors", or "all"
+	AccessLogSampleRate         float64       `yaml:"access_log_sample_rate" env:"ACCESS_LOG_SAMPLE_RATE"`                 // Fraction of successful requests logged when the level is "all"
+	LegacySunset                string        `yaml:"legacy_sunset" env:"LEGACY_SUNSET"`                                   // Date (YYYY-MM-DD) the unversioned API paths will be removed, announced in their Sunset header
 }
 
-// LoadConfig loads and returns the configuration settings for the application.
-// This function initializes and returns a Config struct with hardcoded values.
-// You can update the MongoDB URI and database name to match your specific MongoDB setup.
-func LoadConfig() *Config {
+// Default returns the default configuration settings for the application.
+// Load layers a config file and environment variables on top of these defaults.
+// Secrets such as the bootstrap admin API key have no default and are best set through the environment (ADMIN_API_KEY).
+// HTTPS is enabled by setting either TLS_CERT_FILE and TLS_KEY_FILE or a comma-separated list of AUTOCERT_DOMAINS.
+func Default() *Config {
 	return &Config{
-		MongoDBURI:      "mongodb://localhost:27017", // Update this to match your MongoDB setup
-		MongoDBDatabase: "mydb",                      // Ensure this matches the database name you're trying to use
+		ServerAddr:                  ":8080",                               // Listen on port 8080 on every interface
+		ReadHeaderTimeout:           5 * time.Second,                       // Drop slowloris-style clients that trickle in headers
+		ReadTimeout:                 15 * time.Second,                      // Request bodies are small JSON documents
+		WriteTimeout:                30 * time.Second,                      // Leave room for slow responses such as CPU profiles
+		GRPCAddr:                    ":9090",                               // Serve the gRPC API on port 9090
+		IdleTimeout:                 2 * time.Minute,                       // Reuse keep-alive connections for a couple of minutes
+		MaxRequestBytes:             1 << 20,                               // Ordinary payloads are small JSON documents
+		MaxImportBytes:              32 << 20,                              // Imported games carry their whole event history
+		AutocertCacheDir:            "autocert-cache",                      // TLS is off unless a certificate or autocert domains are configured
+		MongoDBURI:                  "mongodb://localhost:27017",           // Update this to match your MongoDB setup
+		MongoDBDatabase:             "mydb",                                // Ensure this matches the database name you're trying to use
+		MongoMaxPoolSize:            100,                                   // The driver's default pool size
+		MongoMinPoolSize:            0,                                     // Don't hold connections open while the server is idle
+		MongoMaxConnIdleTime:        5 * time.Minute,                       // Close connections that have been idle for five minutes
+		MongoConnectTimeout:         10 * time.Second,                      // Give up on a connection attempt after ten seconds
+		MongoSocketTimeout:          30 * time.Second,                      // Fail reads and writes that hang for thirty seconds
+		MongoServerSelectionTimeout: 10 * time.Second,                      // Ride out short primary elections
+		MongoOperationTimeout:       15 * time.Second,                      // Longer than server selection, so a retry fits after an election
+		MongoRetryMaxAttempts:       3,                                     // Try transient failures up to three times
+		MongoRetryBaseDelay:         100 * time.Millisecond,                // Start retrying after about a tenth of a second
+		SessionTTL:                  24 * time.Hour,                        // Sessions expire a day after they are issued
+		InactivityWindow:            15 * time.Minute,                      // Players idle for longer than this are considered inactive
+		InactivityAction:            "flag",                                // Flag inactive players rather than removing them
+		InactivityCheckInterval:     time.Minute,                           // Check for inactive players every minute
+		PresenceTimeout:             time.Minute,                           // Clients are expected to send a heartbeat well within a minute
+		ReconnectGracePeriod:        2 * time.Minute,                       // Hold a dropped player's seat for a couple of minutes
+		SessionPurgeInterval:        time.Hour,                             // Sweep up expired sessions hourly
+		ArchiveInterval:             10 * time.Minute,                      // Archive stragglers every ten minutes
+		BotTurnInterval:             2 * time.Second,                       // Give people time to follow the bots' moves
+		JobPollInterval:             time.Second,                           // Start queued work about a second after it is submitted
+		JobTimeout:                  10 * time.Minute,                      // Room to rebuild statistics from a large archive
+		CompressArchives:            true,                                  // Gzip archived games to keep the archive small
+		ShuffleEntropy:              "crypto",                              // Shuffle with crypto/rand unless an external source is configured
+		ShuffleEntropyTimeout:       2 * time.Second,                       // Fall back quickly rather than hold up a deal
+		EventBus:                    "off",                                 // Keep events to the API unless a message bus is configured
+		EventBusTopic:               "card_game.events",                    // Subjects such as card_game.events.card_played on NATS
+		EventBusTimeout:             5 * time.Second,                       // Retry on the next run rather than hold up the job
+		EventPublishInterval:        time.Second,                           // Consumers see events within about a second
+		TurnAlertDelay:              10 * time.Minute,                      // Leave players at the table to move without being alerted
+		TurnAlertCheckInterval:      time.Minute,                           // Alert within about a minute of the delay
+		NotifyTimeout:               10 * time.Second,                      // Give up on a slow mail relay or webhook rather than hold up the check
+		FCMURL:                      "https://fcm.googleapis.com/fcm/send", // Firebase Cloud Messaging's HTTP endpoint
+		AccessLogLevel:              "all",                                 // Log every request
+		AccessLogSampleRate:         1,                                     // Log all successful requests; lower this on busy servers
+		LegacySunset:                "2027-06-30",                          // Give clients of the unversioned paths until mid-2027 to move to /api/v1
 	}
 }
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load builds the application's configuration in layers: the defaults, then the config file at path,
+// then any environment variable overrides. The result is validated before it is returned.
+// The file may be YAML or JSON; an empty path skips the file layer.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	// Layer the config file over the defaults; JSON is valid YAML, so one decoder reads both
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+		}
+	}
+
+	// Layer the environment over the file
+	if err := applyEnv(cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate checks that the required settings are present and that every setting holds a usable value.
+func (c *Config) Validate() error {
+	var problems []string
+	require := func(ok bool, problem string) {
+		if !ok {
+			problems = append(problems, problem)
+		}
+	}
+
+	require(c.ServerAddr != "", "server_addr is required")
+	require(c.MaxRequestBytes > 0, "max_request_bytes must be positive")
+	require(c.MaxImportBytes > 0, "max_import_bytes must be positive")
+	require(c.MongoDBURI != "", "mongodb_uri is required")
+	require(c.MongoDBDatabase != "", "mongodb_database is required")
+	require((c.TLSCertFile == "") == (c.TLSKeyFile == ""), "tls_cert_file and tls_key_file must be set together")
+	require(c.MongoMinPoolSize <= c.MongoMaxPoolSize || c.MongoMaxPoolSize == 0, "mongo_min_pool_size cannot exceed mongo_max_pool_size")
+	require(c.MongoConnectTimeout > 0, "mongo_connect_timeout must be positive")
+	require(c.MongoOperationTimeout > 0, "mongo_operation_timeout must be positive")
+	require(c.MongoServerSelectionTimeout == 0 || c.MongoOperationTimeout > c.MongoServerSelectionTimeout, "mongo_operation_timeout must be longer than mongo_server_selection_timeout")
+	require(c.MongoRetryMaxAttempts >= 1, "mongo_retry_max_attempts must be at least 1")
+	require(c.MongoRetryBaseDelay > 0, "mongo_retry_base_delay must be positive")
+	require(c.SessionTTL > 0, "session_ttl must be positive")
+	require(c.InactivityCheckInterval > 0, "inactivity_check_interval must be positive")
+	require(c.PresenceTimeout > 0, "presence_timeout must be positive")
+	require(c.ReconnectGracePeriod > 0, "reconnect_grace_period must be positive")
+	require(c.SessionPurgeInterval > 0, "session_purge_interval must be positive")
+	require(c.ArchiveInterval > 0, "archive_interval must be positive")
+	require(c.BotTurnInterval > 0, "bot_turn_interval must be positive")
+	require(c.JobPollInterval > 0, "job_poll_interval must be positive")
+	require(c.JobTimeout > 0, "job_timeout must be positive")
+	require(c.InactivityAction == "flag" || c.InactivityAction == "remove", `inactivity_action must be "flag" or "remove"`)
+	require(c.ShuffleEntropy == "crypto" || c.ShuffleEntropy == "device" || c.ShuffleEntropy == "http", `shuffle_entropy must be "crypto", "device", or "http"`)
+	require(c.ShuffleEntropy != "device" || c.ShuffleEntropyDevice != "", `shuffle_entropy_device is required when shuffle_entropy is "device"`)
+	require(c.ShuffleEntropy != "http" || c.ShuffleEntropyURL != "", `shuffle_entropy_url is required when shuffle_entropy is "http"`)
+	require(c.ShuffleEntropyTimeout > 0, "shuffle_entropy_timeout must be positive")
+	require(c.EventBus == "off" || c.EventBus == "nats" || c.EventBus == "kafka", `event_bus must be "off", "nats", or "kafka"`)
+	require(c.EventBus == "off" || c.EventBusURL != "", "event_bus_url is required when event_bus is not off")
+	require(c.EventBus == "off" || c.EventBusTopic != "", "event_bus_topic is required when event_bus is not off")
+	require(c.EventBusTimeout > 0, "event_bus_timeout must be positive")
+	require(c.EventPublishInterval > 0, "event_publish_interval must be positive")
+	require(c.TurnAlertDelay > 0, "turn_alert_delay must be positive")
+	require(c.TurnAlertCheckInterval > 0, "turn_alert_check_interval must be positive")
+	require(c.NotifyTimeout > 0, "notify_timeout must be positive")
+	require(c.SMTPAddr == "" || c.SMTPFrom != "", "smtp_from is required when smtp_addr is set")
+	require(c.FCMServerKey == "" || c.FCMURL != "", "fcm_url is required when fcm_server_key is set")
+	require(c.AccessLogLevel == "off" || c.AccessLogLevel == "errors" || c.AccessLogLevel == "all", `access_log_level must be "off", "errors", or "all"`)
+	require(c.AccessLogSampleRate >= 0 && c.AccessLogSampleRate <= 1, "access_log_sample_rate must be between 0 and 1")
+	_, sunsetErr := time.Parse("2006-01-02", c.LegacySunset)
+	require(c.LegacySunset == "" || sunsetErr == nil, "legacy_sunset must be a date such as 2027-06-30")
+
+	if len(problems) > 0 {
+		return errors.New("invalid configuration: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// applyEnv overrides each field whose environment variable, named by its env tag, is set.
+func applyEnv(cfg *Config) error {
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("env")
+		value, ok := os.LookupEnv(name)
+		if name == "" || !ok {
+			continue
+		}
+		if err := setField(v.Field(i), value); err != nil {
+			return fmt.Errorf("environment variable %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// setField parses an environment variable's value into a config field of any of the types Config uses.
+func setField(field reflect.Value, value string) error {
+	// Durations are int64s underneath, so they must be checked before the integer kinds
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		// Lists are comma-separated
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported config field type %s", field.Type())
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"errors"
 	"log"
 	"my-card-game/internal/config"
 	"time"
@@ -13,13 +14,27 @@ import (
 var (
 	client *mongo.Client
 	gameDB *mongo.Database
+
+	// OperationTimeout bounds each database operation made on behalf of a request.
+	// It is set from the configuration when the database is connected.
+	OperationTimeout = 15 * time.Second
 )
 
 // ConnectDB establishes a connection to the MongoDB instance using the provided configuration settings.
 // It initializes the global MongoDB client and the game database instance.
 func ConnectDB(cfg *config.Config) {
-	// Configure MongoDB client options with the provided URI
-	clientOptions := options.Client().ApplyURI(cfg.MongoDBURI)
+	// Configure MongoDB client options with the provided URI, connection pool, and timeouts
+	clientOptions := options.Client().
+		ApplyURI(cfg.MongoDBURI).
+		SetMaxPoolSize(cfg.MongoMaxPoolSize).
+		SetMinPoolSize(cfg.MongoMinPoolSize).
+		SetMaxConnIdleTime(cfg.MongoMaxConnIdleTime).
+		SetConnectTimeout(cfg.MongoConnectTimeout).
+		SetSocketTimeout(cfg.MongoSocketTimeout).
+		SetServerSelectionTimeout(cfg.MongoServerSelectionTimeout)
+	OperationTimeout = cfg.MongoOperationTimeout
+	RetryMaxAttempts = cfg.MongoRetryMaxAttempts
+	RetryBaseDelay = cfg.MongoRetryBaseDelay
 
 	var err error
 	// Create a new MongoDB client
@@ -30,7 +45,7 @@ func ConnectDB(cfg *config.Config) {
 	}
 
 	// Set a timeout for the connection operation
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.MongoConnectTimeout)
 	defer cancel()
 
 	log.Println("Attempting to connect to MongoDB...")
@@ -61,15 +76,12 @@ func ConnectDB(cfg *config.Config) {
 	}
 }
 
-// GetCollection returns a reference to a MongoDB collection in the game database.
+// GetCollection returns a reference to a MongoDB collection in the game database, which holds the default
+// organization's data and the data shared by every organization, such as API keys.
 // It ensures that the database connection is established before accessing collections.
 func GetCollection(collectionName string) *mongo.Collection {
-	if gameDB == nil {
-		// Log and exit if the database connection is nil
-		log.Fatal("Database connection is nil. Ensure ConnectDB is called before accessing collections.")
-	}
 	// Return the requested collection
-	return gameDB.Collection(collectionName)
+	return GetOrgCollection(DefaultOrg, collectionName)
 }
 
 // DisconnectDB disconnects from the MongoDB instance and cleans up the client resources.
@@ -87,3 +99,11 @@ func DisconnectDB() {
 	}
 	log.Println("Disconnected from MongoDB!")
 }
+
+// Ping checks that the MongoDB deployment is reachable.
+func Ping(ctx context.Context) error {
+	if client == nil {
+		return errors.New("database is not connected")
+	}
+	return client.Ping(ctx, nil)
+}
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// indexes lists the indexes the application relies on, keyed by collection name.
+// Keeping them in code means a fresh deployment gets them without any manual MongoDB setup.
+var indexes = map[string][]mongo.IndexModel{
+	"games": {
+		{Keys: bson.D{{Key: "name", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "players", Value: 1}}},
+		{Keys: bson.D{{Key: "created_at", Value: -1}}},
+	},
+	"events": {
+		// Event logs are always read per game in the order they were recorded
+		{Keys: bson.D{{Key: "game_id", Value: 1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}},
+	},
+	"sessions": {
+		// Expired sessions are removed automatically by MongoDB
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	},
+	"api_keys": {
+		{Keys: bson.D{{Key: "key_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	},
+	"snapshots": {
+		// Snapshot names identify a snapshot within its game
+		{Keys: bson.D{{Key: "game_id", Value: 1}, {Key: "name", Value: 1}}, Options: options.Index().SetUnique(true)},
+	},
+	"games_archive": {
+		{Keys: bson.D{{Key: "archived_at", Value: -1}}},
+	},
+	"friendships": {
+		// A pair of players has at most one request or friendship per direction
+		{Keys: bson.D{{Key: "from", Value: 1}, {Key: "to", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "to", Value: 1}}},
+	},
+	"invitations": {
+		{Keys: bson.D{{Key: "to", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+	},
+	"notifications": {
+		// Notifications are listed per player, newest first
+		{Keys: bson.D{{Key: "player", Value: 1}, {Key: "created_at", Value: -1}}},
+	},
+	"achievements": {
+		// A player's achievements are listed together
+		{Keys: bson.D{{Key: "player_name", Value: 1}}},
+	},
+	"jobs": {
+		// Workers take the oldest waiting job, and callers list their own jobs newest first
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "created_at", Value: -1}}},
+		// Finished jobs and their results are removed by MongoDB a week after they finish
+		{Keys: bson.D{{Key: "finished_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(7 * 24 * 60 * 60)},
+	},
+	"wallet_transactions": {
+		// A player's wallet history is listed newest first
+		{Keys: bson.D{{Key: "player_name", Value: 1}, {Key: "created_at", Value: -1}}},
+	},
+	"shuffle_log": {
+		// A game's shuffles are listed in the order they were made
+		{Keys: bson.D{{Key: "game_id", Value: 1}, {Key: "shuffled_at", Value: 1}}},
+	},
+	"audit_log": {
+		// The audit log is listed newest first, overall or for one game or actor
+		{Keys: bson.D{{Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "game_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "actor", Value: 1}, {Key: "created_at", Value: -1}}},
+	},
+}
+
+// EnsureIndexes creates any of the application's indexes that do not exist yet in the default organization's
+// database. Creating an index that already exists is a no-op, so it is safe to call on every startup.
+func EnsureIndexes() error {
+	if err := EnsureOrgIndexes(DefaultOrg); err != nil {
+		return err
+	}
+
+	log.Println("Database indexes are in place")
+	return nil
+}
+
+// EnsureOrgIndexes creates any of the application's indexes that do not exist yet in an organization's database.
+func EnsureOrgIndexes(org string) error {
+	// Index builds can take a while on large collections
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	for collection, models := range indexes {
+		if _, err := GetOrgCollection(org, collection).Indexes().CreateMany(ctx, models); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"log"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DefaultOrg is the organization whose data is kept in the configured database itself, where all data was kept
+// before the server hosted several organizations. Requests that name no organization act in it.
+const DefaultOrg = ""
+
+// orgIDPattern matches the IDs organizations can be given. They become part of a database name, so they are
+// kept short and limited to lowercase letters, digits, and dashes.
+var orgIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+// ValidOrgID reports whether id can be given to a new organization.
+func ValidOrgID(id string) bool {
+	return orgIDPattern.MatchString(id)
+}
+
+// OrgDatabase returns the database an organization's data is kept in. Every organization other than the default
+// one has a database of its own, named after the configured database and the organization's ID, so one
+// organization's queries can never reach another's data.
+func OrgDatabase(org string) *mongo.Database {
+	if gameDB == nil {
+		// Log and exit if the database connection is nil
+		log.Fatal("Database connection is nil. Ensure ConnectDB is called before accessing collections.")
+	}
+	if org == DefaultOrg {
+		return gameDB
+	}
+	return client.Database(gameDB.Name() + "_" + org)
+}
+
+// GetOrgCollection returns a reference to a MongoDB collection in an organization's database.
+func GetOrgCollection(org, collectionName string) *mongo.Collection {
+	return OrgDatabase(org).Collection(collectionName)
+}
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+// Retry settings for transient MongoDB failures. They are set from the configuration when the database is connected.
+var (
+	RetryMaxAttempts = 3                      // Attempts made before giving up, including the first
+	RetryBaseDelay   = 100 * time.Millisecond // Delay before the first retry; it doubles after each attempt
+	RetryMaxDelay    = 2 * time.Second        // Longest delay between two attempts
+)
+
+// transientErrorCodes are MongoDB server error codes that clear up on their own, such as during a primary election.
+var transientErrorCodes = map[int]bool{
+	6:     true, // HostUnreachable
+	7:     true, // HostNotFound
+	89:    true, // NetworkTimeout
+	91:    true, // ShutdownInProgress
+	189:   true, // PrimarySteppedDown
+	262:   true, // ExceededTimeLimit
+	9001:  true, // SocketException
+	10107: true, // NotWritablePrimary
+	11600: true, // InterruptedAtShutdown
+	11602: true, // InterruptedDueToReplStateChange
+	13435: true, // NotPrimaryNoSecondaryOk
+	13436: true, // NotPrimaryOrSecondary
+}
+
+// Retry runs op, retrying it with exponential backoff and jitter while it fails with a transient error.
+// Errors that are not transient, such as a missing document or a validation failure, are returned straight away,
+// and retrying stops early once ctx is done. Inside a transaction op runs once, since the transaction as a whole is retried.
+func Retry(ctx context.Context, op func() error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return op()
+	}
+
+	delay := RetryBaseDelay
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || !IsTransient(err) || attempt >= RetryMaxAttempts {
+			return err
+		}
+
+		// Wait a random time up to the current delay so retrying clients don't move in lockstep
+		wait := delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+
+		delay *= 2
+		if delay > RetryMaxDelay {
+			delay = RetryMaxDelay
+		}
+	}
+}
+
+// IsTransient reports whether err is a MongoDB failure that is likely to succeed if the operation is retried.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if mongo.IsNetworkError(err) {
+		return true
+	}
+
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) {
+		if serverErr.HasErrorLabel("TransientTransactionError") || serverErr.HasErrorLabel("RetryableWriteError") {
+			return true
+		}
+		for code := range transientErrorCodes {
+			if serverErr.HasErrorCode(code) {
+				return true
+			}
+		}
+	}
+
+	// Server selection fails while no suitable server is available, for example during a primary election
+	var selectionErr topology.ServerSelectionError
+	return errors.As(err, &selectionErr)
+}
@@ -0,0 +1,31 @@
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// WithTransaction runs fn inside a MongoDB multi-document transaction.
+// Every database operation fn performs with the context it is given is committed together, or rolled back together
+// if fn returns an error, so compound operations spanning several collections cannot be left half done.
+// If ctx already belongs to a transaction, fn simply joins it. Transactions require MongoDB to run as a replica set.
+func WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// Join the surrounding transaction if there is one
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	// Start a session for the transaction
+	session, err := client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	// Run fn in the transaction; the driver retries it on transient transaction errors
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
@@ -0,0 +1,279 @@
+// Package entropy supplies the random bytes that decks are shuffled with. The bytes come from crypto/rand unless
+// an external source, such as a hardware security module's random device or a random-beacon API, is configured,
+// in which case crypto/rand is the fallback whenever the external source fails.
+package entropy
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	mathrand "math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kinds of entropy source that can be configured.
+const (
+	KindCrypto = "crypto" // The operating system's generator, through crypto/rand
+	KindDevice = "device" // A random device file, such as the one a hardware security module exposes
+	KindHTTP   = "http"   // A random-beacon API that answers GET requests with random bytes
+)
+
+// CryptoName names crypto/rand in shuffle records.
+const CryptoName = "crypto/rand"
+
+// defaultHTTPTimeout is how long a request to a random-beacon API may take when no timeout is configured.
+const defaultHTTPTimeout = 2 * time.Second
+
+// Source supplies random bytes. Read fills p completely or returns an error.
+type Source interface {
+	Name() string
+	Read(p []byte) (int, error)
+}
+
+// Config chooses the entropy source shuffles use.
+type Config struct {
+	Kind    string        // One of the Kind constants; empty means KindCrypto
+	Device  string        // Path of the random device, for KindDevice
+	URL     string        // URL of the random-beacon API, for KindHTTP
+	Timeout time.Duration // How long a request to the random-beacon API may take
+}
+
+// New builds the source described by the configuration.
+func New(cfg Config) (Source, error) {
+	switch cfg.Kind {
+	case "", KindCrypto:
+		return Crypto(), nil
+	case KindDevice:
+		if cfg.Device == "" {
+			return nil, errors.New("a device entropy source needs a device path")
+		}
+		return &deviceSource{path: cfg.Device}, nil
+	case KindHTTP:
+		if cfg.URL == "" {
+			return nil, errors.New("an http entropy source needs a URL")
+		}
+		timeout := cfg.Timeout
+		if timeout <= 0 {
+			timeout = defaultHTTPTimeout
+		}
+		return &httpSource{url: cfg.URL, client: &http.Client{Timeout: timeout}}, nil
+	default:
+		return nil, fmt.Errorf("unknown entropy source %q", cfg.Kind)
+	}
+}
+
+// Crypto returns the source backed by crypto/rand.
+func Crypto() Source {
+	return cryptoSource{}
+}
+
+// SeededName names seeded sources in shuffle records.
+const SeededName = "seeded"
+
+// Seeded returns a source backed by its own math/rand generator started from seed, so the same seed always yields
+// the same bytes. It is for tests and simulations, never for dealing real games, and is safe for concurrent use.
+func Seeded(seed int64) Source {
+	return &seededSource{rand: mathrand.New(mathrand.NewSource(seed))}
+}
+
+// Draw is a supply of random numbers for one shuffle. It reads the bytes it expects to need from its source up
+// front, so an external source is asked once per shuffle, and reads more if it runs short. If the source fails,
+// the draw falls back to crypto/rand for the rest of the shuffle and remembers why.
+type Draw struct {
+	source   Source
+	buffer   []byte
+	read     []byte // Every byte read so far, kept so the draw can be replayed
+	used     string // Name of the source the bytes came from
+	fallback error  // Why the draw fell back to crypto/rand, if it did
+}
+
+// NewDraw starts a draw from source, reading size bytes ahead.
+func NewDraw(source Source, size int) *Draw {
+	d := &Draw{source: source, used: source.Name()}
+	d.fill(size)
+	return d
+}
+
+// Intn returns a uniformly distributed random number in [0, n). It panics if n is not positive.
+func (d *Draw) Intn(n int) int {
+	if n <= 0 {
+		panic("entropy: invalid argument to Intn")
+	}
+
+	// Reject values from the incomplete last block of the range so every result is equally likely
+	limit := uint64(1<<32) - uint64(1<<32)%uint64(n)
+	for {
+		if len(d.buffer) < 4 {
+			d.fill(64)
+		}
+		value := uint64(binary.BigEndian.Uint32(d.buffer[:4]))
+		d.buffer = d.buffer[4:]
+		if value < limit {
+			return int(value % uint64(n))
+		}
+	}
+}
+
+// Source returns the name of the source the draw's bytes came from: the configured source, or crypto/rand
+// once the draw has fallen back to it.
+func (d *Draw) Source() string {
+	return d.used
+}
+
+// Bytes returns every byte the draw has read, whichever source it came from. A draw from Replay(d.Bytes())
+// makes the same choices as d.
+func (d *Draw) Bytes() []byte {
+	return d.read
+}
+
+// Fallback returns why the draw fell back to crypto/rand, or nil if it did not.
+func (d *Draw) Fallback() error {
+	return d.fallback
+}
+
+// fill reads at least size more bytes into the buffer, falling back to crypto/rand if the source fails.
+func (d *Draw) fill(size int) {
+	chunk := make([]byte, size)
+	if d.fallback == nil {
+		_, err := d.source.Read(chunk)
+		if err == nil {
+			d.buffer = append(d.buffer, chunk...)
+			d.read = append(d.read, chunk...)
+			return
+		}
+		d.fallback = err
+		d.used = CryptoName
+	}
+
+	// crypto/rand only fails if the operating system cannot supply randomness at all
+	if _, err := rand.Read(chunk); err != nil {
+		panic("entropy: crypto/rand failed: " + err.Error())
+	}
+	d.buffer = append(d.buffer, chunk...)
+	d.read = append(d.read, chunk...)
+}
+
+// ReplayName names the source of replayed shuffles.
+const ReplayName = "replay"
+
+// Replay returns a source that hands out the given bytes, as recorded from an earlier draw, and fails once they
+// run out.
+func Replay(seed []byte) Source {
+	return &replaySource{seed: seed}
+}
+
+// cryptoSource reads from crypto/rand.
+type cryptoSource struct{}
+
+func (cryptoSource) Name() string { return CryptoName }
+
+func (cryptoSource) Read(p []byte) (int, error) {
+	return io.ReadFull(rand.Reader, p)
+}
+
+// deviceSource reads from a random device file, opened for each read so a device that comes back after an
+// outage is picked up again.
+type deviceSource struct {
+	path string
+}
+
+func (s *deviceSource) Name() string { return "device:" + s.path }
+
+func (s *deviceSource) Read(p []byte) (int, error) {
+	device, err := os.Open(s.path)
+	if err != nil {
+		return 0, err
+	}
+	defer device.Close()
+	return io.ReadFull(device, p)
+}
+
+// httpSource asks a random-beacon API for bytes, repeating the request until it has enough. The API may answer
+// with raw bytes or with hex-encoded text.
+type httpSource struct {
+	url    string
+	client *http.Client
+}
+
+func (s *httpSource) Name() string { return "http:" + s.url }
+
+func (s *httpSource) Read(p []byte) (int, error) {
+	read := 0
+	for read < len(p) {
+		chunk, err := s.fetch()
+		if err != nil {
+			return read, err
+		}
+		if len(chunk) == 0 {
+			return read, errors.New("random beacon returned no bytes")
+		}
+		read += copy(p[read:], chunk)
+	}
+	return read, nil
+}
+
+// fetch makes one request to the random-beacon API and returns the bytes it answered with.
+func (s *httpSource) fetch() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.client.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("random beacon answered %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if err != nil {
+		return nil, err
+	}
+	if decoded, err := hex.DecodeString(strings.TrimSpace(string(body))); err == nil {
+		return decoded, nil
+	}
+	return body, nil
+}
+
+// seededSource reads from a math/rand generator, which is not safe for concurrent use on its own.
+type seededSource struct {
+	mu   sync.Mutex
+	rand *mathrand.Rand
+}
+
+func (s *seededSource) Name() string { return SeededName }
+
+func (s *seededSource) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rand.Read(p)
+}
+
+// replaySource hands out recorded bytes in order.
+type replaySource struct {
+	seed []byte
+}
+
+func (s *replaySource) Name() string { return ReplayName }
+
+func (s *replaySource) Read(p []byte) (int, error) {
+	if len(p) > len(s.seed) {
+		return 0, errors.New("the recorded seed has run out")
+	}
+	n := copy(p, s.seed)
+	s.seed = s.seed[n:]
+	return n, nil
+}
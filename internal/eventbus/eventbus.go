@@ -0,0 +1,258 @@
+// Package eventbus publishes game events to a message bus, so analytics pipelines, fraud detection, and other
+// services can follow the activity on the server without polling the API. It speaks the NATS client protocol
+// directly, and reaches Kafka through a Kafka REST Proxy, so neither needs a client library.
+package eventbus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kinds of message bus that can be configured.
+const (
+	KindOff   = "off"   // Events are not published
+	KindNATS  = "nats"  // A NATS server, reached at a nats:// or tls:// URL
+	KindKafka = "kafka" // A Kafka cluster, reached through the HTTP URL of a Kafka REST Proxy
+)
+
+// defaultTimeout is how long publishing a batch may take when no timeout is configured.
+const defaultTimeout = 5 * time.Second
+
+// Message is one event to publish.
+type Message struct {
+	Key   string // Key the bus orders messages by, such as the game ID; Kafka keeps a key's messages in one partition
+	Type  string // Event type, appended to the subject on NATS
+	Value []byte // Event as JSON
+}
+
+// Publisher publishes messages to a message bus. Publish returns once the bus has accepted every message, or
+// with an error if it may not have accepted some of them, in which case the whole batch is to be sent again.
+type Publisher interface {
+	Name() string
+	Publish(ctx context.Context, messages []Message) error
+}
+
+// Config chooses the message bus events are published to.
+type Config struct {
+	Kind    string        // One of the Kind constants; empty means KindOff
+	URL     string        // URL of the NATS server or the Kafka REST Proxy
+	Topic   string        // Kafka topic, or the NATS subject prefix each event type is appended to
+	Timeout time.Duration // How long publishing a batch may take
+}
+
+// New builds the publisher described by the configuration, or returns nil if publishing is off.
+func New(cfg Config) (Publisher, error) {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	switch cfg.Kind {
+	case "", KindOff:
+		return nil, nil
+	case KindNATS:
+		if cfg.URL == "" || cfg.Topic == "" {
+			return nil, errors.New("a nats event bus needs a URL and a subject")
+		}
+		server, err := url.Parse(cfg.URL)
+		if err != nil || (server.Scheme != "nats" && server.Scheme != "tls") || server.Host == "" {
+			return nil, fmt.Errorf("invalid nats URL %q", cfg.URL)
+		}
+		return &natsPublisher{server: server, subject: cfg.Topic, timeout: timeout}, nil
+	case KindKafka:
+		if cfg.URL == "" || cfg.Topic == "" {
+			return nil, errors.New("a kafka event bus needs a REST proxy URL and a topic")
+		}
+		endpoint := strings.TrimRight(cfg.URL, "/") + "/topics/" + url.PathEscape(cfg.Topic)
+		return &kafkaPublisher{endpoint: endpoint, client: &http.Client{Timeout: timeout}}, nil
+	default:
+		return nil, fmt.Errorf("unknown event bus %q", cfg.Kind)
+	}
+}
+
+// natsPublisher publishes to a NATS server over one connection, which it opens when it first publishes and opens
+// again after any failure.
+type natsPublisher struct {
+	server  *url.URL
+	subject string
+	timeout time.Duration
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func (p *natsPublisher) Name() string { return "nats:" + p.server.Host }
+
+// Publish sends each message on the subject prefix followed by its type, then waits for the server to answer a
+// PING, which it only does once it has processed every message sent before it.
+func (p *natsPublisher) Publish(ctx context.Context, messages []Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn == nil {
+		if err := p.connect(ctx); err != nil {
+			return err
+		}
+	}
+	deadline := time.Now().Add(p.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	p.conn.SetDeadline(deadline)
+
+	var batch bytes.Buffer
+	for _, message := range messages {
+		fmt.Fprintf(&batch, "PUB %s.%s %d\r\n", p.subject, message.Type, len(message.Value))
+		batch.Write(message.Value)
+		batch.WriteString("\r\n")
+	}
+	batch.WriteString("PING\r\n")
+	if _, err := p.conn.Write(batch.Bytes()); err != nil {
+		p.closeConn()
+		return err
+	}
+
+	// Answer the server's own pings while waiting for the pong
+	for {
+		line, err := p.reader.ReadString('\n')
+		if err != nil {
+			p.closeConn()
+			return err
+		}
+		switch line = strings.TrimSpace(line); {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := p.conn.Write([]byte("PONG\r\n")); err != nil {
+				p.closeConn()
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			p.closeConn()
+			return fmt.Errorf("nats server refused the messages: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// connect opens a connection to the server, reads its INFO, upgrades to TLS if the server or URL asks for it, and
+// introduces the client with the credentials in the URL, if any.
+func (p *natsPublisher) connect(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: p.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", p.server.Host)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(p.timeout))
+	reader := bufio.NewReader(conn)
+
+	line, err := reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "INFO ") {
+		conn.Close()
+		return fmt.Errorf("%s did not greet as a nats server", p.server.Host)
+	}
+	var info struct {
+		TLSRequired bool `json:"tls_required"`
+	}
+	json.Unmarshal([]byte(strings.TrimPrefix(line, "INFO ")), &info)
+	if info.TLSRequired || p.server.Scheme == "tls" {
+		secure := tls.Client(conn, &tls.Config{ServerName: p.server.Hostname()})
+		if err := secure.Handshake(); err != nil {
+			conn.Close()
+			return err
+		}
+		conn, reader = secure, bufio.NewReader(secure)
+	}
+
+	options := map[string]interface{}{"verbose": false, "pedantic": false, "name": "my-card-game", "lang": "go"}
+	if user := p.server.User; user != nil {
+		if password, ok := user.Password(); ok {
+			options["user"], options["pass"] = user.Username(), password
+		} else {
+			options["auth_token"] = user.Username()
+		}
+	}
+	connect, _ := json.Marshal(options)
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\n", connect); err != nil {
+		conn.Close()
+		return err
+	}
+
+	p.conn, p.reader = conn, reader
+	return nil
+}
+
+// closeConn drops the connection so the next publish opens a new one.
+func (p *natsPublisher) closeConn() {
+	p.conn.Close()
+	p.conn, p.reader = nil, nil
+}
+
+// kafkaPublisher produces to a Kafka topic through the v2 API of a Kafka REST Proxy.
+type kafkaPublisher struct {
+	endpoint string
+	client   *http.Client
+}
+
+func (p *kafkaPublisher) Name() string { return "kafka:" + p.endpoint }
+
+// Publish produces the messages as one batch of JSON records, keyed so each game's events stay in order.
+func (p *kafkaPublisher) Publish(ctx context.Context, messages []Message) error {
+	type record struct {
+		Key   string          `json:"key"`
+		Value json.RawMessage `json:"value"`
+	}
+	records := make([]record, len(messages))
+	for i, message := range messages {
+		records[i] = record{Key: message.Key, Value: message.Value}
+	}
+	body, err := json.Marshal(map[string]interface{}{"records": records})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("kafka REST proxy answered %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+
+	// The proxy reports a failure for each record it could not produce
+	var result struct {
+		Offsets []struct {
+			ErrorCode *int   `json:"error_code"`
+			Error     string `json:"error"`
+		} `json:"offsets"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	for _, offset := range result.Offsets {
+		if offset.ErrorCode != nil {
+			return fmt.Errorf("kafka could not produce a record: %s", offset.Error)
+		}
+	}
+	return nil
+}
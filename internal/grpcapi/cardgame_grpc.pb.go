@@ -0,0 +1,275 @@
+// The CardGame service exposes the core game operations over gRPC for programmatic clients and game frontends.
+// It shares the service layer with the REST API, so both see the same games.
+//
+// Authentication uses gRPC metadata: "authorization: Bearer <session token>" for players and
+// "x-api-key: <key>" for admins, matching the REST API's headers.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: cardgame.proto
+
+package grpcapi
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	CardGame_CreateGame_FullMethodName    = "/cardgame.v1.CardGame/CreateGame"
+	CardGame_JoinGame_FullMethodName      = "/cardgame.v1.CardGame/JoinGame"
+	CardGame_DealCard_FullMethodName      = "/cardgame.v1.CardGame/DealCard"
+	CardGame_GetHand_FullMethodName       = "/cardgame.v1.CardGame/GetHand"
+	CardGame_GetHandValues_FullMethodName = "/cardgame.v1.CardGame/GetHandValues"
+)
+
+// CardGameClient is the client API for CardGame service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CardGameClient interface {
+	// CreateGame creates a new game in the lobby, owned by the calling player.
+	CreateGame(ctx context.Context, in *CreateGameRequest, opts ...grpc.CallOption) (*Game, error)
+	// JoinGame seats the caller's player in a game. An anonymous caller joining with an unclaimed player name
+	// claims it and is issued a session token for it.
+	JoinGame(ctx context.Context, in *JoinGameRequest, opts ...grpc.CallOption) (*JoinGameResponse, error)
+	// DealCard deals the top card of the game deck to a player. Only the game's owner and admins may deal.
+	DealCard(ctx context.Context, in *DealCardRequest, opts ...grpc.CallOption) (*Card, error)
+	// GetHand returns a player's hand, if the caller is allowed to see it.
+	GetHand(ctx context.Context, in *GetHandRequest, opts ...grpc.CallOption) (*Hand, error)
+	// GetHandValues returns every player's hand value, best hand first.
+	GetHandValues(ctx context.Context, in *GetHandValuesRequest, opts ...grpc.CallOption) (*HandValues, error)
+}
+
+type cardGameClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCardGameClient(cc grpc.ClientConnInterface) CardGameClient {
+	return &cardGameClient{cc}
+}
+
+func (c *cardGameClient) CreateGame(ctx context.Context, in *CreateGameRequest, opts ...grpc.CallOption) (*Game, error) {
+	out := new(Game)
+	err := c.cc.Invoke(ctx, CardGame_CreateGame_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardGameClient) JoinGame(ctx context.Context, in *JoinGameRequest, opts ...grpc.CallOption) (*JoinGameResponse, error) {
+	out := new(JoinGameResponse)
+	err := c.cc.Invoke(ctx, CardGame_JoinGame_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardGameClient) DealCard(ctx context.Context, in *DealCardRequest, opts ...grpc.CallOption) (*Card, error) {
+	out := new(Card)
+	err := c.cc.Invoke(ctx, CardGame_DealCard_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardGameClient) GetHand(ctx context.Context, in *GetHandRequest, opts ...grpc.CallOption) (*Hand, error) {
+	out := new(Hand)
+	err := c.cc.Invoke(ctx, CardGame_GetHand_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardGameClient) GetHandValues(ctx context.Context, in *GetHandValuesRequest, opts ...grpc.CallOption) (*HandValues, error) {
+	out := new(HandValues)
+	err := c.cc.Invoke(ctx, CardGame_GetHandValues_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CardGameServer is the server API for CardGame service.
+// All implementations must embed UnimplementedCardGameServer
+// for forward compatibility
+type CardGameServer interface {
+	// CreateGame creates a new game in the lobby, owned by the calling player.
+	CreateGame(context.Context, *CreateGameRequest) (*Game, error)
+	// JoinGame seats the caller's player in a game. An anonymous caller joining with an unclaimed player name
+	// claims it and is issued a session token for it.
+	JoinGame(context.Context, *JoinGameRequest) (*JoinGameResponse, error)
+	// DealCard deals the top card of the game deck to a player. Only the game's owner and admins may deal.
+	DealCard(context.Context, *DealCardRequest) (*Card, error)
+	// GetHand returns a player's hand, if the caller is allowed to see it.
+	GetHand(context.Context, *GetHandRequest) (*Hand, error)
+	// GetHandValues returns every player's hand value, best hand first.
+	GetHandValues(context.Context, *GetHandValuesRequest) (*HandValues, error)
+	mustEmbedUnimplementedCardGameServer()
+}
+
+// UnimplementedCardGameServer must be embedded to have forward compatible implementations.
+type UnimplementedCardGameServer struct {
+}
+
+func (UnimplementedCardGameServer) CreateGame(context.Context, *CreateGameRequest) (*Game, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGame not implemented")
+}
+func (UnimplementedCardGameServer) JoinGame(context.Context, *JoinGameRequest) (*JoinGameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JoinGame not implemented")
+}
+func (UnimplementedCardGameServer) DealCard(context.Context, *DealCardRequest) (*Card, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DealCard not implemented")
+}
+func (UnimplementedCardGameServer) GetHand(context.Context, *GetHandRequest) (*Hand, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHand not implemented")
+}
+func (UnimplementedCardGameServer) GetHandValues(context.Context, *GetHandValuesRequest) (*HandValues, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHandValues not implemented")
+}
+func (UnimplementedCardGameServer) mustEmbedUnimplementedCardGameServer() {}
+
+// UnsafeCardGameServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CardGameServer will
+// result in compilation errors.
+type UnsafeCardGameServer interface {
+	mustEmbedUnimplementedCardGameServer()
+}
+
+func RegisterCardGameServer(s grpc.ServiceRegistrar, srv CardGameServer) {
+	s.RegisterService(&CardGame_ServiceDesc, srv)
+}
+
+func _CardGame_CreateGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardGameServer).CreateGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CardGame_CreateGame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardGameServer).CreateGame(ctx, req.(*CreateGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CardGame_JoinGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardGameServer).JoinGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CardGame_JoinGame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardGameServer).JoinGame(ctx, req.(*JoinGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CardGame_DealCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DealCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardGameServer).DealCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CardGame_DealCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardGameServer).DealCard(ctx, req.(*DealCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CardGame_GetHand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardGameServer).GetHand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CardGame_GetHand_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardGameServer).GetHand(ctx, req.(*GetHandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CardGame_GetHandValues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHandValuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardGameServer).GetHandValues(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CardGame_GetHandValues_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardGameServer).GetHandValues(ctx, req.(*GetHandValuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CardGame_ServiceDesc is the grpc.ServiceDesc for CardGame service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CardGame_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cardgame.v1.CardGame",
+	HandlerType: (*CardGameServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateGame",
+			Handler:    _CardGame_CreateGame_Handler,
+		},
+		{
+			MethodName: "JoinGame",
+			Handler:    _CardGame_JoinGame_Handler,
+		},
+		{
+			MethodName: "DealCard",
+			Handler:    _CardGame_DealCard_Handler,
+		},
+		{
+			MethodName: "GetHand",
+			Handler:    _CardGame_GetHand_Handler,
+		},
+		{
+			MethodName: "GetHandValues",
+			Handler:    _CardGame_GetHandValues_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "cardgame.proto",
+}
@@ -0,0 +1,226 @@
+// Package grpcapi serves the core game operations over gRPC, next to the REST API and on top of the same service layer.
+// The messages and service stubs are generated from cardgame.proto.
+package grpcapi
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative cardgame.proto
+
+import (
+	"context"
+	"errors"
+	"my-card-game/internal/api/models"
+	"my-card-game/internal/api/services"
+	"my-card-game/internal/auth"
+	"my-card-game/internal/validate"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements the CardGame gRPC service defined in cardgame.proto.
+type Server struct {
+	UnimplementedCardGameServer
+
+	gameService    *services.GameService
+	sessionService *services.SessionService
+	keyService     *services.APIKeyService
+}
+
+// NewServer creates a gRPC server exposing the CardGame service on top of the given services.
+func NewServer(gameService *services.GameService, sessionService *services.SessionService, keyService *services.APIKeyService) *grpc.Server {
+	s := &Server{gameService: gameService, sessionService: sessionService, keyService: keyService}
+
+	server := grpc.NewServer(grpc.UnaryInterceptor(s.authenticate))
+	RegisterCardGameServer(server, s)
+	return server
+}
+
+// authenticate resolves the caller's identity from the request metadata, like the REST auth middleware does
+// from the request headers, and stores it in the context.
+func (s *Server) authenticate(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	identity := auth.Identity{}
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if values := md.Get("authorization"); len(values) > 0 && strings.HasPrefix(values[0], "Bearer ") {
+		token := strings.TrimSpace(strings.TrimPrefix(values[0], "Bearer "))
+		if resolved, ok := s.sessionService.ResolveSession(ctx, token); ok {
+			identity = resolved
+		}
+	}
+	if values := md.Get(strings.ToLower(auth.APIKeyHeader)); len(values) > 0 {
+		// The gRPC API serves the default organization, so only platform keys grant admin rights
+		if org, ok := s.keyService.ValidateAPIKey(ctx, values[0]); ok && org == "" {
+			identity.Admin = true
+			identity.Platform = true
+		}
+	}
+
+	return handler(auth.WithIdentity(ctx, identity), req)
+}
+
+// CreateGame creates a new game in the lobby, owned by the calling player.
+func (s *Server) CreateGame(ctx context.Context, req *CreateGameRequest) (*Game, error) {
+	game, err := s.gameService.CreateGame(req.Name, auth.FromContext(ctx).PlayerName, services.GameOptions{Mode: req.Mode, Private: req.Private, Password: req.Password})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return gameMessage(game), nil
+}
+
+// JoinGame seats a player in a game. Callers with a session join as their own player. An anonymous caller joins
+// with a player name nobody has claimed yet, which claims the name and issues them a session token for it.
+func (s *Server) JoinGame(ctx context.Context, req *JoinGameRequest) (*JoinGameResponse, error) {
+	// Join as the caller's player, or claim the name for an anonymous caller
+	playerName, err := actingPlayer(ctx, req.PlayerName)
+	claimed := false
+	if errors.Is(err, errNoSession) && req.PlayerName != "" {
+		playerName, err = req.PlayerName, s.sessionService.ClaimPlayer(req.PlayerName)
+		claimed = err == nil
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	game, err := s.gameService.AddPlayer(req.GameId, playerName, req.Password, 0)
+	if errors.Is(err, services.ErrAlreadySeated) && auth.FromContext(ctx).PlayerName == playerName {
+		// A seated player joining again with their own session is returning to their seat
+		game, err = s.gameService.ResumePlayer(req.GameId, playerName)
+	}
+	if err != nil {
+		if claimed {
+			// Give the name back if the join it was claimed for failed
+			s.sessionService.ReleasePlayer(playerName)
+		}
+		return nil, statusError(err)
+	}
+
+	// Only a player who just claimed their name is issued a session; everyone else already has one
+	response := &JoinGameResponse{Game: gameMessage(game)}
+	if claimed {
+		if response.SessionToken, _, err = s.sessionService.CreateSession(playerName); err != nil {
+			return nil, statusError(err)
+		}
+	}
+	return response, nil
+}
+
+// DealCard deals the top card of the game deck to a player. Only the game's owner and admins may deal.
+func (s *Server) DealCard(ctx context.Context, req *DealCardRequest) (*Card, error) {
+	identity := auth.FromContext(ctx)
+	viewer := models.Viewer{PlayerName: identity.PlayerName, Admin: identity.Admin}
+
+	card, err := s.gameService.DealCardToPlayer(req.GameId, playerOrCaller(ctx, req.PlayerName), viewer)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return cardMessage(*card), nil
+}
+
+// GetHand returns a player's hand, if the caller is allowed to see it.
+func (s *Server) GetHand(ctx context.Context, req *GetHandRequest) (*Hand, error) {
+	identity := auth.FromContext(ctx)
+	viewer := models.Viewer{PlayerName: identity.PlayerName, Admin: identity.Admin}
+
+	cards, err := s.gameService.GetPlayerHand(req.GameId, playerOrCaller(ctx, req.PlayerName), viewer)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	hand := &Hand{}
+	for _, card := range cards {
+		hand.Cards = append(hand.Cards, cardMessage(card))
+	}
+	return hand, nil
+}
+
+// GetHandValues returns every player's hand value, best hand first.
+func (s *Server) GetHandValues(ctx context.Context, req *GetHandValuesRequest) (*HandValues, error) {
+	players, err := s.gameService.GetPlayersWithHandValues(req.GameId, services.HandValueOptions{})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	values := &HandValues{}
+	for _, player := range players {
+		values.Players = append(values.Players, &PlayerHandValue{PlayerName: player.PlayerName, HandValue: int32(player.HandValue)})
+	}
+	return values, nil
+}
+
+// gameMessage converts a game into its public gRPC summary, which never includes any hands.
+func gameMessage(game *models.Game) *Game {
+	return &Game{
+		Id:       game.ID.Hex(),
+		Name:     game.Name,
+		Owner:    game.Owner,
+		Mode:     game.Mode,
+		Status:   game.Status,
+		Players:  game.Players,
+		DeckSize: int32(len(game.GameDeck)),
+	}
+}
+
+// Errors returned by actingPlayer.
+var (
+	errNoSession   = errors.New("a player session is required")
+	errNotYourName = errors.New("player_name does not match the caller's session")
+)
+
+// actingPlayer returns the player an action is taken as: the caller's session identity. A player_name in the
+// request may be left out, but must match the session when given. Admins may act as any player they name.
+func actingPlayer(ctx context.Context, playerName string) (string, error) {
+	identity := auth.FromContext(ctx)
+	switch {
+	case identity.PlayerName != "":
+		if playerName != "" && playerName != identity.PlayerName {
+			return "", errNotYourName
+		}
+		return identity.PlayerName, nil
+	case identity.Admin && playerName != "":
+		return playerName, nil
+	default:
+		return "", errNoSession
+	}
+}
+
+// playerOrCaller returns the given player name, falling back to the caller's session identity when it is empty.
+// It only chooses whose data a read looks at; what the caller may see is checked against their viewer.
+func playerOrCaller(ctx context.Context, playerName string) string {
+	if playerName != "" {
+		return playerName
+	}
+	return auth.FromContext(ctx).PlayerName
+}
+
+// statusError maps a service error onto the matching gRPC status code.
+func statusError(err error) error {
+	var violations models.Violations
+	var invalid validate.Errors
+	switch {
+	case errors.As(err, &violations):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.As(err, &invalid):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, errNoSession):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrWrongPassword), errors.Is(err, errNotYourName):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, services.ErrNameTaken):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, services.ErrGameFull), errors.Is(err, services.ErrNotEnoughPlayers):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, services.ErrGameNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case err.Error() == "invalid game ID":
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Unknown, err.Error())
+	}
+}
+
+// cardMessage converts a card to its message form.
+func cardMessage(card models.Card) *Card {
+	return &Card{Suit: card.Suit.String(), Value: card.Value.String(), Code: models.CardCode(card)}
+}
@@ -0,0 +1,317 @@
+// Package notify delivers notifications to players outside the app: by email, as a push notification through
+// Firebase Cloud Messaging, or as a webhook posted to a URL the player chose. Each channel is a Notifier, and only
+// the channels that are configured are built, so a server without an SMTP relay simply offers no email.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Channels a player can be notified on.
+const (
+	ChannelEmail   = "email"   // An email sent through the configured SMTP relay
+	ChannelPush    = "push"    // A push notification sent to a device through Firebase Cloud Messaging
+	ChannelWebhook = "webhook" // A JSON POST to an https URL the player chose
+)
+
+// defaultTimeout is how long sending one notification may take when no timeout is configured.
+const defaultTimeout = 10 * time.Second
+
+// DefaultFCMURL is the Firebase Cloud Messaging endpoint push notifications are sent to.
+const DefaultFCMURL = "https://fcm.googleapis.com/fcm/send"
+
+// Message is one notification to deliver.
+type Message struct {
+	Type    string            // Notification type, such as your_turn
+	Subject string            // Short title: the email subject or the push notification's title
+	Body    string            // Text of the notification
+	Data    map[string]string // Details for apps to act on, such as the game ID
+}
+
+// Notifier delivers messages on one channel. The address is the player's address on that channel: an email
+// address, a device's push token, or a webhook URL.
+type Notifier interface {
+	Channel() string
+	Send(ctx context.Context, address string, message Message) error
+}
+
+// Config chooses how each channel delivers its messages.
+type Config struct {
+	SMTPAddr     string        // host:port of the SMTP relay; empty turns email off
+	SMTPUsername string        // User to authenticate to the relay as; empty sends without authenticating
+	SMTPPassword string        // Password of SMTPUsername
+	SMTPFrom     string        // Address emails are sent from
+	FCMServerKey string        // Firebase Cloud Messaging server key; empty turns push notifications off
+	FCMURL       string        // Endpoint push notifications are sent to; empty means DefaultFCMURL
+	Timeout      time.Duration // How long sending one notification may take
+}
+
+// New builds the notifiers of every configured channel, by channel. Webhooks need no configuration, so they are
+// always offered.
+func New(cfg Config) (map[string]Notifier, error) {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	notifiers := map[string]Notifier{
+		ChannelWebhook: &webhookNotifier{client: publicClient(timeout)},
+	}
+	if cfg.SMTPAddr != "" {
+		host, _, err := net.SplitHostPort(cfg.SMTPAddr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid smtp address %q", cfg.SMTPAddr)
+		}
+		if cfg.SMTPFrom == "" {
+			return nil, errors.New("email notifications need a from address")
+		}
+		notifiers[ChannelEmail] = &emailNotifier{
+			addr:    cfg.SMTPAddr,
+			host:    host,
+			from:    cfg.SMTPFrom,
+			auth:    smtpAuth(cfg.SMTPUsername, cfg.SMTPPassword, host),
+			timeout: timeout,
+		}
+	}
+	if cfg.FCMServerKey != "" {
+		endpoint := cfg.FCMURL
+		if endpoint == "" {
+			endpoint = DefaultFCMURL
+		}
+		notifiers[ChannelPush] = &pushNotifier{endpoint: endpoint, key: cfg.FCMServerKey, client: &http.Client{Timeout: timeout}}
+	}
+	return notifiers, nil
+}
+
+// CheckWebhookURL checks that a webhook URL can be posted to: it must be an absolute https URL.
+func CheckWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return errors.New("webhook_url must be an https URL")
+	}
+	return nil
+}
+
+// emailNotifier sends plain-text emails through an SMTP relay, upgrading to TLS when the relay offers it.
+type emailNotifier struct {
+	addr    string
+	host    string
+	from    string
+	auth    smtp.Auth
+	timeout time.Duration
+}
+
+func (n *emailNotifier) Channel() string { return ChannelEmail }
+
+// Send mails the message to the address.
+func (n *emailNotifier) Send(ctx context.Context, address string, message Message) error {
+	if strings.ContainsAny(address, "\r\n") || !strings.Contains(address, "@") {
+		return fmt.Errorf("invalid email address %q", address)
+	}
+
+	var mail bytes.Buffer
+	fmt.Fprintf(&mail, "From: %s\r\n", n.from)
+	fmt.Fprintf(&mail, "To: %s\r\n", address)
+	fmt.Fprintf(&mail, "Subject: %s\r\n", strings.NewReplacer("\r", " ", "\n", " ").Replace(message.Subject))
+	mail.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n")
+	mail.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	mail.WriteString("\r\n")
+
+	// net/smtp has no context support, so the dial and the connection's deadline bound the exchange instead
+	dialer := net.Dialer{Timeout: n.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", n.addr)
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(n.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.host}); err != nil {
+			return err
+		}
+	}
+	if n.auth != nil {
+		if err := client.Auth(n.auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(n.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(address); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(mail.Bytes()); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// smtpAuth returns PLAIN authentication for the relay, or nil to send without authenticating.
+func smtpAuth(username, password, host string) smtp.Auth {
+	if username == "" {
+		return nil
+	}
+	return smtp.PlainAuth("", username, password, host)
+}
+
+// pushNotifier sends push notifications to devices through the Firebase Cloud Messaging HTTP API.
+type pushNotifier struct {
+	endpoint string
+	key      string
+	client   *http.Client
+}
+
+func (n *pushNotifier) Channel() string { return ChannelPush }
+
+// Send pushes the message to the device holding the push token.
+func (n *pushNotifier) Send(ctx context.Context, address string, message Message) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"to":           address,
+		"notification": map[string]string{"title": message.Subject, "body": message.Body},
+		"data":         message.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "key="+n.key)
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("fcm answered %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+
+	// FCM answers 200 even when it could not deliver to the token, and reports why in the results
+	var result struct {
+		Failure int `json:"failure"`
+		Results []struct {
+			Error string `json:"error"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if result.Failure > 0 {
+		reason := "unknown error"
+		if len(result.Results) > 0 && result.Results[0].Error != "" {
+			reason = result.Results[0].Error
+		}
+		return fmt.Errorf("fcm could not deliver the push notification: %s", reason)
+	}
+	return nil
+}
+
+// webhookNotifier posts each message as JSON to the player's webhook URL.
+type webhookNotifier struct {
+	client *http.Client
+}
+
+func (n *webhookNotifier) Channel() string { return ChannelWebhook }
+
+// Send posts the message to the webhook URL, expecting any 2xx answer.
+func (n *webhookNotifier) Send(ctx context.Context, address string, message Message) error {
+	if err := CheckWebhookURL(address); err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"type":    message.Type,
+		"subject": message.Subject,
+		"body":    message.Body,
+		"data":    message.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, address, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+// publicClient returns an HTTP client that only connects to public addresses and does not follow redirects, so a
+// webhook URL cannot be used to reach the server's own network.
+func publicClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !isPublic(ip) {
+				return fmt.Errorf("webhooks cannot be sent to %s", host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// isPublic reports whether the address is reachable on the public internet, rather than a loopback, private,
+// link-local, or unspecified address.
+func isPublic(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsUnspecified() && !ip.IsMulticast()
+}
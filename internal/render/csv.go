@@ -0,0 +1,107 @@
+package render
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// csvEncoder writes values as CSV with a header row. A list becomes one row per item and any other
+// value a single row. Nested objects are flattened into dotted column names such as "by_status.active",
+// and nested lists are written to a single cell with their items separated by semicolons.
+type csvEncoder struct{}
+
+func (csvEncoder) ContentType() string { return "text/csv" }
+
+func (csvEncoder) Encode(w io.Writer, v interface{}) error {
+	tree, err := toTree(v)
+	if err != nil {
+		return err
+	}
+
+	items, ok := tree.([]interface{})
+	if !ok {
+		items = []interface{}{tree}
+	}
+
+	// Flatten every item, collecting the columns in the order they first appear
+	columns := []string{}
+	seen := map[string]bool{}
+	rows := make([]map[string]string, len(items))
+	for i, item := range items {
+		rows[i] = map[string]string{}
+		flatten(item, "", rows[i], func(column string) {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		})
+	}
+
+	writer := csv.NewWriter(w)
+	if len(columns) > 0 {
+		if err := writer.Write(columns); err != nil {
+			return err
+		}
+	}
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			record[i] = row[column]
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// flatten writes the cells of one value into row, reporting each column it fills.
+// Scalars at the top level are written to a column named "value".
+func flatten(value interface{}, prefix string, row map[string]string, column func(string)) {
+	if obj, ok := value.(object); ok {
+		for _, m := range obj {
+			name := m.key
+			if prefix != "" {
+				name = prefix + "." + m.key
+			}
+			flatten(m.value, name, row, column)
+		}
+		return
+	}
+
+	if prefix == "" {
+		prefix = "value"
+	}
+	column(prefix)
+	row[prefix] = cell(value)
+}
+
+// cell formats a scalar or list as the text of one CSV cell.
+func cell(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case bool:
+		if value {
+			return "true"
+		}
+		return "false"
+	case []interface{}:
+		parts := make([]string, len(value))
+		for i, item := range value {
+			parts[i] = cell(item)
+		}
+		return strings.Join(parts, ";")
+	default:
+		// Objects inside lists have no columns of their own, so they are kept as JSON text
+		data, _ := json.Marshal(value)
+		return string(data)
+	}
+}
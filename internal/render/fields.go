@@ -0,0 +1,57 @@
+package render
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Fields returns the response fields a request selects with its fields query parameter, a comma-separated list
+// such as ?fields=name,players,status. It returns nil if the request does not select any fields.
+func Fields(r *http.Request) []string {
+	var fields []string
+	for _, field := range strings.Split(r.URL.Query().Get("fields"), ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// Select keeps only the named top-level fields of v's JSON form, or of each item's if v is a list, in the order
+// they were encoded in. It returns v unchanged when no fields are named, or if v cannot be encoded, leaving the
+// error to be reported when the response is written.
+func Select(v interface{}, fields []string) interface{} {
+	if len(fields) == 0 {
+		return v
+	}
+	tree, err := toTree(v)
+	if err != nil {
+		return v
+	}
+
+	keep := map[string]bool{}
+	for _, field := range fields {
+		keep[field] = true
+	}
+	pick := func(item interface{}) interface{} {
+		obj, ok := item.(object)
+		if !ok {
+			return item
+		}
+		picked := object{}
+		for _, m := range obj {
+			if keep[m.key] {
+				picked = append(picked, m)
+			}
+		}
+		return picked
+	}
+
+	if list, ok := tree.([]interface{}); ok {
+		for i, item := range list {
+			list[i] = pick(item)
+		}
+		return list
+	}
+	return pick(tree)
+}
@@ -0,0 +1,118 @@
+package render
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// msgpackEncoder writes values as MessagePack (https://msgpack.org), a compact binary equivalent of JSON.
+// Whole numbers are written as integers and other numbers as 64-bit floats.
+type msgpackEncoder struct{}
+
+func (msgpackEncoder) ContentType() string { return "application/msgpack" }
+
+func (msgpackEncoder) Encode(w io.Writer, v interface{}) error {
+	tree, err := toTree(v)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := writeMsgpack(&buf, tree); err != nil {
+		return err
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// writeMsgpack appends the MessagePack encoding of a tree value to buf, using the smallest form of each type.
+func writeMsgpack(buf *bytes.Buffer, value interface{}) error {
+	switch value := value.(type) {
+	case nil:
+		buf.WriteByte(0xc0)
+	case bool:
+		if value {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+	case json.Number:
+		if n, err := value.Int64(); err == nil {
+			writeInt(buf, n)
+			return nil
+		}
+		f, err := value.Float64()
+		if err != nil {
+			return err
+		}
+		buf.WriteByte(0xcb)
+		binary.Write(buf, binary.BigEndian, math.Float64bits(f))
+	case string:
+		writeHeader(buf, len(value), 0xa0, 32, 0xd9, 0xda, 0xdb)
+		buf.WriteString(value)
+	case []interface{}:
+		writeHeader(buf, len(value), 0x90, 16, 0, 0xdc, 0xdd)
+		for _, item := range value {
+			if err := writeMsgpack(buf, item); err != nil {
+				return err
+			}
+		}
+	case object:
+		writeHeader(buf, len(value), 0x80, 16, 0, 0xde, 0xdf)
+		for _, m := range value {
+			if err := writeMsgpack(buf, m.key); err != nil {
+				return err
+			}
+			if err := writeMsgpack(buf, m.value); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("msgpack: unsupported value of type %T", value)
+	}
+	return nil
+}
+
+// writeHeader writes the type and length prefix of a string, array, or map. Lengths below fixLimit fit in
+// the fix byte; otherwise the 8-bit (when the type has one), 16-bit, or 32-bit form is used.
+func writeHeader(buf *bytes.Buffer, length int, fix byte, fixLimit int, code8, code16, code32 byte) {
+	switch {
+	case length < fixLimit:
+		buf.WriteByte(fix | byte(length))
+	case code8 != 0 && length <= math.MaxUint8:
+		buf.WriteByte(code8)
+		buf.WriteByte(byte(length))
+	case length <= math.MaxUint16:
+		buf.WriteByte(code16)
+		binary.Write(buf, binary.BigEndian, uint16(length))
+	default:
+		buf.WriteByte(code32)
+		binary.Write(buf, binary.BigEndian, uint32(length))
+	}
+}
+
+// writeInt writes an integer in its smallest MessagePack form.
+func writeInt(buf *bytes.Buffer, n int64) {
+	switch {
+	case n >= 0 && n <= 0x7f:
+		buf.WriteByte(byte(n))
+	case n < 0 && n >= -32:
+		buf.WriteByte(byte(int8(n)))
+	case n >= math.MinInt8 && n <= math.MaxInt8:
+		buf.WriteByte(0xd0)
+		buf.WriteByte(byte(int8(n)))
+	case n >= math.MinInt16 && n <= math.MaxInt16:
+		buf.WriteByte(0xd1)
+		binary.Write(buf, binary.BigEndian, int16(n))
+	case n >= math.MinInt32 && n <= math.MaxInt32:
+		buf.WriteByte(0xd2)
+		binary.Write(buf, binary.BigEndian, int32(n))
+	default:
+		buf.WriteByte(0xd3)
+		binary.Write(buf, binary.BigEndian, n)
+	}
+}
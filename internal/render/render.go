@@ -0,0 +1,192 @@
+// Package render writes handler responses in the format the client asks for in its Accept header.
+//
+// Responses are JSON unless the client prefers another registered format. CSV (text/csv) is
+// registered for spreadsheet users and MessagePack (application/msgpack) for bandwidth-sensitive
+// game clients. Both are produced from the value's JSON form, so json struct tags and custom
+// MarshalJSON methods shape every format the same way. Further formats can be added with Register.
+//
+// Every response carries an ETag computed from its encoded body, and GET requests whose If-None-Match
+// header already names that ETag get an empty 304 Not Modified instead of the body, so clients polling
+// for changes only transfer data when it has actually changed.
+package render
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Encoder writes values in one response format.
+type Encoder interface {
+	// ContentType is the media type the encoder produces, such as "text/csv".
+	ContentType() string
+	// Encode writes v to w in the encoder's format.
+	Encode(w io.Writer, v interface{}) error
+}
+
+var (
+	mu       sync.RWMutex
+	encoders = []Encoder{jsonEncoder{}, csvEncoder{}, msgpackEncoder{}}
+)
+
+// Register adds an encoder, replacing any registered encoder for the same content type.
+func Register(encoder Encoder) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for i, existing := range encoders {
+		if existing.ContentType() == encoder.ContentType() {
+			encoders[i] = encoder
+			return
+		}
+	}
+	encoders = append(encoders, encoder)
+}
+
+// Negotiate picks the encoder that best matches an Accept header. An empty header, or one that
+// accepts anything, gets JSON. It returns false if none of the accepted formats is registered.
+func Negotiate(accept string) (Encoder, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if strings.TrimSpace(accept) == "" {
+		return encoders[0], true
+	}
+
+	// Try the accepted media ranges from most to least preferred, keeping the client's order for ties
+	ranges := parseAccept(accept)
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].quality > ranges[j].quality })
+	for _, mediaRange := range ranges {
+		if mediaRange.quality <= 0 {
+			continue
+		}
+		for _, encoder := range encoders {
+			if mediaRange.matches(encoder.ContentType()) {
+				return encoder, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Write encodes v in the format negotiated from the request's Accept header and writes it with a 200 OK status.
+// If none of the accepted formats is available, it responds with 406 Not Acceptable instead.
+func Write(w http.ResponseWriter, r *http.Request, v interface{}) {
+	encoder, ok := Negotiate(r.Header.Get("Accept"))
+	if !ok {
+		http.Error(w, "Not acceptable: supported formats are "+strings.Join(contentTypes(), ", "), http.StatusNotAcceptable)
+		return
+	}
+
+	// The response differs by Accept header, so caches must keep the formats apart
+	w.Header().Add("Vary", "Accept")
+	writeEncoded(w, r, encoder, v)
+}
+
+// JSON writes v as JSON whatever the Accept header says, with the same ETag and conditional GET handling as Write.
+func JSON(w http.ResponseWriter, r *http.Request, v interface{}) {
+	writeEncoded(w, r, jsonEncoder{}, v)
+}
+
+// writeEncoded encodes v, tags the response with an ETag of the encoded body, and writes either the body
+// or, if the client already holds the same representation, a 304 Not Modified status.
+func writeEncoded(w http.ResponseWriter, r *http.Request, encoder Encoder, v interface{}) {
+	var buf bytes.Buffer
+	if err := encoder.Encode(&buf, v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Hash the content type too so each format of the same value gets its own tag
+	sum := sha256.Sum256(append([]byte(encoder.ContentType()+"\n"), buf.Bytes()...))
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	// Responses can hold hands only this caller may see, so they may only be cached privately and must be revalidated
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+
+	if (r.Method == http.MethodGet || r.Method == http.MethodHead) && etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", encoder.ContentType())
+	w.Write(buf.Bytes())
+}
+
+// etagMatches reports whether an If-None-Match header names the ETag, using the weak comparison
+// the header calls for, so a W/ prefix is ignored. A "*" matches any ETag.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// contentTypes lists the registered formats.
+func contentTypes() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	types := make([]string, len(encoders))
+	for i, encoder := range encoders {
+		types[i] = encoder.ContentType()
+	}
+	return types
+}
+
+// mediaRange is one entry of an Accept header, such as "text/*;q=0.5".
+type mediaRange struct {
+	mediaType string
+	quality   float64
+}
+
+// parseAccept splits an Accept header into its media ranges, skipping malformed entries.
+func parseAccept(accept string) []mediaRange {
+	ranges := []mediaRange{}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		ranges = append(ranges, mediaRange{mediaType: mediaType, quality: quality})
+	}
+	return ranges
+}
+
+// matches reports whether the media range covers the content type, honoring */* and type/* wildcards.
+func (m mediaRange) matches(contentType string) bool {
+	if m.mediaType == "*/*" || m.mediaType == contentType {
+		return true
+	}
+	if strings.HasSuffix(m.mediaType, "/*") {
+		return strings.HasPrefix(contentType, strings.TrimSuffix(m.mediaType, "*"))
+	}
+	return false
+}
+
+// jsonEncoder writes values as JSON, the default format.
+type jsonEncoder struct{}
+
+func (jsonEncoder) ContentType() string { return "application/json" }
+
+func (jsonEncoder) Encode(w io.Writer, v interface{}) error {
+	return json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,90 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// object is a JSON object whose members keep the order they were encoded in,
+// so struct fields come out in declaration order in every format.
+type object []member
+
+// MarshalJSON writes the object's members in order.
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(m.key)
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// member is one key and value of an object.
+type member struct {
+	key   string
+	value interface{}
+}
+
+// toTree encodes v as JSON and decodes it back into a tree of nil, bool, json.Number, string,
+// []interface{}, and object values.
+func toTree(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return readValue(decoder)
+}
+
+// readValue reads the next complete value from the decoder.
+func readValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		obj := object{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := readValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, member{key: key.(string), value: value})
+		}
+		// Consume the closing brace
+		_, err := decoder.Token()
+		return obj, err
+	case json.Delim('['):
+		list := []interface{}{}
+		for decoder.More() {
+			value, err := readValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		// Consume the closing bracket
+		_, err := decoder.Token()
+		return list, err
+	default:
+		return token, nil
+	}
+}
@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"my-card-game/internal/db"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoLocker keeps job locks as documents in a MongoDB collection, one per lock name,
+// recording which replica holds the lock and until when.
+type MongoLocker struct {
+	collection *mongo.Collection
+	owner      string
+}
+
+// NewMongoLocker creates a locker backed by the given collection.
+// Each locker identifies itself by the host name and a random suffix, so replicas on one host stay distinct.
+func NewMongoLocker(collection *mongo.Collection) *MongoLocker {
+	host, _ := os.Hostname()
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+
+	return &MongoLocker{collection: collection, owner: host + "-" + hex.EncodeToString(suffix)}
+}
+
+// Acquire takes the named lock when it is free or has expired, or renews it when this locker already holds it.
+func (l *MongoLocker) Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	// Create a context with the configured operation timeout to manage the database operation
+	ctx, cancel := context.WithTimeout(ctx, db.OperationTimeout)
+	defer cancel()
+
+	now := time.Now().UTC()
+	filter := bson.M{
+		"_id": name,
+		"$or": []bson.M{
+			{"locked_until": bson.M{"$lte": now}},
+			{"owner": l.owner},
+		},
+	}
+	update := bson.M{"$set": bson.M{"owner": l.owner, "locked_until": now.Add(ttl)}}
+
+	// When the lock is held elsewhere the filter misses and the upsert collides with the existing document
+	_, err := l.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,163 @@
+// Package scheduler runs periodic background jobs, such as purging expired sessions or archiving finished games.
+//
+// Each registered job runs on its own interval. Before a run the scheduler takes the job's lock for one interval,
+// so when several replicas of the server share a database only one of them runs the job each period.
+// The scheduler keeps per-job metrics (runs, failures, skipped runs, items affected, and the last outcome)
+// for the admin API.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is a unit of periodic background work.
+type Job struct {
+	Name     string              // Unique name, also used as the job's lock name
+	Interval time.Duration       // How often the job runs
+	Run      func() (int, error) // Does the work and reports how many items it affected
+}
+
+// Locker hands out named, time-limited locks shared by every replica of the server.
+type Locker interface {
+	// Acquire takes the named lock for ttl, or renews it if this replica already holds it.
+	// It returns false without an error if another replica holds the lock.
+	Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error)
+}
+
+// Stats describes a job's schedule and the outcome of its runs since the server started.
+type Stats struct {
+	Name         string     `json:"name"`
+	Interval     string     `json:"interval"`
+	Runs         int64      `json:"runs"`                    // Runs started by this replica
+	Failures     int64      `json:"failures"`                // Runs that returned an error
+	Skipped      int64      `json:"skipped"`                 // Ticks skipped because another replica held the lock
+	Affected     int64      `json:"affected"`                // Items affected across all runs
+	LastRun      *time.Time `json:"last_run,omitempty"`      // When the last run started
+	LastDuration string     `json:"last_duration,omitempty"` // How long the last run took
+	LastError    string     `json:"last_error,omitempty"`    // Error of the last run, if it failed
+}
+
+// Scheduler runs registered jobs on their intervals.
+type Scheduler struct {
+	locker  Locker
+	mu      sync.Mutex
+	jobs    []Job
+	stats   map[string]*Stats
+	started bool
+}
+
+// New creates a scheduler that coordinates with other replicas through the locker.
+// A nil locker runs every job on every replica.
+func New(locker Locker) *Scheduler {
+	return &Scheduler{locker: locker, stats: map[string]*Stats{}}
+}
+
+// Register adds a job. Jobs must be registered before Start is called and their names must be unique.
+func (s *Scheduler) Register(job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return errors.New("scheduler: jobs must be registered before the scheduler starts")
+	}
+	if job.Name == "" || job.Interval <= 0 || job.Run == nil {
+		return errors.New("scheduler: a job needs a name, a positive interval, and a run function")
+	}
+	if _, exists := s.stats[job.Name]; exists {
+		return errors.New("scheduler: job " + job.Name + " is already registered")
+	}
+
+	s.jobs = append(s.jobs, job)
+	s.stats[job.Name] = &Stats{Name: job.Name, Interval: job.Interval.String()}
+	return nil
+}
+
+// Start runs every registered job on its interval until the context is cancelled.
+// The first run of each job happens one interval after Start.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	s.started = true
+	jobs := append([]Job{}, s.jobs...)
+	s.mu.Unlock()
+
+	for _, job := range jobs {
+		go s.loop(ctx, job)
+	}
+}
+
+// Stats returns the metrics of every job in the order they were registered.
+func (s *Scheduler) Stats() []Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make([]Stats, len(s.jobs))
+	for i, job := range s.jobs {
+		stats[i] = *s.stats[job.Name]
+	}
+	return stats
+}
+
+// loop runs one job on every tick of its interval.
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runOnce(ctx, job)
+		}
+	}
+}
+
+// runOnce takes the job's lock and, if it is free, runs the job and records the outcome.
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	// Hold the lock for a whole interval so other replicas skip this period
+	if s.locker != nil {
+		acquired, err := s.locker.Acquire(ctx, job.Name, job.Interval)
+		if err != nil {
+			log.Printf("job %s: could not take its lock: %v", job.Name, err)
+			s.record(job.Name, func(stats *Stats) { stats.Failures++; stats.LastError = err.Error() })
+			return
+		}
+		if !acquired {
+			s.record(job.Name, func(stats *Stats) { stats.Skipped++ })
+			return
+		}
+	}
+
+	started := time.Now().UTC()
+	affected, err := job.Run()
+	duration := time.Since(started)
+
+	if err != nil {
+		log.Printf("job %s failed: %v", job.Name, err)
+	} else if affected > 0 {
+		log.Printf("job %s affected %d item(s)", job.Name, affected)
+	}
+
+	s.record(job.Name, func(stats *Stats) {
+		stats.Runs++
+		stats.Affected += int64(affected)
+		stats.LastRun = &started
+		stats.LastDuration = duration.Round(time.Millisecond).String()
+		stats.LastError = ""
+		if err != nil {
+			stats.Failures++
+			stats.LastError = err.Error()
+		}
+	})
+}
+
+// record updates a job's metrics under the scheduler's lock.
+func (s *Scheduler) record(name string, update func(*Stats)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	update(s.stats[name])
+}
@@ -0,0 +1,194 @@
+// Package validate checks decoded request payloads against rules declared in `validate` struct tags,
+// so handlers can reject bad input with field-level errors before it reaches the services.
+//
+// Rules are separated by commas:
+//
+//	required   the field must be set: a non-blank string, a non-empty slice or map, or a non-nil pointer
+//	max=N      a string may be at most N characters long
+//	name       a string may only hold letters, digits, spaces, and - _ ' . ( ) # :
+//	player     a string may only hold letters, digits, spaces, - and _, as it is used as a database field name
+//	objectid   a string must be a 24-character hex MongoDB ObjectID
+//
+// Rules other than required are skipped for empty values, so optional fields are only checked when given.
+// Nested and embedded structs are checked too, and fields are reported by their JSON names.
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FieldError describes one field that failed validation.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Errors is the list of fields that failed validation.
+type Errors []FieldError
+
+// Error joins the field errors into one message.
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Field + ": " + fieldErr.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Struct validates v, which must be a struct or a pointer to one, and returns the fields that failed as Errors,
+// or nil if every field passed.
+func Struct(v interface{}) error {
+	var errs Errors
+	checkStruct(reflect.Indirect(reflect.ValueOf(v)), "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Field validates a single value against the given rules, reporting failures under the given field name.
+// It is used for values that do not arrive in a struct, such as path variables.
+func Field(name string, value interface{}, rules string) error {
+	var errs Errors
+	checkValue(reflect.ValueOf(value), name, rules, &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// checkStruct validates each field of the struct, descending into nested structs.
+func checkStruct(v reflect.Value, prefix string, errs *Errors) {
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		value := v.Field(i)
+
+		// Embedded structs contribute their fields at the same level
+		if field.Anonymous {
+			checkStruct(reflect.Indirect(value), prefix, errs)
+			continue
+		}
+
+		name := prefix + jsonName(field)
+		checkValue(value, name, field.Tag.Get("validate"), errs)
+
+		// Check the fields of nested structs that were provided
+		if inner := reflect.Indirect(value); inner.Kind() == reflect.Struct && inner.Type() != reflect.TypeOf(primitive.ObjectID{}) {
+			checkStruct(inner, name+".", errs)
+		}
+	}
+}
+
+// checkValue applies the rules to one value.
+func checkValue(value reflect.Value, name, rules string, errs *Errors) {
+	if rules == "" {
+		return
+	}
+
+	// Follow pointers to the value they point at, remembering whether one was given at all
+	present := value.IsValid()
+	for present && value.Kind() == reflect.Ptr {
+		present = !value.IsNil()
+		if present {
+			value = value.Elem()
+		}
+	}
+	if present {
+		present = !isEmpty(value)
+	}
+
+	for _, rule := range strings.Split(rules, ",") {
+		rule, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		if rule == "required" {
+			if !present {
+				*errs = append(*errs, FieldError{Field: name, Message: "is required"})
+				return
+			}
+			continue
+		}
+
+		// The remaining rules only apply to strings that were given
+		if !present || value.Kind() != reflect.String {
+			continue
+		}
+		if message := checkString(value.String(), rule, arg); message != "" {
+			*errs = append(*errs, FieldError{Field: name, Message: message})
+			return
+		}
+	}
+}
+
+// checkString applies one rule to a string, returning a message describing the failure or "" if it passed.
+func checkString(s, rule, arg string) string {
+	switch rule {
+	case "max":
+		limit, err := strconv.Atoi(arg)
+		if err != nil {
+			panic("validate: invalid max rule " + arg)
+		}
+		if utf8.RuneCountInString(s) > limit {
+			return fmt.Sprintf("must be at most %d characters", limit)
+		}
+	case "name":
+		if !onlyAllowed(s, " -_'.()#:") {
+			return "may only contain letters, digits, spaces, and - _ ' . ( ) # :"
+		}
+	case "player":
+		if !onlyAllowed(s, " -_") {
+			return "may only contain letters, digits, spaces, - and _"
+		}
+	case "objectid":
+		if !primitive.IsValidObjectID(s) {
+			return "must be a valid ID"
+		}
+	default:
+		panic("validate: unknown rule " + rule)
+	}
+	return ""
+}
+
+// onlyAllowed reports whether s holds only letters, digits, and the extra characters given.
+func onlyAllowed(s, extra string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(extra, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isEmpty reports whether the value counts as not given: a blank string or an empty slice or map.
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(value.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	default:
+		return false
+	}
+}
+
+// jsonName returns the name a struct field has in JSON.
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
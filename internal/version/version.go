@@ -0,0 +1,39 @@
+// Package version reports which build of the server is running.
+package version
+
+import "runtime/debug"
+
+// Version is the release version of the server. It is set at build time with
+// -ldflags "-X my-card-game/internal/version.Version=v1.2.3".
+var Version = "dev"
+
+// Info describes the running build.
+type Info struct {
+	Version   string `json:"version"`
+	GoVersion string `json:"go_version"`
+	Revision  string `json:"revision,omitempty"`   // VCS commit the binary was built from, when known
+	BuildTime string `json:"build_time,omitempty"` // VCS commit time, when known
+	Modified  bool   `json:"modified,omitempty"`   // Whether the working tree had uncommitted changes
+}
+
+// Get returns the build information of the running binary.
+func Get() Info {
+	info := Info{Version: Version}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.GoVersion = build.GoVersion
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.time":
+			info.BuildTime = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}